@@ -21,13 +21,19 @@ import (
 	"github.com/spf13/viper"
 	"k8s.io/klog/v2"
 	cmdConfig "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/addons"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/image"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
 )
 
 // cacheImageConfigKey is the config field name used to store which images we have previously cached
@@ -110,9 +116,110 @@ var reloadCacheCmd = &cobra.Command{
 	},
 }
 
+var (
+	bundleOutput            string
+	bundleDriver            string
+	bundleKubernetesVersion string
+	bundleContainerRuntime  string
+	bundleCNI               string
+	bundleAddons            []string
+	bundlePreload           bool
+)
+
+// bundleCacheCmd represents the cache bundle command
+var bundleCacheCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Bundle the artifacts required to start a cluster offline.",
+	Long: `Downloads every artifact required to start a cluster with the given configuration (ISO or base image, preload, Kubernetes binaries, CNI and addon images) and writes them to a tarball.
+Extract the tarball into the minikube home directory of a machine without network access and run 'minikube start --offline' with the same configuration.
+If the profile exists, its configuration is used unless overridden by flags.`,
+	Example: `minikube cache bundle --driver=docker --kubernetes-version=v1.30.0 --addons=metrics-server -o bundle.tar.gz
+tar -xzf bundle.tar.gz -C ~/.minikube`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		cc, existingAddons := bundleClusterConfig(cmd)
+		toEnable := addons.ToEnable(cc, existingAddons, bundleAddons)
+		isoURLs := download.DefaultISOURLsForArch(config.GuestArch(*cc))
+		if cc.MinikubeISO != "" {
+			isoURLs = append([]string{cc.MinikubeISO}, isoURLs...)
+		}
+		arts, err := node.RequiredArtifacts(cc, node.ArtifactOptions{
+			ISOURLs: isoURLs,
+			Preload: bundlePreload,
+			Addons:  node.EnabledAddons(toEnable),
+		})
+		if err != nil {
+			exit.Error(reason.InetCacheBundle, "Failed to determine the required artifacts", err)
+		}
+
+		out.Step(style.Caching, "Caching {{.count}} artifacts ...", out.V{"count": len(arts)})
+		if err := node.CacheArtifacts(arts); err != nil {
+			exit.Error(reason.InetCacheBundle, "Failed to cache artifacts", err)
+		}
+		if missing := node.MissingArtifacts(arts); len(missing) > 0 {
+			for _, a := range missing {
+				out.ErrT(style.Option, "{{.kind}} {{.name}} ({{.path}})", out.V{"kind": a.Kind, "name": a.Name, "path": a.Path})
+			}
+			exit.Message(reason.InetCacheBundle, "Unable to cache {{.count}} artifacts", out.V{"count": len(missing)})
+		}
+
+		files, err := node.WriteBundle(arts, bundleOutput)
+		if err != nil {
+			exit.Error(reason.HostCacheBundle, "Failed to write bundle", err)
+		}
+		out.Step(style.Check, "Wrote {{.count}} files to {{.path}}", out.V{"count": len(files), "path": bundleOutput})
+	},
+}
+
+// bundleClusterConfig returns the cluster config to bundle the artifacts for, and the addons already enabled in it
+func bundleClusterConfig(cmd *cobra.Command) (*config.ClusterConfig, map[string]bool) {
+	cc, err := config.Load(ClusterFlagValue())
+	if err != nil {
+		if !config.IsNotExist(err) {
+			exit.Error(reason.HostConfigLoad, "Unable to load config", err)
+		}
+		cc = &config.ClusterConfig{
+			Driver: bundleDriver,
+			KubernetesConfig: config.KubernetesConfig{
+				KubernetesVersion: bundleKubernetesVersion,
+				ContainerRuntime:  bundleContainerRuntime,
+				CNI:               bundleCNI,
+			},
+		}
+	}
+	if cmd.Flags().Changed("driver") {
+		cc.Driver = bundleDriver
+	}
+	if cmd.Flags().Changed("kubernetes-version") {
+		cc.KubernetesConfig.KubernetesVersion = bundleKubernetesVersion
+	}
+	if cmd.Flags().Changed("container-runtime") {
+		cc.KubernetesConfig.ContainerRuntime = bundleContainerRuntime
+	}
+	if cmd.Flags().Changed("cni") {
+		cc.KubernetesConfig.CNI = bundleCNI
+	}
+	if _, err := util.ParseKubernetesVersion(cc.KubernetesConfig.KubernetesVersion); err != nil {
+		exit.Message(reason.Usage, "Invalid Kubernetes version {{.version}}: {{.err}}", out.V{"version": cc.KubernetesConfig.KubernetesVersion, "err": err})
+	}
+
+	existingAddons := cc.Addons
+	if existingAddons == nil {
+		existingAddons = map[string]bool{}
+	}
+	return cc, existingAddons
+}
+
 func init() {
 	addCacheCmdFlags()
+	bundleCacheCmd.Flags().StringVarP(&bundleOutput, "output", "o", "minikube-bundle.tar.gz", "Path of the bundle to write")
+	bundleCacheCmd.Flags().StringVar(&bundleDriver, "driver", driver.Docker, "The driver the bundle is for")
+	bundleCacheCmd.Flags().StringVar(&bundleKubernetesVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The Kubernetes version the bundle is for")
+	bundleCacheCmd.Flags().StringVar(&bundleContainerRuntime, "container-runtime", constants.Docker, "The container runtime the bundle is for. Options include: [docker, containerd, cri-o]")
+	bundleCacheCmd.Flags().StringVar(&bundleCNI, "cni", "", "The CNI the bundle is for, as passed to 'minikube start --cni' (default: auto)")
+	bundleCacheCmd.Flags().StringSliceVar(&bundleAddons, "addons", []string{}, "Addons to include the images of, in addition to the default addons")
+	bundleCacheCmd.Flags().BoolVar(&bundlePreload, "preload", true, "If set, bundle the preloaded images tarball instead of the individual Kubernetes images and binaries, where available")
 	cacheCmd.AddCommand(addCacheCmd)
 	cacheCmd.AddCommand(deleteCacheCmd)
 	cacheCmd.AddCommand(reloadCacheCmd)
+	cacheCmd.AddCommand(bundleCacheCmd)
 }
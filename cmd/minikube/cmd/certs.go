@@ -0,0 +1,278 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	kconst "k8s.io/minikube/third_party/kubeadm/app/constants"
+)
+
+var (
+	certsFormat   string
+	certsRotateCA bool
+)
+
+// certsCmd represents the set of certs subcommands
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "List or rotate the certificates of a cluster",
+	Long:  "Inspects the certificates of a cluster, and issues new ones in place.",
+	Run: func(_ *cobra.Command, _ []string) {
+		exit.Message(reason.Usage, "Usage: minikube certs [list|rotate]")
+	},
+}
+
+// certsListCmd represents the certs list command
+var certsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the certificates of a cluster",
+	Long:  "Lists the subject, SANs, issuer and expiry of the shared CA, the client certs of the profile, and the certs kubeadm issued on every node.",
+	Example: `minikube certs list
+minikube certs list --format json`,
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube certs list")
+		}
+		co := mustload.Running(ClusterFlagValue())
+
+		certs, err := bootstrapper.HostCerts(*co.Config)
+		if err != nil {
+			exit.Error(reason.GuestCertList, "Failed to list the certs of the profile", err)
+		}
+		for _, n := range co.Config.Nodes {
+			m := config.MachineName(*co.Config, n)
+			nc, err := bootstrapper.NodeCerts(nodeRunner(co, m), m)
+			if err != nil {
+				exit.Error(reason.GuestCertList, "Failed to list the certs of the node", err)
+			}
+			certs = append(certs, nc...)
+		}
+
+		if err := printCerts(certs, certsFormat); err != nil {
+			exit.Error(reason.GuestCertList, "Failed to print the certs", err)
+		}
+	},
+}
+
+// certsRotateCmd represents the certs rotate command
+var certsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Issue new certificates to a running cluster",
+	Long: `Issues new certificates to a running cluster in place, restarts the components which use them and updates kubeconfig.
+
+With --ca the certificate authorities are replaced as well. The minikube CA is shared by all profiles, so the certificates of the other profiles have to be rotated afterwards too.`,
+	Example: `minikube certs rotate
+minikube certs rotate --ca`,
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube certs rotate [--ca]")
+		}
+		co := mustload.Running(ClusterFlagValue())
+		cc := co.Config
+		if cc.KubernetesConfig.KubernetesVersion == constants.NoKubernetesVersion {
+			exit.Message(reason.Usage, "The cluster does not run Kubernetes")
+		}
+
+		pcp, err := config.ControlPlane(*cc)
+		if err != nil {
+			exit.Error(reason.GuestCpConfig, "Unable to get the primary control-plane node", err)
+		}
+		pcpRunner := nodeRunner(co, config.MachineName(*cc, pcp))
+
+		if err := bootstrapper.RemoveCerts(*cc, certsRotateCA); err != nil {
+			exit.Error(reason.GuestCertRotate, "Failed to remove the old certs", err)
+		}
+
+		bsName := viper.GetString(cmdcfg.Bootstrapper)
+		for _, n := range rotationOrder(*cc) {
+			m := config.MachineName(*cc, n)
+			out.Step(style.Permissions, "Issuing new certificates to {{.name}} ...", out.V{"name": m})
+			r := nodeRunner(co, m)
+			bs, err := cluster.Bootstrapper(co.API, bsName, *cc, r)
+			if err != nil {
+				exit.Error(reason.InternalBootstrapper, "Failed to get bootstrapper", err)
+			}
+			caRunner := pcpRunner
+			if n.Etcd {
+				// the members of an external etcd get their certs from the CA of etcd on the first one
+				caRunner = nodeRunner(co, config.MachineName(*cc, config.EtcdNodes(*cc)[0]))
+			} else if n.ControlPlane && config.IsExternalEtcd(*cc) {
+				if err := node.CopyEtcdClientCerts(co.API, *cc, r); err != nil {
+					exit.Error(reason.GuestCertRotate, "Failed to copy the etcd client certificate", err)
+				}
+			}
+			if err := bs.RotateCerts(*cc, n, caRunner, certsRotateCA); err != nil {
+				exit.Error(reason.GuestCertRotate, "Failed to issue new certificates", err)
+			}
+		}
+
+		if err := updateKubeconfigCerts(co); err != nil {
+			exit.Error(reason.HostKubeconfigUpdate, "Failed to update kubeconfig", err)
+		}
+
+		bs, err := cluster.Bootstrapper(co.API, bsName, *cc, pcpRunner)
+		if err != nil {
+			exit.Error(reason.InternalBootstrapper, "Failed to get bootstrapper", err)
+		}
+		if err := bs.WaitForNode(*cc, pcp, kconst.DefaultControlPlaneTimeout); err != nil {
+			exit.Error(reason.GuestCertRotate, "The cluster did not come back with the new certificates", err)
+		}
+
+		out.Step(style.Celebrate, "Issued new certificates to cluster {{.name}}", out.V{"name": cc.Name})
+		if certsRotateCA {
+			out.WarningT("The minikube CA is shared by all profiles, run 'minikube certs rotate -p <profile>' for each of the other running profiles")
+			out.Styled(style.Tip, "Pods which cache the cluster CA may have to be restarted")
+		}
+	},
+}
+
+// nodeRunner returns the command runner of a node of a running cluster
+func nodeRunner(co mustload.ClusterController, machineName string) command.Runner {
+	h, err := machine.LoadHost(co.API, machineName)
+	if err != nil {
+		exit.Error(reason.GuestLoadHost, "Error getting host", err)
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
+	}
+	return r
+}
+
+// rotationOrder returns the nodes of a cluster in the order to rotate their certs: the members of an external etcd first,
+// as the apiservers use the client cert they issue, then the primary control-plane node, as it holds the CAs, then the other
+// control-plane nodes, then the workers. The load balancer node has no certs.
+func rotationOrder(cc config.ClusterConfig) []config.Node {
+	etcdNodes := []config.Node{}
+	if config.IsExternalEtcd(cc) {
+		etcdNodes = config.EtcdNodes(cc)
+	}
+	nodes := []config.Node{}
+	workers := []config.Node{}
+	for _, n := range config.KubernetesNodes(cc) {
+		switch {
+		case config.IsPrimaryControlPlane(cc, n):
+			nodes = append([]config.Node{n}, nodes...)
+		case n.ControlPlane:
+			nodes = append(nodes, n)
+		default:
+			workers = append(workers, n)
+		}
+	}
+	return append(append(etcdNodes, nodes...), workers...)
+}
+
+// updateKubeconfigCerts points the kubeconfig context of a cluster at its new certs, keeping its server address
+func updateKubeconfigCerts(co mustload.ClusterController) error {
+	cc := co.Config
+	host, port, err := kubeconfig.Endpoint(cc.Name, kubeconfig.PathFromEnv())
+	if err != nil {
+		host, port = co.CP.Hostname, co.CP.Port
+	}
+	kcs := &kubeconfig.Settings{
+		ClusterName:          cc.Name,
+		Namespace:            cc.KubernetesConfig.Namespace,
+		ClusterServerAddress: fmt.Sprintf("https://%s", net.JoinHostPort(host, strconv.Itoa(port))),
+		ClientCertificate:    localpath.ClientCert(cc.Name),
+		ClientKey:            localpath.ClientKey(cc.Name),
+		CertificateAuthority: bootstrapper.CACert(*cc),
+		KeepContext:          true,
+		EmbedCerts:           cc.EmbedCerts,
+	}
+	kcs.SetPath(kubeconfig.PathFromEnv())
+	return kubeconfig.Update(kcs)
+}
+
+// printCerts prints certs in the given format
+func printCerts(certs []bootstrapper.CertInfo, format string) error {
+	switch format {
+	case "json":
+		b, err := json.Marshal(certs)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", b)
+	case "yaml":
+		b, err := yaml.Marshal(certs)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s", b)
+	case "table":
+		var data [][]string
+		for _, c := range certs {
+			expires := fmt.Sprintf("%s (%s)", c.NotAfter.UTC().Format(time.RFC3339), expiresIn(c.ExpiresIn()))
+			data = append(data, []string{c.Location, c.Path, c.Subject, c.Issuer, strings.Join(c.SANs, "\n"), expires})
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Location", "Path", "Subject", "Issuer", "SANs", "Expires"})
+		table.SetAutoFormatHeaders(false)
+		table.SetAutoWrapText(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetCenterSeparator("|")
+		table.AppendBulk(data)
+		table.Render()
+	default:
+		return fmt.Errorf("unknown format %q, must be one of: table|json|yaml", format)
+	}
+	return nil
+}
+
+// expiresIn formats the time left until a cert expires
+func expiresIn(d time.Duration) string {
+	if d <= 0 {
+		return "expired"
+	}
+	if days := int(d.Hours() / 24); days > 0 {
+		return fmt.Sprintf("%dd", days)
+	}
+	return d.Round(time.Minute).String()
+}
+
+func init() {
+	certsListCmd.Flags().StringVar(&certsFormat, "format", "table", "Format output. One of: table|json|yaml")
+	certsRotateCmd.Flags().BoolVar(&certsRotateCA, "ca", false, "Replace the certificate authorities of the cluster too")
+	certsCmd.AddCommand(certsListCmd)
+	certsCmd.AddCommand(certsRotateCmd)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestRotationOrder(t *testing.T) {
+	names := func(nodes []config.Node) []string {
+		names := []string{}
+		for _, n := range nodes {
+			names = append(names, n.Name)
+		}
+		return names
+	}
+	tests := []struct {
+		name string
+		cc   config.ClusterConfig
+		want []string
+	}{
+		{
+			name: "stacked etcd",
+			cc: config.ClusterConfig{Nodes: []config.Node{
+				{Name: "", ControlPlane: true, Worker: true},
+				{Name: "m02", Worker: true},
+				{Name: "m03", ControlPlane: true, Worker: true},
+			}},
+			want: []string{"", "m03", "m02"},
+		},
+		{
+			name: "external etcd and haproxy",
+			cc: config.ClusterConfig{
+				KubernetesConfig: config.KubernetesConfig{EtcdTopology: config.EtcdExternal, HALoadBalancer: config.HAProxy},
+				Nodes: []config.Node{
+					{Name: "", ControlPlane: true, Worker: true},
+					{Name: "m02", ControlPlane: true, Worker: true},
+					{Name: "m03", Etcd: true},
+					{Name: "m04", Etcd: true},
+					{Name: "m05", LoadBalancer: true},
+					{Name: "m06", Worker: true},
+				},
+			},
+			want: []string{"m03", "m04", "", "m02", "m06"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := names(rotationOrder(tc.cc)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("rotationOrder() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -83,7 +83,7 @@ var settings = []Setting{
 		name:        "disk-size",
 		set:         SetString,
 		validations: []setFn{IsValidDiskSize},
-		callbacks:   []setFn{RequiresRestartMsg},
+		callbacks:   []setFn{ResizeDisks},
 	},
 	{
 		name:        "host-only-cidr",
@@ -111,6 +111,16 @@ var settings = []Setting{
 		set:         SetString,
 		validations: []setFn{IsValidURL, IsURLExists},
 	},
+	{
+		name:        "preload-base-url",
+		set:         SetString,
+		validations: []setFn{IsValidURL},
+	},
+	{
+		name:        config.ArtifactMirror,
+		set:         SetString,
+		validations: []setFn{IsValidArtifactMirror},
+	},
 	{
 		name: config.WantUpdateNotification,
 		set:  SetBool,
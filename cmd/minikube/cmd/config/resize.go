@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+)
+
+// ResizeDisks grows the disks of the nodes of the current cluster to the disk size set, if its driver can resize machines.
+// Nodes with a disk size of their own are left alone, see `minikube node resize`.
+func ResizeDisks(_, disksize string) error {
+	cname := ClusterFlagValue()
+	if !config.ProfileExists(cname) {
+		return nil
+	}
+	size, err := util.CalculateSizeInMB(disksize)
+	if err != nil {
+		return fmt.Errorf("invalid disk size: %v", err)
+	}
+	api, cc := mustload.Partial(cname)
+	if size < cc.DiskSize {
+		return RequiresRestartMsg("", "")
+	}
+	if size == cc.DiskSize {
+		return nil
+	}
+	for _, n := range cc.Nodes {
+		if n.DiskSize != 0 {
+			continue
+		}
+		machineName := config.MachineName(*cc, n)
+		h, err := machine.LoadHost(api, machineName)
+		if err != nil {
+			return err
+		}
+		if _, ok := h.Driver.(machine.Resizer); !ok {
+			return RequiresRestartMsg("", "")
+		}
+		out.Step(style.Provisioning, "Resizing the disk of {{.name}} to {{.size}}MB ...", out.V{"name": machineName, "size": size})
+		if err := machine.ResizeDisk(api, machineName, size); err != nil {
+			return fmt.Errorf("resizing the disk of %s: %v", machineName, err)
+		}
+	}
+	cc.DiskSize = size
+	return config.SaveProfile(cc.Name, cc)
+}
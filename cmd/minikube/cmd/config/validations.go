@@ -27,6 +27,7 @@ import (
 	units "github.com/docker/go-units"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/out"
 )
@@ -117,6 +118,15 @@ func IsURLExists(_, location string) error {
 	return nil
 }
 
+// IsValidArtifactMirror checks if a location is the base URL of an artifact mirror or a valid artifact source file
+func IsValidArtifactMirror(_, location string) error {
+	s, err := download.LoadArtifactSource(location)
+	if err != nil {
+		return err
+	}
+	return s.Validate()
+}
+
 // IsPositive checks if an integer is positive
 func IsPositive(name, val string) error {
 	i, err := strconv.Atoi(val)
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/configure"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	kconst "k8s.io/minikube/third_party/kubeadm/app/constants"
+)
+
+var (
+	configureExtraOptions config.ExtraOptionSlice
+	configureUnset        []string
+	configureFeatureGates string
+	configureNames        []string
+	configureIPs          []net.IP
+	configureDryRun       bool
+)
+
+// configureCmd represents the configure command
+var configureCmd = &cobra.Command{
+	Use:   "configure",
+	Short: "Changes the Kubernetes settings of a running cluster in place",
+	Long: `Changes the Kubernetes settings of a running cluster in place, without recreating it.
+
+Only the config files the settings are rendered into and the components they affect are regenerated and restarted, on each node, then the profile is saved.
+The extra config given is merged into the one of the cluster, use --unset-extra-config to remove options.`,
+	Example: `minikube configure --extra-config=apiserver.v=5
+minikube configure --feature-gates=InPlacePodVerticalScaling=true --dry-run
+minikube configure --apiserver-names=minikube.example.com --unset-extra-config=kubelet.max-pods`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube configure [flags]")
+		}
+		co := mustload.Healthy(ClusterFlagValue())
+		cc := co.Config
+		if cc.KubernetesConfig.KubernetesVersion == constants.NoKubernetesVersion {
+			exit.Message(reason.Usage, "The cluster does not run Kubernetes")
+		}
+		if cc.Bootstrapper == bootstrapper.K3s {
+			exit.Message(reason.Usage, "The {{.bootstrapper}} bootstrapper is reconfigured by running 'minikube start' with the new settings", out.V{"bootstrapper": cc.Bootstrapper})
+		}
+
+		k8s, err := configuredKubernetesConfig(cmd, cc.KubernetesConfig)
+		if err != nil {
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+		}
+		if invalid := bsutil.FindInvalidExtraConfigFlags(k8s.ExtraOptions); len(invalid) > 0 {
+			exit.Message(reason.Usage, "These --extra-config parameters are invalid: {{.invalid_extra_opts}}", out.V{"invalid_extra_opts": invalid})
+		}
+		components, err := configure.Affected(cc.KubernetesConfig, k8s)
+		if err != nil {
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+		}
+		if len(components) == 0 {
+			out.Styled(style.Check, "Nothing to configure, the cluster already has these settings")
+			return
+		}
+		out.Step(style.Option, "Components to reconfigure: {{.components}}", out.V{"components": strings.Join(components, ", ")})
+
+		if configureDryRun {
+			newCC := *cc
+			newCC.KubernetesConfig = k8s
+			for _, n := range cc.Nodes {
+				r := nodeRunner(co, config.MachineName(*cc, n))
+				cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: r, Socket: cc.KubernetesConfig.CRISocket})
+				if err != nil {
+					exit.Error(reason.InternalRuntime, "Failed runtime", err)
+				}
+				files, err := configure.Render(*cc, newCC, n, cr)
+				if err != nil {
+					exit.Error(reason.KubernetesConfigureFailed, "Failed to render the config", err)
+				}
+				for _, f := range files {
+					diff, err := f.Diff()
+					if err != nil {
+						exit.Error(reason.KubernetesConfigureFailed, "Failed to diff the config", err)
+					}
+					fmt.Print(diff)
+				}
+			}
+			out.Styled(style.DryRun, "dry-run complete, the cluster was not changed")
+			return
+		}
+
+		if err := configure.Configure(co.API, cc, k8s); err != nil {
+			exit.Error(reason.KubernetesConfigureFailed, "Failed to reconfigure Kubernetes", err)
+		}
+		pcp, err := config.ControlPlane(*cc)
+		if err != nil {
+			exit.Error(reason.GuestCpConfig, "Unable to get the primary control-plane node", err)
+		}
+		bs, err := cluster.Bootstrapper(co.API, viper.GetString(cmdcfg.Bootstrapper), *cc, nodeRunner(co, config.MachineName(*cc, pcp)))
+		if err != nil {
+			exit.Error(reason.InternalBootstrapper, "Failed to get bootstrapper", err)
+		}
+		if err := bs.WaitForNode(*cc, pcp, kconst.DefaultControlPlaneTimeout); err != nil {
+			exit.Error(reason.KubernetesConfigureFailed, "The cluster did not come back with the new settings", err)
+		}
+		out.Step(style.Celebrate, "Reconfigured cluster {{.name}}", out.V{"name": cc.Name})
+	},
+}
+
+// configuredKubernetesConfig returns the Kubernetes settings of a cluster with the changes given on the command line applied
+func configuredKubernetesConfig(cmd *cobra.Command, k8s config.KubernetesConfig) (config.KubernetesConfig, error) {
+	opts := append(config.ExtraOptionSlice{}, k8s.ExtraOptions...)
+	for _, e := range configureExtraOptions {
+		i := slices.IndexFunc(opts, func(o config.ExtraOption) bool { return o.Component == e.Component && o.Key == e.Key })
+		if i >= 0 {
+			opts[i] = e
+		} else {
+			opts = append(opts, e)
+		}
+	}
+	for _, u := range configureUnset {
+		component, key, ok := strings.Cut(u, ".")
+		if !ok || key == "" {
+			return k8s, fmt.Errorf("invalid --unset-extra-config %q, expected <component>.<key>", u)
+		}
+		i := slices.IndexFunc(opts, func(o config.ExtraOption) bool { return o.Component == component && o.Key == key })
+		if i < 0 {
+			return k8s, fmt.Errorf("--unset-extra-config %q is not set on the cluster", u)
+		}
+		opts = slices.Delete(opts, i, i+1)
+	}
+	k8s.ExtraOptions = opts
+
+	if cmd.Flags().Changed(featureGates) {
+		k8s.FeatureGates = configureFeatureGates
+	}
+	if cmd.Flags().Changed("apiserver-names") {
+		k8s.APIServerNames = configureNames
+	}
+	if cmd.Flags().Changed("apiserver-ips") {
+		k8s.APIServerIPs = configureIPs
+	}
+	return k8s, nil
+}
+
+func init() {
+	configureCmd.Flags().Var(&configureExtraOptions, "extra-config", "A set of key=value pairs that describe configuration that may be passed to different components, merged into the ones of the cluster. The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.")
+	configureCmd.Flags().StringSliceVar(&configureUnset, "unset-extra-config", nil, "A set of <component>.<key> extra config options to remove from the cluster")
+	configureCmd.Flags().StringVar(&configureFeatureGates, featureGates, "", "A set of key=value pairs that describe feature gates for alpha/experimental features, replacing the ones of the cluster")
+	configureCmd.Flags().StringSliceVar(&configureNames, "apiserver-names", nil, "A set of apiserver names which are used in the generated certificate for kubernetes, replacing the ones of the cluster")
+	configureCmd.Flags().IPSliceVar(&configureIPs, "apiserver-ips", nil, "A set of apiserver IP Addresses which are used in the generated certificate for kubernetes, replacing the ones of the cluster")
+	configureCmd.Flags().BoolVar(&configureDryRun, "dry-run", false, "Only print the diff of the config files the new settings are rendered into")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestConfiguredKubernetesConfig(t *testing.T) {
+	old := config.KubernetesConfig{
+		FeatureGates:   "A=true",
+		APIServerNames: []string{"foo"},
+		ExtraOptions: config.ExtraOptionSlice{
+			{Component: "apiserver", Key: "v", Value: "2"},
+			{Component: "kubelet", Key: "max-pods", Value: "50"},
+		},
+	}
+	newCmd := func(flags map[string]string) *cobra.Command {
+		cmd := &cobra.Command{}
+		configureExtraOptions = nil
+		configureUnset = nil
+		cmd.Flags().Var(&configureExtraOptions, "extra-config", "")
+		cmd.Flags().StringSliceVar(&configureUnset, "unset-extra-config", nil, "")
+		cmd.Flags().StringVar(&configureFeatureGates, featureGates, "", "")
+		cmd.Flags().StringSliceVar(&configureNames, "apiserver-names", nil, "")
+		cmd.Flags().IPSliceVar(&configureIPs, "apiserver-ips", nil, "")
+		for k, v := range flags {
+			if err := cmd.Flags().Set(k, v); err != nil {
+				t.Fatalf("set %s: %v", k, err)
+			}
+		}
+		return cmd
+	}
+
+	got, err := configuredKubernetesConfig(newCmd(map[string]string{
+		"extra-config":       "apiserver.v=5",
+		"unset-extra-config": "kubelet.max-pods",
+		"apiserver-names":    "bar",
+	}), old)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := config.KubernetesConfig{
+		FeatureGates:   "A=true",
+		APIServerNames: []string{"bar"},
+		ExtraOptions:   config.ExtraOptionSlice{{Component: "apiserver", Key: "v", Value: "5"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("config mismatch (-want +got):\n%s", diff)
+	}
+	if old.ExtraOptions[0].Value != "2" || len(old.ExtraOptions) != 2 {
+		t.Errorf("expected the current config not to change, got %+v", old.ExtraOptions)
+	}
+
+	if _, err := configuredKubernetesConfig(newCmd(map[string]string{"unset-extra-config": "scheduler.v"}), old); err == nil {
+		t.Error("expected unsetting an option which is not set to fail")
+	}
+}
@@ -339,9 +339,11 @@ func deleteProfile(ctx context.Context, profile *config.Profile) error {
 				klog.Warningf("failed to unpause %s : %v", profile.Name, err)
 			}
 			out.Styled(style.DeletingHost, `Deleting "{{.profile_name}}" in {{.driver_name}} ...`, out.V{"profile_name": profile.Name, "driver_name": profile.Config.Driver})
-			for _, n := range profile.Config.Nodes {
-				machineName := config.MachineName(*profile.Config, n)
-				delete.PossibleLeftOvers(ctx, machineName, profile.Config.Driver)
+		}
+		// nodes added with another driver may be containers in a cluster of VMs, and the other way around
+		for _, n := range profile.Config.Nodes {
+			if drv := config.NodeDriver(*profile.Config, n); driver.IsKIC(drv) {
+				delete.PossibleLeftOvers(ctx, config.MachineName(*profile.Config, n), drv)
 			}
 		}
 	} else {
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+var driversOutput string
+
+// driverInfo is what 'minikube drivers' shows of a driver
+type driverInfo struct {
+	Name         string
+	Alias        []string `json:",omitempty"`
+	Plugin       string   `json:",omitempty"`
+	Priority     string
+	Default      bool
+	Installed    bool
+	Healthy      bool
+	Running      bool
+	Version      string `json:",omitempty"`
+	Error        string `json:",omitempty"`
+	Fix          string `json:",omitempty"`
+	Doc          string `json:",omitempty"`
+	Capabilities registry.Capabilities
+}
+
+// newDriverInfo returns the info of a driver, given its state on the host
+func newDriverInfo(def registry.DriverDef, st registry.State) driverInfo {
+	info := driverInfo{
+		Name:         def.Name,
+		Alias:        def.Alias,
+		Plugin:       def.Plugin,
+		Priority:     def.Priority.String(),
+		Default:      def.Default,
+		Installed:    st.Installed,
+		Healthy:      st.Healthy,
+		Running:      st.Running,
+		Version:      st.Version,
+		Fix:          st.Fix,
+		Doc:          st.Doc,
+		Capabilities: def.Capabilities,
+	}
+	if info.Capabilities == nil {
+		info.Capabilities = registry.Capabilities{}
+	}
+	if st.Error != nil {
+		info.Error = st.Error.Error()
+	}
+	return info
+}
+
+// driversCmd represents the drivers command
+var driversCmd = &cobra.Command{
+	Use:   "drivers",
+	Short: "List the drivers and the features they support",
+	Long:  "Lists the drivers minikube can run clusters with on this host, built-in or plugins, with their state and the features of minikube they support.",
+	Run: func(_ *cobra.Command, _ []string) {
+		exit.Message(reason.Usage, "Usage: minikube drivers [list|describe]")
+	},
+}
+
+// driversListCmd represents the drivers list command
+var driversListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the drivers, their state on the host and their capabilities",
+	Long:  "Lists the drivers, in the order minikube prefers them, with whether they are installed and healthy on this host, their version, and the features of minikube they support.",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube drivers list [-o table|json]")
+		}
+		var infos []driverInfo
+		for _, ds := range registry.Available(false) {
+			infos = append(infos, newDriverInfo(registry.Driver(ds.Name), ds.State))
+		}
+
+		switch strings.ToLower(driversOutput) {
+		case "json":
+			printJSON(infos)
+		case "table":
+			var data [][]string
+			for _, i := range infos {
+				name := i.Name
+				if i.Plugin != "" {
+					name += " (plugin)"
+				}
+				data = append(data, []string{name, i.Priority, yesNo(i.Default), yesNo(i.Installed), yesNo(i.Healthy), i.Version, strings.Join(capabilityNames(i.Capabilities), "\n")})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Driver", "Priority", "Default", "Installed", "Healthy", "Version", "Capabilities"})
+			table.SetAutoFormatHeaders(false)
+			table.SetAutoWrapText(false)
+			table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetCenterSeparator("|")
+			table.SetRowLine(true)
+			table.AppendBulk(data)
+			table.Render()
+		default:
+			exit.Message(reason.Usage, "invalid output format: {{.output}}. Valid values: 'table', 'json'", out.V{"output": driversOutput})
+		}
+	},
+}
+
+// driversDescribeCmd represents the drivers describe command
+var driversDescribeCmd = &cobra.Command{
+	Use:     "describe DRIVER",
+	Short:   "Show the state of a driver on the host and the features it supports",
+	Long:    "Shows the state of a driver on the host, as minikube checks it before selecting the driver, and which features of minikube it supports.",
+	Example: "minikube drivers describe docker -o json",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube drivers describe DRIVER [-o table|json]")
+		}
+		def := registry.Driver(args[0])
+		if def.Empty() {
+			exit.Message(reason.DrvUnsupportedOS, "The driver '{{.driver}}' is not supported on {{.os}}/{{.arch}}", out.V{"driver": args[0], "os": runtime.GOOS, "arch": runtime.GOARCH})
+		}
+		info := newDriverInfo(def, registry.Status(def.Name))
+
+		switch strings.ToLower(driversOutput) {
+		case "json":
+			printJSON(info)
+		case "table":
+			out.Ln("Name:      %s", info.Name)
+			if len(info.Alias) > 0 {
+				out.Ln("Alias:     %s", strings.Join(info.Alias, ", "))
+			}
+			if info.Plugin != "" {
+				out.Ln("Plugin:    %s", info.Plugin)
+			}
+			out.Ln("Priority:  %s", info.Priority)
+			out.Ln("Default:   %s", yesNo(info.Default))
+			out.Ln("Installed: %s", yesNo(info.Installed))
+			out.Ln("Healthy:   %s", yesNo(info.Healthy))
+			if info.Version != "" {
+				out.Ln("Version:   %s", info.Version)
+			}
+			if info.Error != "" {
+				out.Ln("Error:     %s", info.Error)
+			}
+			if info.Fix != "" {
+				out.Ln("Fix:       %s", info.Fix)
+			}
+			if info.Doc != "" {
+				out.Ln("Doc:       %s", info.Doc)
+			}
+			out.Ln("Capabilities:")
+			for _, c := range registry.AllCapabilities {
+				out.Ln("  %-16s %s", c, yesNo(info.Capabilities.Has(c)))
+			}
+		default:
+			exit.Message(reason.Usage, "invalid output format: {{.output}}. Valid values: 'table', 'json'", out.V{"output": driversOutput})
+		}
+	},
+}
+
+// exitIfUnsupported exits, unless forced, if the driver lacks any of the requested capabilities,
+// with the same error whichever command or flag asked for them
+func exitIfUnsupported(drvName string, requested ...registry.Capability) {
+	err := registry.CheckCapabilities(drvName, requested...)
+	if err == nil {
+		return
+	}
+	exitIfNotForced(reason.DrvUnsupportedFeature, "The '{{.driver}}' driver does not support {{.features}}, see 'minikube drivers describe {{.driver}}'", out.V{"driver": drvName, "features": err.(*registry.UnsupportedError).Missing.String()})
+}
+
+func capabilityNames(cs registry.Capabilities) []string {
+	names := make([]string, len(cs))
+	for i, c := range cs {
+		names[i] = string(c)
+	}
+	return names
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func printJSON(v interface{}) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		exit.Error(reason.InternalJSONMarshal, "Failed to marshal to JSON", err)
+	}
+	fmt.Printf("%s\n", b)
+}
+
+func init() {
+	driversCmd.PersistentFlags().StringVarP(&driversOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+	driversCmd.AddCommand(driversListCmd)
+	driversCmd.AddCommand(driversDescribeCmd)
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/etcd"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	kconst "k8s.io/minikube/third_party/kubeadm/app/constants"
+)
+
+var (
+	etcdBackupOutput string
+	etcdStatusFormat string
+)
+
+// etcdCmd represents the set of etcd subcommands
+var etcdCmd = &cobra.Command{
+	Use:   "etcd",
+	Short: "Back up, restore and maintain the etcd of a cluster",
+	Long:  "Manages the etcd members kubeadm runs on the control-plane nodes, or on the dedicated etcd nodes with --etcd-topology=external, with the etcdctl of their etcd containers.",
+	Run: func(_ *cobra.Command, _ []string) {
+		exit.Message(reason.Usage, "Usage: minikube etcd [backup|restore|defrag|status]")
+	},
+}
+
+// etcdBackupCmd represents the etcd backup command
+var etcdBackupCmd = &cobra.Command{
+	Use:     "backup",
+	Short:   "Save a snapshot of etcd to a file",
+	Long:    "Saves a snapshot of the etcd keyspace of a running cluster, taken on the first etcd member, to a file on the host.",
+	Example: "minikube etcd backup -o etcd.db",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 0 || etcdBackupOutput == "" {
+			exit.Message(reason.Usage, "Usage: minikube etcd backup -o <file>")
+		}
+		co := mustloadEtcd()
+		r := nodeRunner(co, config.MachineName(*co.Config, config.EtcdNodes(*co.Config)[0]))
+
+		out.Step(style.Copying, "Saving a snapshot of etcd ...")
+		size, err := backupEtcd(r, etcdBackupOutput)
+		if err != nil {
+			exit.Error(reason.GuestEtcdBackup, "Failed to back up etcd", err)
+		}
+		out.Step(style.Celebrate, "Saved a snapshot of etcd to {{.path}} ({{.size}})", out.V{"path": etcdBackupOutput, "size": units.HumanSize(float64(size))})
+	},
+}
+
+// etcdRestoreCmd represents the etcd restore command
+var etcdRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore etcd from a snapshot file",
+	Long: `Replaces the data of every etcd member of a running cluster by a snapshot taken with 'minikube etcd backup', then restarts etcd and the apiserver.
+
+All the changes made to the cluster since the snapshot was taken are lost.`,
+	Example: "minikube etcd restore etcd.db",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube etcd restore <file>")
+		}
+		src := args[0]
+		if _, err := os.Stat(src); err != nil {
+			exit.Message(reason.Usage, "Invalid snapshot file: {{.err}}", out.V{"err": err})
+		}
+		co := mustloadEtcd()
+		cc := co.Config
+
+		dst := path.Join(etcd.BackupDir, fmt.Sprintf("restore-%s.db", time.Now().Format("20060102150405")))
+		members := etcd.Members(*cc)
+		for i, n := range config.EtcdNodes(*cc) {
+			m := config.MachineName(*cc, n)
+			out.Step(style.Resetting, "Restoring etcd on {{.name}} ...", out.V{"name": m})
+			if err := restoreEtcd(nodeRunner(co, m), src, dst, members[i], members); err != nil {
+				exit.Error(reason.GuestEtcdRestore, "Failed to restore etcd", err)
+			}
+		}
+		for _, n := range config.ControlPlanes(*cc) {
+			if err := restartAPIServer(nodeRunner(co, config.MachineName(*cc, n)), *cc); err != nil {
+				exit.Error(reason.GuestEtcdRestore, "Failed to restart the apiserver", err)
+			}
+		}
+
+		pcp, err := config.ControlPlane(*cc)
+		if err != nil {
+			exit.Error(reason.GuestCpConfig, "Unable to get the primary control-plane node", err)
+		}
+		bs, err := cluster.Bootstrapper(co.API, viper.GetString(cmdcfg.Bootstrapper), *cc, nodeRunner(co, config.MachineName(*cc, pcp)))
+		if err != nil {
+			exit.Error(reason.InternalBootstrapper, "Failed to get bootstrapper", err)
+		}
+		if err := bs.WaitForNode(*cc, pcp, kconst.DefaultControlPlaneTimeout); err != nil {
+			exit.Error(reason.GuestEtcdRestore, "The cluster did not come back after restoring etcd", err)
+		}
+		out.Step(style.Celebrate, "Restored etcd of cluster {{.name}} from {{.path}}", out.V{"name": cc.Name, "path": src})
+		out.Styled(style.Tip, "Pods created or deleted since the snapshot was taken may have to be recreated")
+	},
+}
+
+// etcdDefragCmd represents the etcd defrag command
+var etcdDefragCmd = &cobra.Command{
+	Use:   "defrag",
+	Short: "Release the disk space etcd holds for deleted keys",
+	Long:  "Defragments every etcd member, one at a time, as each member blocks while it is defragmented.",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube etcd defrag")
+		}
+		co := mustloadEtcd()
+		for _, n := range config.EtcdNodes(*co.Config) {
+			m := config.MachineName(*co.Config, n)
+			out.Step(style.Sparkle, "Defragmenting etcd on {{.name}} ...", out.V{"name": m})
+			if err := etcd.Defrag(nodeRunner(co, m)); err != nil {
+				exit.Error(reason.GuestEtcdDefrag, "Failed to defragment etcd", err)
+			}
+		}
+	},
+}
+
+// etcdStatusCmd represents the etcd status command
+var etcdStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of the etcd members",
+	Long:  "Shows the database size, leadership, members and alarms of every etcd member.",
+	Example: `minikube etcd status
+minikube etcd status --format json`,
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube etcd status")
+		}
+		co := mustloadEtcd()
+		statuses := []etcd.Status{}
+		for _, n := range config.EtcdNodes(*co.Config) {
+			m := config.MachineName(*co.Config, n)
+			st, err := etcd.MemberStatus(nodeRunner(co, m), m)
+			if err != nil {
+				exit.Error(reason.GuestEtcdStatus, "Failed to get the status of etcd", err)
+			}
+			statuses = append(statuses, st)
+		}
+		if err := printEtcdStatus(statuses, etcdStatusFormat); err != nil {
+			exit.Error(reason.GuestEtcdStatus, "Failed to print the status of etcd", err)
+		}
+	},
+}
+
+// mustloadEtcd loads a running cluster whose control plane runs etcd as kubeadm does
+func mustloadEtcd() mustload.ClusterController {
+	co := mustload.Running(ClusterFlagValue())
+	if co.Config.KubernetesConfig.KubernetesVersion == constants.NoKubernetesVersion {
+		exit.Message(reason.Usage, "The cluster does not run Kubernetes")
+	}
+	if co.Config.Bootstrapper == bootstrapper.K3s {
+		exit.Message(reason.Usage, "The etcd commands are not supported by the {{.bootstrapper}} bootstrapper", out.V{"bootstrapper": co.Config.Bootstrapper})
+	}
+	return co
+}
+
+// backupEtcd saves a snapshot of etcd on an etcd node and copies it to dst on the host, returning its size
+func backupEtcd(r command.Runner, dst string) (int, error) {
+	snapshot := path.Join(etcd.BackupDir, fmt.Sprintf("backup-%s.db", time.Now().Format("20060102150405")))
+	if err := etcd.Snapshot(r, snapshot); err != nil {
+		return 0, err
+	}
+	defer func() {
+		if _, err := r.RunCmd(exec.Command("sudo", "rm", "-f", snapshot)); err != nil {
+			out.WarningT("Failed to remove the snapshot from the node: {{.err}}", out.V{"err": err})
+		}
+	}()
+
+	rr, err := r.RunCmd(exec.Command("sudo", "cat", snapshot))
+	if err != nil {
+		return 0, errors.Wrap(err, "read snapshot")
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return 0, errors.Wrap(err, "mkdir")
+	}
+	if err := os.WriteFile(dst, rr.Stdout.Bytes(), 0600); err != nil {
+		return 0, errors.Wrap(err, "write snapshot")
+	}
+	return rr.Stdout.Len(), nil
+}
+
+// restoreEtcd copies the snapshot src on the host to dst on an etcd node, and restores the etcd member of the node from it
+func restoreEtcd(r command.Runner, src, dst string, m etcd.Member, members []etcd.Member) error {
+	f, err := assets.NewFileAsset(src, path.Dir(dst), path.Base(dst), "0600")
+	if err != nil {
+		return errors.Wrap(err, "snapshot asset")
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			out.WarningT("Failed to close the snapshot: {{.err}}", out.V{"err": err})
+		}
+	}()
+	if err := r.Copy(f); err != nil {
+		return errors.Wrap(err, "copy snapshot")
+	}
+	if err := etcd.Restore(r, dst, m, members); err != nil {
+		return err
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "rm", "-f", dst)); err != nil {
+		return errors.Wrap(err, "remove snapshot")
+	}
+	return nil
+}
+
+// restartAPIServer restarts the apiserver of a control-plane node, so it does not serve from its cache of the replaced data
+func restartAPIServer(r command.Runner, cc config.ClusterConfig) error {
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: r})
+	if err != nil {
+		return errors.Wrap(err, "container runtime")
+	}
+	ids, err := cr.ListContainers(cruntime.ListContainersOptions{State: cruntime.Running, Name: "kube-apiserver"})
+	if err != nil {
+		return errors.Wrap(err, "list apiserver containers")
+	}
+	// kubelet starts the apiserver again
+	return cr.StopContainers(ids)
+}
+
+// printEtcdStatus prints the status of the etcd members in the given format
+func printEtcdStatus(statuses []etcd.Status, format string) error {
+	switch format {
+	case "json":
+		b, err := json.Marshal(statuses)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", b)
+	case "table":
+		var data [][]string
+		for _, s := range statuses {
+			alarms := "none"
+			if len(s.Alarms) > 0 {
+				alarms = strings.Join(s.Alarms, ",")
+			}
+			size := fmt.Sprintf("%s (%s in use)", units.HumanSize(float64(s.DBSize)), units.HumanSize(float64(s.DBSizeInUse)))
+			data = append(data, []string{s.Node, s.MemberID, s.Version, size, fmt.Sprintf("%t", s.Leader), fmt.Sprintf("%d", s.RaftTerm), strings.Join(s.Members, "\n"), alarms})
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Node", "Member", "Version", "DB Size", "Leader", "Raft Term", "Members", "Alarms"})
+		table.SetAutoFormatHeaders(false)
+		table.SetAutoWrapText(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetCenterSeparator("|")
+		table.AppendBulk(data)
+		table.Render()
+	default:
+		return fmt.Errorf("unknown format %q, must be one of: table|json", format)
+	}
+	return nil
+}
+
+func init() {
+	etcdBackupCmd.Flags().StringVarP(&etcdBackupOutput, "output", "o", "", "The file to save the snapshot to")
+	etcdStatusCmd.Flags().StringVar(&etcdStatusFormat, "format", "table", "Format output. One of: table|json")
+	etcdCmd.AddCommand(etcdBackupCmd)
+	etcdCmd.AddCommand(etcdRestoreCmd)
+	etcdCmd.AddCommand(etcdDefragCmd)
+	etcdCmd.AddCommand(etcdStatusCmd)
+}
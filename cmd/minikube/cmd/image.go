@@ -23,10 +23,12 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/image"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -56,6 +58,15 @@ var (
 	buildEnv   []string
 	buildOpt   []string
 	format     string
+
+	imageNodes    []string
+	inspectFormat string
+	historyFormat string
+	diffFormat    string
+
+	pruneAllNodes  bool
+	pruneAll       bool
+	pruneOlderThan time.Duration
 )
 
 func saveFile(r io.Reader) (string, error) {
@@ -385,6 +396,93 @@ $ minikube image push busybox
 	},
 }
 
+var inspectImageCmd = &cobra.Command{
+	Use:   "inspect IMAGE",
+	Short: "Display detailed information about an image on the nodes",
+	Long:  "Display the config, layers, size, platform, labels and digests of an image on each node. Nodes holding the same image are grouped together.",
+	Example: `
+$ minikube image inspect busybox
+
+$ minikube image inspect busybox --node m02 --format yaml
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		profile, err := config.LoadProfile(viper.GetString(config.ProfileName))
+		if err != nil {
+			exit.Error(reason.Usage, "loading profile", err)
+		}
+
+		if err := machine.InspectImage(profile, args[0], imageNodes, inspectFormat); err != nil {
+			exit.Error(reason.GuestImageInspect, "Failed to inspect image", err)
+		}
+	},
+}
+
+var historyImageCmd = &cobra.Command{
+	Use:   "history IMAGE",
+	Short: "Show the history of an image",
+	Long:  "Show the layers of an image and the commands that created them.",
+	Example: `
+$ minikube image history busybox
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		profile, err := config.LoadProfile(viper.GetString(config.ProfileName))
+		if err != nil {
+			exit.Error(reason.Usage, "loading profile", err)
+		}
+
+		if err := machine.ImageHistory(profile, args[0], nodeName, historyFormat); err != nil {
+			exit.Error(reason.GuestImageInspect, "Failed to get image history", err)
+		}
+	},
+}
+
+var diffImageCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show images that differ between nodes",
+	Long:  "Show images that are missing on some nodes, or whose tags point at different image IDs on different nodes.",
+	Example: `
+$ minikube image diff
+
+$ minikube image diff --node minikube --node minikube-m02
+`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		profile, err := config.LoadProfile(viper.GetString(config.ProfileName))
+		if err != nil {
+			exit.Error(reason.Usage, "loading profile", err)
+		}
+
+		if err := machine.DiffImages(profile, imageNodes, diffFormat); err != nil {
+			exit.Error(reason.GuestImageInspect, "Failed to compare images", err)
+		}
+	},
+}
+
+var pruneImageCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove unused images",
+	Long:  "Remove images which are not used by any container. By default only dangling images are removed from the primary control-plane node.",
+	Example: `
+$ minikube image prune
+
+$ minikube image prune --all --all-nodes --older-than 24h
+`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		profile, err := config.LoadProfile(viper.GetString(config.ProfileName))
+		if err != nil {
+			exit.Error(reason.Usage, "loading profile", err)
+		}
+
+		o := cruntime.PruneImagesOptions{All: pruneAll, OlderThan: pruneOlderThan}
+		if err := machine.PruneImages(profile, pruneAllNodes, nodeName, o); err != nil {
+			exit.Error(reason.GuestImagePrune, "Failed to prune images", err)
+		}
+	},
+}
+
 func init() {
 	loadImageCmd.Flags().BoolVar(&pull, "pull", false, "Pull the remote image (no caching)")
 	loadImageCmd.Flags().BoolVar(&imgDaemon, "daemon", false, "Cache image from docker daemon")
@@ -408,4 +506,18 @@ func init() {
 	imageCmd.AddCommand(listImageCmd)
 	imageCmd.AddCommand(tagImageCmd)
 	imageCmd.AddCommand(pushImageCmd)
+	inspectImageCmd.Flags().StringArrayVar(&imageNodes, "node", nil, "The node to inspect the image on, may be repeated. Defaults to all nodes.")
+	inspectImageCmd.Flags().StringVar(&inspectFormat, "format", "json", "Format output. One of: json|yaml")
+	imageCmd.AddCommand(inspectImageCmd)
+	historyImageCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to show the image history on. Defaults to the primary control plane.")
+	historyImageCmd.Flags().StringVar(&historyFormat, "format", "table", "Format output. One of: table|json|yaml")
+	imageCmd.AddCommand(historyImageCmd)
+	diffImageCmd.Flags().StringArrayVar(&imageNodes, "node", nil, "The node to compare, may be repeated. Defaults to all nodes.")
+	diffImageCmd.Flags().StringVar(&diffFormat, "format", "table", "Format output. One of: table|json|yaml")
+	imageCmd.AddCommand(diffImageCmd)
+	pruneImageCmd.Flags().BoolVar(&pruneAllNodes, "all-nodes", false, "Prune images on all nodes.")
+	pruneImageCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to prune images on. Defaults to the primary control plane.")
+	pruneImageCmd.Flags().BoolVarP(&pruneAll, "all", "a", false, "Remove all unused images, not just dangling ones.")
+	pruneImageCmd.Flags().DurationVar(&pruneOlderThan, "older-than", 0, "Only remove images created more than this long ago (e.g. 24h).")
+	imageCmd.AddCommand(pruneImageCmd)
 }
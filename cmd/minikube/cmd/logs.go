@@ -55,6 +55,10 @@ var (
 	auditLogs bool
 	// lastStartOnly shows logs from last start
 	lastStartOnly bool
+	// apiServerAudit shows the apiserver audit logs of the control-plane nodes
+	apiServerAudit bool
+	// apiServerAuditFilter selects the apiserver audit events to show
+	apiServerAuditFilter logs.APIServerAuditFilter
 )
 
 // logsCmd represents the logs command
@@ -92,6 +96,10 @@ var logsCmd = &cobra.Command{
 			}
 			return
 		}
+		if apiServerAudit {
+			outputAPIServerAudit(logOutput)
+			return
+		}
 		logs.OutputOffline(numberOfLines, logOutput)
 
 		if shouldSilentFail() {
@@ -128,6 +136,29 @@ var logsCmd = &cobra.Command{
 	},
 }
 
+// outputAPIServerAudit outputs the apiserver audit logs of all the control-plane nodes of the cluster
+func outputAPIServerAudit(logOutput *os.File) {
+	co := mustload.Running(ClusterFlagValue())
+	if co.Config.KubernetesConfig.AuditPolicy == "" {
+		exit.Message(reason.Usage, "The apiserver audit log is not enabled, start the cluster with --audit-policy")
+	}
+
+	sources := []logs.APIServerAuditSource{}
+	for _, n := range config.ControlPlanes(*co.Config) {
+		m := config.MachineName(*co.Config, n)
+		sources = append(sources, logs.APIServerAuditSource{Node: m, Runner: nodeRunner(co, m)})
+	}
+	if followLogs {
+		if err := logs.FollowAPIServerAudit(sources, apiServerAuditFilter, logOutput); err != nil {
+			exit.Error(reason.InternalLogFollow, "Follow", err)
+		}
+		return
+	}
+	if err := logs.OutputAPIServerAudit(sources, numberOfLines, apiServerAuditFilter, logOutput); err != nil {
+		exit.Error(reason.GuestAPIServerAudit, "Failed to read the apiserver audit log", err)
+	}
+}
+
 // shouldSilentFail returns true if the user specifies the --file flag and the host isn't running
 // This is to prevent outputting the message 'The control plane node must be running for this command' which confuses
 // many users while gathering logs to report their issue as the message makes them think the log file wasn't generated
@@ -160,4 +191,9 @@ func init() {
 	logsCmd.Flags().StringVar(&fileOutput, "file", "", "If present, writes to the provided file instead of stdout.")
 	logsCmd.Flags().BoolVar(&auditLogs, "audit", false, "Show only the audit logs")
 	logsCmd.Flags().BoolVar(&lastStartOnly, "last-start-only", false, "Show only the last start logs.")
+	logsCmd.Flags().BoolVar(&apiServerAudit, "apiserver-audit", false, "Show only the apiserver audit logs of the control-plane nodes. The cluster must have been started with --audit-policy.")
+	logsCmd.Flags().StringVar(&apiServerAuditFilter.User, "audit-user", "", "Show only the apiserver audit events of this user (requires --apiserver-audit)")
+	logsCmd.Flags().StringVar(&apiServerAuditFilter.Verb, "audit-verb", "", "Show only the apiserver audit events with this verb, such as get, list or create (requires --apiserver-audit)")
+	logsCmd.Flags().StringVar(&apiServerAuditFilter.Resource, "audit-resource", "", "Show only the apiserver audit events on this resource, such as pods (requires --apiserver-audit)")
+	logsCmd.Flags().StringVar(&apiServerAuditFilter.Namespace, "audit-namespace", "", "Show only the apiserver audit events in this namespace (requires --apiserver-audit)")
 }
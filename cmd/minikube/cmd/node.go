@@ -28,6 +28,6 @@ var nodeCmd = &cobra.Command{
 	Short: "Add, remove, or list additional nodes",
 	Long:  "Operations on nodes",
 	Run: func(_ *cobra.Command, _ []string) {
-		exit.Message(reason.Usage, "Usage: minikube node [add|start|stop|delete|list]")
+		exit.Message(reason.Usage, "Usage: minikube node [add|start|stop|delete|list|promote|demote|failover-test|resize|snapshot]")
 	},
 }
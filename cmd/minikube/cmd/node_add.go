@@ -17,11 +17,15 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
+	"runtime"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/mustload"
@@ -29,13 +33,27 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registry"
 	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
 )
 
 var (
 	cpNode              bool
 	workerNode          bool
 	deleteNodeOnFailure bool
+	nodeCPUs            int
+	nodeMemory          string
+	nodeDiskSize        string
+	nodeLabels          []string
+	nodeTaints          []string
+	nodePool            string
+	nodeCount           int
+	nodeSSHIPAddresses  []string
+	nodeSSHUser         string
+	nodeSSHKey          string
+	nodeDriver          string
+	nodeNetwork         string
 )
 
 var nodeAddCmd = &cobra.Command{
@@ -46,8 +64,13 @@ var nodeAddCmd = &cobra.Command{
 		co := mustload.Healthy(ClusterFlagValue())
 		cc := co.Config
 
-		if driver.BareMetal(cc.Driver) {
-			out.FailureT("none driver does not support multi-node clusters")
+		drv := cc.Driver
+		if nodeDriver != "" {
+			drv = nodeDriver
+		}
+		exitIfUnsupported(drv, registry.MultiNode)
+		if drv != cc.Driver {
+			validateNodeDriver(cc, drv)
 		}
 
 		if cpNode && !config.IsHA(*cc) {
@@ -62,53 +85,173 @@ var nodeAddCmd = &cobra.Command{
 			roles = append(roles, "control-plane")
 		}
 
-		// calculate appropriate new node name with id following the last existing one
-		lastID, err := node.ID(cc.Nodes[len(cc.Nodes)-1].Name)
+		pool, err := nodeAddPool(cmd, *cc)
 		if err != nil {
-			lastID = len(cc.Nodes)
-			out.ErrLn("determining last node index (will assume %d): %v", lastID, err)
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
 		}
-		name := node.Name(lastID + 1)
-
-		out.Step(style.Happy, "Adding node {{.name}} to cluster {{.cluster}} as {{.roles}}", out.V{"name": name, "cluster": cc.Name, "roles": roles})
-		n := config.Node{
-			Name:              name,
-			Worker:            workerNode,
-			ControlPlane:      cpNode,
-			KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		if pool.Name != "" {
+			if cpNode {
+				exit.Message(reason.Usage, "Node pools only hold worker nodes, and cannot be used with --control-plane")
+			}
+			if _, ok := config.FindNodePool(*cc, pool.Name); !ok {
+				out.Step(style.Option, "Creating node pool {{.pool}}", out.V{"pool": pool.Name})
+				cc.NodePools = append(cc.NodePools, pool)
+			}
 		}
+		if nodeCount < 1 {
+			exit.Message(reason.Usage, "--count must be at least 1, got {{.count}}", out.V{"count": nodeCount})
+		}
+		if driver.IsSSH(drv) && len(nodeSSHIPAddresses) != nodeCount {
+			exit.Message(reason.Usage, "The ssh driver runs each node on a host of its own, give {{.count}} with --ssh-ip-address", out.V{"count": nodeCount})
+		}
+
+		for i := 0; i < nodeCount; i++ {
+			// calculate appropriate new node name with id following the last existing one
+			lastID, err := node.ID(cc.Nodes[len(cc.Nodes)-1].Name)
+			if err != nil {
+				lastID = len(cc.Nodes)
+				out.ErrLn("determining last node index (will assume %d): %v", lastID, err)
+			}
+			name := node.Name(lastID + 1)
+			if driver.IsSSH(drv) {
+				config.SetSSHHost(cc, lastID+1, nodeSSHIPAddresses[i])
+			}
 
-		// Make sure to decrease the default amount of memory we use per VM if this is the first worker node
-		if len(cc.Nodes) == 1 {
-			if viper.GetString(memory) == "" {
-				cc.Memory = 2200
+			out.Step(style.Happy, "Adding node {{.name}} to cluster {{.cluster}} as {{.roles}}", out.V{"name": name, "cluster": cc.Name, "roles": roles})
+			n := config.Node{
+				Name:              name,
+				Worker:            workerNode,
+				ControlPlane:      cpNode,
+				KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+			}
+			config.ApplyNodePool(&n, pool)
+			if drv != cc.Driver {
+				n.Driver = drv
+				n.Network = nodeNetwork
 			}
 
-			if !cc.MultiNodeRequested || cni.IsDisabled(*cc) {
-				warnAboutMultiNodeCNI()
+			// Make sure to decrease the default amount of memory we use per VM if this is the first worker node
+			if len(cc.Nodes) == 1 {
+				if viper.GetString(memory) == "" {
+					cc.Memory = 2200
+				}
+
+				if !cc.MultiNodeRequested || cni.IsDisabled(*cc) {
+					warnAboutMultiNodeCNI()
+				}
 			}
-		}
 
-		register.Reg.SetStep(register.InitialSetup)
-		if err := node.Add(cc, n, deleteNodeOnFailure); err != nil {
-			_, err := maybeDeleteAndRetry(cmd, *cc, n, nil, err)
-			if err != nil {
-				exit.Error(reason.GuestNodeAdd, "failed to add node", err)
+			register.Reg.SetStep(register.InitialSetup)
+			if err := node.Add(cc, n, deleteNodeOnFailure); err != nil {
+				_, err := maybeDeleteAndRetry(cmd, *cc, n, nil, err)
+				if err != nil {
+					exit.Error(reason.GuestNodeAdd, "failed to add node", err)
+				}
+			}
+
+			if err := config.SaveProfile(cc.Name, cc); err != nil {
+				exit.Error(reason.HostSaveProfile, "failed to save config", err)
 			}
+
+			out.Step(style.Ready, "Successfully added {{.name}} to {{.cluster}}!", out.V{"name": name, "cluster": cc.Name})
 		}
+	},
+}
+
+// validateNodeDriver exits if nodes of the driver drv, which is not the one of the cluster, cannot be added to it,
+// and otherwise prepares the cluster config for them
+func validateNodeDriver(cc *config.ClusterConfig, drv string) {
+	if registry.Driver(drv).Empty() {
+		exit.Message(reason.DrvUnsupported, "The '{{.driver}}' driver is not supported on {{.os}}/{{.arch}}", out.V{"driver": drv, "os": runtime.GOOS, "arch": runtime.GOARCH})
+	}
+	if st := registry.Status(drv); !st.Installed || !st.Healthy {
+		exit.Message(reason.DrvNotHealthy, "The '{{.driver}}' driver is not ready to use: {{.error}}", out.V{"driver": drv, "error": st.Error})
+	}
 
-		if err := config.SaveProfile(cc.Name, cc); err != nil {
-			exit.Error(reason.HostSaveProfile, "failed to save config", err)
+	nodeNetwork = driverNetwork(drv, nodeNetwork)
+	if err := node.CheckDriver(*cc, drv, nodeNetwork); err != nil {
+		exit.Message(reason.DrvUnreachableNetworks, "{{.err}}", out.V{"err": err})
+	}
+
+	// the ISO is only downloaded for clusters of VMs
+	if driver.IsVM(drv) && !driver.IsSSH(drv) && cc.MinikubeISO == "" {
+		url, err := download.ISO(download.DefaultISOURLsForArch(config.GuestArch(*cc)), false)
+		if err != nil {
+			exit.Error(reason.GuestNodeAdd, "Failed to cache ISO", err)
 		}
+		cc.MinikubeISO = url
+	}
 
-		out.Step(style.Ready, "Successfully added {{.name}} to {{.cluster}}!", out.V{"name": name, "cluster": cc.Name})
-	},
+	// the hosts of all the nodes of the ssh driver are reached as the same user
+	if nodeSSHUser != "" {
+		cc.SSHUser = nodeSSHUser
+	}
+	if nodeSSHKey != "" {
+		cc.SSHKey = nodeSSHKey
+	}
+}
+
+// nodeAddPool returns the node pool of the nodes to add: the existing pool given with --pool, or a new one with the resources, labels and taints given,
+// which are set on the nodes alone when no pool is given
+func nodeAddPool(cmd *cobra.Command, cc config.ClusterConfig) (config.NodePool, error) {
+	changed := func(flags ...string) bool {
+		for _, f := range flags {
+			if cmd.Flags().Changed(f) {
+				return true
+			}
+		}
+		return false
+	}
+	if p, ok := config.FindNodePool(cc, nodePool); ok && nodePool != "" {
+		if changed("cpus", "memory", "disk-size", "labels", "taints") {
+			return p, fmt.Errorf("node pool %q already exists, its nodes are added with its own resources, labels and taints", nodePool)
+		}
+		return p, nil
+	}
+
+	p := config.NodePool{Name: nodePool, CPUs: nodeCPUs, Labels: nodeLabels, Taints: nodeTaints}
+	if nodeMemory != "" {
+		mem, err := util.CalculateSizeInMB(nodeMemory)
+		if err != nil {
+			return p, fmt.Errorf("invalid --memory %q: %v", nodeMemory, err)
+		}
+		p.Memory = mem
+	}
+	if nodeDiskSize != "" {
+		disk, err := util.CalculateSizeInMB(nodeDiskSize)
+		if err != nil {
+			return p, fmt.Errorf("invalid --disk-size %q: %v", nodeDiskSize, err)
+		}
+		p.DiskSize = disk
+	}
+	if p.CPUs < 0 {
+		return p, fmt.Errorf("invalid --cpus %d", p.CPUs)
+	}
+	if p.Name == "" {
+		if err := config.ValidateLabels(p.Labels); err != nil {
+			return p, err
+		}
+		return p, config.ValidateTaints(p.Taints)
+	}
+	return p, config.ValidateNodePool(p)
 }
 
 func init() {
 	nodeAddCmd.Flags().BoolVar(&cpNode, "control-plane", false, "If set, added node will become a control-plane. Defaults to false. Currently only supported for existing HA (multi-control plane) clusters.")
 	nodeAddCmd.Flags().BoolVar(&workerNode, "worker", true, "If set, added node will be available as worker. Defaults to true.")
 	nodeAddCmd.Flags().BoolVar(&deleteNodeOnFailure, "delete-on-failure", false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
+	nodeAddCmd.Flags().IntVar(&nodeCPUs, "cpus", 0, "Number of CPUs allocated to the added node, the ones of the cluster if not set.")
+	nodeAddCmd.Flags().StringVar(&nodeMemory, "memory", "", "Amount of RAM allocated to the added node (format: <number>[<unit>], where unit = b, k, m or g), the one of the cluster if not set.")
+	nodeAddCmd.Flags().StringVar(&nodeDiskSize, "disk-size", "", "Disk size allocated to the added node (format: <number>[<unit>], where unit = b, k, m or g), the one of the cluster if not set.")
+	nodeAddCmd.Flags().StringSliceVar(&nodeLabels, "labels", nil, "Kubernetes labels of the added node, as key=value.")
+	nodeAddCmd.Flags().StringSliceVar(&nodeTaints, "taints", nil, "Kubernetes taints of the added node, as key[=value]:effect, where effect = NoSchedule, PreferNoSchedule or NoExecute.")
+	nodeAddCmd.Flags().StringVar(&nodePool, "pool", "", "Node pool to add the node to. A new pool is created with the given resources, labels and taints, the nodes of an existing pool get its own.")
+	nodeAddCmd.Flags().IntVar(&nodeCount, "count", 1, "Number of nodes to add.")
+	nodeAddCmd.Flags().StringSliceVar(&nodeSSHIPAddresses, "ssh-ip-address", nil, "IP addresses of the hosts of the added nodes, one per node (ssh driver only)")
+	nodeAddCmd.Flags().StringVar(&nodeSSHUser, "ssh-user", "", "SSH user of the hosts of the added nodes, the one of the cluster if not set (ssh driver only)")
+	nodeAddCmd.Flags().StringVar(&nodeSSHKey, "ssh-key", "", "SSH key of the hosts of the added nodes, the one of the cluster if not set (ssh driver only)")
+	nodeAddCmd.Flags().StringVar(&nodeDriver, "driver", "", "Driver of the added nodes, the one of the cluster if not set. Nodes of another driver than the one of the cluster have to reach its nodes over the network.")
+	nodeAddCmd.Flags().StringVar(&nodeNetwork, "network", "", "Network of the added nodes, when they are of another driver than the one of the cluster.")
 
 	nodeCmd.AddCommand(nodeAddCmd)
 }
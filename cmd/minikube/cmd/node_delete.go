@@ -43,6 +43,9 @@ var nodeDeleteCmd = &cobra.Command{
 		name := args[0]
 
 		co := mustload.Healthy(ClusterFlagValue())
+		if n, _, err := node.Retrieve(*co.Config, name); err == nil && (n.Etcd || n.LoadBalancer) {
+			exit.Message(reason.Usage, "The etcd and load balancer nodes of a cluster cannot be deleted, the cluster depends on them")
+		}
 		out.Step(style.DeletingHost, "Deleting node {{.name}} from cluster {{.cluster}}", out.V{"name": name, "cluster": co.Config.Name})
 
 		n, err := node.Delete(*co.Config, name)
@@ -50,11 +53,11 @@ var nodeDeleteCmd = &cobra.Command{
 			exit.Error(reason.GuestNodeDelete, "deleting node", err)
 		}
 
-		if driver.IsKIC(co.Config.Driver) {
+		if drv := config.NodeDriver(*co.Config, *n); driver.IsKIC(drv) {
 			machineName := config.MachineName(*co.Config, *n)
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 			defer cancel()
-			delete.PossibleLeftOvers(ctx, machineName, co.Config.Driver)
+			delete.PossibleLeftOvers(ctx, machineName, drv)
 		}
 
 		out.Step(style.Deleted, "Node {{.name}} was successfully deleted.", out.V{"name": name})
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// nodeDemoteCmd represents the node demote command
+var nodeDemoteCmd = &cobra.Command{
+	Use:   "demote",
+	Short: "Demotes a control-plane node to a worker node.",
+	Long:  "Demotes a secondary control-plane node of an HA (multi-control plane) cluster to a worker node, by removing it from the cluster and joining it again as a worker node.",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node demote [name]")
+		}
+		name := args[0]
+
+		co := mustload.Healthy(ClusterFlagValue())
+		n, _, err := node.Retrieve(*co.Config, name)
+		if err != nil {
+			exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+		}
+		if !n.ControlPlane {
+			exit.Message(reason.Usage, "{{.name}} is not a control-plane node", out.V{"name": name})
+		}
+		if config.IsPrimaryControlPlane(*co.Config, *n) {
+			exit.Message(reason.Usage, "The primary control-plane node cannot be demoted")
+		}
+		if len(config.ControlPlanes(*co.Config)) <= 2 {
+			exit.Message(reason.Usage, "An HA (multi-control plane) cluster needs at least two control-plane nodes, promote a worker node before demoting {{.name}}", out.V{"name": name})
+		}
+
+		out.Step(style.ThumbsUp, "Demoting node {{.name}} to worker in cluster {{.cluster}}", out.V{"name": name, "cluster": co.Config.Name})
+		if err := node.SetControlPlane(co.Config, name, false); err != nil {
+			exit.Error(reason.GuestNodeRole, "demoting node", err)
+		}
+		out.Step(style.Happy, "Node {{.name}} is now a worker node.", out.V{"name": name})
+	},
+}
+
+func init() {
+	nodeCmd.AddCommand(nodeDemoteCmd)
+}
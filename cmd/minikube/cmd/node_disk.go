@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/docker/machine/libmachine"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+)
+
+var addDiskSize string
+
+// nodeDiskCmd represents the set of node disk subcommands
+var nodeDiskCmd = &cobra.Command{
+	Use:   "disk",
+	Short: "Adds, removes or lists the extra disks of a node.",
+	Long: `Adds, removes or lists the extra disks of a node, raw block devices besides the one of the node, at once if it runs.
+Currently only implemented for the docker and podman drivers on Linux, whose extra disks are loop devices of the host.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		exit.Message(reason.Usage, "Usage: minikube node disk [add|remove|list]")
+	},
+}
+
+// nodeDiskAddCmd represents the node disk add command
+var nodeDiskAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Adds an extra disk to a node.",
+	Long:  "Adds an extra disk to a node, of the disk size of the node unless --size is given.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube node disk add [node] [--size=<size>]")
+		}
+		api, cc, n := loadDiskNode(args[0])
+		size := config.NodeResources(*cc, *n).DiskSize
+		if cmd.Flags().Changed("size") {
+			var err error
+			if size, err = util.CalculateSizeInMB(addDiskSize); err != nil {
+				exit.Message(reason.Usage, "Invalid --size {{.size}}: {{.error}}", out.V{"size": addDiskSize, "error": err})
+			}
+		}
+		dev, err := machine.AddDisk(api, config.MachineName(*cc, *n), size)
+		if err != nil {
+			exit.Error(reason.GuestNodeDisk, "adding disk", err)
+		}
+		out.Step(style.Happy, "Added a {{.size}}MB disk to node {{.name}} as {{.device}}", out.V{"size": size, "name": args[0], "device": dev})
+	},
+}
+
+// nodeDiskRemoveCmd represents the node disk remove command
+var nodeDiskRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Removes an extra disk from a node.",
+	Long:  "Removes an extra disk from a node and deletes it, with all the data on it.",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.Message(reason.Usage, "Usage: minikube node disk remove [node] [device]")
+		}
+		api, cc, n := loadDiskNode(args[0])
+		if err := machine.RemoveDisk(api, config.MachineName(*cc, *n), args[1]); err != nil {
+			exit.Error(reason.GuestNodeDisk, "removing disk", err)
+		}
+		out.Step(style.Deleted, "Removed disk {{.device}} from node {{.name}}", out.V{"device": args[1], "name": args[0]})
+	},
+}
+
+// nodeDiskListCmd represents the node disk list command
+var nodeDiskListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the extra disks of a node.",
+	Long:  "Lists the devices of the extra disks of a node, in the order they were added.",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube node disk list [node]")
+		}
+		api, cc, n := loadDiskNode(args[0])
+		devices, err := machine.Disks(api, config.MachineName(*cc, *n))
+		if err != nil {
+			exit.Error(reason.GuestNodeDisk, "listing disks", err)
+		}
+		for _, dev := range devices {
+			out.Ln("%s", dev)
+		}
+	},
+}
+
+// loadDiskNode returns a node of the current cluster, exiting if it does not exist
+func loadDiskNode(name string) (libmachine.API, *config.ClusterConfig, *config.Node) {
+	api, cc := mustload.Partial(ClusterFlagValue())
+	n, _, err := node.Retrieve(*cc, name)
+	if err != nil {
+		exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+	}
+	return api, cc, n
+}
+
+func init() {
+	nodeDiskAddCmd.Flags().StringVar(&addDiskSize, "size", "", "Size of the disk (format: <number>[<unit>], where unit = b, k, m or g), the disk size of the node if not set.")
+	nodeDiskCmd.AddCommand(nodeDiskAddCmd)
+	nodeDiskCmd.AddCommand(nodeDiskRemoveCmd)
+	nodeDiskCmd.AddCommand(nodeDiskListCmd)
+	nodeCmd.AddCommand(nodeDiskCmd)
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	failoverRestart bool
+	failoverTimeout time.Duration
+)
+
+// failoverProbeInterval is how often the apiserver is probed while its leader is down
+const failoverProbeInterval = 500 * time.Millisecond
+
+// nodeFailoverTestCmd represents the node failover-test command
+var nodeFailoverTestCmd = &cobra.Command{
+	Use:   "failover-test",
+	Short: "Stops the leader control-plane node and measures how long the apiserver is unavailable.",
+	Long: `Stops the leader control-plane node of an HA (multi-control plane) cluster, the one holding the virtual IP with kube-vip or the kube-controller-manager lease with haproxy,
+then measures how long the apiserver is unreachable through the control-plane endpoint, from another node, and reports the new leader.`,
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube node failover-test [flags]")
+		}
+		co := mustload.Healthy(ClusterFlagValue())
+		cc := co.Config
+		if !config.IsHA(*cc) {
+			exit.Message(reason.Usage, "Failover can only be tested on an HA (multi-control plane) cluster, use 'minikube start --ha' to create one")
+		}
+
+		leader, err := controlPlaneLeader(*cc)
+		if err != nil {
+			exit.Error(reason.GuestNodeFailover, "Unable to find the leader control-plane node", err)
+		}
+		// the apiserver is probed from another Kubernetes node, which resolves the control-plane endpoint
+		var leaderNode, prober *config.Node
+		for _, n := range cc.Nodes {
+			n := n
+			switch {
+			case config.MachineName(*cc, n) == leader:
+				leaderNode = &n
+			case prober == nil && !n.Etcd && !n.LoadBalancer:
+				prober = &n
+			}
+		}
+		if leaderNode == nil {
+			exit.Message(reason.GuestNodeFailover, "The leader {{.name}} is not a node of cluster {{.cluster}}", out.V{"name": leader, "cluster": cc.Name})
+		}
+		r := nodeRunner(co, config.MachineName(*cc, *prober))
+
+		out.Step(style.Shutdown, "Stopping the leader control-plane node {{.name}} ...", out.V{"name": leader})
+		stopped := make(chan error, 1)
+		go func() {
+			stopped <- machine.StopHost(co.API, leader)
+		}()
+
+		var p failoverProbe
+		deadline := time.Now().Add(failoverTimeout)
+		newLeader := ""
+		for {
+			p.observe(time.Now(), probeAPIServer(r, cc.APIServerPort))
+			if stopped != nil {
+				select {
+				case err := <-stopped:
+					if err != nil {
+						exit.Error(reason.GuestStopTimeout, "Unable to stop the leader control-plane node", err)
+					}
+					stopped = nil
+				default:
+				}
+			}
+			if stopped == nil && p.recovered() {
+				if l, err := controlPlaneLeader(*cc); err == nil && l != leader {
+					newLeader = l
+					break
+				}
+			}
+			if time.Now().After(deadline) {
+				exit.Message(reason.GuestNodeFailover, "The apiserver did not fail over within {{.timeout}}: {{.status}}", out.V{"timeout": failoverTimeout, "status": p})
+			}
+			time.Sleep(failoverProbeInterval)
+		}
+		out.Step(style.Check, "{{.status}}", out.V{"status": p})
+		out.Step(style.Celebrate, "{{.name}} is the new leader control-plane node", out.V{"name": newLeader})
+
+		if failoverRestart {
+			out.Ln("")
+			r, pe, m, h, err := node.Provision(cc, leaderNode, false)
+			if err != nil {
+				exit.Error(reason.GuestNodeProvision, "provisioning host for node", err)
+			}
+			s := node.Starter{Runner: r, PreExists: pe, MachineAPI: m, Host: h, Cfg: cc, Node: leaderNode, ExistingAddons: cc.Addons}
+			if _, err := node.Start(s); err != nil {
+				exit.Error(reason.GuestNodeStart, "failed to start node", err)
+			}
+			out.Step(style.Happy, "Successfully restarted node {{.name}}!", out.V{"name": leader})
+		}
+	},
+}
+
+// failoverProbe records the outcomes of probing the apiserver while its leader is down
+type failoverProbe struct {
+	probes    int
+	failures  int
+	firstFail time.Time
+	recovery  time.Time
+}
+
+// observe records the outcome of a probe at the given time
+func (p *failoverProbe) observe(t time.Time, ok bool) {
+	p.probes++
+	if !ok {
+		p.failures++
+		if p.firstFail.IsZero() {
+			p.firstFail = t
+		}
+		return
+	}
+	if !p.firstFail.IsZero() && p.recovery.IsZero() {
+		p.recovery = t
+	}
+}
+
+// recovered returns if the last outage is over, or there was none
+func (p failoverProbe) recovered() bool {
+	return p.probes > 0 && (p.firstFail.IsZero() || !p.recovery.IsZero())
+}
+
+// downtime returns how long the apiserver was unreachable, from the first failed probe to the first successful one after it
+func (p failoverProbe) downtime() time.Duration {
+	if p.firstFail.IsZero() || p.recovery.IsZero() {
+		return 0
+	}
+	return p.recovery.Sub(p.firstFail)
+}
+
+// String summarizes the probes
+func (p failoverProbe) String() string {
+	switch {
+	case p.firstFail.IsZero():
+		return fmt.Sprintf("the apiserver stayed reachable during failover (%d probes)", p.probes)
+	case p.recovery.IsZero():
+		return fmt.Sprintf("the apiserver is unreachable since %s (%d of %d probes failed)", p.firstFail.Format(time.RFC3339), p.failures, p.probes)
+	default:
+		return fmt.Sprintf("the apiserver was unreachable for %s (%d of %d probes failed)", p.downtime().Round(time.Millisecond), p.failures, p.probes)
+	}
+}
+
+// probeAPIServer returns if the apiserver is ready through the control-plane endpoint of the cluster, as seen from a node
+func probeAPIServer(r command.Runner, port int) bool {
+	url := fmt.Sprintf("https://%s:%d/readyz", constants.ControlPlaneAlias, port)
+	_, err := r.RunCmd(exec.Command("curl", "-sfk", "--max-time", "1", url))
+	if err != nil {
+		klog.V(2).Infof("probing %s: %v", url, err)
+	}
+	return err == nil
+}
+
+// controlPlaneLeader returns the machine name of the leader control-plane node: the holder of the kube-vip lease, or of the kube-controller-manager one with haproxy
+func controlPlaneLeader(cc config.ClusterConfig) (string, error) {
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return "", errors.Wrap(err, "client")
+	}
+	lease := "plndr-cp-lock"
+	if config.UsesHAProxy(cc) {
+		lease = "kube-controller-manager"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	l, err := client.CoordinationV1().Leases(meta.NamespaceSystem).Get(ctx, lease, meta.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "lease %s", lease)
+	}
+	if l.Spec.HolderIdentity == nil || *l.Spec.HolderIdentity == "" {
+		return "", fmt.Errorf("lease %s has no holder", lease)
+	}
+	// kube-controller-manager holds its lease as <node>_<uuid>
+	holder, _, _ := strings.Cut(*l.Spec.HolderIdentity, "_")
+	return holder, nil
+}
+
+func init() {
+	nodeFailoverTestCmd.Flags().BoolVar(&failoverRestart, "restart", true, "Start the stopped leader control-plane node again once the cluster failed over")
+	nodeFailoverTestCmd.Flags().DurationVar(&failoverTimeout, "timeout", 3*time.Minute, "Max time to wait for the cluster to fail over")
+	nodeCmd.AddCommand(nodeFailoverTestCmd)
+}
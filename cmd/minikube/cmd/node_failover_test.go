@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFailoverProbe(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	var p failoverProbe
+	if p.recovered() {
+		t.Errorf("expected no probes not to count as recovered")
+	}
+	p.observe(at(0), true)
+	if !p.recovered() || p.downtime() != 0 {
+		t.Errorf("expected no outage, got %s", p)
+	}
+	if got, want := p.String(), "the apiserver stayed reachable during failover (1 probes)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	p.observe(at(500), false)
+	p.observe(at(1000), false)
+	if p.recovered() {
+		t.Errorf("expected an ongoing outage, got %s", p)
+	}
+	p.observe(at(3500), true)
+	p.observe(at(4000), false)
+	p.observe(at(4500), true)
+	if !p.recovered() {
+		t.Errorf("expected the outage to be over, got %s", p)
+	}
+	if got, want := p.downtime(), 3*time.Second; got != want {
+		t.Errorf("downtime() = %v, want %v", got, want)
+	}
+	if got, want := p.String(), "the apiserver was unreachable for 3s (3 of 6 probes failed)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
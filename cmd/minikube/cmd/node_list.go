@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
@@ -31,7 +32,7 @@ import (
 var nodeListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List nodes.",
-	Long:  "List existing minikube nodes.",
+	Long:  "List existing minikube nodes, with their node pool if the cluster has node pools, and their driver if not all of them are of the same driver.",
 	Run: func(_ *cobra.Command, args []string) {
 		if len(args) != 0 {
 			exit.Message(reason.Usage, "Usage: minikube node list")
@@ -47,8 +48,20 @@ var nodeListCmd = &cobra.Command{
 		}
 
 		for _, n := range cc.Nodes {
-			machineName := config.MachineName(*cc, n)
-			fmt.Printf("%s\t%s\n", machineName, n.IP)
+			fields := []string{config.MachineName(*cc, n), n.IP}
+			// the node pools are only listed for the clusters which have some
+			if len(cc.NodePools) != 0 {
+				pool := n.Pool
+				if pool == "" {
+					pool = "-"
+				}
+				fields = append(fields, pool)
+			}
+			// and so are the drivers of the nodes for the clusters whose nodes are not all of the same driver
+			if config.IsHybrid(*cc) {
+				fields = append(fields, config.NodeDriver(*cc, n))
+			}
+			fmt.Println(strings.Join(fields, "\t"))
 		}
 		os.Exit(0)
 	},
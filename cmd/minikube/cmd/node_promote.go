@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// nodePromoteCmd represents the node promote command
+var nodePromoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Promotes a worker node to a control-plane node.",
+	Long:  "Promotes a worker node of an HA (multi-control plane) cluster to a control-plane node, by removing it from the cluster and joining it again as a control-plane node.",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node promote [name]")
+		}
+		name := args[0]
+
+		co := mustload.Healthy(ClusterFlagValue())
+		n, _, err := node.Retrieve(*co.Config, name)
+		if err != nil {
+			exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+		}
+		if !config.IsHA(*co.Config) {
+			exit.Message(reason.Usage, "Only the nodes of an HA (multi-control plane) cluster can be promoted, use 'minikube start --ha' to create one")
+		}
+		if n.ControlPlane || n.Etcd || n.LoadBalancer {
+			exit.Message(reason.Usage, "{{.name}} is not a worker node", out.V{"name": name})
+		}
+
+		out.Step(style.ThumbsUp, "Promoting node {{.name}} to control-plane in cluster {{.cluster}}", out.V{"name": name, "cluster": co.Config.Name})
+		if err := node.SetControlPlane(co.Config, name, true); err != nil {
+			exit.Error(reason.GuestNodeRole, "promoting node", err)
+		}
+		out.Step(style.Happy, "Node {{.name}} is now a control-plane node.", out.V{"name": name})
+	},
+}
+
+func init() {
+	nodeCmd.AddCommand(nodePromoteCmd)
+}
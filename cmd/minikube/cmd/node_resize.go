@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+)
+
+var (
+	resizeDiskSize string
+	resizeMemory   string
+)
+
+// nodeResizeCmd represents the node resize command
+var nodeResizeCmd = &cobra.Command{
+	Use:   "resize",
+	Short: "Resizes the disk or the memory of a node.",
+	Long: `Resizes the disk or the memory of a node, without recreating it. Disks can only grow, and their filesystem grows along,
+at once if the node is running or at its next start otherwise. Memory can only shrink below the one the node was created with, and grow back up to it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node resize [name] [--disk-size=<size>] [--memory=<size>]")
+		}
+		if !cmd.Flags().Changed("disk-size") && !cmd.Flags().Changed("memory") {
+			exit.Message(reason.Usage, "Nothing to resize, pass --disk-size or --memory")
+		}
+		name := args[0]
+		api, cc := mustload.Partial(ClusterFlagValue())
+
+		n, _, err := node.Retrieve(*cc, name)
+		if err != nil {
+			exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+		}
+		machineName := config.MachineName(*cc, *n)
+		current := config.NodeResources(*cc, *n)
+
+		if cmd.Flags().Changed("disk-size") {
+			disk, err := util.CalculateSizeInMB(resizeDiskSize)
+			if err != nil {
+				exit.Message(reason.Usage, "Invalid --disk-size {{.size}}: {{.error}}", out.V{"size": resizeDiskSize, "error": err})
+			}
+			if disk < current.DiskSize {
+				exit.Message(reason.Usage, "Disks can only grow, the one of {{.name}} is {{.size}}MB", out.V{"name": name, "size": current.DiskSize})
+			}
+			out.Step(style.Provisioning, "Resizing the disk of {{.name}} to {{.size}}MB ...", out.V{"name": name, "size": disk})
+			if err := machine.ResizeDisk(api, machineName, disk); err != nil {
+				exit.Error(reason.GuestNodeResize, "resizing disk", err)
+			}
+			n.DiskSize = disk
+		}
+		if cmd.Flags().Changed("memory") {
+			mem, err := util.CalculateSizeInMB(resizeMemory)
+			if err != nil {
+				exit.Message(reason.Usage, "Invalid --memory {{.size}}: {{.error}}", out.V{"size": resizeMemory, "error": err})
+			}
+			out.Step(style.Provisioning, "Setting the memory of {{.name}} to {{.size}}MB ...", out.V{"name": name, "size": mem})
+			if err := machine.SetMemory(api, machineName, mem); err != nil {
+				exit.Error(reason.GuestNodeResize, "setting memory", err)
+			}
+			n.Memory = mem
+		}
+
+		if err := config.SaveNode(cc, n); err != nil {
+			exit.Error(reason.HostSaveProfile, "failed to save node", err)
+		}
+		out.Step(style.Happy, "Successfully resized node {{.name}}", out.V{"name": name})
+	},
+}
+
+func init() {
+	nodeResizeCmd.Flags().StringVar(&resizeDiskSize, "disk-size", "", "Disk size of the node (format: <number>[<unit>], where unit = b, k, m or g), which can only grow.")
+	nodeResizeCmd.Flags().StringVar(&resizeMemory, "memory", "", "Amount of RAM of the node (format: <number>[<unit>], where unit = b, k, m or g), up to the one it was created with.")
+	nodeCmd.AddCommand(nodeResizeCmd)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// nodeSnapshotCmd represents the set of node snapshot subcommands
+var nodeSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Saves, restores, deletes or lists the snapshots of a node.",
+	Long:  "Saves, restores, deletes or lists the snapshots of a node. A snapshot of a running node holds its memory as well as its disk.",
+	Run: func(_ *cobra.Command, _ []string) {
+		exit.Message(reason.Usage, "Usage: minikube node snapshot [save|restore|delete|list]")
+	},
+}
+
+// nodeSnapshotSaveCmd represents the node snapshot save command
+var nodeSnapshotSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Saves a snapshot of a node.",
+	Long:  "Saves a snapshot of a node, replacing the one of the same name if any.",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.Message(reason.Usage, "Usage: minikube node snapshot save [node] [snapshot]")
+		}
+		s := loadSnapshotter(args[0])
+		if err := s.Snapshot(args[1]); err != nil {
+			exit.Error(reason.GuestNodeSnapshot, "saving snapshot", err)
+		}
+		out.Step(style.Happy, "Saved snapshot {{.snapshot}} of node {{.name}}", out.V{"snapshot": args[1], "name": args[0]})
+	},
+}
+
+// nodeSnapshotRestoreCmd represents the node snapshot restore command
+var nodeSnapshotRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restores a node to a snapshot.",
+	Long:  "Restores a node to a snapshot, discarding every change made since.",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.Message(reason.Usage, "Usage: minikube node snapshot restore [node] [snapshot]")
+		}
+		s := loadSnapshotter(args[0])
+		if err := s.RestoreSnapshot(args[1]); err != nil {
+			exit.Error(reason.GuestNodeSnapshot, "restoring snapshot", err)
+		}
+		out.Step(style.Restarting, "Restored node {{.name}} to snapshot {{.snapshot}}", out.V{"snapshot": args[1], "name": args[0]})
+	},
+}
+
+// nodeSnapshotDeleteCmd represents the node snapshot delete command
+var nodeSnapshotDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Deletes a snapshot of a node.",
+	Long:  "Deletes a snapshot of a node.",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.Message(reason.Usage, "Usage: minikube node snapshot delete [node] [snapshot]")
+		}
+		s := loadSnapshotter(args[0])
+		if err := s.DeleteSnapshot(args[1]); err != nil {
+			exit.Error(reason.GuestNodeSnapshot, "deleting snapshot", err)
+		}
+		out.Step(style.Deleted, "Deleted snapshot {{.snapshot}} of node {{.name}}", out.V{"snapshot": args[1], "name": args[0]})
+	},
+}
+
+// nodeSnapshotListCmd represents the node snapshot list command
+var nodeSnapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the snapshots of a node.",
+	Long:  "Lists the snapshots of a node.",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube node snapshot list [node]")
+		}
+		s := loadSnapshotter(args[0])
+		names, err := s.Snapshots()
+		if err != nil {
+			exit.Error(reason.GuestNodeSnapshot, "listing snapshots", err)
+		}
+		for _, name := range names {
+			out.Ln("%s", name)
+		}
+	},
+}
+
+// loadSnapshotter returns the driver of a node of the current cluster, exiting if it can not snapshot it
+func loadSnapshotter(name string) machine.Snapshotter {
+	api, cc := mustload.Partial(ClusterFlagValue())
+	n, _, err := node.Retrieve(*cc, name)
+	if err != nil {
+		exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+	}
+	s, err := machine.LoadSnapshotter(api, config.MachineName(*cc, *n))
+	if err != nil {
+		exit.Error(reason.GuestNodeSnapshot, "loading node", err)
+	}
+	return s
+}
+
+func init() {
+	nodeSnapshotCmd.AddCommand(nodeSnapshotSaveCmd)
+	nodeSnapshotCmd.AddCommand(nodeSnapshotRestoreCmd)
+	nodeSnapshotCmd.AddCommand(nodeSnapshotDeleteCmd)
+	nodeSnapshotCmd.AddCommand(nodeSnapshotListCmd)
+	nodeCmd.AddCommand(nodeSnapshotCmd)
+}
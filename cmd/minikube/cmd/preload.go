@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	preloadbuilder "k8s.io/minikube/pkg/minikube/preload"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+)
+
+var (
+	preloadK8sVersion       string
+	preloadContainerRuntime string
+	preloadExtraImages      []string
+	preloadImageRepository  string
+	preloadOutputDir        string
+)
+
+// preloadCmd represents the preload command
+var preloadCmd = &cobra.Command{
+	Use:   "preload",
+	Short: "Manage preloaded images tarballs",
+	Long:  "Manage the tarballs of preloaded images and binaries that minikube uses to speed up cluster start.",
+}
+
+// preloadBuildCmd represents the preload build command
+var preloadBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build a preloaded images tarball",
+	Long:  "Build a preloaded images tarball in a temporary docker container. By default the tarball is written to the minikube cache, where 'minikube start' will use it.",
+	Example: `minikube preload build --kubernetes-version=v1.30.0 --container-runtime=containerd
+minikube preload build --extra-images=docker.io/library/nginx:latest --output-dir=./out`,
+	Run: func(_ *cobra.Command, _ []string) {
+		if _, err := util.ParseKubernetesVersion(preloadK8sVersion); err != nil {
+			exit.Message(reason.Usage, "Invalid Kubernetes version {{.version}}: {{.err}}", out.V{"version": preloadK8sVersion, "err": err})
+		}
+		// the tarball name has no room for the repository, and 'minikube start' never uses a preload with another one
+		if preloadImageRepository != "" && preloadOutputDir == "" {
+			exit.Message(reason.Usage, "--image-repository requires --output-dir, as a preload of another image repository can not replace the one in the minikube cache")
+		}
+		if preloadOutputDir == "" {
+			preloadOutputDir = filepath.Dir(download.TarballPath(preloadK8sVersion, preloadContainerRuntime))
+		}
+
+		out.Step(style.Caching, "Building preload for Kubernetes {{.version}} and {{.runtime}} ...", out.V{"version": preloadK8sVersion, "runtime": preloadContainerRuntime})
+		path, err := preloadbuilder.Build(preloadbuilder.BuildConfig{
+			KubernetesVersion: preloadK8sVersion,
+			ContainerRuntime:  preloadContainerRuntime,
+			ExtraImages:       preloadExtraImages,
+			ImageRepository:   preloadImageRepository,
+			ContainerName:     "minikube-preload-build",
+			OutputDir:         preloadOutputDir,
+		})
+		if err != nil {
+			exit.Error(reason.InternalPreloadBuild, "Failed to build preload", err)
+		}
+		out.Step(style.Check, "Preload written to {{.path}}", out.V{"path": path})
+	},
+}
+
+func init() {
+	preloadBuildCmd.Flags().StringVar(&preloadK8sVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The Kubernetes version to build the preload for")
+	preloadBuildCmd.Flags().StringVar(&preloadContainerRuntime, "container-runtime", constants.Docker, "The container runtime to build the preload for. Options include: [docker, containerd, cri-o]")
+	preloadBuildCmd.Flags().StringSliceVar(&preloadExtraImages, "extra-images", []string{}, "Additional images to include in the preload")
+	preloadBuildCmd.Flags().StringVar(&preloadImageRepository, "image-repository", "", "Alternative image repository to pull the Kubernetes images from, which requires --output-dir")
+	preloadBuildCmd.Flags().StringVar(&preloadOutputDir, "output-dir", "", "Directory to write the tarball and its checksum file to (defaults to the minikube preload cache)")
+	preloadCmd.AddCommand(preloadBuildCmd)
+}
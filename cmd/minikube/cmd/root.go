@@ -35,6 +35,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/detect"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/notify"
@@ -85,6 +86,15 @@ var RootCmd = &cobra.Command{
 		if viper.GetBool(config.Rootless) {
 			os.Setenv(constants.MinikubeRootlessEnv, "true")
 		}
+		if mirror := viper.GetString(config.ArtifactMirror); mirror != "" {
+			s, err := download.LoadArtifactSource(mirror)
+			if err == nil {
+				err = download.SetArtifactSource(s)
+			}
+			if err != nil {
+				exit.Error(reason.HostArtifactMirror, "Failed to load the artifact mirror", err)
+			}
+		}
 	},
 	PersistentPostRun: func(_ *cobra.Command, _ []string) {
 		if err := audit.LogCommandEnd(auditID); err != nil {
@@ -248,6 +258,7 @@ func init() {
 				podmanEnvCmd,
 				cacheCmd,
 				imageCmd,
+				preloadCmd,
 			},
 		},
 		{
@@ -257,6 +268,7 @@ func init() {
 				configCmd.ConfigCmd,
 				configCmd.ProfileCmd,
 				updateContextCmd,
+				configureCmd,
 			},
 		},
 		{
@@ -274,6 +286,9 @@ func init() {
 				kubectlCmd,
 				nodeCmd,
 				cpCmd,
+				upgradeCmd,
+				certsCmd,
+				etcdCmd,
 			},
 		},
 		{
@@ -285,6 +300,7 @@ func init() {
 				logsCmd,
 				updateCheckCmd,
 				versionCmd,
+				driversCmd,
 				optionsCmd,
 			},
 		},
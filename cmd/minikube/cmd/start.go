@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -47,6 +48,7 @@ import (
 	"github.com/spf13/viper"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/firewall"
 	netutil "k8s.io/minikube/pkg/network"
@@ -54,7 +56,11 @@ import (
 	"k8s.io/klog/v2"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/drivers/qemu"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -163,8 +169,12 @@ func runStart(cmd *cobra.Command, _ []string) {
 	displayVersion(version.GetVersion())
 	go download.CleanUpOlderPreloads()
 
-	// Avoid blocking execution on optional HTTP fetches
-	go notify.MaybePrintUpdateTextFromGithub()
+	if viper.GetBool(offline) {
+		download.SetOffline(true)
+	} else {
+		// Avoid blocking execution on optional HTTP fetches
+		go notify.MaybePrintUpdateTextFromGithub()
+	}
 
 	displayEnviron(os.Environ())
 	if viper.GetBool(force) {
@@ -287,6 +297,12 @@ func runStart(cmd *cobra.Command, _ []string) {
 		exit.Error(reason.GuestStart, "failed to start node", err)
 	}
 
+	if len(starter.Cfg.WaitFor) > 0 {
+		if err := waitForResources(starter.Cfg); err != nil {
+			exit.Message(reason.KubernetesWaitTimeout, "{{.err}}", out.V{"err": err})
+		}
+	}
+
 	if err := showKubectlInfo(kubeconfig, starter.Node.KubernetesVersion, starter.Node.ContainerRuntime, starter.Cfg.Name); err != nil {
 		klog.Errorf("kubectl info: %v", err)
 	}
@@ -308,6 +324,10 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 		validateDockerStorageDriver(driverName)
 	}
 
+	if viper.GetString(preloadBaseURL) != "" {
+		download.SetPreloadBaseURL(viper.GetString(preloadBaseURL))
+	}
+
 	k8sVersion, err := getKubernetesVersion(existing)
 	if err != nil {
 		klog.Warningf("failed getting Kubernetes version: %v", err)
@@ -322,7 +342,7 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 	}
 
 	// Download & update the driver, even in --download-only mode
-	if !viper.GetBool(dryRun) {
+	if !viper.GetBool(dryRun) && !viper.GetBool(offline) {
 		updateDriver(driverName)
 	}
 
@@ -345,7 +365,11 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 		}
 	}
 
-	if driver.IsVM(cc.Driver) && runtime.GOARCH == "arm64" && cc.KubernetesConfig.ContainerRuntime == "crio" {
+	if driver.IsQEMU(cc.Driver) && qemu.IsEmulated(qemu.Accel(cc.QemuAccel, config.GuestArch(cc))) {
+		out.WarningT("The {{.arch}} guest is emulated in software with TCG, expect it to be several times slower than with hardware acceleration", out.V{"arch": config.GuestArch(cc)})
+	}
+
+	if driver.IsVM(cc.Driver) && config.GuestArch(cc) == "arm64" && cc.KubernetesConfig.ContainerRuntime == "crio" {
 		exit.Message(reason.Unimplemented, "arm64 VM drivers do not currently support the crio container runtime. See https://github.com/kubernetes/minikube/issues/14146 for details.")
 	}
 
@@ -355,12 +379,10 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 		os.Exit(0)
 	}
 
-	if driver.IsVM(driverName) && !driver.IsSSH(driverName) {
-		url, err := download.ISO(viper.GetStringSlice(isoURL), cmd.Flags().Changed(isoURL))
-		if err != nil {
-			return node.Starter{}, errors.Wrap(err, "Failed to cache ISO")
+	if viper.GetString(preloadPath) != "" {
+		if err := download.ImportPreload(viper.GetString(preloadPath), cc.KubernetesConfig.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime); err != nil {
+			exit.Error(reason.HostPreloadImport, "Failed to import preload", err)
 		}
-		cc.MinikubeISO = url
 	}
 
 	var existingAddons map[string]bool
@@ -371,6 +393,22 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 		}
 	}
 
+	if viper.GetBool(offline) {
+		validateOfflineArtifacts(&cc, existingAddons)
+	}
+
+	if driver.IsVM(driverName) && !driver.IsSSH(driverName) {
+		isoURLs := viper.GetStringSlice(isoURL)
+		if !cmd.Flags().Changed(isoURL) && config.GuestArch(cc) != runtime.GOARCH {
+			isoURLs = download.DefaultISOURLsForArch(config.GuestArch(cc))
+		}
+		url, err := download.ISO(isoURLs, cmd.Flags().Changed(isoURL))
+		if err != nil {
+			return node.Starter{}, errors.Wrap(err, "Failed to cache ISO")
+		}
+		cc.MinikubeISO = url
+	}
+
 	if viper.GetBool(nativeSSH) {
 		ssh.SetDefaultClient(ssh.Native)
 	} else {
@@ -394,6 +432,31 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 	}, nil
 }
 
+// validateOfflineArtifacts exits with the list of missing artifacts unless everything needed to start cc is on the host
+func validateOfflineArtifacts(cc *config.ClusterConfig, existingAddons map[string]bool) {
+	arts, err := node.OfflineArtifacts(cc, existingAddons)
+	if err != nil {
+		exit.Error(reason.HostOfflineArtifacts, "Failed to determine the artifacts required to start offline", err)
+	}
+	var missing []node.Artifact
+	for _, a := range node.MissingArtifacts(arts) {
+		// the docker driver uses the base image from the docker daemon if it is there
+		if a.Kind == node.ArtifactKicBase && driver.IsDocker(cc.Driver) && download.ImageExistsInDaemon(a.Name) {
+			continue
+		}
+		missing = append(missing, a)
+	}
+	if len(missing) == 0 {
+		klog.Infof("all %d artifacts required to start offline are cached", len(arts))
+		return
+	}
+	out.ErrT(style.Failure, "The following artifacts required to start offline are missing:")
+	for _, a := range missing {
+		out.ErrT(style.Option, "{{.kind}} {{.name}} ({{.path}})", out.V{"kind": a.Kind, "name": a.Name, "path": a.Path})
+	}
+	exit.Message(reason.HostOfflineArtifacts, "Run 'minikube cache bundle' with the same flags on a machine with network access, and extract the bundle into {{.home}}", out.V{"home": localpath.MiniPath()})
+}
+
 func virtualBoxMacOS13PlusWarning(driverName string) {
 	if !driver.IsVirtualBox(driverName) || !detect.MacOS13Plus() {
 		return
@@ -454,6 +517,11 @@ func imageMatchesBinaryVersion(imageVersion, binaryVersion string) bool {
 }
 
 func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.ClusterConfig) (*kubeconfig.Settings, error) {
+	// start the dedicated etcd and load balancer nodes the primary control-plane node depends on
+	if err := startTopologyNodes(starter, existing); err != nil {
+		return nil, err
+	}
+
 	// start primary control-plane node
 	kubeconfig, err := node.Start(starter)
 	if err != nil {
@@ -483,8 +551,12 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 		var n config.Node
 		if existing != nil {
 			n = existing.Nodes[i]
+			if n.Etcd || n.LoadBalancer {
+				continue
+			}
 		} else {
-			nodeName := node.Name(i + 1)
+			// nodes are named after the etcd and load balancer nodes already added
+			nodeName := node.Name(len(starter.Cfg.Nodes) + 1)
 			n = config.Node{
 				Name:              nodeName,
 				Port:              starter.Cfg.APIServerPort,
@@ -508,6 +580,65 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 	return kubeconfig, nil
 }
 
+// startTopologyNodes adds the dedicated etcd nodes of --etcd-topology=external and the load balancer node of --ha-load-balancer=haproxy,
+// or starts the ones of an existing cluster
+func startTopologyNodes(starter node.Starter, existing *config.ClusterConfig) error {
+	infra := []config.Node{}
+	if existing != nil {
+		for _, n := range existing.Nodes {
+			if n.Etcd || n.LoadBalancer {
+				infra = append(infra, n)
+			}
+		}
+	} else {
+		newNode := func() config.Node {
+			return config.Node{
+				Name:              node.Name(len(starter.Cfg.Nodes) + len(infra) + 1),
+				KubernetesVersion: starter.Cfg.KubernetesConfig.KubernetesVersion,
+				ContainerRuntime:  starter.Cfg.KubernetesConfig.ContainerRuntime,
+			}
+		}
+		if config.IsExternalEtcd(*starter.Cfg) {
+			for i := 0; i < viper.GetInt(etcdNodes); i++ {
+				n := newNode()
+				n.Etcd = true
+				infra = append(infra, n)
+			}
+		}
+		if config.UsesHAProxy(*starter.Cfg) {
+			n := newNode()
+			n.LoadBalancer = true
+			infra = append(infra, n)
+		}
+	}
+
+	for _, n := range infra {
+		out.Ln("") // extra newline for clarity on the command line
+		if err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure)); err != nil {
+			return errors.Wrap(err, "adding node")
+		}
+	}
+	return nil
+}
+
+// waitForResources waits for the resource conditions given with --wait-for, once all the nodes are up and the addons enabled
+func waitForResources(cc *config.ClusterConfig) error {
+	gates, err := kverify.ParseResourceGates(cc.WaitFor)
+	if err != nil {
+		return err
+	}
+	out.Step(style.Waiting, "Waiting for resources: {{.resources}} ...", out.V{"resources": strings.Join(cc.WaitFor, ", ")})
+	rc, err := kapi.ClientConfig(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "client config")
+	}
+	dc, err := dynamic.NewForConfig(rc)
+	if err != nil {
+		return errors.Wrap(err, "dynamic client")
+	}
+	return kverify.WaitForResourceGates(dc, gates, viper.GetDuration(waitTimeout))
+}
+
 func warnAboutMultiNodeCNI() {
 	out.WarningT("Cluster was created without any CNI, adding a node to it might cause broken networking.")
 }
@@ -1280,6 +1411,24 @@ func validateFlags(cmd *cobra.Command, drvName string) { //nolint:gocyclo
 		viper.Set(imageRepository, validateImageRepository(viper.GetString(imageRepository)))
 	}
 
+	if viper.GetBool(offline) {
+		if viper.GetBool(downloadOnly) {
+			exit.Message(reason.Usage, "Cannot use both --offline and --download-only")
+		}
+		if viper.GetString(imageMirrorCountry) != "" {
+			exit.Message(reason.Usage, "Cannot use --image-mirror-country with --offline, as finding the closest mirror requires network access")
+		}
+	}
+
+	if cmd.Flags().Changed(preloadPath) {
+		if !driver.AllowsPreload(drvName) {
+			exit.Message(reason.Usage, "The '{{.name}}' driver does not support preloaded images", out.V{"name": drvName})
+		}
+		if _, err := os.Stat(viper.GetString(preloadPath)); err != nil {
+			exit.Message(reason.Usage, "Invalid --preload-path: {{.err}}", out.V{"err": err})
+		}
+	}
+
 	if cmd.Flags().Changed(ports) {
 		err := validatePorts(viper.GetStringSlice(ports))
 		if err != nil {
@@ -1321,18 +1470,84 @@ func validateFlags(cmd *cobra.Command, drvName string) { //nolint:gocyclo
 		}
 	}
 
+	if cmd.Flags().Changed(imageGCThreshold) {
+		if err := validateImageGCThreshold(viper.GetInt(imageGCThreshold)); err != nil {
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+		}
+	}
+
+	if cmd.Flags().Changed(caCert) || cmd.Flags().Changed(caKey) {
+		if err := validateCustomCA(viper.GetString(caCert), viper.GetString(caKey)); err != nil {
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+		}
+	}
+
+	if cmd.Flags().Changed(oidcIssuerURL) {
+		if err := validateOIDC(getOIDCConfig()); err != nil {
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+		}
+	}
+
+	if cmd.Flags().Changed(oidcCAFile) {
+		if err := validateOIDCCAFile(viper.GetString(oidcCAFile)); err != nil {
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+		}
+	}
+
+	if cmd.Flags().Changed(auditPolicy) {
+		if err := validateAuditPolicy(viper.GetString(auditPolicy)); err != nil {
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+		}
+	}
+
+	if cmd.Flags().Changed(waitFor) {
+		if _, err := kverify.ParseResourceGates(viper.GetStringSlice(waitFor)); err != nil {
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+		}
+	}
+
+	if err := validateBootstrapper(viper.GetString(cmdcfg.Bootstrapper), viper.GetBool(ha)); err != nil {
+		exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+	}
+
+	if err := validateHATopology(viper.GetString(etcdTopology), viper.GetInt(etcdNodes), viper.GetString(haLoadBalancer), viper.GetBool(ha)); err != nil {
+		exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+	}
+
+	if cmd.Flags().Changed(admissionConfig) {
+		if _, err := os.Stat(viper.GetString(admissionConfig)); err != nil {
+			exit.Message(reason.Usage, "Invalid --admission-config: {{.err}}", out.V{"err": err})
+		}
+	}
+
+	if cmd.Flags().Changed(qemuAccel) || cmd.Flags().Changed(qemuArch) {
+		if err := validateQemuEmulation(drvName, viper.GetString(qemuAccel), viper.GetString(qemuArch)); err != nil {
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+		}
+	}
+
 	if driver.IsSSH(drvName) {
-		sshIPAddress := viper.GetString(sshIPAddress)
-		if sshIPAddress == "" {
+		sshIPAddresses := viper.GetStringSlice(sshIPAddress)
+		if len(sshIPAddresses) == 0 {
 			exit.Message(reason.Usage, "No IP address provided. Try specifying --ssh-ip-address, or see https://minikube.sigs.k8s.io/docs/drivers/ssh/")
 		}
 
-		if net.ParseIP(sshIPAddress) == nil {
-			_, err := net.LookupIP(sshIPAddress)
-			if err != nil {
-				exit.Error(reason.Usage, "Could not resolve IP address", err)
+		for _, addr := range sshIPAddresses {
+			if net.ParseIP(addr) == nil {
+				_, err := net.LookupIP(addr)
+				if err != nil {
+					exit.Error(reason.Usage, "Could not resolve IP address", err)
+				}
 			}
 		}
+
+		// one node per host, unless told otherwise
+		if !cmd.Flags().Changed(nodes) {
+			viper.Set(nodes, len(sshIPAddresses))
+		}
+		if viper.GetInt(nodes) > len(sshIPAddresses) {
+			exit.Message(reason.Usage, "The ssh driver runs each node on a host of its own, but {{.nodes}} nodes were asked for with {{.hosts}} hosts given with --ssh-ip-address", out.V{"nodes": viper.GetInt(nodes), "hosts": len(sshIPAddresses)})
+		}
 	}
 
 	// validate kubeadm extra args
@@ -1360,6 +1575,7 @@ func validateFlags(cmd *cobra.Command, drvName string) { //nolint:gocyclo
 		exit.Message(reason.Usage, "Sorry, please set the --output flag to one of the following valid options: [text,json]")
 	}
 
+	validateCapabilities(cmd, drvName)
 	validateBareMetal(drvName)
 	validateRegistryMirror()
 	validateInsecureRegistry()
@@ -1454,6 +1670,27 @@ func validateRuntime(rtime string) error {
 	return nil
 }
 
+// validateQemuEmulation validates the accelerator and the architecture of qemu guests
+func validateQemuEmulation(drvName, accel, arch string) error {
+	if !driver.IsQEMU(drvName) {
+		return errors.Errorf("--%s and --%s are only supported by the qemu2 driver", qemuAccel, qemuArch)
+	}
+	switch accel {
+	case "", "kvm", "hvf", "tcg":
+	default:
+		return errors.Errorf("invalid --%s %q, valid values are kvm, hvf and tcg", qemuAccel, accel)
+	}
+	switch arch {
+	case "", "amd64", "arm64":
+	default:
+		return errors.Errorf("invalid --%s %q, valid values are amd64 and arm64", qemuArch, arch)
+	}
+	if arch != "" && arch != runtime.GOARCH && accel != "" && !qemu.IsEmulated(accel) {
+		return errors.Errorf("%s cannot run %s guests on an %s host, use --%s=tcg to emulate them", accel, arch, runtime.GOARCH, qemuAccel)
+	}
+	return nil
+}
+
 // validateGPUs validates that a valid option was given, and if so, can it be used with the given configuration
 func validateGPUs(value, drvName, rtime string) error {
 	if value == "" {
@@ -1486,6 +1723,94 @@ func validateAutoPauseInterval(interval time.Duration) error {
 	return nil
 }
 
+func validateImageGCThreshold(threshold int) error {
+	if threshold < 0 || threshold > 100 {
+		return errors.New("image-gc-threshold must be a percentage between 0 and 100")
+	}
+	return nil
+}
+
+// validateCustomCA validates the CA given with --ca-cert and --ca-key can sign certificates
+func validateCustomCA(certPath, keyPath string) error {
+	if certPath == "" || keyPath == "" {
+		return errors.New("--ca-cert and --ca-key must be given together")
+	}
+	if err := util.ValidateCACert(certPath, keyPath); err != nil {
+		return errors.Wrap(err, "invalid CA given with --ca-cert and --ca-key")
+	}
+	return nil
+}
+
+// validateOIDC validates the OpenID Connect options
+func validateOIDC(oidc config.OIDCConfig) error {
+	if oidc.ClientID == "" {
+		return errors.New("--oidc-client-id is required with --oidc-issuer-url")
+	}
+	u, err := url.Parse(oidc.IssuerURL)
+	if err != nil {
+		return errors.Wrap(err, "invalid --oidc-issuer-url")
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("--oidc-issuer-url must be an https URL, got %q", oidc.IssuerURL)
+	}
+	return nil
+}
+
+// validateOIDCCAFile validates the CA file of the OpenID Connect provider holds a certificate
+func validateOIDCCAFile(caFile string) error {
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return errors.Wrap(err, "reading --oidc-ca-file")
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return fmt.Errorf("--oidc-ca-file %s does not contain any PEM encoded certificate", caFile)
+	}
+	return nil
+}
+
+// validateAuditPolicy validates the audit policy is a preset or an existing file
+func validateAuditPolicy(policy string) error {
+	if policy == "" || bsutil.IsAuditPolicyPreset(policy) {
+		return nil
+	}
+	if _, err := os.Stat(policy); err != nil {
+		return fmt.Errorf("--audit-policy must be one of %s or the path of an audit policy file: %v", strings.Join(bsutil.AuditPolicyPresets(), ", "), err)
+	}
+	return nil
+}
+
+// validateBootstrapper validates the bootstrapper is known and supports the requested topology
+func validateBootstrapper(name string, haRequested bool) error {
+	switch name {
+	case bootstrapper.Kubeadm:
+		return nil
+	case bootstrapper.K3s:
+		if haRequested {
+			return fmt.Errorf("the %s bootstrapper supports a single control-plane node, and cannot be used with --ha", name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("--bootstrapper must be one of %s, %s, got %q", bootstrapper.Kubeadm, bootstrapper.K3s, name)
+	}
+}
+
+// validateHATopology validates the etcd topology and the load balancer, which only apply to HA (multi-control plane) clusters
+func validateHATopology(topology string, etcdCount int, lb string, haRequested bool) error {
+	if topology != config.EtcdStacked && topology != config.EtcdExternal {
+		return fmt.Errorf("--%s must be one of %s, %s, got %q", etcdTopology, config.EtcdStacked, config.EtcdExternal, topology)
+	}
+	if lb != config.KubeVIP && lb != config.HAProxy {
+		return fmt.Errorf("--%s must be one of %s, %s, got %q", haLoadBalancer, config.KubeVIP, config.HAProxy, lb)
+	}
+	if !haRequested && (topology != config.EtcdStacked || lb != config.KubeVIP) {
+		return fmt.Errorf("--%s and --%s only apply to HA (multi-control plane) clusters, use --ha", etcdTopology, haLoadBalancer)
+	}
+	if topology == config.EtcdExternal && etcdCount < 1 {
+		return fmt.Errorf("--%s must be at least 1, got %d", etcdNodes, etcdCount)
+	}
+	return nil
+}
+
 func getContainerRuntime(old *config.ClusterConfig) string {
 	paramRuntime := viper.GetString(containerRuntime)
 
@@ -1885,6 +2210,15 @@ $ minikube config unset kubernetes-version`)
 		paramVersion = old.KubernetesConfig.KubernetesVersion
 	}
 
+	kubernetesVer, err := resolveKubernetesVersion(paramVersion)
+	if err != nil && !errors.Is(err, ErrKubernetesPatchNotFound) {
+		exit.Message(reason.Usage, `Unable to parse "{{.kubernetes_version}}": {{.error}}`, out.V{"kubernetes_version": paramVersion, "error": err})
+	}
+	return kubernetesVer, err
+}
+
+// resolveKubernetesVersion returns the Kubernetes version a --kubernetes-version value stands for, such as "stable" or "v1.30"
+func resolveKubernetesVersion(paramVersion string) (string, error) {
 	if paramVersion == "" || strings.EqualFold(paramVersion, "stable") {
 		paramVersion = constants.DefaultKubernetesVersion
 	} else if strings.EqualFold(strings.ToLower(paramVersion), "latest") || strings.EqualFold(strings.ToLower(paramVersion), "newest") {
@@ -1901,7 +2235,7 @@ $ minikube config unset kubernetes-version`)
 	}
 	nvs, err := semver.Make(kubernetesSemver)
 	if err != nil {
-		exit.Message(reason.Usage, `Unable to parse "{{.kubernetes_version}}": {{.error}}`, out.V{"kubernetes_version": paramVersion, "error": err})
+		return "", err
 	}
 
 	return version.VersionPrefix + nvs.String(), nil
@@ -1950,10 +2284,8 @@ func validateSubnet(subnet string) error {
 }
 
 func validateStaticIP(staticIP, drvName, subnet string) error {
+	// whether the driver supports static IPs at all is up to validateCapabilities
 	if !driver.IsKIC(drvName) {
-		if staticIP != "" {
-			out.WarningT("--static-ip is only implemented on Docker and Podman drivers, flag will be ignored")
-		}
 		return nil
 	}
 	if subnet != "" {
@@ -1973,6 +2305,30 @@ func validateStaticIP(staticIP, drvName, subnet string) error {
 	return nil
 }
 
+// validateCapabilities validates that the driver supports the features the flags ask for
+func validateCapabilities(cmd *cobra.Command, drvName string) {
+	var requested []registry.Capability
+	if viper.GetBool(createMount) {
+		requested = append(requested, registry.Mount)
+	}
+	if viper.GetInt(nodes) > 1 || viper.GetBool(ha) {
+		requested = append(requested, registry.MultiNode)
+	}
+	if cmd.Flags().Changed(staticIP) && viper.GetString(staticIP) != "" {
+		requested = append(requested, registry.StaticIP)
+	}
+	if cmd.Flags().Changed(extraDisks) && viper.GetInt(extraDisks) > 0 {
+		requested = append(requested, registry.ExtraDisks)
+	}
+	if cmd.Flags().Changed(gpus) && viper.GetString(gpus) != "" {
+		requested = append(requested, registry.GPUs)
+	}
+	if len(viper.GetStringSlice(ports)) > 0 {
+		requested = append(requested, registry.ExposedPorts)
+	}
+	exitIfUnsupported(drvName, requested...)
+}
+
 func validateBareMetal(drvName string) {
 	if !driver.BareMetal(drvName) {
 		return
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
@@ -28,8 +29,10 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"k8s.io/klog/v2"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/drivers/kic"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
 	"k8s.io/minikube/pkg/minikube/cni"
@@ -103,6 +106,7 @@ const (
 	dnsProxy                = "dns-proxy"
 	hostDNSResolver         = "host-dns-resolver"
 	waitComponents          = "wait"
+	waitFor                 = "wait-for"
 	force                   = "force"
 	dryRun                  = "dry-run"
 	interactive             = "interactive"
@@ -116,6 +120,9 @@ const (
 	hostOnlyNicType         = "host-only-nic-type"
 	natNicType              = "nat-nic-type"
 	ha                      = "ha"
+	etcdTopology            = "etcd-topology"
+	etcdNodes               = "etcd-nodes"
+	haLoadBalancer          = "ha-load-balancer"
 	nodes                   = "nodes"
 	preload                 = "preload"
 	deleteOnFailure         = "delete-on-failure"
@@ -130,6 +137,7 @@ const (
 	sshSSHUser              = "ssh-user"
 	sshSSHKey               = "ssh-key"
 	sshSSHPort              = "ssh-port"
+	sshPrepareHost          = "ssh-prepare-host"
 	defaultSSHUser          = "root"
 	defaultSSHPort          = 22
 	listenAddress           = "listen-address"
@@ -139,11 +147,31 @@ const (
 	disableOptimizations    = "disable-optimizations"
 	disableMetrics          = "disable-metrics"
 	qemuFirmwarePath        = "qemu-firmware-path"
+	qemuAccel               = "qemu-accel"
+	qemuArch                = "qemu-arch"
 	socketVMnetClientPath   = "socket-vmnet-client-path"
 	socketVMnetPath         = "socket-vmnet-path"
 	staticIP                = "static-ip"
 	gpus                    = "gpus"
 	autoPauseInterval       = "auto-pause-interval"
+	imageGCThreshold        = "image-gc-threshold"
+	imageGCOlderThan        = "image-gc-older-than"
+	preloadPath             = "preload-path"
+	preloadBaseURL          = "preload-base-url"
+	offline                 = "offline"
+	caCert                  = "ca-cert"
+	caKey                   = "ca-key"
+	oidcIssuerURL           = "oidc-issuer-url"
+	oidcClientID            = "oidc-client-id"
+	oidcClientSecret        = "oidc-client-secret"
+	oidcUsernameClaim       = "oidc-username-claim"
+	oidcUsernamePrefix      = "oidc-username-prefix"
+	oidcGroupsClaim         = "oidc-groups-claim"
+	oidcGroupsPrefix        = "oidc-groups-prefix"
+	oidcCAFile              = "oidc-ca-file"
+	auditPolicy             = "audit-policy"
+	admissionPlugins        = "admission-plugins"
+	admissionConfig         = "admission-config"
 )
 
 var (
@@ -187,11 +215,15 @@ func initMinikubeFlags() {
 	startCmd.Flags().Bool(enableDefaultCNI, false, "DEPRECATED: Replaced by --cni=bridge")
 	startCmd.Flags().String(cniFlag, "", "CNI plug-in to use. Valid options: auto, bridge, calico, cilium, flannel, kindnet, or path to a CNI manifest (default: auto)")
 	startCmd.Flags().StringSlice(waitComponents, kverify.DefaultWaitList, fmt.Sprintf("comma separated list of Kubernetes components to verify and wait for after starting a cluster. defaults to %q, available options: %q . other acceptable values are 'all' or 'none', 'true' and 'false'", strings.Join(kverify.DefaultWaitList, ","), strings.Join(kverify.AllComponentsList, ",")))
+	startCmd.Flags().StringSlice(waitFor, nil, fmt.Sprintf("comma separated list of resource conditions to wait for after starting a cluster, as <kind>/[<namespace>/]<name>=<condition>[@<timeout>], for example deployment/ns/name=Available, crd/foos.example.com=Established or job/ns/name=Complete@2m. kinds are %q or <resource>.<version>.<group> for custom resources, and the timeout defaults to --wait-timeout", strings.Join(kverify.ResourceGateKinds(), ",")))
 	startCmd.Flags().Duration(waitTimeout, 6*time.Minute, "max time to wait per Kubernetes or host to be healthy.")
 	startCmd.Flags().Bool(nativeSSH, true, "Use native Golang SSH client (default true). Set to 'false' to use the command line 'ssh' command when accessing the docker machine. Useful for the machine drivers when they will not start with 'Waiting for SSH'.")
 	startCmd.Flags().Bool(autoUpdate, true, "If set, automatically updates drivers to the latest version. Defaults to true.")
 	startCmd.Flags().Bool(installAddons, true, "If set, install addons. Defaults to true.")
 	startCmd.Flags().Bool(ha, false, "Create Highly Available Multi-Control Plane Cluster with a minimum of three control-plane nodes that will also be marked for work.")
+	startCmd.Flags().String(etcdTopology, config.EtcdStacked, fmt.Sprintf("Topology of etcd in an HA (multi-control plane) cluster: %q runs a member on every control-plane node, %q runs the members on dedicated etcd nodes", config.EtcdStacked, config.EtcdExternal))
+	startCmd.Flags().Int(etcdNodes, 3, fmt.Sprintf("The number of dedicated etcd nodes to create with --etcd-topology=%s.", config.EtcdExternal))
+	startCmd.Flags().String(haLoadBalancer, config.KubeVIP, fmt.Sprintf("Load balancer of the apiservers of an HA (multi-control plane) cluster: %q announces a virtual IP from the control-plane nodes, %q runs on a dedicated load balancer node", config.KubeVIP, config.HAProxy))
 	startCmd.Flags().IntP(nodes, "n", 1, "The total number of nodes to spin up. Defaults to 1.")
 	startCmd.Flags().Bool(preload, true, "If set, download tarball of preloaded images if available to improve start time. Defaults to true.")
 	startCmd.Flags().Bool(noKubernetes, false, "If set, minikube VM/container will start without starting or configuring Kubernetes. (only works on new clusters)")
@@ -200,7 +232,7 @@ func initMinikubeFlags() {
 	startCmd.Flags().String(network, "", "network to run minikube with. Now it is used by docker/podman and KVM drivers. If left empty, minikube will create a new network.")
 	startCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
 	startCmd.Flags().String(trace, "", "Send trace events. Options include: [gcp]")
-	startCmd.Flags().Int(extraDisks, 0, "Number of extra disks created and attached to the minikube VM (currently only implemented for hyperkit, kvm2, and qemu2 drivers)")
+	startCmd.Flags().Int(extraDisks, 0, "Number of extra disks created and attached to each node (currently only implemented for the hyperkit, kvm2, qemu2, vfkit, docker and podman drivers, the latter two on Linux as loop devices of the host)")
 	startCmd.Flags().Duration(certExpiration, constants.DefaultCertExpiration, "Duration until minikube certificate expiration, defaults to three years (26280h).")
 	startCmd.Flags().String(binaryMirror, "", "Location to fetch kubectl, kubelet, & kubeadm binaries from.")
 	startCmd.Flags().Bool(disableOptimizations, false, "If set, disables optimizations that are set for local Kubernetes. Including decreasing CoreDNS replicas from 2 to 1. Defaults to false.")
@@ -208,6 +240,11 @@ func initMinikubeFlags() {
 	startCmd.Flags().String(staticIP, "", "Set a static IP for the minikube cluster, the IP must be: private, IPv4, and the last octet must be between 2 and 254, for example 192.168.200.200 (Docker and Podman drivers only)")
 	startCmd.Flags().StringP(gpus, "g", "", "Allow pods to use your GPUs. Options include: [all,nvidia,amd] (Docker driver with Docker container-runtime only)")
 	startCmd.Flags().Duration(autoPauseInterval, time.Minute*1, "Duration of inactivity before the minikube VM is paused (default 1m0s)")
+	startCmd.Flags().Int(imageGCThreshold, 0, "Percentage of /var usage on a node above which dangling images are pruned in the background when the node status is checked. 0 disables pruning.")
+	startCmd.Flags().Duration(imageGCOlderThan, 0, "Only prune images older than this duration when --image-gc-threshold is exceeded.")
+	startCmd.Flags().String(preloadPath, "", "Path to a preloaded images tarball, such as one created by 'minikube preload build', to import into the cache instead of downloading it.")
+	startCmd.Flags().Bool(offline, false, "If set, never access the network and fail up front if any artifact required to start is missing from the cache. Use 'minikube cache bundle' to gather them on a connected machine.")
+	startCmd.Flags().String(preloadBaseURL, "", "Base URL to download preloaded images tarballs and their checksums from, instead of the default storage bucket.")
 }
 
 // initKubernetesFlags inits the commandline flags for Kubernetes related options
@@ -225,6 +262,19 @@ func initKubernetesFlags() {
 	startCmd.Flags().String(apiServerName, constants.APIServerName, "The authoritative apiserver hostname for apiserver certificates and connectivity. This can be used if you want to make the apiserver available from outside the machine")
 	startCmd.Flags().StringSliceVar(&apiServerNames, "apiserver-names", nil, "A set of apiserver names which are used in the generated certificate for kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
 	startCmd.Flags().IPSliceVar(&apiServerIPs, "apiserver-ips", nil, "A set of apiserver IP Addresses which are used in the generated certificate for kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
+	startCmd.Flags().String(caCert, "", "Path to a CA certificate to sign the apiserver and client certificates with, instead of the minikube CA. Requires --ca-key.")
+	startCmd.Flags().String(caKey, "", "Path to the private key of the CA certificate given with --ca-cert.")
+	startCmd.Flags().String(oidcIssuerURL, "", "The https URL of the OpenID Connect provider to authenticate users with. Requires --oidc-client-id.")
+	startCmd.Flags().String(oidcClientID, "", "The client ID of minikube registered with the OpenID Connect provider.")
+	startCmd.Flags().String(oidcClientSecret, "", "The client secret of minikube registered with the OpenID Connect provider, used by the kubeconfig user to log in.")
+	startCmd.Flags().String(oidcUsernameClaim, "", "The claim of the OpenID Connect token to use as the user name. Defaults to 'sub'.")
+	startCmd.Flags().String(oidcUsernamePrefix, "", "The prefix prepended to the user names of the OpenID Connect provider.")
+	startCmd.Flags().String(oidcGroupsClaim, "", "The claim of the OpenID Connect token to use as the groups of the user.")
+	startCmd.Flags().String(oidcGroupsPrefix, "", "The prefix prepended to the groups of the OpenID Connect provider.")
+	startCmd.Flags().String(oidcCAFile, "", "Path to the CA certificate of the OpenID Connect provider, if it is not trusted by the host.")
+	startCmd.Flags().String(auditPolicy, "", fmt.Sprintf("Enable the audit log of the apiserver with this policy. Either a preset (%s) or the path of an audit policy file.", strings.Join(bsutil.AuditPolicyPresets(), ", ")))
+	startCmd.Flags().StringSlice(admissionPlugins, nil, "Comma separated list of admission plugins to enable in addition to the default ones.")
+	startCmd.Flags().String(admissionConfig, "", "Path of an AdmissionConfiguration file for the apiserver. The configuration of each plugin has to be inlined.")
 }
 
 // initDriverFlags inits the commandline flags for vm drivers
@@ -263,11 +313,13 @@ func initDriverFlags() {
 
 	// docker & podman
 	startCmd.Flags().String(listenAddress, "", "IP Address to use to expose ports (docker and podman driver only)")
-	startCmd.Flags().StringSlice(ports, []string{}, "List of ports that should be exposed (docker and podman driver only)")
+	startCmd.Flags().StringSlice(ports, []string{}, "List of ports that should be exposed (docker and podman driver, and qemu driver with the builtin network only)")
 	startCmd.Flags().String(subnet, "", "Subnet to be used on kic cluster. If left empty, minikube will choose subnet address, beginning from 192.168.49.0. (docker and podman driver only)")
 
 	// qemu
 	startCmd.Flags().String(qemuFirmwarePath, "", "Path to the qemu firmware file. Defaults: For Linux, the default firmware location. For macOS, the brew installation location. For Windows, C:\\Program Files\\qemu\\share")
+	startCmd.Flags().String(qemuAccel, "", "The accelerator of the qemu guests: kvm, hvf or tcg for software emulation. Defaults to the hardware accelerator of the host, or tcg if there is none or the guests are of another architecture (qemu2 driver only)")
+	startCmd.Flags().String(qemuArch, "", "The architecture of the qemu guests, amd64 or arm64. Defaults to the architecture of the host; any other is emulated with tcg (qemu2 driver only)")
 }
 
 // initNetworkingFlags inits the commandline flags for connectivity related flags for start
@@ -281,10 +333,11 @@ func initNetworkingFlags() {
 	startCmd.Flags().StringArrayVar(&config.DockerOpt, "docker-opt", nil, "Specify arbitrary flags to pass to the Docker daemon. (format: key=value)")
 
 	// ssh
-	startCmd.Flags().String(sshIPAddress, "", "IP address (ssh driver only)")
+	startCmd.Flags().StringSlice(sshIPAddress, []string{}, "IP addresses of the hosts of the nodes, starting with the primary control-plane node, one node is started per host unless --nodes is given (ssh driver only)")
 	startCmd.Flags().String(sshSSHUser, defaultSSHUser, "SSH user (ssh driver only)")
 	startCmd.Flags().String(sshSSHKey, "", "SSH key (ssh driver only)")
 	startCmd.Flags().Int(sshSSHPort, defaultSSHPort, "SSH port (ssh driver only)")
+	startCmd.Flags().Bool(sshPrepareHost, false, "Prepare the hosts for Kubernetes: load kernel modules, set sysctls, turn swap off and install the container runtime if missing, all of which is undone when the cluster is deleted (ssh driver only)")
 
 	// socket vmnet
 	startCmd.Flags().String(socketVMnetClientPath, "", "Path to the socket vmnet client binary (QEMU driver only)")
@@ -457,6 +510,42 @@ func getRepository(cmd *cobra.Command, k8sVersion string) string {
 	return repository
 }
 
+// absFlagPath returns the absolute path given with a flag, as the profile may be used from another directory
+func absFlagPath(key string) string {
+	p := viper.GetString(key)
+	if p == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		klog.Warningf("unable to get the absolute path of %s: %v", p, err)
+		return p
+	}
+	return abs
+}
+
+// getAuditPolicy returns the audit policy preset, or the absolute path of the audit policy file
+func getAuditPolicy() string {
+	if bsutil.IsAuditPolicyPreset(viper.GetString(auditPolicy)) {
+		return viper.GetString(auditPolicy)
+	}
+	return absFlagPath(auditPolicy)
+}
+
+// getOIDCConfig returns the OpenID Connect options given with the flags
+func getOIDCConfig() config.OIDCConfig {
+	return config.OIDCConfig{
+		IssuerURL:      viper.GetString(oidcIssuerURL),
+		ClientID:       viper.GetString(oidcClientID),
+		ClientSecret:   viper.GetString(oidcClientSecret),
+		UsernameClaim:  viper.GetString(oidcUsernameClaim),
+		UsernamePrefix: viper.GetString(oidcUsernamePrefix),
+		GroupsClaim:    viper.GetString(oidcGroupsClaim),
+		GroupsPrefix:   viper.GetString(oidcGroupsPrefix),
+		CAFile:         absFlagPath(oidcCAFile),
+	}
+}
+
 func getCNIConfig(cmd *cobra.Command) string {
 	// Backwards compatibility with --enable-default-cni
 	chosenCNI := viper.GetString(cniFlag)
@@ -468,7 +557,11 @@ func getCNIConfig(cmd *cobra.Command) string {
 }
 
 func getNetwork(driverName string) string {
-	n := viper.GetString(network)
+	return driverNetwork(driverName, viper.GetString(network))
+}
+
+// driverNetwork returns the network n of the driver, the default one of QEMU if none is given
+func driverNetwork(driverName, n string) string {
 	if !driver.IsQEMU(driverName) {
 		return n
 	}
@@ -503,6 +596,15 @@ func getNetwork(driverName string) string {
 	return n
 }
 
+// firstSSHHost returns the host of the primary control-plane node with the ssh driver
+func firstSSHHost() string {
+	hosts := viper.GetStringSlice(sshIPAddress)
+	if len(hosts) == 0 {
+		return ""
+	}
+	return hosts[0]
+}
+
 // generateNewConfigFromFlags generate a config.ClusterConfig based on flags
 func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, rtime string, drvName string) config.ClusterConfig {
 	var cc config.ClusterConfig
@@ -521,8 +623,6 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, rtime str
 
 	checkNumaCount(k8sVersion)
 
-	checkExtraDiskOptions(cmd, drvName)
-
 	cc = config.ClusterConfig{
 		Name:                    ClusterFlagValue(),
 		KeepContext:             viper.GetBool(keepContext),
@@ -563,12 +663,16 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, rtime str
 		NatNicType:              viper.GetString(natNicType),
 		StartHostTimeout:        viper.GetDuration(waitTimeout),
 		ExposedPorts:            viper.GetStringSlice(ports),
-		SSHIPAddress:            viper.GetString(sshIPAddress),
+		SSHIPAddress:            firstSSHHost(),
+		SSHIPAddresses:          viper.GetStringSlice(sshIPAddress),
 		SSHUser:                 viper.GetString(sshSSHUser),
 		SSHKey:                  viper.GetString(sshSSHKey),
 		SSHPort:                 viper.GetInt(sshSSHPort),
+		SSHPrepareHost:          viper.GetBool(sshPrepareHost),
 		ExtraDisks:              viper.GetInt(extraDisks),
 		CertExpiration:          viper.GetDuration(certExpiration),
+		CustomCACert:            absFlagPath(caCert),
+		CustomCAKey:             absFlagPath(caKey),
 		Mount:                   viper.GetBool(createMount),
 		MountString:             viper.GetString(mountString),
 		Mount9PVersion:          viper.GetString(mount9PVersion),
@@ -583,6 +687,8 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, rtime str
 		DisableOptimizations:    viper.GetBool(disableOptimizations),
 		DisableMetrics:          viper.GetBool(disableMetrics),
 		CustomQemuFirmwarePath:  viper.GetString(qemuFirmwarePath),
+		QemuAccel:               viper.GetString(qemuAccel),
+		QemuArch:                viper.GetString(qemuArch),
 		SocketVMnetClientPath:   detect.SocketVMNetClientPath(),
 		SocketVMnetPath:         detect.SocketVMNetPath(),
 		StaticIP:                viper.GetString(staticIP),
@@ -603,12 +709,22 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, rtime str
 			ExtraOptions:           getExtraOptions(),
 			ShouldLoadCachedImages: viper.GetBool(cacheImages),
 			CNI:                    getCNIConfig(cmd),
+			OIDC:                   getOIDCConfig(),
+			AuditPolicy:            getAuditPolicy(),
+			AdmissionPlugins:       viper.GetStringSlice(admissionPlugins),
+			AdmissionConfig:        absFlagPath(admissionConfig),
+			EtcdTopology:           viper.GetString(etcdTopology),
+			HALoadBalancer:         viper.GetString(haLoadBalancer),
 		},
+		Bootstrapper:       viper.GetString(cmdcfg.Bootstrapper),
 		MultiNodeRequested: viper.GetInt(nodes) > 1 || viper.GetBool(ha),
 		GPUs:               viper.GetString(gpus),
 		AutoPauseInterval:  viper.GetDuration(autoPauseInterval),
+		ImageGCThreshold:   viper.GetInt(imageGCThreshold),
+		ImageGCOlderThan:   viper.GetDuration(imageGCOlderThan),
 	}
 	cc.VerifyComponents = interpretWaitFlag(*cmd)
+	cc.WaitFor = viper.GetStringSlice(waitFor)
 	if viper.GetBool(createMount) && driver.IsKIC(drvName) {
 		cc.ContainerVolumeMounts = []string{viper.GetString(mountString)}
 	}
@@ -669,6 +785,14 @@ func addFeatureGate(featureGates, s string) string {
 	return strings.Join(split, ",")
 }
 
+// existingBootstrapper returns the bootstrapper of an existing cluster, which profiles created before it was recorded left empty
+func existingBootstrapper(existing *config.ClusterConfig) string {
+	if existing.Bootstrapper == "" {
+		return bootstrapper.Kubeadm
+	}
+	return existing.Bootstrapper
+}
+
 // validateHANodeCount ensures correct total number of nodes in ha (multi-control plane) cluster.
 func validateHANodeCount(cmd *cobra.Command) {
 	if !viper.GetBool(ha) {
@@ -750,6 +874,14 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		out.WarningT("Changing the HA (multi-control plane) mode of an existing minikube cluster is not currently supported. Please first delete the cluster and use 'minikube start --ha' to create new one.")
 	}
 
+	if cmd.Flags().Changed(etcdTopology) || cmd.Flags().Changed(etcdNodes) || cmd.Flags().Changed(haLoadBalancer) {
+		out.WarningT("Changing the etcd topology or the load balancer of an existing minikube HA (multi-control plane) cluster is not currently supported. Please first delete the cluster.")
+	}
+
+	if cmd.Flags().Changed(cmdcfg.Bootstrapper) && viper.GetString(cmdcfg.Bootstrapper) != existingBootstrapper(existing) {
+		out.WarningT("Changing the bootstrapper of an existing minikube cluster is not currently supported. Please first delete the cluster.")
+	}
+
 	if cmd.Flags().Changed(apiServerPort) && config.IsHA(*existing) {
 		out.WarningT("Changing the API server port of an existing minikube HA (multi-control plane) cluster is not currently supported. Please first delete the cluster.")
 	} else {
@@ -771,7 +903,6 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		out.WarningT("You cannot change the disk size for an existing minikube cluster. Please first delete the cluster.")
 	}
 
-	checkExtraDiskOptions(cmd, cc.Driver)
 	if cmd.Flags().Changed(extraDisks) && viper.GetInt(extraDisks) != existing.ExtraDisks {
 		out.WarningT("You cannot add or remove extra disks for an existing minikube cluster. Please first delete the cluster.")
 	}
@@ -780,6 +911,14 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		out.WarningT("You cannot change the static IP of an existing minikube cluster. Please first delete the cluster.")
 	}
 
+	if cmd.Flags().Changed(qemuArch) && viper.GetString(qemuArch) != existing.QemuArch {
+		out.WarningT("You cannot change the architecture of an existing minikube cluster. Please first delete the cluster.")
+	}
+
+	if cmd.Flags().Changed(qemuAccel) && viper.GetString(qemuAccel) != existing.QemuAccel {
+		out.WarningT("You cannot change the accelerator of an existing minikube cluster. Please first delete the cluster.")
+	}
+
 	updateBoolFromFlag(cmd, &cc.KeepContext, keepContext)
 	updateBoolFromFlag(cmd, &cc.EmbedCerts, embedCerts)
 	updateStringFromFlag(cmd, &cc.MinikubeISO, isoURL)
@@ -806,10 +945,14 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 	updateStringFromFlag(cmd, &cc.NatNicType, natNicType)
 	updateDurationFromFlag(cmd, &cc.StartHostTimeout, waitTimeout)
 	updateStringSliceFromFlag(cmd, &cc.ExposedPorts, ports)
-	updateStringFromFlag(cmd, &cc.SSHIPAddress, sshIPAddress)
+	if cmd.Flags().Changed(sshIPAddress) {
+		cc.SSHIPAddress = firstSSHHost()
+		cc.SSHIPAddresses = viper.GetStringSlice(sshIPAddress)
+	}
 	updateStringFromFlag(cmd, &cc.SSHUser, sshSSHUser)
 	updateStringFromFlag(cmd, &cc.SSHKey, sshSSHKey)
 	updateIntFromFlag(cmd, &cc.SSHPort, sshSSHPort)
+	updateBoolFromFlag(cmd, &cc.SSHPrepareHost, sshPrepareHost)
 	updateStringFromFlag(cmd, &cc.KubernetesConfig.Namespace, startNamespace)
 	updateStringFromFlag(cmd, &cc.KubernetesConfig.APIServerName, apiServerName)
 	updateStringSliceFromFlag(cmd, &cc.KubernetesConfig.APIServerNames, "apiserver-names")
@@ -837,6 +980,23 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 	updateStringFromFlag(cmd, &cc.SocketVMnetClientPath, socketVMnetClientPath)
 	updateStringFromFlag(cmd, &cc.SocketVMnetPath, socketVMnetPath)
 	updateDurationFromFlag(cmd, &cc.AutoPauseInterval, autoPauseInterval)
+	updateIntFromFlag(cmd, &cc.ImageGCThreshold, imageGCThreshold)
+	updateDurationFromFlag(cmd, &cc.ImageGCOlderThan, imageGCOlderThan)
+	updatePathFromFlag(cmd, &cc.CustomCACert, caCert)
+	updatePathFromFlag(cmd, &cc.CustomCAKey, caKey)
+	updateStringFromFlag(cmd, &cc.KubernetesConfig.OIDC.IssuerURL, oidcIssuerURL)
+	updateStringFromFlag(cmd, &cc.KubernetesConfig.OIDC.ClientID, oidcClientID)
+	updateStringFromFlag(cmd, &cc.KubernetesConfig.OIDC.ClientSecret, oidcClientSecret)
+	updateStringFromFlag(cmd, &cc.KubernetesConfig.OIDC.UsernameClaim, oidcUsernameClaim)
+	updateStringFromFlag(cmd, &cc.KubernetesConfig.OIDC.UsernamePrefix, oidcUsernamePrefix)
+	updateStringFromFlag(cmd, &cc.KubernetesConfig.OIDC.GroupsClaim, oidcGroupsClaim)
+	updateStringFromFlag(cmd, &cc.KubernetesConfig.OIDC.GroupsPrefix, oidcGroupsPrefix)
+	updatePathFromFlag(cmd, &cc.KubernetesConfig.OIDC.CAFile, oidcCAFile)
+	updateStringSliceFromFlag(cmd, &cc.KubernetesConfig.AdmissionPlugins, admissionPlugins)
+	updatePathFromFlag(cmd, &cc.KubernetesConfig.AdmissionConfig, admissionConfig)
+	if cmd.Flags().Changed(auditPolicy) {
+		cc.KubernetesConfig.AuditPolicy = getAuditPolicy()
+	}
 
 	if cmd.Flags().Changed(kubernetesVersion) {
 		kubeVer, err := getKubernetesVersion(existing)
@@ -861,6 +1021,8 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		cc.VerifyComponents = interpretWaitFlag(*cmd)
 	}
 
+	updateStringSliceFromFlag(cmd, &cc.WaitFor, waitFor)
+
 	if cmd.Flags().Changed("apiserver-ips") {
 		// IPSlice not supported in Viper
 		// https://github.com/spf13/viper/issues/460
@@ -887,6 +1049,13 @@ func updateStringFromFlag(cmd *cobra.Command, v *string, key string) {
 	}
 }
 
+// updatePathFromFlag will update the existing path from the flag, made absolute.
+func updatePathFromFlag(cmd *cobra.Command, v *string, key string) {
+	if cmd.Flags().Changed(key) {
+		*v = absFlagPath(key)
+	}
+}
+
 // updateBoolFromFlag will update the existing bool from the flag.
 func updateBoolFromFlag(cmd *cobra.Command, v *bool, key string) {
 	if cmd.Flags().Changed(key) {
@@ -966,20 +1135,3 @@ func interpretWaitFlag(cmd cobra.Command) map[string]bool {
 	klog.Infof("Waiting for components: %+v", waitComponents)
 	return waitComponents
 }
-
-func checkExtraDiskOptions(cmd *cobra.Command, driverName string) {
-	supportedDrivers := []string{driver.HyperKit, driver.KVM2, driver.QEMU2, driver.VFKit}
-
-	if cmd.Flags().Changed(extraDisks) {
-		supported := false
-		for _, driver := range supportedDrivers {
-			if driverName == driver {
-				supported = true
-				break
-			}
-		}
-		if !supported {
-			out.WarningT("Specifying extra disks is currently only supported for the following drivers: {{.supported_drivers}}. If you can contribute to add this feature, please create a PR.", out.V{"supported_drivers": supportedDrivers})
-		}
-	}
-}
@@ -18,6 +18,9 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -832,6 +835,35 @@ func TestValidateGPUs(t *testing.T) {
 	}
 }
 
+func TestValidateQemuEmulation(t *testing.T) {
+	other := "arm64"
+	if runtime.GOARCH == "arm64" {
+		other = "amd64"
+	}
+	tests := []struct {
+		drvName     string
+		accel       string
+		arch        string
+		shouldError bool
+	}{
+		{"qemu2", "tcg", "", false},
+		{"qemu2", "", other, false},
+		{"qemu2", "tcg", other, false},
+		{"qemu2", "kvm", runtime.GOARCH, false},
+		{"qemu2", "kvm", other, true},
+		{"qemu2", "hvf", other, true},
+		{"qemu2", "whpx", "", true},
+		{"qemu2", "", "riscv64", true},
+		{"kvm2", "tcg", "", true},
+	}
+	for _, tc := range tests {
+		err := validateQemuEmulation(tc.drvName, tc.accel, tc.arch)
+		if (err != nil) != tc.shouldError {
+			t.Errorf("validateQemuEmulation(%s, %s, %s) = %v; want error: %t", tc.drvName, tc.accel, tc.arch, err, tc.shouldError)
+		}
+	}
+}
+
 func TestValidateAutoPause(t *testing.T) {
 	tests := []struct {
 		interval    string
@@ -857,3 +889,104 @@ func TestValidateAutoPause(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateImageGCThreshold(t *testing.T) {
+	tests := []struct {
+		threshold   int
+		shouldError bool
+	}{
+		{0, false},
+		{85, false},
+		{100, false},
+		{-1, true},
+		{101, true},
+	}
+	for _, tc := range tests {
+		err := validateImageGCThreshold(tc.threshold)
+		if err != nil && !tc.shouldError {
+			t.Errorf("threshold of %d failed validation; expected it to pass: %v", tc.threshold, err)
+		}
+		if err == nil && tc.shouldError {
+			t.Errorf("threshold of %d passed validation; expected it to fail", tc.threshold)
+		}
+	}
+}
+
+func TestValidateAuditPolicy(t *testing.T) {
+	policyFile := filepath.Join(t.TempDir(), "audit-policy.yaml")
+	if err := os.WriteFile(policyFile, []byte("kind: Policy\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		policy      string
+		shouldError bool
+	}{
+		{"", false},
+		{"metadata", false},
+		{"requestresponse", false},
+		{policyFile, false},
+		{"metadta", true},
+		{filepath.Join(t.TempDir(), "missing.yaml"), true},
+	}
+	for _, tc := range tests {
+		err := validateAuditPolicy(tc.policy)
+		if err != nil && !tc.shouldError {
+			t.Errorf("audit policy %q failed validation; expected it to pass: %v", tc.policy, err)
+		}
+		if err == nil && tc.shouldError {
+			t.Errorf("audit policy %q passed validation; expected it to fail", tc.policy)
+		}
+	}
+}
+
+func TestValidateBootstrapper(t *testing.T) {
+	tests := []struct {
+		name        string
+		ha          bool
+		shouldError bool
+	}{
+		{"kubeadm", false, false},
+		{"kubeadm", true, false},
+		{"k3s", false, false},
+		{"k3s", true, true},
+		{"kubeam", false, true},
+	}
+	for _, tc := range tests {
+		err := validateBootstrapper(tc.name, tc.ha)
+		if err != nil && !tc.shouldError {
+			t.Errorf("bootstrapper %q (ha: %v) failed validation; expected it to pass: %v", tc.name, tc.ha, err)
+		}
+		if err == nil && tc.shouldError {
+			t.Errorf("bootstrapper %q (ha: %v) passed validation; expected it to fail", tc.name, tc.ha)
+		}
+	}
+}
+
+func TestValidateHATopology(t *testing.T) {
+	tests := []struct {
+		topology    string
+		etcdNodes   int
+		lb          string
+		ha          bool
+		shouldError bool
+	}{
+		{"stacked", 3, "kube-vip", false, false},
+		{"stacked", 3, "kube-vip", true, false},
+		{"external", 3, "haproxy", true, false},
+		{"external", 1, "kube-vip", true, false},
+		{"external", 0, "kube-vip", true, true},
+		{"external", 3, "kube-vip", false, true},
+		{"stacked", 3, "haproxy", false, true},
+		{"separate", 3, "kube-vip", true, true},
+		{"stacked", 3, "nginx", true, true},
+	}
+	for _, tc := range tests {
+		err := validateHATopology(tc.topology, tc.etcdNodes, tc.lb, tc.ha)
+		if err != nil && !tc.shouldError {
+			t.Errorf("topology %q with %d etcd nodes and load balancer %q (ha: %v) failed validation; expected it to pass: %v", tc.topology, tc.etcdNodes, tc.lb, tc.ha, err)
+		}
+		if err == nil && tc.shouldError {
+			t.Errorf("topology %q with %d etcd nodes and load balancer %q (ha: %v) passed validation; expected it to fail", tc.topology, tc.etcdNodes, tc.lb, tc.ha)
+		}
+	}
+}
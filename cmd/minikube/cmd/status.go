@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"strings"
 	"text/template"
 	"time"
@@ -33,6 +34,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/notify"
@@ -66,12 +68,18 @@ docker-env: {{.DockerEnv}}
 {{- if .PodManEnv }}
 podman-env: {{.PodManEnv}}
 {{- end }}
+{{- if .DiskPressure }}
+disk-pressure: {{.DiskPressure}}
+{{- end }}
 
 `
 	workerStatusFormat = `{{.Name}}
 type: Worker
 host: {{.Host}}
 kubelet: {{.Kubelet}}
+{{- if .DiskPressure }}
+disk-pressure: {{.DiskPressure}}
+{{- end }}
 
 `
 )
@@ -127,6 +135,8 @@ func writeStatusesAtInterval(duration time.Duration, api libmachine.API, cc *con
 			}
 		}
 
+		pruneImagesInBackground(cc, statuses)
+
 		switch output {
 		case "text":
 			for _, st := range statuses {
@@ -156,6 +166,34 @@ func writeStatusesAtInterval(duration time.Duration, api libmachine.API, cc *con
 	}
 }
 
+// pruneImagesInBackground prunes the dangling images of the nodes above the image garbage collection threshold of the cluster.
+// Each prune runs in a minikube process of its own, which outlives the status check instead of delaying it.
+func pruneImagesInBackground(cc *config.ClusterConfig, statuses []*cluster.Status) {
+	for _, st := range statuses {
+		if st == nil || st.DiskPressure == "" || !machine.ImageGCDue(*cc, st.Name) {
+			continue
+		}
+		bin, err := os.Executable()
+		if err != nil {
+			klog.Warningf("unable to find the minikube binary: %v", err)
+			return
+		}
+		args := []string{"image", "prune", "-p", cc.Name, "--node", st.Name}
+		if cc.ImageGCOlderThan > 0 {
+			args = append(args, "--older-than", cc.ImageGCOlderThan.String())
+		}
+		klog.Infof("%s: %s, pruning dangling images in the background: %s %v", st.Name, st.DiskPressure, bin, args)
+		c := exec.Command(bin, args...)
+		if err := c.Start(); err != nil {
+			klog.Warningf("unable to prune images: %v", err)
+			continue
+		}
+		if err := c.Process.Release(); err != nil {
+			klog.Warningf("unable to release the image prune process: %v", err)
+		}
+	}
+}
+
 // exitCode calculates the appropriate exit code given a set of status messages
 func exitCode(statuses []*cluster.Status) int {
 	c := 0
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/blang/semver/v4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/upgrade"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	upgradeK8sVersion string
+	upgradeForce      bool
+	upgradeDryRun     bool
+)
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrades the Kubernetes version of a running cluster in place",
+	Long: `Upgrades the Kubernetes version of a running cluster in place with kubeadm.
+
+Pre-flight checks make sure that no deprecated API removed by the new version is still in use, that the enabled addons support it and that the nodes are within the supported version skew.
+etcd is snapshotted first, then the control-plane nodes are upgraded one at a time, followed by the workers, which are drained while they are upgraded.
+If the upgrade fails, the nodes are reverted and etcd is restored from the snapshot.
+Clusters of the k3s bootstrapper cannot be upgraded in place.`,
+	Example: `minikube upgrade --kubernetes-version=v1.31.0
+minikube upgrade --kubernetes-version=stable --dry-run`,
+	Run: func(_ *cobra.Command, _ []string) {
+		if upgradeK8sVersion == "" {
+			exit.Message(reason.Usage, "Usage: minikube upgrade --kubernetes-version=<version>")
+		}
+		co := mustload.Healthy(ClusterFlagValue())
+		cc := co.Config
+
+		if cc.KubernetesConfig.KubernetesVersion == constants.NoKubernetesVersion {
+			exit.Message(reason.Usage, "The cluster does not run Kubernetes")
+		}
+		if cc.Bootstrapper == bootstrapper.K3s {
+			exit.Message(reason.Usage, "In-place upgrades are not supported by the {{.bootstrapper}} bootstrapper", out.V{"bootstrapper": cc.Bootstrapper})
+		}
+		target, err := resolveKubernetesVersion(upgradeK8sVersion)
+		if err != nil {
+			exit.Message(reason.Usage, `Unable to parse "{{.kubernetes_version}}": {{.error}}`, out.V{"kubernetes_version": upgradeK8sVersion, "error": err})
+		}
+		tv := semver.MustParse(target[1:])
+		cv, err := semver.ParseTolerant(cc.KubernetesConfig.KubernetesVersion)
+		if err != nil {
+			exit.Error(reason.InternalSemverParse, "Unable to parse the Kubernetes version of the cluster", err)
+		}
+		if tv.GT(semver.MustParse(constants.NewestKubernetesVersion[1:])) && !upgradeForce {
+			exit.Message(reason.KubernetesTooNew, "Kubernetes {{.version}} is not supported by this release of minikube", out.V{"version": target})
+		}
+		if tv.LT(cv) {
+			profileArg := ""
+			if cc.Name != constants.DefaultClusterName {
+				profileArg = " -p " + cc.Name
+			}
+			exit.Message(reason.KubernetesDowngrade, "Unable to safely downgrade existing Kubernetes v{{.old}} cluster to v{{.new}}",
+				out.V{"prefix": version.VersionPrefix, "new": tv, "old": cv, "profile": profileArg, "suggestedName": cc.Name + "2"})
+		}
+		if err := upgrade.ValidateVersion(cv, tv); err != nil {
+			out.Styled(style.Check, "Nothing to upgrade: {{.error}}", out.V{"error": err})
+			return
+		}
+
+		client, err := kapi.Client(cc.Name)
+		if err != nil {
+			exit.Error(reason.InternalKubernetesClient, "Unable to get a Kubernetes client", err)
+		}
+		out.Step(style.Verifying, "Running pre-flight checks for Kubernetes {{.version}} ...", out.V{"version": target})
+		checks, err := upgrade.Preflight(cc, client, tv)
+		if err != nil {
+			exit.Error(reason.KubernetesUpgradePreflight, "Unable to run the pre-flight checks", err)
+		}
+		passed := true
+		for _, c := range checks {
+			if c.Passed() {
+				out.Styled(style.Check, "{{.check}}", out.V{"check": c.Name})
+				continue
+			}
+			passed = false
+			out.Styled(style.Failure, "{{.check}}", out.V{"check": c.Name})
+			for _, p := range c.Problems {
+				out.Styled(style.Indent, "{{.problem}}", out.V{"problem": p})
+			}
+		}
+		if !passed && !upgradeForce {
+			exit.Message(reason.KubernetesUpgradePreflight, "The pre-flight checks failed, fix the problems above or upgrade anyway with --force")
+		}
+		if upgradeDryRun {
+			out.Styled(style.DryRun, "dry-run validation complete!")
+			return
+		}
+
+		if err := upgrade.Upgrade(co.API, cc, upgrade.Options{KubernetesVersion: target, Bootstrapper: viper.GetString(cmdcfg.Bootstrapper)}); err != nil {
+			exit.Error(reason.KubernetesUpgradeFailed, "Failed to upgrade Kubernetes", err)
+		}
+		out.Step(style.Celebrate, "Upgraded cluster {{.name}} to Kubernetes {{.version}}", out.V{"name": cc.Name, "version": target})
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeK8sVersion, "kubernetes-version", "", "The Kubernetes version to upgrade to, such as v1.31.0, v1.31, stable or latest")
+	upgradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "Upgrade even if the pre-flight checks fail, or to a version newer than minikube supports")
+	upgradeCmd.Flags().BoolVar(&upgradeDryRun, "dry-run", false, "Only run the pre-flight checks")
+}
@@ -17,15 +17,11 @@ limitations under the License.
 package main
 
 import (
-	"bytes"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"runtime/debug"
-	"strings"
 
 	"github.com/pkg/errors"
 
@@ -41,17 +37,14 @@ const (
 )
 
 var (
-	dockerStorageDriver   = "overlay2"
-	containerdSnapshotter = "overlayfs"
-	podmanStorageDriver   = "overlay"
-	containerRuntimes     = []string{"docker", "containerd", "cri-o"}
-	k8sVersions           []string
-	k8sVersion            = flag.String("kubernetes-version", "", "desired Kubernetes version, for example `v1.17.2`")
-	noUpload              = flag.Bool("no-upload", false, "Do not upload tarballs to GCS")
-	force                 = flag.Bool("force", false, "Generate the preload tarball even if it's already exists")
-	limit                 = flag.Int("limit", 0, "Limit the number of tarballs to generate")
-	armUpload             = flag.Bool("arm-upload", false, "Upload the arm64 preload tarballs to GCS")
-	armPreloadsDir        = flag.String("arm-preloads-dir", "artifacts", "Directory containing the arm64 preload tarballs")
+	containerRuntimes = []string{"docker", "containerd", "cri-o"}
+	k8sVersions       []string
+	k8sVersion        = flag.String("kubernetes-version", "", "desired Kubernetes version, for example `v1.17.2`")
+	noUpload          = flag.Bool("no-upload", false, "Do not upload tarballs to GCS")
+	force             = flag.Bool("force", false, "Generate the preload tarball even if it's already exists")
+	limit             = flag.Int("limit", 0, "Limit the number of tarballs to generate")
+	armUpload         = flag.Bool("arm-upload", false, "Upload the arm64 preload tarballs to GCS")
+	armPreloadsDir    = flag.String("arm-preloads-dir", "artifacts", "Directory containing the arm64 preload tarballs")
 )
 
 type preloadCfg struct {
@@ -151,7 +144,7 @@ func makePreload(cfg preloadCfg) error {
 		}
 	}()
 
-	if err := generateTarball(kv, cr, tf); err != nil {
+	if err := generateTarball(kv, cr); err != nil {
 		return errors.Wrap(err, fmt.Sprintf("generating tarball for k8s version %s with %s", kv, cr))
 	}
 
@@ -165,62 +158,6 @@ func makePreload(cfg preloadCfg) error {
 	return nil
 }
 
-var verifyDockerStorage = func() error {
-	cmd := exec.Command("docker", "exec", profile, "docker", "info", "-f", "{{.Info.Driver}}")
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("%v: %v:\n%s", cmd.Args, err, stderr.String())
-	}
-	driver := strings.Trim(string(output), " \n")
-	if driver != dockerStorageDriver {
-		return fmt.Errorf("docker storage driver %s does not match requested %s", driver, dockerStorageDriver)
-	}
-	return nil
-}
-
-var verifyContainerdStorage = func() error {
-	cmd := exec.Command("docker", "exec", profile, "sudo", "containerd", "config", "dump")
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("%v: %v:\n%s", cmd.Args, err, stderr.String())
-	}
-	var driver string
-	for _, line := range strings.Split(string(output), "\n") {
-		if strings.Contains(line, "snapshotter = ") {
-			driver = strings.Split(line, " = ")[1]
-			driver = strings.Trim(driver, "\"")
-		}
-	}
-	if driver != containerdSnapshotter {
-		return fmt.Errorf("containerd snapshotter %s does not match requested %s", driver, containerdSnapshotter)
-	}
-	return nil
-}
-
-var verifyPodmanStorage = func() error {
-	cmd := exec.Command("docker", "exec", profile, "sudo", "podman", "info", "-f", "json")
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("%v: %v:\n%s", cmd.Args, err, stderr.String())
-	}
-	var info map[string]map[string]interface{}
-	err = json.Unmarshal(output, &info)
-	if err != nil {
-		return err
-	}
-	driver := info["store"]["graphDriverName"]
-	if driver != podmanStorageDriver {
-		return fmt.Errorf("podman storage driver %s does not match requested %s", driver, podmanStorageDriver)
-	}
-	return nil
-}
-
 // exit will exit and clean up minikube
 func exit(msg string, err error) {
 	fmt.Printf("WithError(%s)=%v called from:\n%s", msg, err, debug.Stack())
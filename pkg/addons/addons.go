@@ -519,6 +519,7 @@ func Enable(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]bo
 	sort.Strings(toEnableList)
 
 	var awg sync.WaitGroup
+	var mu sync.Mutex
 
 	defer func() { // making it show after verifications (see #7613)
 		register.Reg.SetStep(register.EnablingAddons)
@@ -531,7 +532,9 @@ func Enable(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]bo
 			if err != nil && !errors.Is(err, ErrSkipThisAddon) {
 				out.WarningT("Enabling '{{.name}}' returned an error: {{.error}}", out.V{"name": name, "error": err})
 			} else {
+				mu.Lock()
 				enabledAddons = append(enabledAddons, name)
+				mu.Unlock()
 			}
 			awg.Done()
 		}(a)
@@ -539,6 +542,8 @@ func Enable(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]bo
 
 	// Wait until all of the addons are enabled
 	awg.Wait()
+	// in a stable order, whichever finished first
+	sort.Strings(enabledAddons)
 
 	// send the slice of all successfully enabled addons to channel and close
 	enabled <- enabledAddons
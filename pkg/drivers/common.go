@@ -23,6 +23,7 @@ import (
 	"io"
 	"net"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"syscall"
@@ -84,6 +85,15 @@ func CreateRawDisk(diskPath string, sizeMB int) error {
 	return nil
 }
 
+// Sudo runs a command as root on the host, without prompting for a password, returning its output
+func Sudo(args ...string) (string, error) {
+	out, err := exec.Command("sudo", append([]string{"-n"}, args...)...).CombinedOutput()
+	if err != nil {
+		return string(out), errors.Wrapf(err, "sudo %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
 // CommonDriver is the common driver base class
 type CommonDriver struct{}
 
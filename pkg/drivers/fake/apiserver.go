@@ -0,0 +1,269 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	autoscaling "k8s.io/api/autoscaling/v1"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// apiServer answers for the apiserver of a sandbox, for as long as minikube runs: the calls minikube makes to check on
+// a cluster get the answers of a healthy one, over TLS with the certs minikube put in the sandbox, while any of its services runs
+type apiServer struct {
+	mu     sync.Mutex
+	runner *command.SandboxRunner
+	root   string
+	node   string
+	// replicas are the replicas of the deployments, by namespace/name
+	replicas map[string]int32
+}
+
+var (
+	// apiServers are the apiservers this process serves, by address
+	apiServers   = map[string]*apiServer{}
+	apiServersMu sync.Mutex
+)
+
+// serveAPI serves the apiserver of the machine from this process, unless it already does. Machines at the same address,
+// as of the clusters of two profiles, share the server, which answers for the one whose runner was created last
+func (d *Driver) serveAPI(r *command.SandboxRunner) {
+	if d.APIServerPort == 0 || d.IPAddress == "" {
+		return
+	}
+	addr := net.JoinHostPort(d.IPAddress, strconv.Itoa(d.APIServerPort))
+
+	apiServersMu.Lock()
+	defer apiServersMu.Unlock()
+	if s, ok := apiServers[addr]; ok {
+		s.set(r, d.Root(), d.MachineName)
+		return
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		// another minikube process may serve it
+		klog.Infof("not serving the apiserver of %s: %v", d.MachineName, err)
+		return
+	}
+	s := &apiServer{replicas: map[string]int32{}}
+	s.set(r, d.Root(), d.MachineName)
+	apiServers[addr] = s
+	srv := &http.Server{
+		Handler:           s.handler(),
+		TLSConfig:         &tls.Config{GetCertificate: s.certificate, MinVersion: tls.VersionTLS12},
+		ReadHeaderTimeout: 5 * time.Second,
+		// failed handshakes are how a stopped sandbox answers, they are no news
+		ErrorLog: klog.NewStandardLogger("INFO"),
+	}
+	go func() {
+		if err := srv.ServeTLS(l, "", ""); err != nil {
+			klog.Warningf("apiserver of %s: %v", d.MachineName, err)
+		}
+	}()
+}
+
+// set makes the server answer for a machine
+func (s *apiServer) set(r *command.SandboxRunner, root, node string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runner, s.root, s.node = r, root, node
+}
+
+// sandbox returns the runner and the root of the machine the server answers for, and the name of its node
+func (s *apiServer) sandbox() (*command.SandboxRunner, string, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runner, s.root, s.node
+}
+
+// certificate returns the serving cert of the apiserver, failing the handshake as a stopped apiserver would refuse the connection
+func (s *apiServer) certificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r, root, _ := s.sandbox()
+	if !r.Running() {
+		return nil, fmt.Errorf("the sandbox is not running")
+	}
+	dir := filepath.Join(root, filepath.FromSlash(vmpath.GuestKubernetesCertsDir))
+	cert, err := tls.LoadX509KeyPair(filepath.Join(dir, "apiserver.crt"), filepath.Join(dir, "apiserver.key"))
+	if err != nil {
+		return nil, errors.Wrap(err, "apiserver cert")
+	}
+	return &cert, nil
+}
+
+// handler routes the calls minikube makes, anything else is not found
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, p := range []string{"/healthz", "/livez", "/readyz"} {
+		mux.HandleFunc("GET "+p, func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, "ok")
+		})
+	}
+	mux.HandleFunc("GET /version", func(w http.ResponseWriter, _ *http.Request) {
+		_, root, _ := s.sandbox()
+		v := kubernetesVersion(root)
+		reply(w, version.Info{Major: strconv.FormatUint(v.Major, 10), Minor: strconv.FormatUint(v.Minor, 10), GitVersion: "v" + v.String()})
+	})
+	mux.HandleFunc("GET /api/v1/nodes", func(w http.ResponseWriter, _ *http.Request) {
+		_, _, name := s.sandbox()
+		reply(w, core.NodeList{TypeMeta: meta.TypeMeta{Kind: "NodeList", APIVersion: "v1"}, Items: []core.Node{node(name)}})
+	})
+	mux.HandleFunc("GET /api/v1/nodes/{name}", func(w http.ResponseWriter, req *http.Request) {
+		reply(w, node(req.PathValue("name")))
+	})
+	mux.HandleFunc("GET /api/v1/namespaces/kube-system/pods", func(w http.ResponseWriter, _ *http.Request) {
+		_, _, name := s.sandbox()
+		reply(w, systemPods(name))
+	})
+	mux.HandleFunc("GET /api/v1/namespaces/default/serviceaccounts", func(w http.ResponseWriter, _ *http.Request) {
+		reply(w, core.ServiceAccountList{
+			TypeMeta: meta.TypeMeta{Kind: "ServiceAccountList", APIVersion: "v1"},
+			Items:    []core.ServiceAccount{{ObjectMeta: meta.ObjectMeta{Name: "default", Namespace: "default"}}},
+		})
+	})
+	mux.HandleFunc("GET /apis/storage.k8s.io/v1/storageclasses", func(w http.ResponseWriter, _ *http.Request) {
+		reply(w, storage.StorageClassList{TypeMeta: meta.TypeMeta{Kind: "StorageClassList", APIVersion: "storage.k8s.io/v1"}})
+	})
+	mux.HandleFunc("GET /apis/apps/v1/namespaces/{namespace}/deployments/{name}/scale", func(w http.ResponseWriter, req *http.Request) {
+		reply(w, s.scale(req.PathValue("namespace"), req.PathValue("name"), nil))
+	})
+	mux.HandleFunc("PUT /apis/apps/v1/namespaces/{namespace}/deployments/{name}/scale", func(w http.ResponseWriter, req *http.Request) {
+		var sc autoscaling.Scale
+		if err := json.NewDecoder(req.Body).Decode(&sc); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reply(w, s.scale(req.PathValue("namespace"), req.PathValue("name"), &sc.Spec.Replicas))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(meta.Status{
+			TypeMeta: meta.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   meta.StatusFailure,
+			Message:  fmt.Sprintf("%s %s is not served by the sandbox", req.Method, req.URL.Path),
+			Reason:   meta.StatusReasonNotFound,
+			Code:     http.StatusNotFound,
+		})
+	})
+	return mux
+}
+
+// scale returns the scale of a deployment, setting its replicas first if given. Deployments start with 2 replicas, as CoreDNS does
+func (s *apiServer) scale(namespace, name string, replicas *int32) autoscaling.Scale {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := namespace + "/" + name
+	if replicas != nil {
+		s.replicas[key] = *replicas
+	}
+	n, ok := s.replicas[key]
+	if !ok {
+		n = 2
+	}
+	return autoscaling.Scale{
+		TypeMeta:   meta.TypeMeta{Kind: "Scale", APIVersion: "autoscaling/v1"},
+		ObjectMeta: meta.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       autoscaling.ScaleSpec{Replicas: n},
+		Status:     autoscaling.ScaleStatus{Replicas: n},
+	}
+}
+
+// reply writes an object as JSON
+func reply(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		klog.Warningf("unable to reply: %v", err)
+	}
+}
+
+// kubernetesVersion returns the newest Kubernetes version the sandbox has the binaries of
+func kubernetesVersion(root string) semver.Version {
+	newest := semver.Version{}
+	entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(path.Join(vmpath.GuestPersistentDir, "binaries"))))
+	if err != nil {
+		return newest
+	}
+	for _, e := range entries {
+		if v, err := semver.ParseTolerant(e.Name()); err == nil && v.GT(newest) {
+			newest = v
+		}
+	}
+	return newest
+}
+
+// node returns a node which is Ready, with no pressure on its resources
+func node(name string) core.Node {
+	return core.Node{
+		TypeMeta:   meta.TypeMeta{Kind: "Node", APIVersion: "v1"},
+		ObjectMeta: meta.ObjectMeta{Name: name},
+		Status: core.NodeStatus{
+			Conditions: []core.NodeCondition{
+				{Type: core.NodeReady, Status: core.ConditionTrue},
+				{Type: core.NodeMemoryPressure, Status: core.ConditionFalse},
+				{Type: core.NodeDiskPressure, Status: core.ConditionFalse},
+				{Type: core.NodePIDPressure, Status: core.ConditionFalse},
+				{Type: core.NodeNetworkUnavailable, Status: core.ConditionFalse},
+			},
+		},
+	}
+}
+
+// systemPods returns the pods of the control plane and of the cluster addons minikube waits for, all Running and Ready
+func systemPods(node string) core.PodList {
+	pods := core.PodList{TypeMeta: meta.TypeMeta{Kind: "PodList", APIVersion: "v1"}}
+	labels := []map[string]string{
+		{"component": "etcd"},
+		{"component": "kube-apiserver"},
+		{"component": "kube-controller-manager"},
+		{"component": "kube-scheduler"},
+		{"k8s-app": "kube-dns"},
+		{"k8s-app": "kube-proxy"},
+	}
+	for _, l := range labels {
+		for _, name := range l {
+			pods.Items = append(pods.Items, core.Pod{
+				ObjectMeta: meta.ObjectMeta{Name: name + "-" + node, Namespace: meta.NamespaceSystem, Labels: l},
+				Spec:       core.PodSpec{NodeName: node},
+				Status: core.PodStatus{
+					Phase:      core.PodRunning,
+					Conditions: []core.PodCondition{{Type: core.PodReady, Status: core.ConditionTrue}},
+				},
+			})
+		}
+	}
+	return pods
+}
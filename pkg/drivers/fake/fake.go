@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides a driver whose machines are sandboxes on the host: a directory standing in
+// for the root filesystem, and recorded responses standing in for the commands run on it.
+// It exists to test minikube, and whatever wraps it, end-to-end without a VM or a container.
+package fake
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+const (
+	// DriverName is the name of the driver
+	DriverName = "fake"
+	// ResponsesFile is the file, relative to the store path, holding the responses recorded for all fake machines
+	ResponsesFile = "fake/responses.json"
+
+	rootfsDir = "rootfs"
+	stateFile = "state"
+)
+
+// Driver is a driver whose machines are sandboxes on the host
+type Driver struct {
+	*drivers.BaseDriver
+	*pkgdrivers.CommonDriver
+	KubernetesVersion string
+	// APIServerPort is where the apiserver of a control-plane machine listens, 0 for workers
+	APIServerPort int
+}
+
+// Config is configuration for the fake driver
+type Config struct {
+	MachineName       string
+	StorePath         string
+	IPAddress         string
+	KubernetesVersion string
+	APIServerPort     int
+}
+
+// NewDriver returns a fully configured fake driver
+func NewDriver(c Config) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: c.MachineName,
+			StorePath:   c.StorePath,
+			IPAddress:   c.IPAddress,
+		},
+		KubernetesVersion: c.KubernetesVersion,
+		APIServerPort:     c.APIServerPort,
+	}
+}
+
+// Root returns the directory standing in for the root filesystem of the machine
+func (d *Driver) Root() string {
+	return d.ResolveStorePath(rootfsDir)
+}
+
+// Runner returns the command runner of the machine, answering with the recorded responses.
+// The apiserver of a control-plane machine is served from then on, for as long as minikube runs
+func (d *Driver) Runner() (command.Runner, error) {
+	r := command.NewSandboxRunner(d.Root())
+	r.SetCommandToResponse(Responses)
+	if err := r.LoadResponses(filepath.Join(d.StorePath, filepath.FromSlash(ResponsesFile))); err != nil {
+		return nil, err
+	}
+	d.serveAPI(r)
+	return r, nil
+}
+
+// PreCreateCheck checks that the machine can be created
+func (d *Driver) PreCreateCheck() error {
+	return nil
+}
+
+// Create a host using the driver's config
+func (d *Driver) Create() error {
+	if err := os.MkdirAll(d.Root(), 0755); err != nil {
+		return errors.Wrap(err, "rootfs")
+	}
+	// the processes of the sandbox, pid 1 standing for all, are in a cgroup v2 hierarchy without freezer,
+	// so minikube tells a paused sandbox by the file it leaves in it
+	cgroup := filepath.Join(d.Root(), "proc", "1", "cgroup")
+	if err := os.MkdirAll(filepath.Dir(cgroup), 0755); err != nil {
+		return errors.Wrap(err, "cgroups")
+	}
+	if err := os.WriteFile(cgroup, []byte("0::/\n"), 0644); err != nil {
+		return errors.Wrap(err, "cgroups")
+	}
+	// the sandbox comes with the Kubernetes binaries, as they would otherwise be downloaded
+	if d.KubernetesVersion != "" {
+		dir := filepath.Join(d.Root(), filepath.FromSlash(path.Join(vmpath.GuestPersistentDir, "binaries", d.KubernetesVersion)))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errors.Wrap(err, "binaries")
+		}
+		for _, name := range constants.KubernetesReleaseBinaries {
+			if err := os.WriteFile(filepath.Join(dir, name), nil, 0755); err != nil {
+				return errors.Wrapf(err, "binary %s", name)
+			}
+		}
+	}
+	return d.setState(state.Running)
+}
+
+// DriverName returns the name of the driver
+func (d *Driver) DriverName() string {
+	return DriverName
+}
+
+// GetIP returns an IP or hostname that this host is available at
+func (d *Driver) GetIP() (string, error) {
+	return d.IPAddress, nil
+}
+
+// GetSSHHostname returns hostname for use with ssh
+func (d *Driver) GetSSHHostname() (string, error) {
+	return "", fmt.Errorf("driver does not support ssh commands")
+}
+
+// GetSSHPort returns port for use with ssh
+func (d *Driver) GetSSHPort() (int, error) {
+	return 0, fmt.Errorf("driver does not support ssh commands")
+}
+
+// GetURL returns a Docker URL inside this host
+func (d *Driver) GetURL() (string, error) {
+	return fmt.Sprintf("tcp://%s:2376", d.IPAddress), nil
+}
+
+// GetState returns the state that the host is in (running, stopped, etc)
+func (d *Driver) GetState() (state.State, error) {
+	b, err := os.ReadFile(d.ResolveStorePath(stateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state.Error, constants.ErrMachineMissing
+		}
+		return state.Error, errors.Wrap(err, "state")
+	}
+	s := strings.TrimSpace(string(b))
+	for _, st := range []state.State{state.Running, state.Paused, state.Stopped} {
+		if st.String() == s {
+			return st, nil
+		}
+	}
+	return state.Error, fmt.Errorf("unknown state %q", s)
+}
+
+// Kill stops a host forcefully
+func (d *Driver) Kill() error {
+	return d.Stop()
+}
+
+// Remove a host, including any data which may have been written by it.
+func (d *Driver) Remove() error {
+	klog.Infof("removing sandbox %s", d.Root())
+	if err := os.RemoveAll(d.Root()); err != nil {
+		return errors.Wrap(err, "rootfs")
+	}
+	if err := os.Remove(d.ResolveStorePath(stateFile)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "state")
+	}
+	return nil
+}
+
+// Restart a host
+func (d *Driver) Restart() error {
+	return d.Start()
+}
+
+// Start a host
+func (d *Driver) Start() error {
+	return d.setState(state.Running)
+}
+
+// Stop a host gracefully, which takes down every service of the sandbox
+func (d *Driver) Stop() error {
+	if err := command.NewSandboxRunner(d.Root()).StopServices(); err != nil {
+		return errors.Wrap(err, "services")
+	}
+	return d.setState(state.Stopped)
+}
+
+// RunSSHCommandFromDriver implements direct ssh control to the driver
+func (d *Driver) RunSSHCommandFromDriver() error {
+	return fmt.Errorf("driver does not support ssh commands")
+}
+
+// setState records the state of the machine
+func (d *Driver) setState(s state.State) error {
+	if _, err := os.Stat(d.Root()); err != nil {
+		return constants.ErrMachineMissing
+	}
+	return os.WriteFile(d.ResolveStorePath(stateFile), []byte(s.String()+"\n"), 0644)
+}
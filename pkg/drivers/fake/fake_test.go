@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestDriverLifecycle(t *testing.T) {
+	store := t.TempDir()
+	d := NewDriver(Config{MachineName: "minikube", StorePath: store, IPAddress: "127.0.0.1", KubernetesVersion: "v1.31.2"})
+
+	if _, err := d.GetState(); err != constants.ErrMachineMissing {
+		t.Fatalf("GetState before Create = %v, want %v", err, constants.ErrMachineMissing)
+	}
+	if err := d.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := d.GetState(); err != nil || s != state.Running {
+		t.Fatalf("GetState after Create = %s, %v", s, err)
+	}
+	if _, err := os.Stat(filepath.Join(d.Root(), "var", "lib", "minikube", "binaries", "v1.31.2", "kubeadm")); err != nil {
+		t.Errorf("sandbox does not come with the Kubernetes binaries: %v", err)
+	}
+
+	r, err := d.Runner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "systemctl", "start", "kubelet")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := d.GetState(); err != nil || s != state.Stopped {
+		t.Fatalf("GetState after Stop = %s, %v", s, err)
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "systemctl", "is-active", "--quiet", "kubelet")); err == nil {
+		t.Errorf("kubelet is still active once the machine stopped")
+	}
+
+	if err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.GetState(); err != constants.ErrMachineMissing {
+		t.Errorf("GetState after Remove = %v, want %v", err, constants.ErrMachineMissing)
+	}
+	if err := d.Start(); err == nil {
+		t.Errorf("removed machine was started")
+	}
+}
+
+func TestRunnerResponses(t *testing.T) {
+	store := t.TempDir()
+	d := NewDriver(Config{MachineName: "minikube", StorePath: store})
+	if err := os.MkdirAll(filepath.Join(store, "fake"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(store, filepath.FromSlash(ResponsesFile)), []byte(`{"crio --version": {"stdout": "crio version 1.30.0\n"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := d.Runner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		cmd  *exec.Cmd
+		want string
+	}{
+		{exec.Command("docker", "version", "--format", "{{.Server.Version}}"), "27.3.1\n"},
+		{exec.Command("crio", "--version"), "crio version 1.30.0\n"},
+		{exec.Command("containerd", "--version"), Responses["containerd --version"].Stdout},
+	}
+	for _, tc := range tests {
+		rr, err := r.RunCmd(tc.cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rr.Stdout.String(); got != tc.want {
+			t.Errorf("%s: stdout = %q, want %q", rr.Command(), got, tc.want)
+		}
+	}
+}
+
+func TestAPIServer(t *testing.T) {
+	store := t.TempDir()
+	d := NewDriver(Config{MachineName: "minikube", StorePath: store, KubernetesVersion: "v1.31.2"})
+	if err := d.Create(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := d.Runner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &apiServer{replicas: map[string]int32{}}
+	s.set(r.(*command.SandboxRunner), d.Root(), d.MachineName)
+	srv := httptest.NewServer(s.handler())
+	defer srv.Close()
+
+	do := func(method, path, body string) (int, string) {
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(b)
+	}
+
+	if code, body := do("GET", "/healthz", ""); code != http.StatusOK || body != "ok" {
+		t.Errorf("healthz = %d %q", code, body)
+	}
+	if _, body := do("GET", "/version", ""); !strings.Contains(body, `"gitVersion":"v1.31.2"`) {
+		t.Errorf("version = %s", body)
+	}
+	if _, body := do("GET", "/api/v1/nodes/minikube", ""); !strings.Contains(body, `"type":"Ready","status":"True"`) {
+		t.Errorf("node = %s", body)
+	}
+	if code, _ := do("GET", "/api/v1/namespaces/default/pods", ""); code != http.StatusNotFound {
+		t.Errorf("pods of default = %d, want %d", code, http.StatusNotFound)
+	}
+
+	scale := "/apis/apps/v1/namespaces/kube-system/deployments/coredns/scale"
+	if _, body := do("PUT", scale, `{"spec":{"replicas":1}}`); !strings.Contains(body, `"replicas":1`) {
+		t.Errorf("scale = %s", body)
+	}
+	_, body := do("GET", scale, "")
+	var sc struct {
+		Spec struct {
+			Replicas int32 `json:"replicas"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal([]byte(body), &sc); err != nil {
+		t.Fatal(err)
+	}
+	if sc.Spec.Replicas != 1 {
+		t.Errorf("replicas once scaled = %d, want 1", sc.Spec.Replicas)
+	}
+}
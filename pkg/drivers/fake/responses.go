@@ -0,0 +1,31 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import "k8s.io/minikube/pkg/minikube/command"
+
+// Responses are what every sandbox answers to the commands whose output minikube reads, unless
+// ResponsesFile says otherwise: a machine with all the supported container runtimes installed and room to spare.
+var Responses = map[string]command.Response{
+	"docker version --format {{.Server.Version}}": {Stdout: "27.3.1\n"},
+	"docker info --format {{.CgroupDriver}}":      {Stdout: "cgroupfs\n"},
+	"containerd --version":                        {Stdout: "containerd github.com/containerd/containerd v1.7.23 57f17b0a6295a39009d861b89e3b3b87b005ca27\n"},
+	"crio --version":                              {Stdout: "crio version 1.29.1\n"},
+	"sudo crictl info":                            {Stdout: `{"config":{"containerd":{"runtimes":{"runc":{"options":{"SystemdCgroup":false}}}}}}` + "\n"},
+	`sh -c "df -h /var | awk 'NR==2{print $5}'"`:  {Stdout: "10%\n"},
+	`sh -c "df -BG /var | awk 'NR==2{print $4}'"`: {Stdout: "17G\n"},
+}
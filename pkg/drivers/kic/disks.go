@@ -0,0 +1,234 @@
+/*
+Copyright 2026 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kic
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// loopMajor is the major number of the loop devices of Linux
+const loopMajor = 7
+
+// loopRule is the device cgroup rule letting a node container use the loop devices passed into it once it runs
+var loopRule = fmt.Sprintf("b %d:* rmw", loopMajor)
+
+// ExtraDisk is a sparse file of the host, attached to a loop device passed into the node container
+type ExtraDisk struct {
+	Path   string // the sparse file
+	Device string // the loop device of the host, which has the same path in the container
+}
+
+// extraDisksSupported returns an error unless loop devices of the host can be passed into the node containers
+func extraDisksSupported(ociBin string) error {
+	if runtime.GOOS != "linux" || oci.IsExternalDaemonHost(ociBin) {
+		return fmt.Errorf("extra disks are loop devices of the host, which only a local %s daemon on Linux can pass into its containers", ociBin)
+	}
+	si, err := oci.CachedDaemonInfo(ociBin)
+	if err != nil {
+		return errors.Wrapf(err, "%s info", ociBin)
+	}
+	if si.Rootless {
+		return fmt.Errorf("extra disks are loop devices of the host, which a rootless %s daemon can not pass into its containers", ociBin)
+	}
+	return nil
+}
+
+// createExtraDisks creates the extra disks of a node, before its container to pass them into it
+func (d *Driver) createExtraDisks() error {
+	for i := 0; i < d.NodeConfig.ExtraDisks; i++ {
+		if _, err := d.newDisk(d.NodeConfig.DiskSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newDisk creates a sparse file of sizeMB and attaches it to a free loop device of the host
+func (d *Driver) newDisk(sizeMB int) (ExtraDisk, error) {
+	if err := extraDisksSupported(d.OCIBinary); err != nil {
+		return ExtraDisk{}, err
+	}
+	id := 0
+	for d.diskIndex(pkgdrivers.ExtraDiskPath(d.BaseDriver, id)) != -1 {
+		id++
+	}
+	path := pkgdrivers.ExtraDiskPath(d.BaseDriver, id)
+	// a leftover of a former node would hand its data over to this one
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return ExtraDisk{}, errors.Wrap(err, "remove leftover disk")
+	}
+	if err := pkgdrivers.CreateRawDisk(path, sizeMB); err != nil {
+		return ExtraDisk{}, errors.Wrap(err, "create disk")
+	}
+	dev, err := pkgdrivers.Sudo("losetup", "--find", "--show", path)
+	if err != nil {
+		_ = os.Remove(path)
+		return ExtraDisk{}, errors.Wrap(err, "attach disk")
+	}
+	disk := ExtraDisk{Path: path, Device: strings.TrimSpace(dev)}
+	klog.Infof("attached extra disk %s to %s", disk.Path, disk.Device)
+	d.ExtraDisks = append(d.ExtraDisks, disk)
+	return disk, nil
+}
+
+// diskIndex returns the index of the extra disk of a file or a device, or -1 if there is none
+func (d *Driver) diskIndex(pathOrDevice string) int {
+	for i, disk := range d.ExtraDisks {
+		if disk.Path == pathOrDevice || disk.Device == pathOrDevice {
+			return i
+		}
+	}
+	return -1
+}
+
+// backingFile returns the file a loop device is attached to, or "" if it is free
+func backingFile(device string) string {
+	out, err := pkgdrivers.Sudo("losetup", "--noheadings", "--output", "BACK-FILE", device)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// loopMinor returns the minor number of a loop device, which follows from its name
+func loopMinor(device string) (string, error) {
+	minor := strings.TrimPrefix(device, "/dev/loop")
+	if minor == device || minor == "" {
+		return "", fmt.Errorf("%s is not a loop device", device)
+	}
+	return minor, nil
+}
+
+// attachExtraDisks attaches the extra disks of a node back to their loop devices, which the host detaches on reboot.
+// The devices are the ones the container was created with, so they have to be the same again.
+func (d *Driver) attachExtraDisks() error {
+	for _, disk := range d.ExtraDisks {
+		switch f := backingFile(disk.Device); f {
+		case disk.Path:
+			continue
+		case "":
+		default:
+			return fmt.Errorf("the loop device %s of the disk %s is taken by %s, remove the disk with 'minikube node disk remove'", disk.Device, disk.Path, f)
+		}
+		if _, err := os.Stat(disk.Device); os.IsNotExist(err) {
+			minor, err := loopMinor(disk.Device)
+			if err != nil {
+				return err
+			}
+			if _, err := pkgdrivers.Sudo("mknod", "-m", "0660", disk.Device, "b", fmt.Sprint(loopMajor), minor); err != nil {
+				return errors.Wrapf(err, "create %s", disk.Device)
+			}
+		}
+		if _, err := pkgdrivers.Sudo("losetup", disk.Device, disk.Path); err != nil {
+			return errors.Wrapf(err, "attach disk %s", disk.Path)
+		}
+	}
+	return nil
+}
+
+// passExtraDisks creates the device nodes of the extra disks in the running node container,
+// which only has the ones of the disks it was created or started with
+func (d *Driver) passExtraDisks() error {
+	r := command.NewKICRunner(d.MachineName, d.OCIBinary)
+	for _, disk := range d.ExtraDisks {
+		minor, err := loopMinor(disk.Device)
+		if err != nil {
+			return err
+		}
+		script := fmt.Sprintf("test -b %[1]s || mknod -m 0660 %[1]s b %d %s", disk.Device, loopMajor, minor)
+		if _, err := r.RunCmd(exec.Command("sh", "-c", script)); err != nil {
+			return errors.Wrapf(err, "pass %s", disk.Device)
+		}
+	}
+	return nil
+}
+
+// deleteDisk detaches an extra disk from its loop device and deletes its file
+func (d *Driver) deleteDisk(disk ExtraDisk) error {
+	if backingFile(disk.Device) == disk.Path {
+		if _, err := pkgdrivers.Sudo("losetup", "--detach", disk.Device); err != nil {
+			return errors.Wrapf(err, "detach disk %s", disk.Path)
+		}
+	}
+	if err := os.Remove(disk.Path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "remove disk %s", disk.Path)
+	}
+	if i := d.diskIndex(disk.Path); i != -1 {
+		d.ExtraDisks = append(d.ExtraDisks[:i], d.ExtraDisks[i+1:]...)
+	}
+	return nil
+}
+
+// removeExtraDisks detaches and deletes all the extra disks of a node
+func (d *Driver) removeExtraDisks() error {
+	for len(d.ExtraDisks) > 0 {
+		if err := d.deleteDisk(d.ExtraDisks[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddDisk creates an extra disk of sizeMB and attaches it to the node, at once if it runs, returning its device
+func (d *Driver) AddDisk(sizeMB int) (string, error) {
+	disk, err := d.newDisk(sizeMB)
+	if err != nil {
+		return "", err
+	}
+	if s, err := d.GetState(); err == nil && s == state.Running {
+		if err := d.passExtraDisks(); err != nil {
+			return disk.Device, err
+		}
+	}
+	return disk.Device, nil
+}
+
+// RemoveDisk detaches the extra disk of a device from the node and deletes it
+func (d *Driver) RemoveDisk(device string) error {
+	i := d.diskIndex(device)
+	if i == -1 {
+		return fmt.Errorf("%s is not an extra disk of %s", device, d.MachineName)
+	}
+	if s, err := d.GetState(); err == nil && s == state.Running {
+		r := command.NewKICRunner(d.MachineName, d.OCIBinary)
+		if _, err := r.RunCmd(exec.Command("rm", "-f", device)); err != nil {
+			return errors.Wrapf(err, "remove %s from the node", device)
+		}
+	}
+	return d.deleteDisk(d.ExtraDisks[i])
+}
+
+// Disks returns the devices of the extra disks of the node, in the order they were added
+func (d *Driver) Disks() []string {
+	devices := make([]string, len(d.ExtraDisks))
+	for i, disk := range d.ExtraDisks {
+		devices[i] = disk.Device
+	}
+	return devices
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2026 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kic
+
+import "testing"
+
+func TestLoopMinor(t *testing.T) {
+	tests := []struct {
+		device  string
+		want    string
+		wantErr bool
+	}{
+		{device: "/dev/loop0", want: "0"},
+		{device: "/dev/loop12", want: "12"},
+		{device: "/dev/loop", wantErr: true},
+		{device: "/dev/sdb", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := loopMinor(tc.device)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("loopMinor(%q) = %q, %v, expected %q, error %v", tc.device, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestDiskIndex(t *testing.T) {
+	d := &Driver{ExtraDisks: []ExtraDisk{{Path: "/store/m-0.rawdisk", Device: "/dev/loop3"}, {Path: "/store/m-1.rawdisk", Device: "/dev/loop5"}}}
+	for pathOrDevice, want := range map[string]int{"/dev/loop5": 1, "/store/m-0.rawdisk": 0, "/dev/loop4": -1} {
+		if got := d.diskIndex(pathOrDevice); got != want {
+			t.Errorf("diskIndex(%q) = %d, expected %d", pathOrDevice, got, want)
+		}
+	}
+}
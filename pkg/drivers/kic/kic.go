@@ -56,7 +56,8 @@ type Driver struct {
 	URL        string
 	exec       command.Runner
 	NodeConfig Config
-	OCIBinary  string // docker,podman
+	OCIBinary  string      // docker,podman
+	ExtraDisks []ExtraDisk // extra disks of the node, in the order they were added
 }
 
 // NewDriver returns a fully configured Kic driver
@@ -74,7 +75,7 @@ func NewDriver(c Config) *Driver {
 }
 
 // Create a host using the driver's config
-func (d *Driver) Create() error {
+func (d *Driver) Create() (err error) {
 	ctx := context.Background()
 	params := oci.CreateParams{
 		Mounts:        d.NodeConfig.Mounts,
@@ -181,6 +182,23 @@ func (d *Driver) Create() error {
 		return errors.Wrap(err, "setting up container node")
 	}
 
+	// the loop devices of the extra disks have to exist before the container, to be passed into it
+	if err := d.createExtraDisks(); err != nil {
+		return errors.Wrap(err, "creating extra disks")
+	}
+	defer func() {
+		if err != nil {
+			if rerr := d.removeExtraDisks(); rerr != nil {
+				klog.Warningf("failed to remove the extra disks of %s: %v", d.MachineName, rerr)
+			}
+		}
+	}()
+	params.Devices = d.Disks()
+	// the disks were created above, so the daemon can pass them in
+	if d.NodeConfig.ExtraDisks > 0 {
+		params.DeviceRules = []string{loopRule}
+	}
+
 	var waitForPreload sync.WaitGroup
 	waitForPreload.Add(1)
 	var pErr error
@@ -376,7 +394,11 @@ func (d *Driver) Remove() error {
 			return errors.Wrap(err, "stuck delete")
 		}
 		if strings.Contains(err.Error(), "No such container:") {
-			return nil // nothing was found to delete.
+			// nothing was found to delete, but the extra disks outlive the container
+			if err := d.removeExtraDisks(); err != nil {
+				klog.Warningf("failed to remove the extra disks of %s: %v", d.MachineName, err)
+			}
+			return nil
 		}
 
 	}
@@ -386,6 +408,10 @@ func (d *Driver) Remove() error {
 		return fmt.Errorf("expected no container ID be found for %q after delete. but got %q", d.MachineName, id)
 	}
 
+	if err := d.removeExtraDisks(); err != nil {
+		klog.Warningf("failed to remove the extra disks of %s: %v", d.MachineName, err)
+	}
+
 	if err := oci.RemoveNetwork(d.OCIBinary, d.NodeConfig.ClusterName); err != nil {
 		klog.Warningf("failed to remove network (which might be okay) %s: %v", d.NodeConfig.ClusterName, err)
 	}
@@ -412,6 +438,9 @@ func (d *Driver) Restart() error {
 
 // Start an already created kic container
 func (d *Driver) Start() error {
+	if err := d.attachExtraDisks(); err != nil {
+		return errors.Wrap(err, "attaching extra disks")
+	}
 	if err := oci.StartContainer(d.NodeConfig.OCIBinary, d.MachineName); err != nil {
 		oci.LogContainerDebug(d.OCIBinary, d.MachineName)
 		if _, err := oci.DaemonInfo(d.OCIBinary); err != nil {
@@ -440,7 +469,7 @@ func (d *Driver) Start() error {
 
 		return errors.Wrapf(oci.ErrExitedUnexpectedly, "container name %q: log: %s", d.MachineName, excerpt)
 	}
-	return nil
+	return d.passExtraDisks()
 }
 
 // Stop a host gracefully, including any containers that we are managing.
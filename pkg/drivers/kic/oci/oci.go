@@ -200,6 +200,8 @@ func CreateContainerNode(p CreateParams) error { //nolint to suppress cyclomatic
 		runArgs = append(runArgs, "--device", "/dev/kfd", "--device", "/dev/dri", "--group-add", "video", "--group-add", "render")
 	}
 
+	runArgs = append(runArgs, deviceArgs(p.Devices, p.DeviceRules)...)
+
 	memcgSwap := hasMemorySwapCgroup()
 	memcg := HasMemoryCgroup()
 
@@ -286,6 +288,18 @@ func CreateContainerNode(p CreateParams) error { //nolint to suppress cyclomatic
 	return nil
 }
 
+// deviceArgs returns the arguments passing devices of the host into a container, and allowing it to use the devices of the cgroup rules
+func deviceArgs(devices, rules []string) []string {
+	var args []string
+	for _, d := range devices {
+		args = append(args, "--device", d)
+	}
+	for _, r := range rules {
+		args = append(args, "--device-cgroup-rule", r)
+	}
+	return args
+}
+
 // CreateContainer creates a container with "docker/podman run"
 func createContainer(ociBin string, image string, opts ...createOpt) error {
 	o := &createOpts{}
@@ -18,6 +18,7 @@ package oci
 
 import (
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -142,3 +143,14 @@ func TestDaemonHost(t *testing.T) {
 		}
 	}
 }
+
+func TestDeviceArgs(t *testing.T) {
+	if got := deviceArgs(nil, nil); len(got) != 0 {
+		t.Errorf("deviceArgs(nil, nil) = %v, expected none", got)
+	}
+	got := deviceArgs([]string{"/dev/loop3", "/dev/loop4"}, []string{"b 7:* rmw"})
+	want := []string{"--device", "/dev/loop3", "--device", "/dev/loop4", "--device-cgroup-rule", "b 7:* rmw"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("deviceArgs() = %v, expected %v", got, want)
+	}
+}
@@ -62,6 +62,8 @@ type CreateParams struct {
 	Network       string            // network name that the container will attach to
 	IP            string            // static IP to assign the container in the cluster network
 	GPUs          string            // add GPU devices to the container
+	Devices       []string          // devices of the host to pass into the container
+	DeviceRules   []string          // device cgroup rules of the container, for devices passed into it once it runs
 }
 
 // createOpt is an option for Create
@@ -70,4 +70,6 @@ type Config struct {
 	ExtraArgs         []string          // a list of any extra option to pass to oci binary during creation time, for example --expose 8080...
 	ListenAddress     string            // IP Address to listen to
 	GPUs              string            // add GPU devices to the container
+	ExtraDisks        int               // number of extra disks, loop devices of the host passed into the container
+	DiskSize          int               // size of each extra disk in MB
 }
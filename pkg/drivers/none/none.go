@@ -132,7 +132,7 @@ func (d *Driver) GetState() (state.State, error) {
 	}
 
 	// Confusing logic, as libmachine.Stop will loop until the state == Stopped
-	ast, err := kverify.APIServerStatus(d.exec, hostname, port)
+	ast, err := kverify.APIServerStatus(d.exec, d.BaseDriver.MachineName, hostname, port)
 	if err != nil {
 		return ast, err
 	}
@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nspawn
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/network"
+)
+
+// firstSubnet is the first subnet tried for the bridge of a cluster, the docker driver starts at 192.168.49.0
+const firstSubnet = "192.168.94.0"
+
+// bridgeName returns the name of the bridge of the cluster, within the 15 characters allowed for interfaces
+func bridgeName(cluster string) string {
+	name := "mk-" + cluster
+	if len(name) <= 15 {
+		return name
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(cluster))
+	return fmt.Sprintf("mk-%08x", h.Sum32())
+}
+
+// nodeIP returns the IP of the node with the given ID on the network with the gateway, .2 for the first node
+func nodeIP(gateway net.IP, id int) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	copy(ip, gateway.To4())
+	ip[3] += byte(id)
+	return ip
+}
+
+// bridgeAddress returns the address of the host on the bridge, if the bridge exists
+func bridgeAddress(bridge string) (*net.IPNet, error) {
+	iface, err := net.InterfaceByName(bridge)
+	if err != nil {
+		return nil, nil
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addrs {
+		if ipnet, ok := a.(*net.IPNet); ok && ipnet.IP.To4() != nil {
+			return ipnet, nil
+		}
+	}
+	return nil, fmt.Errorf("bridge %s has no IPv4 address", bridge)
+}
+
+// ensureBridge creates the bridge of the cluster, unless another node of the cluster already did. The first time,
+// it picks a free subnet and assigns the IP of the node on it, which are kept for the lifetime of the node: a bridge
+// gone with a reboot of the host is created again on the same subnet.
+func (d *Driver) ensureBridge() error {
+	addr, err := bridgeAddress(d.Bridge)
+	if err != nil {
+		return err
+	}
+	if addr == nil {
+		gateway, prefix := d.Gateway, d.Prefix
+		if gateway == "" {
+			subnet, err := network.FreeSubnet(firstSubnet, 9, 20)
+			if err != nil {
+				return errors.Wrap(err, "free subnet")
+			}
+			gateway, prefix = subnet.Gateway, subnet.Prefix
+		}
+		klog.Infof("creating bridge %s on %s/%d", d.Bridge, gateway, prefix)
+		steps := [][]string{
+			{"ip", "link", "add", d.Bridge, "type", "bridge"},
+			{"ip", "addr", "add", fmt.Sprintf("%s/%d", gateway, prefix), "dev", d.Bridge},
+			{"ip", "link", "set", d.Bridge, "up"},
+		}
+		for _, s := range steps {
+			if _, err := pkgdrivers.Sudo(s...); err != nil {
+				return err
+			}
+		}
+		if addr, err = bridgeAddress(d.Bridge); err != nil || addr == nil {
+			return fmt.Errorf("bridge %s was not created: %v", d.Bridge, err)
+		}
+	}
+	if d.Gateway != "" && !addr.IP.Equal(net.ParseIP(d.Gateway)) {
+		return fmt.Errorf("bridge %s is on %s, while %s was created on %s/%d", d.Bridge, addr, d.MachineName, d.Gateway, d.Prefix)
+	}
+	if err := d.ensureNAT(addr); err != nil {
+		return errors.Wrap(err, "nat")
+	}
+
+	if d.Gateway == "" {
+		prefix, _ := addr.Mask.Size()
+		d.Gateway = addr.IP.String()
+		d.Prefix = prefix
+		d.IPAddress = nodeIP(addr.IP, d.NodeID).String()
+	}
+	return nil
+}
+
+// natRules returns the iptables rules letting the containers on the bridge reach out of the host
+func (d *Driver) natRules(addr *net.IPNet) [][]string {
+	subnet := (&net.IPNet{IP: addr.IP.Mask(addr.Mask), Mask: addr.Mask}).String()
+	return [][]string{
+		{"-t", "nat", "POSTROUTING", "-s", subnet, "!", "-o", d.Bridge, "-j", "MASQUERADE"},
+		{"-t", "filter", "FORWARD", "-i", d.Bridge, "-j", "ACCEPT"},
+		{"-t", "filter", "FORWARD", "-o", d.Bridge, "-j", "ACCEPT"},
+	}
+}
+
+// ensureNAT enables forwarding and adds the rules of natRules which are missing
+func (d *Driver) ensureNAT(addr *net.IPNet) error {
+	if _, err := pkgdrivers.Sudo("sysctl", "-w", "net.ipv4.ip_forward=1"); err != nil {
+		return err
+	}
+	for _, r := range d.natRules(addr) {
+		table, chain, rule := r[:2], r[2], r[3:]
+		check := append(append(append([]string{"iptables"}, table...), "-C", chain), rule...)
+		if _, err := pkgdrivers.Sudo(check...); err == nil {
+			continue
+		}
+		add := append(append(append([]string{"iptables"}, table...), "-A", chain), rule...)
+		if _, err := pkgdrivers.Sudo(add...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeBridge removes the bridge and its rules once no container of the cluster is attached to it anymore
+func (d *Driver) removeBridge() error {
+	addr, err := bridgeAddress(d.Bridge)
+	if err != nil || addr == nil {
+		return err
+	}
+	ports, err := os.ReadDir(filepath.Join("/sys/class/net", d.Bridge, "brif"))
+	if err == nil && len(ports) > 0 {
+		klog.Infof("bridge %s is still used by %d containers", d.Bridge, len(ports))
+		return nil
+	}
+	for _, r := range d.natRules(addr) {
+		table, chain, rule := r[:2], r[2], r[3:]
+		del := append(append(append([]string{"iptables"}, table...), "-D", chain), rule...)
+		if out, err := pkgdrivers.Sudo(del...); err != nil {
+			klog.Warningf("removing rule %s: %v", strings.Join(r, " "), out)
+		}
+	}
+	klog.Infof("removing bridge %s", d.Bridge)
+	_, err = pkgdrivers.Sudo("ip", "link", "delete", d.Bridge)
+	return err
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nspawn
+
+import (
+	"net"
+	"testing"
+)
+
+func TestBridgeName(t *testing.T) {
+	tests := []struct {
+		cluster string
+		want    string
+	}{
+		{"minikube", "mk-minikube"},
+		{"multinode-1", "mk-multinode-1"},
+		{"a-very-long-profile-name", "mk-64ec03e4"},
+	}
+	for _, tc := range tests {
+		got := bridgeName(tc.cluster)
+		if got != tc.want {
+			t.Errorf("bridgeName(%q) = %q, want %q", tc.cluster, got, tc.want)
+		}
+		if len(got) > 15 {
+			t.Errorf("bridgeName(%q) = %q is longer than 15 characters", tc.cluster, got)
+		}
+	}
+}
+
+func TestNodeIP(t *testing.T) {
+	gateway := net.ParseIP("192.168.94.1")
+	for id, want := range map[int]string{1: "192.168.94.2", 3: "192.168.94.4"} {
+		if got := nodeIP(gateway, id).String(); got != want {
+			t.Errorf("nodeIP(%s, %d) = %s, want %s", gateway, id, got, want)
+		}
+	}
+	if got := gateway.String(); got != "192.168.94.1" {
+		t.Errorf("nodeIP modified the gateway: %s", got)
+	}
+}
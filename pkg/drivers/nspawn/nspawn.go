@@ -0,0 +1,364 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nspawn provides a driver whose machines are systemd-nspawn containers on the host, booting the
+// root filesystem of the kicbase image: nearly the performance of the none driver, but with each node isolated.
+package nspawn
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/ssh"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+const (
+	// DriverName is the name of the driver
+	DriverName = "nspawn"
+
+	rootfsDir      = "rootfs"
+	defaultSSHUser = "docker"
+)
+
+// Driver is a driver whose machines are systemd-nspawn containers
+type Driver struct {
+	*drivers.BaseDriver
+	*pkgdrivers.CommonDriver
+	ClusterName string
+	Image       string // the image the root filesystem is extracted from
+	CPU         int
+	Memory      int
+	NodeID      int
+	Bridge      string // the bridge the containers of the cluster are attached to
+	Gateway     string // the IP of the host on Bridge, picked when the node is created
+	Prefix      int    // the prefix length of the network on Bridge
+}
+
+// Config is configuration for the nspawn driver
+type Config struct {
+	ClusterName string
+	MachineName string
+	StorePath   string
+	Image       string
+	CPU         int
+	Memory      int
+	NodeID      int
+}
+
+// NewDriver returns a fully configured nspawn driver
+func NewDriver(c Config) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: c.MachineName,
+			StorePath:   c.StorePath,
+			SSHUser:     defaultSSHUser,
+			SSHPort:     22,
+		},
+		ClusterName: c.ClusterName,
+		Image:       c.Image,
+		CPU:         c.CPU,
+		Memory:      c.Memory,
+		NodeID:      c.NodeID,
+		Bridge:      bridgeName(c.ClusterName),
+	}
+}
+
+// DriverName returns the name of the driver
+func (d *Driver) DriverName() string {
+	return DriverName
+}
+
+// rootfs returns the directory of the root filesystem of the container
+func (d *Driver) rootfs() string {
+	return d.ResolveStorePath(rootfsDir)
+}
+
+// unit returns the systemd unit the container runs as
+func (d *Driver) unit() string {
+	return "minikube-" + d.MachineName + ".service"
+}
+
+// PreCreateCheck checks that the machine can be created
+func (d *Driver) PreCreateCheck() error {
+	for _, program := range []string{"systemd-nspawn", "machinectl", "systemd-run", "nsenter", "ip", "iptables"} {
+		if _, err := exec.LookPath(program); err != nil {
+			return fmt.Errorf("%s is missing: %v", program, err)
+		}
+	}
+	if _, err := pkgdrivers.Sudo("true"); err != nil {
+		return errors.Wrap(err, "the nspawn driver has no rootless mode, it needs to run sudo without a password")
+	}
+	return nil
+}
+
+// Create a host using the driver's config
+func (d *Driver) Create() error {
+	if err := d.extractRootfs(); err != nil {
+		return errors.Wrap(err, "rootfs")
+	}
+
+	klog.Infof("creating ssh key for %s", d.MachineName)
+	if err := ssh.GenerateSSHKey(d.GetSSHKeyPath()); err != nil {
+		return errors.Wrap(err, "ssh key")
+	}
+	keys := filepath.Join(d.rootfs(), "home", defaultSSHUser, ".ssh", "authorized_keys")
+	if _, err := pkgdrivers.Sudo("install", "-D", "-m", "0644", d.GetSSHKeyPath()+".pub", keys); err != nil {
+		return errors.Wrap(err, "authorized keys")
+	}
+	if _, err := pkgdrivers.Sudo("chroot", d.rootfs(), "chown", "-R", defaultSSHUser+":"+defaultSSHUser, "/home/"+defaultSSHUser+"/.ssh"); err != nil {
+		return errors.Wrap(err, "chown authorized keys")
+	}
+
+	return d.Start()
+}
+
+// extractRootfs extracts the image, downloaded to the cache if needed, into the root filesystem of the container.
+// Extracting it as root keeps the owners and modes of its files.
+func (d *Driver) extractRootfs() error {
+	if err := download.ImageToCache(d.Image); err != nil {
+		return errors.Wrapf(err, "download %s", d.Image)
+	}
+	img, err := tarball.ImageFromPath(download.ImageCachePath(d.Image), nil)
+	if err != nil {
+		return errors.Wrapf(err, "load %s", d.Image)
+	}
+	if err := os.MkdirAll(d.rootfs(), 0755); err != nil {
+		return err
+	}
+
+	klog.Infof("extracting %s to %s", d.Image, d.rootfs())
+	layers := mutate.Extract(img)
+	defer layers.Close()
+	cmd := exec.Command("sudo", "tar", "-x", "-p", "--numeric-owner", "-C", d.rootfs())
+	cmd.Stdin = layers
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "tar: %s", out)
+	}
+	return nil
+}
+
+// GetSSHHostname returns hostname for use with ssh
+func (d *Driver) GetSSHHostname() (string, error) {
+	return d.IPAddress, nil
+}
+
+// GetSSHUsername returns username for use with ssh
+func (d *Driver) GetSSHUsername() string {
+	return defaultSSHUser
+}
+
+// GetIP returns an IP or hostname that this host is available at
+func (d *Driver) GetIP() (string, error) {
+	return d.IPAddress, nil
+}
+
+// GetURL returns a Docker URL inside this host
+func (d *Driver) GetURL() (string, error) {
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(d.IPAddress, "2376")), nil
+}
+
+// GetState returns the state that the host is in (running, stopped, etc)
+func (d *Driver) GetState() (state.State, error) {
+	if _, err := os.Stat(d.rootfs()); err != nil {
+		return state.Error, constants.ErrMachineMissing
+	}
+	// is-active exits with an error for anything but active units, what it prints is enough
+	out, _ := exec.Command("systemctl", "is-active", d.unit()).Output()
+	switch strings.TrimSpace(string(out)) {
+	case "active":
+		return state.Running, nil
+	case "activating", "reloading":
+		return state.Starting, nil
+	case "deactivating":
+		return state.Stopping, nil
+	}
+	return state.Stopped, nil
+}
+
+// Start a host
+func (d *Driver) Start() error {
+	if s, err := d.GetState(); err != nil || s == state.Running {
+		return err
+	}
+	// a unit which failed is kept around, under the name the container needs
+	_, _ = pkgdrivers.Sudo("systemctl", "reset-failed", d.unit())
+	// the bridge does not survive reboots of the host
+	if err := d.ensureBridge(); err != nil {
+		return errors.Wrap(err, "bridge")
+	}
+
+	args := []string{"systemd-run", "--unit=" + d.unit(),
+		// the kubelet and the container runtime of the node manage cgroups of their own
+		"--property=Delegate=yes",
+		// so do they devices, and system calls nspawn would filter out otherwise
+		"--property=DevicePolicy=auto",
+		"--setenv=SYSTEMD_SECCOMP=0",
+		"--setenv=SYSTEMD_NSPAWN_UNIFIED_HIERARCHY=1",
+	}
+	if d.CPU > 0 {
+		args = append(args, fmt.Sprintf("--property=CPUQuota=%d%%", d.CPU*100))
+	}
+	if d.Memory > 0 {
+		args = append(args, fmt.Sprintf("--property=MemoryMax=%dM", d.Memory))
+	}
+	args = append(args, "systemd-nspawn", "--quiet", "--keep-unit", "--boot",
+		"--machine="+d.MachineName,
+		"--directory="+d.rootfs(),
+		"--network-bridge="+d.Bridge,
+		"--capability=all",
+		"--private-users=no",
+		"--resolv-conf=copy-host",
+		"--bind-ro=/lib/modules",
+	)
+	if _, err := pkgdrivers.Sudo(args...); err != nil {
+		return errors.Wrap(err, "start container")
+	}
+	if err := d.setupNetwork(); err != nil {
+		return errors.Wrap(err, "network")
+	}
+
+	addr := net.JoinHostPort(d.IPAddress, strconv.Itoa(d.SSHPort))
+	dial := func() error {
+		conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+	klog.Infof("waiting for ssh on %s", addr)
+	return retry.Expo(dial, time.Second, 2*time.Minute)
+}
+
+// leader returns the PID of the init process of the running container
+func (d *Driver) leader() (string, error) {
+	var pid string
+	get := func() error {
+		out, err := pkgdrivers.Sudo("machinectl", "show", d.MachineName, "--property=Leader", "--value")
+		if err != nil {
+			return err
+		}
+		if pid = strings.TrimSpace(out); pid == "" || pid == "0" {
+			return fmt.Errorf("no leader yet")
+		}
+		return nil
+	}
+	if err := retry.Expo(get, 500*time.Millisecond, 30*time.Second); err != nil {
+		return "", err
+	}
+	return pid, nil
+}
+
+// setupNetwork gives the container its IP on the bridge, and the mounts the kubelet expects,
+// as the kicbase entrypoint does with docker
+func (d *Driver) setupNetwork() error {
+	pid, err := d.leader()
+	if err != nil {
+		return errors.Wrap(err, "leader")
+	}
+	nsenter := func(ns string, args ...string) error {
+		_, err := pkgdrivers.Sudo(append([]string{"nsenter", "--target", pid, ns, "--"}, args...)...)
+		return err
+	}
+	steps := [][]string{
+		{"--net", "ip", "addr", "replace", fmt.Sprintf("%s/%d", d.IPAddress, d.Prefix), "dev", "host0"},
+		{"--net", "ip", "link", "set", "host0", "up"},
+		{"--net", "ip", "route", "replace", "default", "via", d.Gateway},
+		{"--mount", "mount", "--make-rshared", "/"},
+	}
+	for _, s := range steps {
+		if err := nsenter(s[0], s[1:]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop a host gracefully
+func (d *Driver) Stop() error {
+	if s, err := d.GetState(); err != nil || s == state.Stopped {
+		return err
+	}
+	if _, err := pkgdrivers.Sudo("machinectl", "poweroff", d.MachineName); err != nil {
+		return errors.Wrap(err, "poweroff")
+	}
+	stopped := func() error {
+		s, err := d.GetState()
+		if err != nil {
+			return err
+		}
+		if s != state.Stopped {
+			return fmt.Errorf("%s is %s", d.MachineName, s)
+		}
+		return nil
+	}
+	if err := retry.Expo(stopped, time.Second, 90*time.Second); err != nil {
+		klog.Warningf("%s did not power off, killing it: %v", d.MachineName, err)
+		return d.Kill()
+	}
+	return nil
+}
+
+// Kill stops a host forcefully
+func (d *Driver) Kill() error {
+	if s, err := d.GetState(); err != nil || s == state.Stopped {
+		return err
+	}
+	if _, err := pkgdrivers.Sudo("machinectl", "terminate", d.MachineName); err != nil {
+		return errors.Wrap(err, "terminate")
+	}
+	_, _ = pkgdrivers.Sudo("systemctl", "reset-failed", d.unit())
+	return nil
+}
+
+// Restart a host
+func (d *Driver) Restart() error {
+	if err := d.Stop(); err != nil {
+		return err
+	}
+	return d.Start()
+}
+
+// Remove a host, including any data which may have been written by it.
+func (d *Driver) Remove() error {
+	if err := d.Kill(); err != nil && err != constants.ErrMachineMissing {
+		klog.Warningf("killing %s failed, will continue with remove anyways: %v", d.MachineName, err)
+	}
+	if _, err := pkgdrivers.Sudo("rm", "-rf", d.rootfs()); err != nil {
+		return errors.Wrap(err, "rootfs")
+	}
+	return d.removeBridge()
+}
+
+// RunSSHCommandFromDriver implements direct ssh control to the driver
+func (d *Driver) RunSSHCommandFromDriver() error {
+	return fmt.Errorf("driver does not support RunSSHCommandFromDriver commands")
+}
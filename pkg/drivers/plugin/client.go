@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/docker/machine/libmachine/drivers"
+	rpcdriver "github.com/docker/machine/libmachine/drivers/rpc"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/docker/machine/libmachine/version"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// Driver is the drivers.Driver of a plugin, whose calls are forwarded to the plugin, which is started on the first one
+type Driver struct {
+	path string
+
+	mu     sync.Mutex
+	client *rpc.Client
+	err    error
+}
+
+// NewDriver returns the driver of the plugin at path
+func NewDriver(path string) *Driver {
+	return &Driver{path: path}
+}
+
+// stdio is the stdin and stdout of a plugin, over which it serves its driver
+type stdio struct {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+// Close closes both the stdin and stdout of the plugin
+func (s stdio) Close() error {
+	werr := s.WriteCloser.Close()
+	if err := s.ReadCloser.Close(); err != nil {
+		return err
+	}
+	return werr
+}
+
+// connect starts the plugin once, and returns the client of its driver
+func (d *Driver) connect() (*rpc.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client != nil || d.err != nil {
+		return d.client, d.err
+	}
+
+	cmd := exec.Command(d.path, ServeCommand)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", APIVersionEnv, APIVersion))
+	cmd.Stderr = klogWriter{d.path}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		d.err = errors.Wrap(err, "stdin")
+		return nil, d.err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		d.err = errors.Wrap(err, "stdout")
+		return nil, d.err
+	}
+	klog.Infof("starting driver plugin %s", d.path)
+	if err := cmd.Start(); err != nil {
+		d.err = errors.Wrapf(err, "starting driver plugin %s", d.path)
+		return nil, d.err
+	}
+	// the plugin exits once minikube closes its stdin, at the latest when minikube exits
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			klog.Warningf("driver plugin %s exited: %v", d.path, err)
+		}
+	}()
+
+	client, err := newClient(stdio{stdout, stdin})
+	if err != nil {
+		d.err = errors.Wrapf(err, "driver plugin %s", d.path)
+		return nil, d.err
+	}
+	d.client = client
+	return client, nil
+}
+
+// newClient returns the client of a driver served over conn, once it checked the driver speaks the libmachine API of minikube
+func newClient(conn io.ReadWriteCloser) (*rpc.Client, error) {
+	client := rpc.NewClient(conn)
+	var v int
+	if err := client.Call(rpcdriver.RPCServiceNameV1+rpcdriver.GetVersionMethod, struct{}{}, &v); err != nil {
+		client.Close()
+		return nil, errors.Wrap(err, "get version")
+	}
+	if v != version.APIVersion {
+		client.Close()
+		return nil, fmt.Errorf("driver uses version %d of the libmachine API, minikube uses version %d", v, version.APIVersion)
+	}
+	return client, nil
+}
+
+// call calls a method of the driver of the plugin
+func (d *Driver) call(method string, args interface{}, reply interface{}) error {
+	client, err := d.connect()
+	if err != nil {
+		return err
+	}
+	return client.Call(rpcdriver.RPCServiceNameV1+method, args, reply)
+}
+
+// stringCall calls a method of the driver of the plugin which returns a string
+func (d *Driver) stringCall(method string) (string, error) {
+	var s string
+	err := d.call(method, struct{}{}, &s)
+	return s, err
+}
+
+// Close stops the plugin
+func (d *Driver) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client == nil {
+		return nil
+	}
+	err := d.client.Close()
+	d.client = nil
+	return err
+}
+
+// MarshalJSON returns the config of the driver, as saved in the machine config
+func (d *Driver) MarshalJSON() ([]byte, error) {
+	var data []byte
+	err := d.call(rpcdriver.GetConfigRawMethod, struct{}{}, &data)
+	return data, err
+}
+
+// UnmarshalJSON sets the config of the driver, as saved in the machine config
+func (d *Driver) UnmarshalJSON(data []byte) error {
+	return d.call(rpcdriver.SetConfigRawMethod, data, nil)
+}
+
+// Create a host using the driver's config
+func (d *Driver) Create() error {
+	return d.call(rpcdriver.CreateMethod, struct{}{}, nil)
+}
+
+// DriverName returns the name of the driver
+func (d *Driver) DriverName() string {
+	name, err := d.stringCall(rpcdriver.DriverNameMethod)
+	if err != nil {
+		klog.Warningf("driver plugin %s: driver name: %v", d.path, err)
+	}
+	return name
+}
+
+// GetCreateFlags returns the mcnflag.Flag slice representing the flags that can be set, their descriptions and defaults
+func (d *Driver) GetCreateFlags() []mcnflag.Flag {
+	var flags []mcnflag.Flag
+	if err := d.call(rpcdriver.GetCreateFlagsMethod, struct{}{}, &flags); err != nil {
+		klog.Warningf("driver plugin %s: create flags: %v", d.path, err)
+	}
+	return flags
+}
+
+// GetIP returns an IP or hostname that this host is available at
+func (d *Driver) GetIP() (string, error) {
+	return d.stringCall(rpcdriver.GetIPMethod)
+}
+
+// GetMachineName returns the name of the machine
+func (d *Driver) GetMachineName() string {
+	name, err := d.stringCall(rpcdriver.GetMachineNameMethod)
+	if err != nil {
+		klog.Warningf("driver plugin %s: machine name: %v", d.path, err)
+	}
+	return name
+}
+
+// GetSSHHostname returns hostname for use with ssh
+func (d *Driver) GetSSHHostname() (string, error) {
+	return d.stringCall(rpcdriver.GetSSHHostnameMethod)
+}
+
+// GetSSHKeyPath returns key path for use with ssh
+func (d *Driver) GetSSHKeyPath() string {
+	p, err := d.stringCall(rpcdriver.GetSSHKeyPathMethod)
+	if err != nil {
+		klog.Warningf("driver plugin %s: ssh key path: %v", d.path, err)
+	}
+	return p
+}
+
+// GetSSHPort returns port for use with ssh
+func (d *Driver) GetSSHPort() (int, error) {
+	var port int
+	err := d.call(rpcdriver.GetSSHPortMethod, struct{}{}, &port)
+	return port, err
+}
+
+// GetSSHUsername returns username for use with ssh
+func (d *Driver) GetSSHUsername() string {
+	user, err := d.stringCall(rpcdriver.GetSSHUsernameMethod)
+	if err != nil {
+		klog.Warningf("driver plugin %s: ssh username: %v", d.path, err)
+	}
+	return user
+}
+
+// GetURL returns a Docker compatible host URL for connecting to this host
+func (d *Driver) GetURL() (string, error) {
+	return d.stringCall(rpcdriver.GetURLMethod)
+}
+
+// GetState returns the state that the host is in (running, stopped, etc)
+func (d *Driver) GetState() (state.State, error) {
+	var s state.State
+	err := d.call(rpcdriver.GetStateMethod, struct{}{}, &s)
+	return s, err
+}
+
+// Kill stops a host forcefully
+func (d *Driver) Kill() error {
+	return d.call(rpcdriver.KillMethod, struct{}{}, nil)
+}
+
+// PreCreateCheck allows for pre-create operations to make sure a driver is ready for creation
+func (d *Driver) PreCreateCheck() error {
+	return d.call(rpcdriver.PreCreateCheckMethod, struct{}{}, nil)
+}
+
+// Remove a host
+func (d *Driver) Remove() error {
+	return d.call(rpcdriver.RemoveMethod, struct{}{}, nil)
+}
+
+// Restart a host
+func (d *Driver) Restart() error {
+	return d.call(rpcdriver.RestartMethod, struct{}{}, nil)
+}
+
+// SetConfigFromFlags configures the driver with the object that was returned by RegisterCreateFlags
+func (d *Driver) SetConfigFromFlags(opts drivers.DriverOptions) error {
+	return d.call(rpcdriver.SetConfigFromFlagsMethod, &opts, nil)
+}
+
+// Start a host
+func (d *Driver) Start() error {
+	return d.call(rpcdriver.StartMethod, struct{}{}, nil)
+}
+
+// Stop a host gracefully
+func (d *Driver) Stop() error {
+	return d.call(rpcdriver.StopMethod, struct{}{}, nil)
+}
+
+// klogWriter logs the stderr of a plugin
+type klogWriter struct {
+	path string
+}
+
+// Write logs a chunk of the stderr of a plugin
+func (w klogWriter) Write(p []byte) (int, error) {
+	klog.Infof("(%s) %s", w.path, p)
+	return len(p), nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin implements out-of-tree driver plugins: minikube-driver-<name> executables, discovered on the PATH or in ~/.minikube/drivers,
+// which describe themselves over stdio and serve the libmachine driver API over their stdin and stdout.
+//
+// A plugin is run with one of these arguments:
+//
+//	info    prints its Info as JSON
+//	status  prints its Status on the host as JSON
+//	serve   serves the drivers.Driver RPC API of libmachine over its stdin and stdout, until its stdin is closed
+//
+// Plugins written in Go implement all of them by calling Serve from their main function.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
+
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+const (
+	// APIVersion is the version of the plugin protocol, which plugins declare in their Info
+	APIVersion = 1
+	// BinaryPrefix is the prefix of the name of the executables of plugins, followed by the name of their driver
+	BinaryPrefix = "minikube-driver-"
+	// APIVersionEnv is the environment variable which tells plugins the version of the protocol minikube speaks
+	APIVersionEnv = "MINIKUBE_DRIVER_API_VERSION"
+
+	// InfoCommand makes a plugin print its Info
+	InfoCommand = "info"
+	// StatusCommand makes a plugin print its Status
+	StatusCommand = "status"
+	// ServeCommand makes a plugin serve its driver over its stdin and stdout
+	ServeCommand = "serve"
+)
+
+// queryTimeout is how long a plugin may take to print its Info or Status
+var queryTimeout = 10 * time.Second
+
+// Info describes the driver of a plugin
+type Info struct {
+	// APIVersion is the version of the plugin protocol the plugin speaks
+	APIVersion int
+	// Name is the name of the driver, which has to match the one of the executable
+	Name string
+	// Alias are other names of the driver
+	Alias []string `json:",omitempty"`
+	// Priority is how the driver is prioritized when minikube selects one: Experimental, Discouraged, Deprecated, Fallback, Default or Preferred
+	Priority string
+	// Default is whether the driver may be selected without being asked for
+	Default bool
+	// Capabilities are the features of minikube the driver supports, such as "mount" or "multi-node"
+	Capabilities registry.Capabilities `json:",omitempty"`
+}
+
+// Status is the status of the driver of a plugin on the host, as in registry.State
+type Status struct {
+	Installed        bool
+	Healthy          bool
+	Running          bool
+	NeedsImprovement bool
+	Error            string `json:",omitempty"`
+	Reason           string `json:",omitempty"`
+	Fix              string `json:",omitempty"`
+	Doc              string `json:",omitempty"`
+	Version          string `json:",omitempty"`
+}
+
+// MachineConfig is the config of a machine, which minikube sets on the driver of a plugin as JSON, as libmachine does.
+// Drivers get it by embedding *drivers.BaseDriver and declaring the other fields they support.
+type MachineConfig struct {
+	*drivers.BaseDriver
+
+	Boot2DockerURL    string // URL of the minikube ISO
+	CPU               int
+	Memory            int // in MB
+	DiskSize          int // in MB
+	ExtraDisks        int
+	Network           string
+	KubernetesVersion string
+	ContainerRuntime  string
+}
+
+// priorities are the priorities plugins can declare, which cannot be HighlyPreferred as the drivers built into minikube
+var priorities = map[string]registry.Priority{
+	"Experimental": registry.Experimental,
+	"Discouraged":  registry.Discouraged,
+	"Deprecated":   registry.Deprecated,
+	"Fallback":     registry.Fallback,
+	"Default":      registry.Default,
+	"Preferred":    registry.Preferred,
+}
+
+// Binary is the executable of a plugin
+type Binary struct {
+	Name string // name of the driver
+	Path string
+}
+
+// SearchPath returns the directories plugins are searched in: ~/.minikube/drivers, then the PATH
+func SearchPath() []string {
+	return append([]string{localpath.MakeMiniPath("drivers")}, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// Discover returns the plugins found in the given directories, sorted by name. A plugin found in an earlier directory hides the ones of the same name in later ones, as with the PATH.
+func Discover(dirs []string) []Binary {
+	found := map[string]Binary{}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(dir, BinaryPrefix+"*"))
+		if err != nil {
+			continue
+		}
+		for _, m := range matches {
+			name := strings.TrimPrefix(filepath.Base(m), BinaryPrefix)
+			if runtime.GOOS == "windows" {
+				if !strings.HasSuffix(strings.ToLower(name), ".exe") {
+					continue
+				}
+				name = name[:len(name)-len(".exe")]
+			}
+			if name == "" {
+				continue
+			}
+			if _, ok := found[name]; ok {
+				continue
+			}
+			fi, err := os.Stat(m)
+			if err != nil || fi.IsDir() || (runtime.GOOS != "windows" && fi.Mode()&0111 == 0) {
+				continue
+			}
+			found[name] = Binary{Name: name, Path: m}
+		}
+	}
+
+	bins := []Binary{}
+	for _, b := range found {
+		bins = append(bins, b)
+	}
+	sort.Slice(bins, func(i, j int) bool { return bins[i].Name < bins[j].Name })
+	return bins
+}
+
+// QueryInfo runs a plugin to get the Info of its driver, and validates it
+func QueryInfo(b Binary) (Info, error) {
+	var info Info
+	if err := query(b.Path, InfoCommand, &info); err != nil {
+		return info, err
+	}
+	return info, validateInfo(b, info)
+}
+
+// QueryStatus runs a plugin to get the status of its driver on the host
+func QueryStatus(b Binary) registry.State {
+	var st Status
+	if err := query(b.Path, StatusCommand, &st); err != nil {
+		return registry.State{Error: err, Fix: fmt.Sprintf("Check that %s works, or remove it", b.Path)}
+	}
+	return st.State()
+}
+
+// RegistryPriority returns the registry priority of the driver of a plugin
+func (i Info) RegistryPriority() registry.Priority {
+	return priorities[i.Priority]
+}
+
+// State converts the status of a plugin to the state of the registry
+func (s Status) State() registry.State {
+	st := registry.State{
+		Installed:        s.Installed,
+		Healthy:          s.Healthy,
+		Running:          s.Running,
+		NeedsImprovement: s.NeedsImprovement,
+		Reason:           s.Reason,
+		Fix:              s.Fix,
+		Doc:              s.Doc,
+		Version:          s.Version,
+	}
+	if s.Error != "" {
+		st.Error = errors.New(s.Error)
+	}
+	return st
+}
+
+// validateInfo validates the Info a plugin printed
+func validateInfo(b Binary, info Info) error {
+	if info.APIVersion != APIVersion {
+		return fmt.Errorf("plugin %s speaks version %d of the driver plugin protocol, minikube speaks version %d", b.Path, info.APIVersion, APIVersion)
+	}
+	if info.Name != b.Name {
+		return fmt.Errorf("plugin %s is named %q, its executable has to be named %s%s", b.Path, info.Name, BinaryPrefix, info.Name)
+	}
+	if _, ok := priorities[info.Priority]; !ok {
+		known := []string{}
+		for p := range priorities {
+			known = append(known, p)
+		}
+		sort.Strings(known)
+		return fmt.Errorf("plugin %s has priority %q, expected one of %s", b.Path, info.Priority, strings.Join(known, ", "))
+	}
+	return nil
+}
+
+// query runs a plugin with a command, and decodes the JSON it prints
+func query(path string, command string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, command)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", APIVersionEnv, APIVersion))
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return errors.Wrapf(err, "%s %s: %s", path, command, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return errors.Wrapf(err, "%s %s", path, command)
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return errors.Wrapf(err, "decoding the output of %s %s", path, command)
+	}
+	return nil
+}
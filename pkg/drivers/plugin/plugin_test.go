@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/state"
+)
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are found by their .exe extension on windows")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	write := func(dir, name string, perm os.FileMode) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte("#!/bin/sh\n"), perm); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	vmPlatform := write(first, BinaryPrefix+"vmplatform", 0755)
+	write(second, BinaryPrefix+"vmplatform", 0755)
+	other := write(second, BinaryPrefix+"other", 0755)
+	write(second, BinaryPrefix+"notexec", 0644)
+	write(second, "docker-machine-driver-kvm2", 0755)
+
+	got := Discover([]string{first, "", second, filepath.Join(first, "missing")})
+	want := []Binary{{Name: "other", Path: other}, {Name: "vmplatform", Path: vmPlatform}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() = %+v, want %+v", got, want)
+	}
+}
+
+func TestValidateInfo(t *testing.T) {
+	b := Binary{Name: "vmplatform", Path: "/usr/local/bin/minikube-driver-vmplatform"}
+	tests := []struct {
+		info        Info
+		shouldError bool
+	}{
+		{Info{APIVersion: APIVersion, Name: "vmplatform", Priority: "Default"}, false},
+		{Info{APIVersion: APIVersion, Name: "vmplatform", Priority: "Preferred", Alias: []string{"vmp"}, Default: true}, false},
+		{Info{APIVersion: APIVersion + 1, Name: "vmplatform", Priority: "Default"}, true},
+		{Info{APIVersion: APIVersion, Name: "other", Priority: "Default"}, true},
+		{Info{APIVersion: APIVersion, Name: "vmplatform", Priority: "HighlyPreferred"}, true},
+		{Info{APIVersion: APIVersion, Name: "vmplatform"}, true},
+	}
+	for _, tc := range tests {
+		err := validateInfo(b, tc.info)
+		if err != nil && !tc.shouldError {
+			t.Errorf("info %+v failed validation; expected it to pass: %v", tc.info, err)
+		}
+		if err == nil && tc.shouldError {
+			t.Errorf("info %+v passed validation; expected it to fail", tc.info)
+		}
+	}
+}
+
+// testDriver is a driver which keeps the state of its machine in memory
+type testDriver struct {
+	*drivers.BaseDriver
+	CPU    int
+	Memory int
+	State  state.State
+}
+
+func (d *testDriver) Create() error                                  { d.State = state.Running; return nil }
+func (d *testDriver) GetCreateFlags() []mcnflag.Flag                 { return nil }
+func (d *testDriver) DriverName() string                             { return "vmplatform" }
+func (d *testDriver) GetSSHHostname() (string, error)                { return d.IPAddress, nil }
+func (d *testDriver) GetURL() (string, error)                        { return "tcp://" + d.IPAddress + ":2376", nil }
+func (d *testDriver) GetState() (state.State, error)                 { return d.State, nil }
+func (d *testDriver) Kill() error                                    { d.State = state.Stopped; return nil }
+func (d *testDriver) Remove() error                                  { return nil }
+func (d *testDriver) Restart() error                                 { d.State = state.Running; return nil }
+func (d *testDriver) SetConfigFromFlags(drivers.DriverOptions) error { return nil }
+func (d *testDriver) Start() error                                   { d.State = state.Running; return nil }
+func (d *testDriver) Stop() error                                    { d.State = state.Stopped; return nil }
+
+func TestDriverRPC(t *testing.T) {
+	server, conn := net.Pipe()
+	go func() {
+		if err := serveConn(server, &testDriver{BaseDriver: &drivers.BaseDriver{}}); err != nil {
+			t.Errorf("serveConn: %v", err)
+		}
+	}()
+	client, err := newClient(conn)
+	if err != nil {
+		t.Fatalf("newClient: %v", err)
+	}
+	d := &Driver{path: "minikube-driver-vmplatform", client: client}
+	defer d.Close()
+
+	mc := MachineConfig{
+		BaseDriver: &drivers.BaseDriver{MachineName: "minikube-m02", IPAddress: "192.168.39.2", SSHUser: "docker"},
+		CPU:        4,
+		Memory:     8192,
+	}
+	raw, err := json.Marshal(mc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, d); err != nil {
+		t.Fatalf("setting the config of the driver: %v", err)
+	}
+
+	if got := d.GetMachineName(); got != "minikube-m02" {
+		t.Errorf("GetMachineName() = %q, want minikube-m02", got)
+	}
+	if got := d.DriverName(); got != "vmplatform" {
+		t.Errorf("DriverName() = %q, want vmplatform", got)
+	}
+	if err := d.Create(); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if st, err := d.GetState(); err != nil || st != state.Running {
+		t.Errorf("GetState() = %v, %v, want Running", st, err)
+	}
+	if ip, err := d.GetIP(); err != nil || ip != "192.168.39.2" {
+		t.Errorf("GetIP() = %q, %v, want 192.168.39.2", ip, err)
+	}
+	if err := d.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	saved, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("getting the config of the driver: %v", err)
+	}
+	var got testDriver
+	if err := json.Unmarshal(saved, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.CPU != 4 || got.Memory != 8192 || got.State != state.Stopped || got.MachineName != "minikube-m02" {
+		t.Errorf("unexpected saved config: %s", saved)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/rpc"
+	"os"
+
+	"github.com/docker/machine/libmachine/drivers"
+	rpcdriver "github.com/docker/machine/libmachine/drivers/rpc"
+	"github.com/docker/machine/libmachine/log"
+)
+
+// Serve implements a plugin: it prints the info or the status of its driver, or serves a new driver over stdio, depending on its argument
+func Serve(info Info, status func() Status, newDriver func() drivers.Driver) {
+	if len(os.Args) != 2 {
+		fmt.Fprintf(os.Stderr, "usage: %s %s|%s|%s\n", os.Args[0], InfoCommand, StatusCommand, ServeCommand)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case InfoCommand:
+		info.APIVersion = APIVersion
+		printJSON(info)
+	case StatusCommand:
+		printJSON(status())
+	case ServeCommand:
+		// stdout carries the RPCs, anything the driver prints goes to stderr instead
+		conn := stdio{os.Stdin, os.Stdout}
+		os.Stdout = os.Stderr
+		log.SetOutWriter(os.Stderr)
+		log.SetErrWriter(os.Stderr)
+		if err := serveConn(conn, newDriver()); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q, expected %s, %s or %s\n", os.Args[1], InfoCommand, StatusCommand, ServeCommand)
+		os.Exit(2)
+	}
+}
+
+// serveConn serves a driver over conn, until conn is closed
+func serveConn(conn io.ReadWriteCloser, d drivers.Driver) error {
+	sd := rpcdriver.NewRPCServerDriver(d)
+	server := rpc.NewServer()
+	if err := server.RegisterName(rpcdriver.RPCServiceNameV1, sd); err != nil {
+		return err
+	}
+	// the close and heartbeat calls of libmachine block until they are received, as the plugin exits once its stdin is closed
+	go func() {
+		for {
+			select {
+			case <-sd.CloseCh:
+			case <-sd.HeartbeatCh:
+			}
+		}
+	}()
+	server.ServeConn(conn)
+	return nil
+}
+
+// printJSON prints v as JSON on stdout
+func printJSON(v interface{}) {
+	if err := json.NewEncoder(os.Stdout).Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
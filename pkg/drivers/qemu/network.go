@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/network"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+const (
+	// userNetdev is the id of the user-mode network device of the builtin network
+	userNetdev = "user0"
+	// SharedNetworkPrefix is the prefix length of SharedNetworkIP
+	SharedNetworkPrefix = 24
+	// sharedNetworkFile is the systemd-networkd configuration of the interface of the machine on its shared network
+	sharedNetworkFile = "/etc/systemd/network/05-minikube-shared.network"
+)
+
+// userNetworkArgs returns the arguments of the builtin network: a user-mode network device, forwarding the ports
+// of SSH, of docker and the ones given to the machine, and a second network device on the shared network if any
+func (d *Driver) userNetworkArgs() ([]string, error) {
+	hostfwds := []string{
+		fmt.Sprintf("hostfwd=tcp::%d-:22", d.SSHPort),
+		fmt.Sprintf("hostfwd=tcp::%d-:2376", d.EnginePort),
+	}
+	fwds, err := hostForwards(d.ForwardedPorts)
+	if err != nil {
+		return nil, err
+	}
+	hostfwds = append(hostfwds, fwds...)
+
+	args := []string{
+		"-nic", fmt.Sprintf("user,id=%s,model=virtio,%s,hostname=%s", userNetdev, strings.Join(hostfwds, ","), d.GetMachineName()),
+	}
+	if d.SharedNetwork != "" {
+		// every machine of the cluster joins the same multicast group on the loopback interface, which makes up a
+		// network between them which neither needs root nor leaves the host
+		args = append(args,
+			"-device", fmt.Sprintf("virtio-net-pci,netdev=shared0,mac=%s", d.MACAddress),
+			"-netdev", fmt.Sprintf("socket,id=shared0,mcast=%s,localaddr=127.0.0.1", d.SharedNetwork),
+		)
+	}
+	return args, nil
+}
+
+// hostForwards returns the hostfwd options forwarding ports, given as [hostIP:][hostPort:]guestPort[/proto] like
+// the ports published by docker, ranges included. A port with no host port is forwarded from the same port on the host.
+func hostForwards(ports []string) ([]string, error) {
+	hostfwds := []string{}
+	seen := map[nat.PortMapping]bool{}
+	for _, p := range ports {
+		// each spec on its own, to keep the order of the ports given and of the ports of a range
+		mappings, err := nat.ParsePortSpec(p)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse ports")
+		}
+		for _, m := range mappings {
+			if seen[m] {
+				continue
+			}
+			seen[m] = true
+			hostPort := m.Binding.HostPort
+			if hostPort == "" {
+				hostPort = m.Port.Port()
+			}
+			hostfwds = append(hostfwds, fmt.Sprintf("hostfwd=%s:%s:%s-:%s", m.Port.Proto(), m.Binding.HostIP, hostPort, m.Port.Port()))
+		}
+	}
+	return hostfwds, nil
+}
+
+// ForwardPort forwards a TCP port of the host, on the loopback interface, to a port of the running machine
+func (d *Driver) ForwardPort(hostPort, guestPort int) error {
+	if !network.IsBuiltinQEMU(d.Network) {
+		return fmt.Errorf("ports are only forwarded with the builtin network")
+	}
+	return d.humanMonitorCommand(fmt.Sprintf("hostfwd_add %s tcp:127.0.0.1:%d-:%d", userNetdev, hostPort, guestPort))
+}
+
+// configureSharedNetwork sets the IP of the machine on the shared network, which has no DHCP server
+func (d *Driver) configureSharedNetwork() error {
+	if net.ParseIP(d.SharedNetworkIP) == nil {
+		return fmt.Errorf("invalid shared network IP %q", d.SharedNetworkIP)
+	}
+	unit := fmt.Sprintf("[Match]\\nMACAddress=%s\\n\\n[Network]\\nAddress=%s/%d\\nLinkLocalAddressing=no\\n", d.MACAddress, d.SharedNetworkIP, SharedNetworkPrefix)
+	cmd := fmt.Sprintf("printf '%s' | sudo tee %s >/dev/null && sudo networkctl reload && sudo networkctl reconfigure $(ip -o link | awk -F': ' '/%s/ {print $2}')",
+		unit, sharedNetworkFile, d.MACAddress)
+
+	// sshd may take a little longer than its port to be up
+	configure := func() error {
+		_, err := drivers.RunSSHCommandFromDriver(d, cmd)
+		return err
+	}
+	if err := retry.Expo(configure, time.Second, 30*time.Second); err != nil {
+		return errors.Wrap(err, "configure shared network")
+	}
+	log.Infof("IP on the shared network: %s", d.SharedNetworkIP)
+	return nil
+}
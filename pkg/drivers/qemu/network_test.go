@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHostForwards(t *testing.T) {
+	tests := []struct {
+		name  string
+		ports []string
+		want  []string
+	}{
+		{"none", nil, []string{}},
+		{"guest port", []string{"80"}, []string{"hostfwd=tcp::80-:80"}},
+		{"host port", []string{"8080:80"}, []string{"hostfwd=tcp::8080-:80"}},
+		{"host ip", []string{"127.0.0.1:8080:80"}, []string{"hostfwd=tcp:127.0.0.1:8080-:80"}},
+		{"udp", []string{"5353:53/udp"}, []string{"hostfwd=udp::5353-:53"}},
+		{"several", []string{"30080:30080", "8443:443"}, []string{"hostfwd=tcp::30080-:30080", "hostfwd=tcp::8443-:443"}},
+		{"range", []string{"8000-8002:9000-9002"}, []string{"hostfwd=tcp::8000-:9000", "hostfwd=tcp::8001-:9001", "hostfwd=tcp::8002-:9002"}},
+		{"guest range", []string{"127.0.0.1::5000-5001/udp"}, []string{"hostfwd=udp:127.0.0.1:5000-:5000", "hostfwd=udp:127.0.0.1:5001-:5001"}},
+		{"twice", []string{"8080:80", "8080:80"}, []string{"hostfwd=tcp::8080-:80"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := hostForwards(tc.ports)
+			if err != nil {
+				t.Fatalf("hostForwards(%v): %v", tc.ports, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("hostForwards(%v) mismatch (-want +got):\n%s", tc.ports, diff)
+			}
+		})
+	}
+
+	if _, err := hostForwards([]string{"http"}); err == nil {
+		t.Errorf("hostForwards of an invalid port succeeded")
+	}
+}
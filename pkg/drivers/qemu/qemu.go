@@ -19,7 +19,6 @@ package qemu
 import (
 	"archive/tar"
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
@@ -66,12 +65,14 @@ type Driver struct {
 	FirstQuery bool
 
 	Memory                int
+	BalloonMemory         int // memory left to the machine by its balloon, in MB, or 0 for all of Memory
 	DiskSize              int
 	CPU                   int
 	Program               string
 	BIOS                  bool
 	CPUType               string
 	MachineType           string
+	Accel                 string
 	Firmware              string
 	Display               bool
 	DisplayType           string
@@ -92,6 +93,10 @@ type Driver struct {
 	SocketVMNetPath       string
 	SocketVMNetClientPath string
 	ExtraDisks            int
+	ForwardedPorts        []string // ports forwarded from the host with the builtin network, see hostForwards
+	SharedNetwork         string   // multicast group:port of the network between the machines of a cluster with the builtin network
+	SharedNetworkIP       string   // IP of the machine on SharedNetwork
+	GrowFilesystem        bool     // the disk grew since its filesystem last did, see ResizeDisk
 }
 
 func (d *Driver) GetMachineName() string {
@@ -209,20 +214,8 @@ func (d *Driver) GetState() (state.State, error) {
 		return state.Error, err
 	}
 
-	// RunState is one of:
-	// 'debug', 'inmigrate', 'internal-error', 'io-error', 'paused',
-	// 'postmigrate', 'prelaunch', 'finish-migrate', 'restore-vm',
-	// 'running', 'save-vm', 'shutdown', 'suspended', 'watchdog',
-	// 'guest-panicked'
-	switch ret["status"] {
-	case "running":
-		return state.Running, nil
-	case "paused":
-		return state.Paused, nil
-	case "shutdown":
-		return state.Stopped, nil
-	}
-	return state.None, nil
+	status, _ := ret["status"].(string)
+	return runState(status), nil
 }
 
 func (d *Driver) PreCreateCheck() error {
@@ -413,9 +406,16 @@ func (d *Driver) Start() error {
 	}
 
 	// hardware acceleration is important, it increases performance by 10x
-	accel := hardwareAcceleration()
-	if accel != "" {
+	accel := d.Accel
+	if accel == "" {
+		accel = hardwareAcceleration()
+	}
+	if IsEmulated(accel) {
+		klog.Infof("Using TCG software emulation, which is much slower than hardware acceleration")
+	} else if accel != "" {
 		klog.Infof("Using %s for hardware acceleration", accel)
+	}
+	if accel != "" {
 		startCmd = append(startCmd,
 			"-accel", accel)
 	}
@@ -435,13 +435,17 @@ func (d *Driver) Start() error {
 	startCmd = append(startCmd,
 		"-qmp", fmt.Sprintf("unix:%s,server,nowait", d.monitorPath()),
 		"-pidfile", d.pidfilePath(),
+		// the balloon gives memory back to the host, see SetMemory
+		"-device", "virtio-balloon-pci",
 	)
 
 	switch d.Network {
 	case "builtin", "user":
-		startCmd = append(startCmd,
-			"-nic", fmt.Sprintf("user,model=virtio,hostfwd=tcp::%d-:22,hostfwd=tcp::%d-:2376,hostname=%s", d.SSHPort, d.EnginePort, d.GetMachineName()),
-		)
+		args, err := d.userNetworkArgs()
+		if err != nil {
+			return err
+		}
+		startCmd = append(startCmd, args...)
 	case "socket_vmnet":
 		startCmd = append(startCmd,
 			"-device", fmt.Sprintf("virtio-net-pci,netdev=net0,mac=%s", d.MACAddress), "-netdev", "socket,id=net0,fd=3",
@@ -499,6 +503,12 @@ func (d *Driver) Start() error {
 		return err
 	}
 
+	if d.BalloonMemory != 0 {
+		if err := d.balloon(); err != nil {
+			log.Warnf("Failed to set the memory to %d MB: %v", d.BalloonMemory, err)
+		}
+	}
+
 	switch d.Network {
 	case "builtin", "user":
 		d.IPAddress = "127.0.0.1"
@@ -538,7 +548,33 @@ func (d *Driver) Start() error {
 
 	log.Infof("Waiting for VM to start (ssh -p %d docker@%s)...", d.SSHPort, d.IPAddress)
 
-	return WaitForTCPWithDelay(fmt.Sprintf("%s:%d", d.IPAddress, d.SSHPort), time.Second)
+	if err := WaitForTCPWithDelay(fmt.Sprintf("%s:%d", d.IPAddress, d.SSHPort), time.Second); err != nil {
+		return err
+	}
+	if network.IsBuiltinQEMU(d.Network) && d.SharedNetwork != "" {
+		return d.configureSharedNetwork()
+	}
+	return nil
+}
+
+// Accel returns the accelerator to run a guest of the given architecture with: accel if it is set, else
+// the one of the host if the guest matches it, else TCG, which emulates any architecture in software
+func Accel(accel, arch string) string {
+	if accel != "" {
+		return accel
+	}
+	if arch != "" && arch != runtime.GOARCH {
+		return "tcg"
+	}
+	if a := hardwareAcceleration(); a != "" {
+		return a
+	}
+	return "tcg"
+}
+
+// IsEmulated returns whether guests run with the accelerator are emulated in software
+func IsEmulated(accel string) bool {
+	return accel == "tcg"
 }
 
 func hardwareAcceleration() string {
@@ -771,96 +807,6 @@ func (d *Driver) generateUserdataDisk(userdataFile string) (string, error) {
 	return ccRoot, nil
 }
 
-func (d *Driver) RunQMPCommand(command string) (map[string]interface{}, error) {
-	// connect to monitor
-	conn, err := net.Dial("unix", d.monitorPath())
-	if err != nil {
-		return nil, errors.Wrap(err, "connect")
-	}
-	defer conn.Close()
-
-	// initial QMP response
-	var buf [1024]byte
-	nr, err := conn.Read(buf[:])
-	if err != nil {
-		return nil, errors.Wrap(err, "read initial resp")
-	}
-	type qmpInitialResponse struct {
-		QMP struct {
-			Version struct {
-				QEMU struct {
-					Micro int `json:"micro"`
-					Minor int `json:"minor"`
-					Major int `json:"major"`
-				} `json:"qemu"`
-				Package string `json:"package"`
-			} `json:"version"`
-			Capabilities []string `json:"capabilities"`
-		} `jason:"QMP"`
-	}
-
-	var initialResponse qmpInitialResponse
-	if err := json.Unmarshal(buf[:nr], &initialResponse); err != nil {
-		return nil, errors.Wrap(err, "unmarshal initial resp")
-	}
-
-	// run 'qmp_capabilities' to switch to command mode
-	// { "execute": "qmp_capabilities" }
-	type qmpCommand struct {
-		Command string `json:"execute"`
-	}
-	jsonCommand, err := json.Marshal(qmpCommand{Command: "qmp_capabilities"})
-	if err != nil {
-		return nil, errors.Wrap(err, "marshal qmp_capabilities")
-	}
-	if _, err := conn.Write(jsonCommand); err != nil {
-		return nil, errors.Wrap(err, "write qmp_capabilities")
-	}
-	nr, err = conn.Read(buf[:])
-	if err != nil {
-		return nil, errors.Wrap(err, "read qmp_capabilities resp")
-	}
-	type qmpResponse struct {
-		Return map[string]interface{} `json:"return"`
-	}
-	var response qmpResponse
-	if err := json.Unmarshal(buf[:nr], &response); err != nil {
-		return nil, errors.Wrap(err, "unmarshal qmp_capabilities resp")
-	}
-	// expecting empty response
-	if len(response.Return) != 0 {
-		return nil, fmt.Errorf("qmp_capabilities failed: %v", response.Return)
-	}
-
-	// { "execute": command }
-	jsonCommand, err = json.Marshal(qmpCommand{Command: command})
-	if err != nil {
-		return nil, errors.Wrap(err, "marshal command")
-	}
-	if _, err := conn.Write(jsonCommand); err != nil {
-		return nil, errors.Wrap(err, "write command")
-	}
-	nr, err = conn.Read(buf[:])
-	if err != nil {
-		return nil, errors.Wrap(err, "read command resp")
-	}
-
-	// Sometimes QEMU returns two JSON objects with the first object being the command response
-	// and the second object being an event log (unimportant)
-	firstRespObj := strings.Split(string(buf[:nr]), "\n")[0]
-	if err := json.Unmarshal([]byte(firstRespObj), &response); err != nil {
-		return nil, errors.Wrap(err, "unmarshal command resp")
-	}
-	if strings.HasPrefix(command, "query-") {
-		return response.Return, nil
-	}
-	// non-query commands should return an empty response
-	if len(response.Return) != 0 {
-		return nil, fmt.Errorf("%s failed: %v", command, response.Return)
-	}
-	return response.Return, nil
-}
-
 func WaitForTCPWithDelay(addr string, duration time.Duration) error {
 	for {
 		conn, err := net.Dial("tcp", addr)
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+)
+
+// qmpCommand is a command sent over QMP
+type qmpCommand struct {
+	Command   string      `json:"execute"`
+	Arguments interface{} `json:"arguments,omitempty"`
+}
+
+// qmpMessage is anything QEMU sends over QMP: its greeting, an event, or the response to a command
+type qmpMessage struct {
+	QMP    json.RawMessage `json:"QMP"`
+	Event  string          `json:"event"`
+	Return json.RawMessage `json:"return"`
+	Error  *struct {
+		Class string `json:"class"`
+		Desc  string `json:"desc"`
+	} `json:"error"`
+}
+
+// RunQMPCommand runs a command without arguments over QMP, returning what query commands return
+func (d *Driver) RunQMPCommand(command string) (map[string]interface{}, error) {
+	ret := map[string]interface{}{}
+	if err := d.runQMP(command, nil, &ret); err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(command, "query-") {
+		return ret, nil
+	}
+	// non-query commands should return an empty response
+	if len(ret) != 0 {
+		return nil, fmt.Errorf("%s failed: %v", command, ret)
+	}
+	return ret, nil
+}
+
+// runQMP runs a command over QMP, decoding what it returns into ret unless it is nil
+func (d *Driver) runQMP(command string, arguments interface{}, ret interface{}) error {
+	conn, err := net.Dial("unix", d.monitorPath())
+	if err != nil {
+		return errors.Wrap(err, "connect")
+	}
+	defer conn.Close()
+
+	enc := json.NewEncoder(conn)
+	dec := json.NewDecoder(conn)
+
+	var greeting qmpMessage
+	if err := dec.Decode(&greeting); err != nil {
+		return errors.Wrap(err, "read initial resp")
+	}
+	// run 'qmp_capabilities' to switch to command mode
+	if err := qmpExecute(enc, dec, "qmp_capabilities", nil, nil); err != nil {
+		return err
+	}
+	return qmpExecute(enc, dec, command, arguments, ret)
+}
+
+// qmpExecute sends a command over a QMP connection in command mode and reads its response
+func qmpExecute(enc *json.Encoder, dec *json.Decoder, command string, arguments interface{}, ret interface{}) error {
+	if err := enc.Encode(qmpCommand{Command: command, Arguments: arguments}); err != nil {
+		return errors.Wrapf(err, "write %s", command)
+	}
+	for {
+		var msg qmpMessage
+		if err := dec.Decode(&msg); err != nil {
+			return errors.Wrapf(err, "read %s resp", command)
+		}
+		// events may come before the response, and are unimportant
+		if msg.Event != "" {
+			continue
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("%s failed: %s", command, msg.Error.Desc)
+		}
+		if ret == nil || len(msg.Return) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(msg.Return, ret); err != nil {
+			return errors.Wrapf(err, "unmarshal %s resp", command)
+		}
+		return nil
+	}
+}
+
+// humanMonitorCommand runs a command of the human monitor, for what QMP has no stable command for
+func (d *Driver) humanMonitorCommand(cmdline string) error {
+	var output string
+	if err := d.runQMP("human-monitor-command", map[string]string{"command-line": cmdline}, &output); err != nil {
+		return err
+	}
+	// the commands print nothing unless they fail, which the human monitor reports as output
+	if output = strings.TrimSpace(output); output != "" {
+		return fmt.Errorf("%s failed: %s", cmdline, output)
+	}
+	return nil
+}
+
+// runState returns the state of a machine whose run state, as reported by query-status, is status
+func runState(status string) state.State {
+	switch status {
+	case "running":
+		return state.Running
+	case "paused", "debug", "save-vm", "postmigrate":
+		// the vCPUs are stopped, by the user, a debugger, a snapshot or a migration which left the machine behind
+		return state.Paused
+	case "suspended":
+		// the guest suspended itself to RAM
+		return state.Saved
+	case "prelaunch", "inmigrate", "restore-vm":
+		// the machine is not running yet, waiting for its state to come in
+		return state.Starting
+	case "finish-migrate":
+		return state.Stopping
+	case "shutdown":
+		return state.Stopped
+	case "internal-error", "io-error", "guest-panicked", "watchdog":
+		return state.Error
+	}
+	return state.None
+}
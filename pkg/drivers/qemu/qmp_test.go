@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+)
+
+func TestRunState(t *testing.T) {
+	tests := []struct {
+		status string
+		want   state.State
+	}{
+		{"running", state.Running},
+		{"paused", state.Paused},
+		{"save-vm", state.Paused},
+		{"suspended", state.Saved},
+		{"inmigrate", state.Starting},
+		{"finish-migrate", state.Stopping},
+		{"shutdown", state.Stopped},
+		{"guest-panicked", state.Error},
+		{"colo", state.None},
+	}
+	for _, tc := range tests {
+		t.Run(tc.status, func(t *testing.T) {
+			if got := runState(tc.status); got != tc.want {
+				t.Errorf("runState(%q) = %s, want %s", tc.status, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// ResizeDisk grows the disk of the machine to sizeMB, over QMP if the machine runs. The filesystem
+// on the disk is left to grow, until FilesystemGrown is called
+func (d *Driver) ResizeDisk(sizeMB int) error {
+	if sizeMB < d.DiskSize {
+		return fmt.Errorf("the disk can only grow, it is already %d MB", d.DiskSize)
+	}
+	if sizeMB == d.DiskSize {
+		return nil
+	}
+	grow := int64(sizeMB-d.DiskSize) * 1024 * 1024
+
+	running, err := d.isRunning()
+	if err != nil {
+		return err
+	}
+	if running {
+		dev, size, err := d.diskDevice()
+		if err != nil {
+			return err
+		}
+		klog.Infof("resizing block device %v from %d to %d bytes", dev, size, size+grow)
+		args := map[string]interface{}{"size": size + grow}
+		for k, v := range dev {
+			args[k] = v
+		}
+		if err := d.runQMP("block_resize", args, nil); err != nil {
+			return err
+		}
+	} else if _, err := qemuImg("resize", d.diskPath(), fmt.Sprintf("+%d", grow)); err != nil {
+		return err
+	}
+	d.DiskSize = sizeMB
+	d.GrowFilesystem = true
+	return nil
+}
+
+// FilesystemGrowPending returns whether the disk grew since its filesystem last did
+func (d *Driver) FilesystemGrowPending() bool {
+	return d.GrowFilesystem
+}
+
+// FilesystemGrown records that the filesystem fills the disk again
+func (d *Driver) FilesystemGrown() {
+	d.GrowFilesystem = false
+}
+
+// diskDevice returns how QMP addresses the block device of the disk of the running machine, and its size in bytes
+func (d *Driver) diskDevice() (map[string]string, int64, error) {
+	var blocks []struct {
+		Device   string `json:"device"`
+		Inserted *struct {
+			File     string `json:"file"`
+			NodeName string `json:"node-name"`
+			Image    struct {
+				VirtualSize int64 `json:"virtual-size"`
+			} `json:"image"`
+		} `json:"inserted"`
+	}
+	if err := d.runQMP("query-block", nil, &blocks); err != nil {
+		return nil, 0, err
+	}
+	for _, b := range blocks {
+		if b.Inserted == nil || b.Inserted.File != d.diskPath() {
+			continue
+		}
+		// block devices created with -blockdev have no name, only the node of their image does
+		if b.Device != "" {
+			return map[string]string{"device": b.Device}, b.Inserted.Image.VirtualSize, nil
+		}
+		return map[string]string{"node-name": b.Inserted.NodeName}, b.Inserted.Image.VirtualSize, nil
+	}
+	return nil, 0, fmt.Errorf("no block device has %s inserted", d.diskPath())
+}
+
+// SetMemory sets the memory of the machine to sizeMB by inflating or deflating its balloon, so it can not exceed
+// the memory the machine boots with. A machine which is not running gets it at its next start.
+func (d *Driver) SetMemory(sizeMB int) error {
+	if sizeMB > d.Memory {
+		return fmt.Errorf("the memory can not exceed the %d MB the machine boots with", d.Memory)
+	}
+	d.BalloonMemory = sizeMB
+	if sizeMB == d.Memory {
+		d.BalloonMemory = 0
+	}
+	running, err := d.isRunning()
+	if err != nil || !running {
+		return err
+	}
+	return d.balloon()
+}
+
+// balloon sets the size of the balloon of the running machine, to leave it the memory it was given
+func (d *Driver) balloon() error {
+	sizeMB := d.BalloonMemory
+	if sizeMB == 0 {
+		sizeMB = d.Memory
+	}
+	if err := d.runQMP("balloon", map[string]int64{"value": int64(sizeMB) * 1024 * 1024}, nil); err != nil {
+		return errors.Wrap(err, "balloon")
+	}
+	return nil
+}
+
+// isRunning returns whether the qemu process of the machine runs, which is then managed over QMP
+func (d *Driver) isRunning() (bool, error) {
+	s, err := d.GetState()
+	if err != nil {
+		return false, err
+	}
+	return s != state.Stopped, nil
+}
+
+// qemuImg runs qemu-img, on the images of machines which are not running, returning its output
+func qemuImg(args ...string) (string, error) {
+	stdout, _, err := cmdOutErr("qemu-img", args...)
+	if err != nil {
+		return "", errors.Wrapf(err, "qemu-img %s", args[0])
+	}
+	return stdout, nil
+}
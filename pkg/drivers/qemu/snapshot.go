@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// snapshotName is what the names of snapshots are made of, so that the human monitor parses them as one argument
+var snapshotName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// Snapshot saves the state of the machine as a snapshot of its disk: with its memory if it runs, with savevm
+func (d *Driver) Snapshot(name string) error {
+	if !snapshotName.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q: only letters, digits, '.', '_' and '-' are allowed", name)
+	}
+	running, err := d.isRunning()
+	if err != nil {
+		return err
+	}
+	if running {
+		return d.humanMonitorCommand("savevm " + name)
+	}
+	_, err = qemuImg("snapshot", "-c", name, d.diskPath())
+	return err
+}
+
+// RestoreSnapshot brings the machine back to a snapshot. A machine which is not running only gets its disk
+// restored, booting from it at its next start.
+func (d *Driver) RestoreSnapshot(name string) error {
+	if err := d.hasSnapshot(name); err != nil {
+		return err
+	}
+	running, err := d.isRunning()
+	if err != nil {
+		return err
+	}
+	if running {
+		return d.humanMonitorCommand("loadvm " + name)
+	}
+	_, err = qemuImg("snapshot", "-a", name, d.diskPath())
+	return err
+}
+
+// DeleteSnapshot deletes a snapshot of the machine
+func (d *Driver) DeleteSnapshot(name string) error {
+	if err := d.hasSnapshot(name); err != nil {
+		return err
+	}
+	running, err := d.isRunning()
+	if err != nil {
+		return err
+	}
+	if running {
+		return d.humanMonitorCommand("delvm " + name)
+	}
+	_, err = qemuImg("snapshot", "-d", name, d.diskPath())
+	return err
+}
+
+// Snapshots returns the names of the snapshots of the machine, oldest first
+func (d *Driver) Snapshots() ([]string, error) {
+	// -U reads the image even while the running machine holds its lock
+	stdout, err := qemuImg("info", "-U", "--output=json", d.diskPath())
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapshots([]byte(stdout))
+}
+
+// hasSnapshot returns an error unless the machine has a snapshot
+func (d *Driver) hasSnapshot(name string) error {
+	names, err := d.Snapshots()
+	if err != nil {
+		return err
+	}
+	for _, n := range names {
+		if n == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("no snapshot named %q", name)
+}
+
+// parseSnapshots returns the names of the snapshots listed in the output of qemu-img info --output=json
+func parseSnapshots(info []byte) ([]string, error) {
+	var img struct {
+		Snapshots []struct {
+			Name string `json:"name"`
+		} `json:"snapshots"`
+	}
+	if err := json.Unmarshal(info, &img); err != nil {
+		return nil, errors.Wrap(err, "parse image info")
+	}
+	names := []string{}
+	for _, s := range img.Snapshots {
+		names = append(names, s.Name)
+	}
+	return names, nil
+}
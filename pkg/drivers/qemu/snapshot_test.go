@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSnapshots(t *testing.T) {
+	tests := []struct {
+		name string
+		info string
+		want []string
+	}{
+		{"none", `{"virtual-size": 20971520000, "filename": "disk.qcow2", "format": "qcow2"}`, []string{}},
+		{"some", `{"snapshots": [{"id": "1", "name": "clean", "vm-state-size": 0}, {"id": "2", "name": "before-upgrade", "vm-state-size": 318767104}], "format": "qcow2"}`, []string{"clean", "before-upgrade"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseSnapshots([]byte(tc.info))
+			if err != nil {
+				t.Fatalf("parseSnapshots: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("parseSnapshots mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := parseSnapshots([]byte("qemu-img: Could not open 'disk.qcow2'")); err == nil {
+		t.Errorf("parseSnapshots of an error message succeeded")
+	}
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+)
+
+const (
+	// modulesFile loads the kernel modules Kubernetes needs at boot
+	modulesFile = "/etc/modules-load.d/minikube.conf"
+	// sysctlFile sets the sysctls Kubernetes needs at boot
+	sysctlFile = "/etc/sysctl.d/99-minikube.conf"
+	// fstabBackup is the fstab of the host before minikube turned swap off
+	fstabBackup = "/etc/fstab.minikube-backup"
+)
+
+var (
+	kernelModules = []string{"overlay", "br_netfilter"}
+	sysctls       = []string{"net.bridge.bridge-nf-call-iptables = 1", "net.bridge.bridge-nf-call-ip6tables = 1", "net.ipv4.ip_forward = 1"}
+)
+
+// runtimePrograms maps the names of the container runtimes to their program, which is also the name of their service
+var runtimePrograms = map[string]string{
+	"Docker":     "docker",
+	"containerd": "containerd",
+	"CRI-O":      "crio",
+}
+
+// packageManager installs and removes the packages of a Linux distribution
+type packageManager struct {
+	name    string
+	refresh []string
+	install []string
+	remove  []string
+	// packages maps the programs minikube needs to the packages providing them, "" when the distribution has none
+	packages map[string]string
+}
+
+var packageManagers = map[string]packageManager{
+	"apt": {
+		name:     "apt",
+		refresh:  []string{"apt-get", "update"},
+		install:  []string{"env", "DEBIAN_FRONTEND=noninteractive", "apt-get", "install", "-y"},
+		remove:   []string{"env", "DEBIAN_FRONTEND=noninteractive", "apt-get", "purge", "-y"},
+		packages: map[string]string{"docker": "docker.io", "containerd": "containerd", "crio": "", "conntrack": "conntrack", "socat": "socat"},
+	},
+	"dnf": {
+		name:     "dnf",
+		install:  []string{"dnf", "install", "-y"},
+		remove:   []string{"dnf", "remove", "-y"},
+		packages: map[string]string{"docker": "moby-engine", "containerd": "containerd", "crio": "cri-o", "conntrack": "conntrack-tools", "socat": "socat"},
+	},
+	"zypper": {
+		name:     "zypper",
+		refresh:  []string{"zypper", "--non-interactive", "refresh"},
+		install:  []string{"zypper", "--non-interactive", "install"},
+		remove:   []string{"zypper", "--non-interactive", "remove"},
+		packages: map[string]string{"docker": "docker", "containerd": "containerd", "crio": "cri-o", "conntrack": "conntrack-tools", "socat": "socat"},
+	},
+}
+
+// distroPackageManagers maps the IDs of Linux distributions, as os-release gives them, to their package manager
+var distroPackageManagers = map[string]string{
+	"debian":        "apt",
+	"ubuntu":        "apt",
+	"fedora":        "dnf",
+	"rhel":          "dnf",
+	"centos":        "dnf",
+	"rocky":         "dnf",
+	"almalinux":     "dnf",
+	"opensuse":      "zypper",
+	"suse":          "zypper",
+	"sles":          "zypper",
+	"opensuse-leap": "zypper",
+}
+
+// packageManagerOf returns the package manager of a distribution, or of the one it is like
+func packageManagerOf(osr *provision.OsRelease) (packageManager, bool) {
+	for _, id := range append([]string{osr.ID}, strings.Fields(osr.IDLike)...) {
+		if pm, ok := distroPackageManagers[id]; ok {
+			return packageManagers[pm], true
+		}
+	}
+	return packageManager{}, false
+}
+
+// check is the outcome of a pre-flight check of the host
+type check struct {
+	name   string
+	ok     bool
+	fatal  bool // the host cannot run a node, whether prepared or not
+	detail string
+}
+
+// preflight checks the host can run a node, reports on what it found, and fails if it cannot
+func (d *Driver) preflight() (*provision.OsRelease, error) {
+	checks := []check{}
+	add := func(name string, ok, fatal bool, detail string) {
+		checks = append(checks, check{name: name, ok: ok, fatal: fatal, detail: detail})
+	}
+
+	osr := &provision.OsRelease{}
+	if rr, err := d.exec.RunCmd(exec.Command("cat", "/etc/os-release")); err != nil {
+		add("distribution", false, false, "unknown, /etc/os-release is missing")
+	} else if osr, err = provision.NewOsRelease(rr.Stdout.Bytes()); err != nil {
+		add("distribution", false, false, fmt.Sprintf("unknown: %v", err))
+	} else {
+		pm, ok := packageManagerOf(osr)
+		detail := fmt.Sprintf("%s, packages installed with %s", osr.PrettyName, pm.name)
+		if !ok {
+			detail = fmt.Sprintf("%s, which minikube cannot install packages on", osr.PrettyName)
+		}
+		add("distribution", ok, false, detail)
+	}
+
+	if _, err := d.exec.RunCmd(exec.Command("sudo", "-n", "true")); err != nil {
+		add("sudo", false, true, fmt.Sprintf("%s cannot run sudo without a password", d.GetSSHUsername()))
+	} else {
+		add("sudo", true, true, "without a password")
+	}
+
+	add("init system", true, false, sysinit.New(d.exec).Name())
+
+	cpus := d.output("nproc")
+	n, _ := strconv.Atoi(cpus)
+	add("CPUs", n >= 2, false, fmt.Sprintf("%s, Kubernetes needs 2", cpus))
+
+	mem := d.output("sh", "-c", "awk '/MemTotal/ {print int($2 / 1024)}' /proc/meminfo")
+	mb, _ := strconv.Atoi(mem)
+	add("memory", mb >= 1800, false, fmt.Sprintf("%s MB, Kubernetes needs 1800 MB", mem))
+
+	swap := d.output("sh", "-c", "swapon --show --noheadings | wc -l")
+	add("swap", swap == "0", false, "off")
+
+	bridge := d.output("cat", "/proc/sys/net/bridge/bridge-nf-call-iptables")
+	add("bridge netfilter", bridge == "1", false, "br_netfilter loaded and net.bridge.bridge-nf-call-iptables = 1")
+
+	forward := d.output("cat", "/proc/sys/net/ipv4/ip_forward")
+	add("IP forwarding", forward == "1", false, "net.ipv4.ip_forward = 1")
+
+	for _, program := range d.programs() {
+		add(program, d.hasProgram(program), false, "installed")
+	}
+
+	out.Step(style.Verifying, "Pre-flight checks of {{.host}}:", out.V{"host": d.IPAddress})
+	failed := []string{}
+	for _, c := range checks {
+		st := style.Check
+		if !c.ok {
+			st = style.Warning
+			if c.fatal || !d.PrepareHost {
+				failed = append(failed, c.name)
+			}
+		}
+		out.Styled(st, "{{.check}}: {{.detail}}", out.V{"check": c.name, "detail": c.detail})
+		if !c.ok && c.fatal {
+			return osr, fmt.Errorf("%s check failed: %s", c.name, c.detail)
+		}
+	}
+	if len(failed) > 0 {
+		klog.Warningf("pre-flight checks of %s failed, which the host is not prepared to pass: %v", d.IPAddress, failed)
+	}
+	return osr, nil
+}
+
+// prepare prepares the host for Kubernetes, which it is safe to do again
+func (d *Driver) prepare(osr *provision.OsRelease) error {
+	step := func(name string) { klog.Infof("preparing %s: %s", d.IPAddress, name) }
+
+	step("kernel modules")
+	for _, m := range kernelModules {
+		if _, err := d.exec.RunCmd(exec.Command("test", "-d", "/sys/module/"+m)); err != nil && !slices.Contains(d.LoadedModules, m) {
+			d.LoadedModules = append(d.LoadedModules, m)
+		}
+	}
+	if err := d.exec.Copy(assets.NewMemoryAssetTarget([]byte(strings.Join(kernelModules, "\n")+"\n"), modulesFile, "0644")); err != nil {
+		return errors.Wrap(err, "kernel modules")
+	}
+	if _, err := d.exec.RunCmd(exec.Command("sudo", append([]string{"modprobe", "-a"}, kernelModules...)...)); err != nil {
+		return errors.Wrap(err, "modprobe")
+	}
+
+	step("sysctls")
+	// preparing again must not take the values set the first time for the former ones
+	if d.Sysctls == nil {
+		d.Sysctls = map[string]string{}
+		for _, kv := range sysctls {
+			key := strings.TrimSpace(strings.SplitN(kv, "=", 2)[0])
+			if value := d.output("sysctl", "-n", key); value != "" {
+				d.Sysctls[key] = value
+			}
+		}
+	}
+	if err := d.exec.Copy(assets.NewMemoryAssetTarget([]byte(strings.Join(sysctls, "\n")+"\n"), sysctlFile, "0644")); err != nil {
+		return errors.Wrap(err, "sysctls")
+	}
+	if _, err := d.exec.RunCmd(exec.Command("sudo", "sysctl", "--system")); err != nil {
+		return errors.Wrap(err, "sysctl")
+	}
+
+	step("swap")
+	if d.output("sh", "-c", "swapon --show --noheadings | wc -l") != "0" {
+		if _, err := d.exec.RunCmd(exec.Command("sudo", "swapoff", "-a")); err != nil {
+			return errors.Wrap(err, "swapoff")
+		}
+		// comment the swaps out of fstab, once, so that swap stays off after a reboot
+		script := fmt.Sprintf(`[ -e %[2]s ] || { cp -p %[1]s %[2]s && sed -i '/^[^#].*\sswap\s/s/^/#/' %[1]s; }`, "/etc/fstab", fstabBackup)
+		if _, err := d.exec.RunCmd(exec.Command("sudo", "sh", "-c", script)); err != nil {
+			return errors.Wrap(err, "fstab")
+		}
+		d.SwapDisabled = true
+	}
+
+	step("packages")
+	missing := []string{}
+	for _, program := range d.programs() {
+		if !d.hasProgram(program) {
+			missing = append(missing, program)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	pm, ok := packageManagerOf(osr)
+	if !ok {
+		return fmt.Errorf("%s are missing, and minikube cannot install them on %s", strings.Join(missing, ", "), osr.PrettyName)
+	}
+	pkgs := []string{}
+	for _, program := range missing {
+		pkg := pm.packages[program]
+		if pkg == "" {
+			return fmt.Errorf("%s is missing, and %s has no package for it", program, osr.PrettyName)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	out.Step(style.Provisioning, "Installing {{.packages}} on {{.host}} ...", out.V{"packages": strings.Join(pkgs, ", "), "host": d.IPAddress})
+	if pm.refresh != nil {
+		if _, err := d.exec.RunCmd(exec.Command("sudo", pm.refresh...)); err != nil {
+			return errors.Wrap(err, "refresh packages")
+		}
+	}
+	if _, err := d.exec.RunCmd(exec.Command("sudo", append(pm.install, pkgs...)...)); err != nil {
+		return errors.Wrap(err, "install packages")
+	}
+	d.PackageManager = pm.name
+	d.InstalledPackages = append(d.InstalledPackages, pkgs...)
+
+	if program := runtimePrograms[d.runtime.Name()]; d.hasProgram(program) {
+		if err := sysinit.New(d.exec).EnableNow(program); err != nil {
+			return errors.Wrapf(err, "enable %s", program)
+		}
+	}
+	return nil
+}
+
+// unprepare undoes what prepare did to the host
+func (d *Driver) unprepare() error {
+	var errs []string
+	run := func(args ...string) {
+		if _, err := d.exec.RunCmd(exec.Command("sudo", args...)); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	run("rm", "-f", modulesFile, sysctlFile)
+	// the bridge sysctls go away with br_netfilter, so they are set back before it is unloaded
+	keys := make([]string, 0, len(d.Sysctls))
+	for key := range d.Sysctls {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		run("sysctl", "-w", key+"="+d.Sysctls[key])
+	}
+	for _, m := range d.LoadedModules {
+		// another container runtime of the host may use the module by now
+		if _, err := d.exec.RunCmd(exec.Command("sudo", "modprobe", "-r", m)); err != nil {
+			klog.Warningf("unable to unload the kernel module %s, which may be in use: %v", m, err)
+		}
+	}
+	if d.SwapDisabled {
+		run("sh", "-c", fmt.Sprintf("[ ! -e %[2]s ] || mv %[2]s %[1]s", "/etc/fstab", fstabBackup))
+		run("swapon", "-a")
+	}
+	if pm, ok := packageManagers[d.PackageManager]; ok && len(d.InstalledPackages) > 0 {
+		out.Step(style.DeletingHost, "Uninstalling {{.packages}} from {{.host}} ...", out.V{"packages": strings.Join(d.InstalledPackages, ", "), "host": d.IPAddress})
+		run(append(pm.remove, d.InstalledPackages...)...)
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// programs returns the programs a node needs on the host
+func (d *Driver) programs() []string {
+	programs := []string{}
+	if p, ok := runtimePrograms[d.runtime.Name()]; ok {
+		programs = append(programs, p)
+	}
+	return append(programs, "conntrack", "socat")
+}
+
+// hasProgram returns whether a program is installed on the host
+func (d *Driver) hasProgram(program string) bool {
+	_, err := d.exec.RunCmd(exec.Command("sh", "-c", "command -v "+program))
+	return err == nil
+}
+
+// output returns the trimmed output of a command run on the host, or "" if it failed
+func (d *Driver) output(args ...string) string {
+	rr, err := d.exec.RunCmd(exec.Command(args[0], args[1:]...))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(rr.Stdout.String())
+}
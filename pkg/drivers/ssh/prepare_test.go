@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/provision"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestPackageManagerOf(t *testing.T) {
+	tests := []struct {
+		osRelease string
+		expected  string
+	}{
+		{"ID=ubuntu\nID_LIKE=debian\nPRETTY_NAME=\"Ubuntu 24.04.1 LTS\"\n", "apt"},
+		{"ID=\"rocky\"\nID_LIKE=\"rhel centos fedora\"\n", "dnf"},
+		{"ID=\"opensuse-tumbleweed\"\nID_LIKE=\"opensuse suse\"\n", "zypper"},
+		{"ID=linuxmint\nID_LIKE=\"ubuntu debian\"\n", "apt"},
+		{"ID=arch\n", ""},
+	}
+	for _, tc := range tests {
+		osr, err := provision.NewOsRelease([]byte(tc.osRelease))
+		if err != nil {
+			t.Fatalf("NewOsRelease(%q): %v", tc.osRelease, err)
+		}
+		t.Run(osr.ID, func(t *testing.T) {
+			pm, ok := packageManagerOf(osr)
+			if pm.name != tc.expected || ok != (tc.expected != "") {
+				t.Errorf("packageManagerOf(%s) = %q, %v, expected %q", osr.ID, pm.name, ok, tc.expected)
+			}
+		})
+	}
+}
+
+// recordingRunner records the commands it runs, all of which succeed
+type recordingRunner struct {
+	*command.FakeCommandRunner
+	cmds []string
+}
+
+func (r *recordingRunner) RunCmd(cmd *exec.Cmd) (*command.RunResult, error) {
+	r.cmds = append(r.cmds, strings.Join(cmd.Args, " "))
+	return &command.RunResult{Args: cmd.Args}, nil
+}
+
+func TestUnprepare(t *testing.T) {
+	r := &recordingRunner{FakeCommandRunner: command.NewFakeCommandRunner()}
+	d := &Driver{
+		BaseDriver:    &drivers.BaseDriver{IPAddress: "192.168.1.10"},
+		LoadedModules: []string{"br_netfilter"},
+		Sysctls:       map[string]string{"net.ipv4.ip_forward": "0", "net.bridge.bridge-nf-call-iptables": "0"},
+		exec:          r,
+	}
+	if err := d.unprepare(); err != nil {
+		t.Fatalf("unprepare: %v", err)
+	}
+	expected := []string{
+		"sudo rm -f " + modulesFile + " " + sysctlFile,
+		"sudo sysctl -w net.bridge.bridge-nf-call-iptables=0",
+		"sudo sysctl -w net.ipv4.ip_forward=0",
+		"sudo modprobe -r br_netfilter",
+	}
+	if !reflect.DeepEqual(r.cmds, expected) {
+		t.Errorf("unprepare ran %q, expected %q", r.cmds, expected)
+	}
+}
@@ -36,9 +36,11 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
 )
 
 // Driver is a driver designed to run kubeadm w/o VM management.
@@ -48,8 +50,19 @@ type Driver struct {
 	*pkgdrivers.CommonDriver
 	EnginePort int
 	SSHKey     string
-	runtime    cruntime.Manager
-	exec       command.Runner
+	// PrepareHost is whether to prepare the host for Kubernetes, undoing it on Remove
+	PrepareHost bool
+	// LoadedModules are the kernel modules loaded by minikube, to unload them
+	LoadedModules []string
+	// Sysctls are the values the sysctls set by minikube had before, to set them back
+	Sysctls map[string]string
+	// SwapDisabled is whether swap was turned off, to turn it back on
+	SwapDisabled bool
+	// PackageManager and InstalledPackages are the packages installed by minikube, to uninstall them
+	PackageManager    string
+	InstalledPackages []string
+	runtime           cruntime.Manager
+	exec              command.Runner
 }
 
 // Config is configuration for the SSH driver
@@ -141,6 +154,16 @@ func (d *Driver) Create() error {
 		}
 	}
 
+	osr, err := d.preflight()
+	if err != nil {
+		return errors.Wrap(err, "pre-flight checks")
+	}
+	if d.PrepareHost {
+		if err := d.prepare(osr); err != nil {
+			return errors.Wrap(err, "prepare host")
+		}
+	}
+
 	if d.runtime.Name() == "Docker" {
 		groups, err := d.exec.RunCmd(exec.Command("groups", d.GetSSHUsername()))
 		if err != nil {
@@ -248,7 +271,36 @@ func (d *Driver) Kill() error {
 }
 
 // Remove a host, including any data which may have been written by it.
+// The host itself is left, as it was before minikube prepared it.
 func (d *Driver) Remove() error {
+	if s, err := d.GetState(); err != nil || s != state.Running {
+		klog.Warningf("%s is unreachable, leaving it as it is", d.IPAddress)
+		return nil
+	}
+	if err := d.Kill(); err != nil {
+		klog.Warningf("couldn't kill the containers, will continue with remove anyways: %v", err)
+	}
+	if err := sysinit.New(d.exec).DisableNow("kubelet"); err != nil {
+		klog.Warningf("couldn't disable kubelet, will continue with remove anyways: %v", err)
+	}
+
+	// kubeadm reset undoes kubeadm init and join, the rest is what minikube copied to the host
+	reset := fmt.Sprintf("for k in %s/binaries/*/kubeadm; do [ -x \"$k\" ] && \"$k\" reset --force; done; true", vmpath.GuestPersistentDir)
+	if _, err := d.exec.RunCmd(exec.Command("sudo", "sh", "-c", reset)); err != nil {
+		klog.Warningf("kubeadm reset failed, will continue with remove anyways: %v", err)
+	}
+	rm := []string{"rm", "-rf", vmpath.GuestPersistentDir, vmpath.GuestEphemeralDir, vmpath.GuestAuditLogDir, "/etc/kubernetes", "/var/lib/kubelet",
+		bsutil.KubeletServiceFile, path.Dir(bsutil.KubeletSystemdConfFile)}
+	if _, err := d.exec.RunCmd(exec.Command("sudo", rm...)); err != nil {
+		return errors.Wrap(err, "remove files")
+	}
+	if _, err := d.exec.RunCmd(exec.Command("sudo", "systemctl", "daemon-reload")); err != nil {
+		klog.Warningf("systemctl daemon-reload failed: %v", err)
+	}
+
+	if d.PrepareHost {
+		return d.unprepare()
+	}
 	return nil
 }
 
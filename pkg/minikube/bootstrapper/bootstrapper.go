@@ -48,12 +48,16 @@ type Bootstrapper interface {
 	LogCommands(config.ClusterConfig, LogOptions) map[string]string
 	// SetupCerts gets the generated credentials required to talk to the APIServer.
 	SetupCerts(config.ClusterConfig, config.Node, cruntime.CommandRunner) error
+	// RotateCerts issues new certs to a node of a running cluster, replacing its CAs as well if the last argument is set.
+	RotateCerts(config.ClusterConfig, config.Node, cruntime.CommandRunner, bool) error
 	GetAPIServerStatus(string, int) (string, error)
 }
 
 const (
 	// Kubeadm is the kubeadm bootstrapper type
 	Kubeadm = "kubeadm"
+	// K3s is the k3s bootstrapper type, which runs the whole control plane as a single process
+	K3s = "k3s"
 )
 
 // GetCachedBinaryList returns the list of binaries
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// AuditLogPath is where the apiserver writes its audit log on the control-plane nodes
+	AuditLogPath = vmpath.GuestAuditLogDir + "/audit.log"
+
+	auditPolicyPath     = vmpath.GuestAPIServerConfigDir + "/audit-policy.yaml"
+	admissionConfigPath = vmpath.GuestAPIServerConfigDir + "/admission-config.yaml"
+)
+
+// auditPolicyHeader is shared by the audit policy presets: it skips the health checks and events, which are too noisy to audit
+const auditPolicyHeader = `apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages:
+  - "RequestReceived"
+rules:
+  - level: None
+    nonResourceURLs: ["/healthz*", "/livez*", "/readyz*", "/version"]
+  - level: None
+    resources:
+      - group: ""
+        resources: ["events"]
+`
+
+// auditPolicyPresets are the audit policies which can be given by name instead of a file
+var auditPolicyPresets = map[string]string{
+	// metadata records who did what to which object, but not the objects themselves
+	"metadata": auditPolicyHeader + `  - level: Metadata
+`,
+	// request records the objects of the requests which modify the cluster, except for secrets
+	"request": auditPolicyHeader + `  - level: Metadata
+    resources:
+      - group: ""
+        resources: ["secrets", "configmaps"]
+      - group: "authentication.k8s.io"
+        resources: ["tokenreviews"]
+  - level: Request
+    verbs: ["create", "update", "patch", "delete", "deletecollection"]
+  - level: Metadata
+`,
+	// requestresponse records the objects of all the requests and responses, except for secrets
+	"requestresponse": auditPolicyHeader + `  - level: Metadata
+    resources:
+      - group: ""
+        resources: ["secrets", "configmaps"]
+      - group: "authentication.k8s.io"
+        resources: ["tokenreviews"]
+  - level: RequestResponse
+`,
+}
+
+// AuditPolicyPresets returns the names of the audit policy presets
+func AuditPolicyPresets() []string {
+	presets := []string{}
+	for p := range auditPolicyPresets {
+		presets = append(presets, p)
+	}
+	sort.Strings(presets)
+	return presets
+}
+
+// IsAuditPolicyPreset returns whether the audit policy is one of the presets rather than a file
+func IsAuditPolicyPreset(policy string) bool {
+	_, ok := auditPolicyPresets[policy]
+	return ok
+}
+
+// auditPolicy returns the audit policy of a preset or a file
+func auditPolicy(policy string) ([]byte, error) {
+	if p, ok := auditPolicyPresets[policy]; ok {
+		return []byte(p), nil
+	}
+	b, err := os.ReadFile(policy)
+	if err != nil {
+		return nil, errors.Wrap(err, "read audit policy")
+	}
+	return b, nil
+}
+
+// APIServerConfigFiles returns the audit policy and admission configuration of the apiserver, to copy onto the control-plane nodes
+func APIServerConfigFiles(k8s config.KubernetesConfig) ([]assets.CopyableFile, error) {
+	files := []assets.CopyableFile{}
+	if k8s.AuditPolicy != "" {
+		policy, err := auditPolicy(k8s.AuditPolicy)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, assets.NewMemoryAssetTarget(policy, auditPolicyPath, "0600"))
+	}
+	if k8s.AdmissionConfig != "" {
+		admission, err := os.ReadFile(k8s.AdmissionConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "read admission config")
+		}
+		files = append(files, assets.NewMemoryAssetTarget(admission, admissionConfigPath, "0600"))
+	}
+	return files, nil
+}
+
+// APIServerOptions returns the apiserver options of the OIDC, audit and admission settings of the cluster
+func APIServerOptions(k8s config.KubernetesConfig) config.ExtraOptionSlice {
+	return append(oidcExtraOptions(k8s.OIDC), apiServerConfigOptions(k8s)...)
+}
+
+// apiServerConfigOptions returns the apiserver options which enable the audit log and the admission plugins
+func apiServerConfigOptions(k8s config.KubernetesConfig) config.ExtraOptionSlice {
+	var opts config.ExtraOptionSlice
+	if k8s.AuditPolicy != "" {
+		opts = append(opts,
+			config.ExtraOption{Component: Apiserver, Key: "audit-policy-file", Value: auditPolicyPath},
+			config.ExtraOption{Component: Apiserver, Key: "audit-log-path", Value: AuditLogPath},
+			// keeps the audit log from filling up the disk of the node
+			config.ExtraOption{Component: Apiserver, Key: "audit-log-maxsize", Value: "100"},
+			config.ExtraOption{Component: Apiserver, Key: "audit-log-maxbackup", Value: "3"},
+		)
+	}
+	if len(k8s.AdmissionPlugins) > 0 {
+		plugins := append([]string{}, util.DefaultAdmissionControllers...)
+		for _, p := range k8s.AdmissionPlugins {
+			if !config.ContainsParam(plugins, p) {
+				plugins = append(plugins, p)
+			}
+		}
+		opts = append(opts, config.ExtraOption{Component: Apiserver, Key: "enable-admission-plugins", Value: strings.Join(plugins, ",")})
+	}
+	if k8s.AdmissionConfig != "" {
+		opts = append(opts, config.ExtraOption{Component: Apiserver, Key: "admission-control-config-file", Value: admissionConfigPath})
+	}
+	return opts
+}
+
+// apiServerExtraVolumes returns the host paths the apiserver needs for its audit log and admission configuration
+func apiServerExtraVolumes(k8s config.KubernetesConfig) []extraVolume {
+	var volumes []extraVolume
+	if k8s.AuditPolicy != "" || k8s.AdmissionConfig != "" {
+		volumes = append(volumes, extraVolume{Name: "apiserver-config", HostPath: vmpath.GuestAPIServerConfigDir, MountPath: vmpath.GuestAPIServerConfigDir, ReadOnly: true, PathType: "DirectoryOrCreate"})
+	}
+	if k8s.AuditPolicy != "" {
+		volumes = append(volumes, extraVolume{Name: "audit-log", HostPath: vmpath.GuestAuditLogDir, MountPath: vmpath.GuestAuditLogDir, PathType: "DirectoryOrCreate"})
+	}
+	return volumes
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestAPIServerConfigFiles(t *testing.T) {
+	admission := filepath.Join(t.TempDir(), "admission.yaml")
+	if err := os.WriteFile(admission, []byte("kind: AdmissionConfiguration\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		k8s       config.KubernetesConfig
+		want      map[string]string
+		shouldErr bool
+	}{
+		{
+			name: "none",
+			k8s:  config.KubernetesConfig{AdmissionPlugins: []string{"AlwaysPullImages"}},
+			want: map[string]string{},
+		},
+		{
+			name: "preset and admission config",
+			k8s:  config.KubernetesConfig{AuditPolicy: "requestresponse", AdmissionConfig: admission},
+			want: map[string]string{
+				auditPolicyPath:     "level: RequestResponse",
+				admissionConfigPath: "kind: AdmissionConfiguration",
+			},
+		},
+		{
+			name: "policy file",
+			k8s:  config.KubernetesConfig{AuditPolicy: admission},
+			want: map[string]string{auditPolicyPath: "kind: AdmissionConfiguration"},
+		},
+		{
+			name:      "missing policy file",
+			k8s:       config.KubernetesConfig{AuditPolicy: "metadta"},
+			shouldErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			files, err := APIServerConfigFiles(tc.k8s)
+			if (err != nil) != tc.shouldErr {
+				t.Fatalf("APIServerConfigFiles() error = %v, shouldErr %v", err, tc.shouldErr)
+			}
+			if tc.shouldErr {
+				return
+			}
+			if len(files) != len(tc.want) {
+				t.Fatalf("APIServerConfigFiles() returned %d files, want %d", len(files), len(tc.want))
+			}
+			for _, f := range files {
+				b, err := io.ReadAll(f)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, ok := tc.want[f.GetTargetPath()]
+				if !ok || !strings.Contains(string(b), want) {
+					t.Errorf("APIServerConfigFiles() %s = %q, want it to contain %q", f.GetTargetPath(), b, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditPolicyPresets(t *testing.T) {
+	for _, p := range AuditPolicyPresets() {
+		if !IsAuditPolicyPreset(p) {
+			t.Errorf("IsAuditPolicyPreset(%q) = false", p)
+		}
+		policy, err := auditPolicy(p)
+		if err != nil {
+			t.Fatalf("auditPolicy(%q) error: %v", p, err)
+		}
+		if !strings.HasPrefix(string(policy), "apiVersion: audit.k8s.io/v1\nkind: Policy\n") {
+			t.Errorf("auditPolicy(%q) = %q, want an audit policy", p, policy)
+		}
+	}
+	if IsAuditPolicyPreset("/etc/audit-policy.yaml") {
+		t.Errorf("IsAuditPolicyPreset() = true for a file")
+	}
+}
@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"os/exec"
 	"path"
-	"runtime"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -37,8 +36,8 @@ import (
 	"k8s.io/minikube/pkg/minikube/vmpath"
 )
 
-// TransferBinaries transfers all required Kubernetes binaries
-func TransferBinaries(cfg config.KubernetesConfig, c command.Runner, sm sysinit.Manager, binariesURL string) error {
+// TransferBinaries transfers all required Kubernetes binaries, built for the given architecture
+func TransferBinaries(cfg config.KubernetesConfig, arch string, c command.Runner, sm sysinit.Manager, binariesURL string) error {
 	ok, err := binariesExist(cfg, c)
 	if err == nil && ok {
 		klog.Info("Found k8s binaries, skipping transfer")
@@ -56,7 +55,7 @@ func TransferBinaries(cfg config.KubernetesConfig, c command.Runner, sm sysinit.
 	for _, name := range constants.KubernetesReleaseBinaries {
 		name := name
 		g.Go(func() error {
-			src, err := download.Binary(name, cfg.KubernetesVersion, "linux", runtime.GOARCH, binariesURL)
+			src, err := download.Binary(name, cfg.KubernetesVersion, "linux", arch, binariesURL)
 			if err != nil {
 				return errors.Wrapf(err, "downloading %s", name)
 			}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"bytes"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/ktmpl"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+var (
+	// EtcdCACert is the CA cert of etcd, in the certificates dir kubeadm generates it in
+	EtcdCACert = path.Join(vmpath.GuestKubernetesCertsDir, "etcd", "ca.crt")
+	// EtcdCAKey is the key of the CA of etcd
+	EtcdCAKey = path.Join(vmpath.GuestKubernetesCertsDir, "etcd", "ca.key")
+	// EtcdClientCert is the cert the apiservers authenticate to an external etcd with
+	EtcdClientCert = path.Join(vmpath.GuestKubernetesCertsDir, "apiserver-etcd-client.crt")
+	// EtcdClientKey is the key of the cert the apiservers authenticate to an external etcd with
+	EtcdClientKey = path.Join(vmpath.GuestKubernetesCertsDir, "apiserver-etcd-client.key")
+)
+
+// externalEtcd is how the apiservers reach the members of an external etcd
+type externalEtcd struct {
+	Endpoints []string
+	CAFile    string
+	CertFile  string
+	KeyFile   string
+}
+
+// externalEtcdConfig returns how the apiservers reach the external etcd of a cluster, or nil if etcd is stacked on the control-plane nodes
+func externalEtcdConfig(cc config.ClusterConfig) *externalEtcd {
+	if !config.IsExternalEtcd(cc) {
+		return nil
+	}
+	e := &externalEtcd{CAFile: EtcdCACert, CertFile: EtcdClientCert, KeyFile: EtcdClientKey}
+	for _, n := range config.EtcdNodes(cc) {
+		e.Endpoints = append(e.Endpoints, "https://"+net.JoinHostPort(n.IP, "2379"))
+	}
+	return e
+}
+
+// kubeadmAPIVersion returns the version of the kubeadm config API to use with a Kubernetes version
+func kubeadmAPIVersion(version semver.Version) string {
+	switch {
+	case version.LT(semver.MustParse("1.17.0")):
+		return "v1beta1"
+	case version.LT(semver.MustParse("1.23.0")):
+		return "v1beta2"
+	case version.LT(semver.MustParse("1.31.0")):
+		return "v1beta3"
+	default:
+		return "v1beta4"
+	}
+}
+
+// GenerateEtcdKubeadmYAML generates the kubeadm config of the external etcd member of a dedicated etcd node.
+// initialCluster are the <name>=<peer url> of the members, including the one of the node,
+// and state is "new" for the first member and "existing" for the ones joining it.
+func GenerateEtcdKubeadmYAML(cc config.ClusterConfig, n config.Node, initialCluster []string, state string) ([]byte, error) {
+	k8s := cc.KubernetesConfig
+	version, err := util.ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing Kubernetes version")
+	}
+
+	extraArgs := etcdExtraArgs(k8s.ExtraOptions)
+	extraArgs["proxy-refresh-interval"] = "70000"
+	extraArgs["initial-cluster"] = strings.Join(initialCluster, ",")
+	extraArgs["initial-cluster-state"] = state
+
+	apiVersion := kubeadmAPIVersion(version)
+	opts := struct {
+		APIVersion        string
+		AdvertiseAddress  string
+		NodeName          string
+		ImageRepository   string
+		CertDir           string
+		EtcdDataDir       string
+		EtcdExtraArgs     map[string]string
+		ExtraArgsList     bool
+		KubernetesVersion string
+	}{
+		APIVersion:       apiVersion,
+		AdvertiseAddress: n.IP,
+		// kubeadm names the member after the node
+		NodeName:          config.MachineName(cc, n),
+		ImageRepository:   k8s.ImageRepository,
+		CertDir:           vmpath.GuestKubernetesCertsDir,
+		EtcdDataDir:       EtcdDataDir(),
+		EtcdExtraArgs:     extraArgs,
+		ExtraArgsList:     apiVersion == "v1beta4",
+		KubernetesVersion: k8s.KubernetesVersion,
+	}
+
+	b := bytes.Buffer{}
+	if err := ktmpl.EtcdMember.Execute(&b, opts); err != nil {
+		return nil, err
+	}
+	klog.Infof("etcd member kubeadm config:\n%s\n", b.String())
+	return b.Bytes(), nil
+}
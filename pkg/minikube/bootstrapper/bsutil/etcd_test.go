@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+func TestGenerateEtcdKubeadmYAML(t *testing.T) {
+	cc := config.ClusterConfig{
+		Name: "mk",
+		KubernetesConfig: config.KubernetesConfig{
+			EtcdTopology: config.EtcdExternal,
+			ExtraOptions: config.ExtraOptionSlice{{Component: Etcd, Key: "quota-backend-bytes", Value: "8589934592"}},
+		},
+		Nodes: []config.Node{
+			{Name: "", IP: "192.168.49.2", ControlPlane: true},
+			{Name: "m02", IP: "192.168.49.3", Etcd: true},
+			{Name: "m03", IP: "192.168.49.4", Etcd: true},
+		},
+	}
+	initialCluster := []string{"mk-m02=https://192.168.49.3:2380", "mk-m03=https://192.168.49.4:2380"}
+
+	tests := []struct {
+		version  string
+		expected []string
+	}{
+		{
+			version: "v1.30.0",
+			expected: []string{
+				"apiVersion: kubeadm.k8s.io/v1beta3",
+				"advertiseAddress: 192.168.49.4",
+				`name: "mk-m03"`,
+				"certificatesDir: /var/lib/minikube/certs",
+				"dataDir: /var/lib/minikube/etcd",
+				"initial-cluster: \"mk-m02=https://192.168.49.3:2380,mk-m03=https://192.168.49.4:2380\"",
+				"initial-cluster-state: \"existing\"",
+				"quota-backend-bytes: \"8589934592\"",
+				"kubernetesVersion: v1.30.0",
+			},
+		},
+		{
+			version: "v1.31.0",
+			expected: []string{
+				"apiVersion: kubeadm.k8s.io/v1beta4",
+				"- name: \"initial-cluster-state\"\n        value: \"existing\"",
+				"- name: \"quota-backend-bytes\"\n        value: \"8589934592\"",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.version, func(t *testing.T) {
+			cc.KubernetesConfig.KubernetesVersion = tc.version
+			got, err := GenerateEtcdKubeadmYAML(cc, cc.Nodes[2], initialCluster, "existing")
+			if err != nil {
+				t.Fatalf("GenerateEtcdKubeadmYAML: %v", err)
+			}
+			for _, want := range tc.expected {
+				if !strings.Contains(string(got), want) {
+					t.Errorf("expected the config to contain %q, got:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateKubeadmYAMLExternalEtcd(t *testing.T) {
+	fcr := command.NewFakeCommandRunner()
+	fcr.SetCommandToOutput(map[string]string{
+		"docker info --format {{.CgroupDriver}}": "systemd\n",
+	})
+	r, err := cruntime.New(cruntime.Config{Type: "docker", Runner: fcr, Socket: "/var/run/dockershim.sock"})
+	if err != nil {
+		t.Fatalf("runtime: %v", err)
+	}
+	cc := config.ClusterConfig{
+		Name: "mk",
+		KubernetesConfig: config.KubernetesConfig{
+			KubernetesVersion: "v1.31.0",
+			ClusterName:       "kubernetes",
+			EtcdTopology:      config.EtcdExternal,
+		},
+		Nodes: []config.Node{
+			{Name: "", IP: "192.168.49.2", ControlPlane: true},
+			{Name: "m02", IP: "192.168.49.3", Etcd: true},
+			{Name: "m03", IP: "192.168.49.4", Etcd: true},
+		},
+	}
+	got, err := GenerateKubeadmYAML(cc, cc.Nodes[0], r)
+	if err != nil {
+		t.Fatalf("GenerateKubeadmYAML: %v", err)
+	}
+	expected := `etcd:
+  external:
+    endpoints:
+      - https://192.168.49.3:2379
+      - https://192.168.49.4:2379
+    caFile: /var/lib/minikube/certs/etcd/ca.crt
+    certFile: /var/lib/minikube/certs/apiserver-etcd-client.crt
+    keyFile: /var/lib/minikube/certs/apiserver-etcd-client.key
+kubernetesVersion: v1.31.0
+`
+	if !strings.Contains(string(got), expected) {
+		t.Errorf("expected the config to contain:\n%s\ngot:\n%s", expected, got)
+	}
+	if strings.Contains(string(got), "local:") {
+		t.Errorf("expected no local etcd with an external one, got:\n%s", got)
+	}
+}
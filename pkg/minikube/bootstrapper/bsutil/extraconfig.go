@@ -26,6 +26,7 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
 )
 
 // enum to differentiate kubeadm command line parameters from kubeadm config file parameters (see the
@@ -39,9 +40,19 @@ const (
 
 // componentOptions holds extra args for a component
 type componentOptions struct {
-	Component string
-	ExtraArgs map[string]string
-	Pairs     map[string]string
+	Component    string
+	ExtraArgs    map[string]string
+	Pairs        map[string]string
+	ExtraVolumes []extraVolume
+}
+
+// extraVolume is a host path mounted into the static pod of a component
+type extraVolume struct {
+	Name      string
+	HostPath  string
+	MountPath string
+	ReadOnly  bool
+	PathType  string
 }
 
 // mapping of component to the section name in kubeadm.
@@ -207,6 +218,32 @@ func createExtraComponentConfig(extraOptions config.ExtraOptionSlice, version se
 	return validComponents, nil
 }
 
+// oidcExtraOptions returns the apiserver options which authenticate users with an OpenID Connect provider
+func oidcExtraOptions(oidc config.OIDCConfig) config.ExtraOptionSlice {
+	if !oidc.Enabled() {
+		return nil
+	}
+	caFile := ""
+	if oidc.CAFile != "" {
+		caFile = vmpath.GuestOIDCCACert
+	}
+	var extraOpts config.ExtraOptionSlice
+	for _, o := range []struct{ key, value string }{
+		{"oidc-issuer-url", oidc.IssuerURL},
+		{"oidc-client-id", oidc.ClientID},
+		{"oidc-username-claim", oidc.UsernameClaim},
+		{"oidc-username-prefix", oidc.UsernamePrefix},
+		{"oidc-groups-claim", oidc.GroupsClaim},
+		{"oidc-groups-prefix", oidc.GroupsPrefix},
+		{"oidc-ca-file", caFile},
+	} {
+		if o.value != "" {
+			extraOpts = append(extraOpts, config.ExtraOption{Component: Apiserver, Key: o.key, Value: o.value})
+		}
+	}
+	return extraOpts
+}
+
 // createKubeProxyOptions generates a map of extra config for kube-proxy
 func createKubeProxyOptions(extraOptions config.ExtraOptionSlice) map[string]string {
 	kubeProxyOptions := extraOptions.AsMap().Get(Kubeproxy)
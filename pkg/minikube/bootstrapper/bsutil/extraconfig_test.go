@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
 )
 
 func TestFindInvalidExtraConfigFlags(t *testing.T) {
@@ -57,3 +58,44 @@ func TestFindInvalidExtraConfigFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestOIDCExtraOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		oidc config.OIDCConfig
+		want config.ExtraOptionSlice
+	}{
+		{
+			name: "disabled",
+			oidc: config.OIDCConfig{ClientID: "minikube"},
+			want: nil,
+		},
+		{
+			name: "issuer and client only",
+			oidc: config.OIDCConfig{IssuerURL: "https://dex.example.com", ClientID: "minikube", ClientSecret: "secret"},
+			want: config.ExtraOptionSlice{
+				{Component: Apiserver, Key: "oidc-issuer-url", Value: "https://dex.example.com"},
+				{Component: Apiserver, Key: "oidc-client-id", Value: "minikube"},
+			},
+		},
+		{
+			name: "with claims and ca",
+			oidc: config.OIDCConfig{IssuerURL: "https://dex.example.com", ClientID: "minikube", UsernameClaim: "email", GroupsClaim: "groups", GroupsPrefix: "oidc:", CAFile: "/home/user/dex-ca.pem"},
+			want: config.ExtraOptionSlice{
+				{Component: Apiserver, Key: "oidc-issuer-url", Value: "https://dex.example.com"},
+				{Component: Apiserver, Key: "oidc-client-id", Value: "minikube"},
+				{Component: Apiserver, Key: "oidc-username-claim", Value: "email"},
+				{Component: Apiserver, Key: "oidc-groups-claim", Value: "groups"},
+				{Component: Apiserver, Key: "oidc-groups-prefix", Value: "oidc:"},
+				{Component: Apiserver, Key: "oidc-ca-file", Value: vmpath.GuestOIDCCACert},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := oidcExtraOptions(tt.oidc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("oidcExtraOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
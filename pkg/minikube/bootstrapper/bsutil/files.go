@@ -30,6 +30,8 @@ const (
 	KubeletServiceFile = "/lib/systemd/system/kubelet.service"
 	// KubeletSystemdConfFile is config for the systemd kubelet.service
 	KubeletSystemdConfFile = "/etc/systemd/system/kubelet.service.d/10-kubeadm.conf"
+	// StandaloneKubeletConfigFile is the config of the kubelet of the dedicated etcd and load balancer nodes, where kubeadm does not write one
+	StandaloneKubeletConfigFile = "/var/lib/kubelet/config.yaml"
 	// InitRestartWrapper is ...
 	InitRestartWrapper = "/etc/init.d/.restart_wrapper.sh"
 	// KubeletInitPath is where Sys-V style init script is installed
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ktmpl
+
+import "text/template"
+
+// EtcdMember is the kubeadm config of a member of an external etcd, on a dedicated etcd node,
+// which kubeadm generates the certificates and the static pod of the member from
+// ref: https://kubernetes.io/docs/setup/production-environment/tools/kubeadm/setup-ha-etcd-with-kubeadm/
+var EtcdMember = template.Must(template.New("etcdMemberTmpl").Funcs(template.FuncMap{
+	"printMapInOrder": printMapInOrder,
+}).Parse(`apiVersion: kubeadm.k8s.io/{{.APIVersion}}
+kind: InitConfiguration
+localAPIEndpoint:
+  advertiseAddress: {{.AdvertiseAddress}}
+nodeRegistration:
+  name: "{{.NodeName}}"
+---
+apiVersion: kubeadm.k8s.io/{{.APIVersion}}
+kind: ClusterConfiguration
+{{if .ImageRepository}}imageRepository: {{.ImageRepository}}
+{{end}}certificatesDir: {{.CertDir}}
+etcd:
+  local:
+    dataDir: {{.EtcdDataDir}}
+    serverCertSANs:
+      - "{{.AdvertiseAddress}}"
+    peerCertSANs:
+      - "{{.AdvertiseAddress}}"
+    extraArgs:
+{{- if .ExtraArgsList}}
+{{- range $key, $val := .EtcdExtraArgs}}
+      - name: "{{$key}}"
+        value: "{{$val}}"
+{{- end}}
+{{- else}}
+{{- range $i, $val := printMapInOrder .EtcdExtraArgs ": "}}
+      {{$val}}
+{{- end}}
+{{- end}}
+kubernetesVersion: {{.KubernetesVersion}}
+`))
@@ -44,3 +44,23 @@ RestartSec=600ms
 [Install]
 WantedBy=multi-user.target
 `))
+
+// StandaloneKubeletConfig is the config of a kubelet which only runs the static pods of a node, without joining the cluster,
+// as on the dedicated etcd and load balancer nodes
+var StandaloneKubeletConfig = template.Must(template.New("standaloneKubeletConfigTmpl").Parse(`apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+address: 127.0.0.1
+authentication:
+  anonymous:
+    enabled: false
+  webhook:
+    enabled: false
+authorization:
+  mode: AlwaysAllow
+cgroupDriver: {{.CgroupDriver}}
+{{- if .ContainerRuntimeEndpoint}}
+containerRuntimeEndpoint: {{.ContainerRuntimeEndpoint}}
+{{- end}}
+failSwapOn: false
+staticPodPath: {{.StaticPodPath}}
+`))
@@ -51,6 +51,16 @@ kind: ClusterConfiguration
 {{- range $i, $val := printMapInOrder .ExtraArgs ": " }}
     {{$val}}
 {{- end}}
+{{- if .ExtraVolumes}}
+  extraVolumes:
+{{- range .ExtraVolumes}}
+    - name: {{.Name}}
+      hostPath: {{.HostPath}}
+      mountPath: {{.MountPath}}
+      readOnly: {{.ReadOnly}}
+      pathType: {{.PathType}}
+{{- end}}
+{{- end}}
 {{end -}}
 {{if .FeatureArgs}}featureGates:
 {{range $i, $val := .FeatureArgs}}{{$i}}: {{$val}}
@@ -61,10 +71,21 @@ controlPlaneEndpoint: {{.ControlPlaneAddress}}:{{.APIServerPort}}
 dns:
   type: CoreDNS
 etcd:
+{{- if .ExternalEtcd}}
+  external:
+    endpoints:
+{{- range .ExternalEtcd.Endpoints}}
+      - {{.}}
+{{- end}}
+    caFile: {{.ExternalEtcd.CAFile}}
+    certFile: {{.ExternalEtcd.CertFile}}
+    keyFile: {{.ExternalEtcd.KeyFile}}
+{{- else}}
   local:
     dataDir: {{.EtcdDataDir}}
     extraArgs:
       listen-metrics-urls: http://127.0.0.1:2381,http://{{.AdvertiseAddress}}:2381
+{{- end}}
 kubernetesVersion: {{.KubernetesVersion}}
 networking:
   dnsDomain: {{if .DNSDomain}}{{.DNSDomain}}{{else}}cluster.local{{end}}
@@ -51,6 +51,16 @@ kind: ClusterConfiguration
 {{- range $i, $val := printMapInOrder .ExtraArgs ": " }}
     {{$val}}
 {{- end}}
+{{- if .ExtraVolumes}}
+  extraVolumes:
+{{- range .ExtraVolumes}}
+    - name: {{.Name}}
+      hostPath: {{.HostPath}}
+      mountPath: {{.MountPath}}
+      readOnly: {{.ReadOnly}}
+      pathType: {{.PathType}}
+{{- end}}
+{{- end}}
 {{end -}}
 {{if .FeatureArgs}}featureGates:
 {{range $i, $val := .FeatureArgs}}{{$i}}: {{$val}}
@@ -61,6 +71,16 @@ controlPlaneEndpoint: {{.ControlPlaneAddress}}:{{.APIServerPort}}
 dns:
   type: CoreDNS
 etcd:
+{{- if .ExternalEtcd}}
+  external:
+    endpoints:
+{{- range .ExternalEtcd.Endpoints}}
+      - {{.}}
+{{- end}}
+    caFile: {{.ExternalEtcd.CAFile}}
+    certFile: {{.ExternalEtcd.CertFile}}
+    keyFile: {{.ExternalEtcd.KeyFile}}
+{{- else}}
   local:
     dataDir: {{.EtcdDataDir}}
     extraArgs:
@@ -68,6 +88,7 @@ etcd:
 {{- range $i, $val := printMapInOrder .EtcdExtraArgs ": " }}
       {{$val}}
 {{- end}}
+{{- end}}
 kubernetesVersion: {{.KubernetesVersion}}
 networking:
   dnsDomain: {{if .DNSDomain}}{{.DNSDomain}}{{else}}cluster.local{{end}}
@@ -51,6 +51,16 @@ kind: ClusterConfiguration
 {{- range $i, $val := printMapInOrder .ExtraArgs ": " }}
     {{$val}}
 {{- end}}
+{{- if .ExtraVolumes}}
+  extraVolumes:
+{{- range .ExtraVolumes}}
+    - name: {{.Name}}
+      hostPath: {{.HostPath}}
+      mountPath: {{.MountPath}}
+      readOnly: {{.ReadOnly}}
+      pathType: {{.PathType}}
+{{- end}}
+{{- end}}
 {{end -}}
 {{if .FeatureArgs}}featureGates:
 {{range $i, $val := .FeatureArgs}}{{$i}}: {{$val}}
@@ -59,6 +69,16 @@ certificatesDir: {{.CertDir}}
 clusterName: mk
 controlPlaneEndpoint: {{.ControlPlaneAddress}}:{{.APIServerPort}}
 etcd:
+{{- if .ExternalEtcd}}
+  external:
+    endpoints:
+{{- range .ExternalEtcd.Endpoints}}
+      - {{.}}
+{{- end}}
+    caFile: {{.ExternalEtcd.CAFile}}
+    certFile: {{.ExternalEtcd.CertFile}}
+    keyFile: {{.ExternalEtcd.KeyFile}}
+{{- else}}
   local:
     dataDir: {{.EtcdDataDir}}
     extraArgs:
@@ -66,6 +86,7 @@ etcd:
 {{- range $i, $val := printMapInOrder .EtcdExtraArgs ": " }}
       {{$val}}
 {{- end}}
+{{- end}}
 kubernetesVersion: {{.KubernetesVersion}}
 networking:
   dnsDomain: {{if .DNSDomain}}{{.DNSDomain}}{{else}}cluster.local{{end}}
@@ -53,6 +53,16 @@ kind: ClusterConfiguration
     - name: "{{$key}}"
       value: "{{$val}}"
 {{- end}}
+{{- if .ExtraVolumes}}
+  extraVolumes:
+{{- range .ExtraVolumes}}
+    - name: {{.Name}}
+      hostPath: {{.HostPath}}
+      mountPath: {{.MountPath}}
+      readOnly: {{.ReadOnly}}
+      pathType: {{.PathType}}
+{{- end}}
+{{- end}}
 {{end -}}
 {{if .FeatureArgs}}featureGates:
 {{range $i, $val := .FeatureArgs}}{{$i}}: {{$val}}
@@ -61,6 +71,16 @@ certificatesDir: {{.CertDir}}
 clusterName: mk
 controlPlaneEndpoint: {{.ControlPlaneAddress}}:{{.APIServerPort}}
 etcd:
+{{- if .ExternalEtcd}}
+  external:
+    endpoints:
+{{- range .ExternalEtcd.Endpoints}}
+      - {{.}}
+{{- end}}
+    caFile: {{.ExternalEtcd.CAFile}}
+    certFile: {{.ExternalEtcd.CertFile}}
+    keyFile: {{.ExternalEtcd.KeyFile}}
+{{- else}}
   local:
     dataDir: {{.EtcdDataDir}}
     extraArgs:
@@ -70,6 +90,7 @@ etcd:
       - name: "{{$key}}"
         value: "{{$val}}"
 {{- end}}
+{{- end}}
 kubernetesVersion: {{.KubernetesVersion}}
 networking:
   dnsDomain: {{if .DNSDomain}}{{.DNSDomain}}{{else}}cluster.local{{end}}
@@ -66,10 +66,17 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		return nil, errors.Wrap(err, "getting cgroup driver")
 	}
 
-	componentOpts, err := createExtraComponentConfig(k8s.ExtraOptions, version, componentFeatureArgs, n)
+	// options given with --extra-config come last, so they override the OIDC, audit and admission ones
+	extraOpts := append(APIServerOptions(k8s), k8s.ExtraOptions...)
+	componentOpts, err := createExtraComponentConfig(extraOpts, version, componentFeatureArgs, n)
 	if err != nil {
 		return nil, errors.Wrap(err, "generating extra component config for kubeadm")
 	}
+	for i := range componentOpts {
+		if componentOpts[i].Component == componentToKubeadmConfigKey[Apiserver] {
+			componentOpts[i].ExtraVolumes = apiServerExtraVolumes(k8s)
+		}
+	}
 
 	cnm, err := cni.New(&cc)
 	if err != nil {
@@ -116,6 +123,7 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		KubernetesVersion          string
 		EtcdDataDir                string
 		EtcdExtraArgs              map[string]string
+		ExternalEtcd               *externalEtcd
 		ClusterName                string
 		NodeName                   string
 		DNSDomain                  string
@@ -141,6 +149,7 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		KubernetesVersion: k8s.KubernetesVersion,
 		EtcdDataDir:       EtcdDataDir(),
 		EtcdExtraArgs:     etcdExtraArgs(k8s.ExtraOptions),
+		ExternalEtcd:      externalEtcdConfig(cc),
 		ClusterName:       cc.Name,
 		// kubeadm uses NodeName as the --hostname-override parameter, so this needs to be the name of the machine
 		NodeName:                   KubeNodeName(cc, n),
@@ -33,6 +33,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/vmpath"
 	"k8s.io/minikube/pkg/util"
 )
 
@@ -149,6 +150,64 @@ func NewKubeletConfig(mc config.ClusterConfig, nc config.Node, r cruntime.Manage
 	return b.Bytes(), nil
 }
 
+// NewStandaloneKubeletConfig generates the systemd unit and the config of a kubelet which only runs the static pods of a node,
+// without an apiserver to register with, as on the dedicated etcd and load balancer nodes.
+func NewStandaloneKubeletConfig(mc config.ClusterConfig, nc config.Node, r cruntime.Manager) ([]byte, []byte, error) {
+	k8s := mc.KubernetesConfig
+	version, err := util.ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "parsing Kubernetes version")
+	}
+	cgroupDriver, err := r.CGroupDriver()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "getting cgroup driver")
+	}
+
+	flags := map[string]string{
+		"config":            StandaloneKubeletConfigFile,
+		"hostname-override": KubeNodeName(mc, nc),
+		"node-ip":           nc.IP,
+	}
+	// the runtime endpoint is set in the config from k8s v1.27, as for the kubelets of the cluster
+	runtimeEndpoint := ""
+	for k, v := range r.KubeletOptions() {
+		if k == "container-runtime-endpoint" && version.GTE(semver.MustParse("1.27.0")) {
+			runtimeEndpoint = v
+			continue
+		}
+		flags[k] = v
+	}
+
+	unit := bytes.Buffer{}
+	unitOpts := struct {
+		ExtraOptions     string
+		ContainerRuntime string
+		KubeletPath      string
+	}{
+		ExtraOptions:     convertToFlags(flags),
+		ContainerRuntime: k8s.ContainerRuntime,
+		KubeletPath:      path.Join(binRoot(k8s.KubernetesVersion), "kubelet"),
+	}
+	if err := ktmpl.KubeletSystemdTemplate.Execute(&unit, unitOpts); err != nil {
+		return nil, nil, err
+	}
+
+	cfg := bytes.Buffer{}
+	cfgOpts := struct {
+		CgroupDriver             string
+		ContainerRuntimeEndpoint string
+		StaticPodPath            string
+	}{
+		CgroupDriver:             cgroupDriver,
+		ContainerRuntimeEndpoint: runtimeEndpoint,
+		StaticPodPath:            vmpath.GuestManifestsDir,
+	}
+	if err := ktmpl.StandaloneKubeletConfig.Execute(&cfg, cfgOpts); err != nil {
+		return nil, nil, err
+	}
+	return unit.Bytes(), cfg.Bytes(), nil
+}
+
 // NewKubeletService returns a generated systemd unit file for the kubelet
 func NewKubeletService(cfg config.KubernetesConfig) ([]byte, error) {
 	var b bytes.Buffer
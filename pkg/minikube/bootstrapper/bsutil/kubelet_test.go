@@ -20,7 +20,9 @@ package bsutil
 import (
 	"testing"
 
+	"github.com/blang/semver/v4"
 	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
@@ -203,3 +205,69 @@ ExecStart=/var/lib/minikube/binaries/v1.18.2/kubelet --authorization-mode=Webhoo
 		})
 	}
 }
+
+func TestNewStandaloneKubeletConfig(t *testing.T) {
+	fcr := command.NewFakeCommandRunner()
+	fcr.SetCommandToOutput(map[string]string{
+		"docker info --format {{.CgroupDriver}}": "systemd\n",
+	})
+	cc := config.ClusterConfig{
+		Name: "mk",
+		KubernetesConfig: config.KubernetesConfig{
+			KubernetesVersion: "v1.31.0",
+			ContainerRuntime:  "docker",
+		},
+		Nodes: []config.Node{
+			{Name: "", IP: "192.168.49.2", ControlPlane: true},
+			{Name: "m02", IP: "192.168.49.3", Etcd: true},
+		},
+	}
+	runtime, err := cruntime.New(cruntime.Config{Type: "docker", Runner: fcr, KubernetesVersion: semver.MustParse("1.31.0")})
+	if err != nil {
+		t.Fatalf("runtime: %v", err)
+	}
+
+	unit, cfg, err := NewStandaloneKubeletConfig(cc, cc.Nodes[1], runtime)
+	if err != nil {
+		t.Fatalf("NewStandaloneKubeletConfig: %v", err)
+	}
+	expectedUnit := `[Unit]
+Wants=docker.socket
+
+[Service]
+ExecStart=
+ExecStart=/var/lib/minikube/binaries/v1.31.0/kubelet --config=/var/lib/kubelet/config.yaml --hostname-override=mk-m02 --node-ip=192.168.49.3
+
+[Install]
+`
+	expectedCfg := `apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+address: 127.0.0.1
+authentication:
+  anonymous:
+    enabled: false
+  webhook:
+    enabled: false
+authorization:
+  mode: AlwaysAllow
+cgroupDriver: systemd
+containerRuntimeEndpoint: unix:///var/run/cri-dockerd.sock
+failSwapOn: false
+staticPodPath: /etc/kubernetes/manifests
+`
+	for _, tc := range []struct{ name, expected, got string }{{"unit", expectedUnit, string(unit)}, {"config", expectedCfg, string(cfg)}} {
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(tc.expected),
+			B:        difflib.SplitLines(tc.got),
+			FromFile: "Expected",
+			ToFile:   "Got",
+			Context:  1,
+		})
+		if err != nil {
+			t.Fatalf("diff error: %v", err)
+		}
+		if diff != "" {
+			t.Errorf("unexpected %s diff:\n%s", tc.name, diff)
+		}
+	}
+}
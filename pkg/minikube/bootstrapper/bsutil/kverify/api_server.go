@@ -28,6 +28,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -73,9 +74,9 @@ func WaitForAPIServerProcess(r cruntime.Manager, bs bootstrapper.Bootstrapper, c
 	return nil
 }
 
-// APIServerPID returns our best guess to the apiserver pid
+// APIServerPID returns our best guess to the apiserver pid, which is the k3s server for clusters bootstrapped with k3s
 func APIServerPID(cr command.Runner) (int, error) {
-	rr, err := cr.RunCmd(exec.Command("sudo", "pgrep", "-xnf", "kube-apiserver.*minikube.*"))
+	rr, err := cr.RunCmd(exec.Command("sudo", "pgrep", "-xnf", "kube-apiserver.*minikube.*|.*minikube.*k3s server.*"))
 	if err != nil {
 		return 0, err
 	}
@@ -98,7 +99,7 @@ func WaitForHealthyAPIServer(r cruntime.Manager, bs bootstrapper.Bootstrapper, c
 			time.Sleep(kconst.APICallRetryInterval * 5)
 		}
 
-		status, err := apiServerHealthzNow(hostname, port)
+		status, err := apiServerHealthzNow(cfg.Name, hostname, port)
 		if err != nil {
 			klog.Warningf("status: %v", err)
 			return false, nil
@@ -148,11 +149,11 @@ func APIServerVersionMatch(client *kubernetes.Clientset, expected string) error
 // WaitForAPIServerStatus waits for 'to' duration to get apiserver pod running or stopped
 // this functions is intended to use in situations where apiserver process can be recreated
 // by container runtime restart for example and there is a gap before it comes back
-func WaitForAPIServerStatus(cr command.Runner, to time.Duration, hostname string, port int) (state.State, error) {
+func WaitForAPIServerStatus(cr command.Runner, to time.Duration, clusterName, hostname string, port int) (state.State, error) {
 	var st state.State
 	err := wait.PollUntilContextTimeout(context.Background(), 500*time.Millisecond, to, true, func(_ context.Context) (bool, error) {
 		var err error
-		st, err = APIServerStatus(cr, hostname, port)
+		st, err = APIServerStatus(cr, clusterName, hostname, port)
 		if st == state.Stopped {
 			return false, nil
 		}
@@ -162,7 +163,7 @@ func WaitForAPIServerStatus(cr command.Runner, to time.Duration, hostname string
 }
 
 // APIServerStatus returns apiserver status in libmachine style state.State
-func APIServerStatus(cr command.Runner, hostname string, port int) (state.State, error) {
+func APIServerStatus(cr command.Runner, clusterName, hostname string, port int) (state.State, error) {
 	klog.Infof("Checking apiserver status ...")
 
 	pid, err := APIServerPID(cr)
@@ -175,7 +176,7 @@ func APIServerStatus(cr command.Runner, hostname string, port int) (state.State,
 	rr, err := cr.RunCmd(exec.Command("sudo", "egrep", "^[0-9]+:freezer:", fmt.Sprintf("/proc/%d/cgroup", pid)))
 	if err != nil {
 		klog.Warningf("unable to find freezer cgroup: %v", err)
-		return nonFreezerServerStatus(cr, hostname, port)
+		return nonFreezerServerStatus(cr, clusterName, hostname, port)
 
 	}
 	freezer := strings.TrimSpace(rr.Stdout.String())
@@ -183,7 +184,7 @@ func APIServerStatus(cr command.Runner, hostname string, port int) (state.State,
 	fparts := strings.Split(freezer, ":")
 	if len(fparts) != 3 {
 		klog.Warningf("unable to parse freezer - found %d parts: %s", len(fparts), freezer)
-		return nonFreezerServerStatus(cr, hostname, port)
+		return nonFreezerServerStatus(cr, clusterName, hostname, port)
 	}
 
 	rr, err = cr.RunCmd(exec.Command("sudo", "cat", path.Join("/sys/fs/cgroup/freezer", fparts[2], "freezer.state")))
@@ -197,7 +198,7 @@ func APIServerStatus(cr command.Runner, hostname string, port int) (state.State,
 			klog.Warningf("unable to get freezer state: %s", rr.Stderr.String())
 		}
 
-		return nonFreezerServerStatus(cr, hostname, port)
+		return nonFreezerServerStatus(cr, clusterName, hostname, port)
 	}
 
 	fs := strings.TrimSpace(rr.Stdout.String())
@@ -205,11 +206,11 @@ func APIServerStatus(cr command.Runner, hostname string, port int) (state.State,
 	if fs == "FREEZING" || fs == "FROZEN" {
 		return state.Paused, nil
 	}
-	return apiServerHealthz(hostname, port)
+	return apiServerHealthz(clusterName, hostname, port)
 }
 
 // nonFreezerServerStatus is the alternative flow if the guest does not have the freezer cgroup so different methods to detect the apiserver status are used
-func nonFreezerServerStatus(cr command.Runner, hostname string, port int) (state.State, error) {
+func nonFreezerServerStatus(cr command.Runner, clusterName, hostname string, port int) (state.State, error) {
 	rr, err := cr.RunCmd(exec.Command("ls"))
 	if err != nil {
 		return state.None, err
@@ -217,17 +218,17 @@ func nonFreezerServerStatus(cr command.Runner, hostname string, port int) (state
 	if strings.Contains(rr.Stdout.String(), "paused") {
 		return state.Paused, nil
 	}
-	return apiServerHealthz(hostname, port)
+	return apiServerHealthz(clusterName, hostname, port)
 }
 
 // apiServerHealthz checks apiserver in a patient and tolerant manner
-func apiServerHealthz(hostname string, port int) (state.State, error) {
+func apiServerHealthz(clusterName, hostname string, port int) (state.State, error) {
 	var st state.State
 	var err error
 
 	check := func() error {
 		// etcd gets upset sometimes and causes healthz to report a failure. Be tolerant of it.
-		st, err = apiServerHealthzNow(hostname, port)
+		st, err = apiServerHealthzNow(clusterName, hostname, port)
 		if err != nil {
 			return err
 		}
@@ -247,11 +248,20 @@ func apiServerHealthz(hostname string, port int) (state.State, error) {
 	return st, err
 }
 
+// clusterCACert returns the CA of a cluster, which clusters started with --ca-cert keep in their profile
+func clusterCACert(clusterName string) string {
+	p := filepath.Join(localpath.Profile(clusterName), "ca.crt")
+	if _, err := os.Stat(p); err == nil {
+		return p
+	}
+	return localpath.CACert()
+}
+
 // apiServerHealthzNow hits the /healthz endpoint and returns libmachine style state.State
-func apiServerHealthzNow(hostname string, port int) (state.State, error) {
+func apiServerHealthzNow(clusterName, hostname string, port int) (state.State, error) {
 	url := fmt.Sprintf("https://%s/healthz", net.JoinHostPort(hostname, fmt.Sprint(port)))
 	klog.Infof("Checking apiserver healthz at %s ...", url)
-	cert, err := os.ReadFile(localpath.CACert())
+	cert, err := os.ReadFile(clusterCACert(clusterName))
 	if err != nil {
 		klog.Infof("ca certificate: %v", err)
 		return state.Stopped, err
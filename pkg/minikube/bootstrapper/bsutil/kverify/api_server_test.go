@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kverify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestClusterCACert(t *testing.T) {
+	t.Setenv(localpath.MinikubeHome, t.TempDir())
+	custom := filepath.Join(localpath.Profile("custom"), "ca.crt")
+	if err := os.MkdirAll(filepath.Dir(custom), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(custom, []byte("custom ca"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := clusterCACert("custom"); got != custom {
+		t.Errorf("clusterCACert of a cluster started with --ca-cert = %q, want %q", got, custom)
+	}
+	if got := clusterCACert("minikube"); got != localpath.CACert() {
+		t.Errorf("clusterCACert = %q, want %q", got, localpath.CACert())
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kverify verifies a running Kubernetes cluster is healthy
+package kverify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	kconst "k8s.io/minikube/third_party/kubeadm/app/constants"
+)
+
+// ResourceGate is a condition of a Kubernetes resource to wait for, given with --wait-for as <kind>/[<namespace>/]<name>=<condition>[@<timeout>]
+type ResourceGate struct {
+	Expression string
+	Resource   schema.GroupVersionResource
+	Namespace  string
+	Name       string
+	Condition  string
+	// Timeout is how long to wait for the condition, the --wait-timeout if zero
+	Timeout time.Duration
+}
+
+// String returns the resource the gate waits for, as given with --wait-for
+func (g ResourceGate) String() string {
+	return g.Expression
+}
+
+// gateKind is a kind of resource which can be waited for by its short name
+type gateKind struct {
+	resource   schema.GroupVersionResource
+	namespaced bool
+}
+
+// gateKinds are the kinds of resources which can be waited for by their short name, other resources are given as <resource>.<version>.<group>
+var gateKinds = map[string]gateKind{
+	"deployment":  {schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, true},
+	"statefulset": {schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, true},
+	"daemonset":   {schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, true},
+	"job":         {schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, true},
+	"pod":         {schema.GroupVersionResource{Version: "v1", Resource: "pods"}, true},
+	"node":        {schema.GroupVersionResource{Version: "v1", Resource: "nodes"}, false},
+	"crd":         {schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}, false},
+	"apiservice":  {schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}, false},
+}
+
+// ResourceGateKinds returns the short names of the kinds of resources which can be waited for
+func ResourceGateKinds() []string {
+	kinds := []string{}
+	for k := range gateKinds {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// ParseResourceGates parses the resource conditions given with --wait-for
+func ParseResourceGates(exprs []string) ([]ResourceGate, error) {
+	gates := []ResourceGate{}
+	for _, expr := range exprs {
+		g, err := ParseResourceGate(expr)
+		if err != nil {
+			return nil, err
+		}
+		gates = append(gates, g)
+	}
+	return gates, nil
+}
+
+// ParseResourceGate parses a resource condition, such as deployment/ns/name=Available, crd/foos.example.com=Established@2m or foos.v1.example.com/ns/name=Ready
+func ParseResourceGate(expr string) (ResourceGate, error) {
+	g := ResourceGate{Expression: expr}
+	invalid := func(format string, a ...interface{}) (ResourceGate, error) {
+		return ResourceGate{}, fmt.Errorf("invalid --wait-for %q: %s, expected <kind>/[<namespace>/]<name>=<condition>[@<timeout>]", expr, fmt.Sprintf(format, a...))
+	}
+
+	ref, cond, ok := strings.Cut(expr, "=")
+	if !ok {
+		return invalid("missing condition")
+	}
+	if c, timeout, ok := strings.Cut(cond, "@"); ok {
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return invalid("timeout %q is not a positive duration", timeout)
+		}
+		cond = c
+		g.Timeout = d
+	}
+	if cond == "" {
+		return invalid("missing condition")
+	}
+	g.Condition = cond
+
+	parts := strings.Split(ref, "/")
+	for _, p := range parts {
+		if p == "" {
+			return invalid("empty kind, namespace or name")
+		}
+	}
+	if len(parts) < 2 || len(parts) > 3 {
+		return invalid("expected a kind and a name")
+	}
+
+	kind, ok := gateKinds[strings.ToLower(parts[0])]
+	switch {
+	case ok:
+		g.Resource = kind.resource
+	case strings.Count(parts[0], ".") >= 2:
+		// <resource>.<version>.<group>, as in kubectl get, whose scope follows from the given namespace
+		r, rest, _ := strings.Cut(parts[0], ".")
+		v, group, _ := strings.Cut(rest, ".")
+		g.Resource = schema.GroupVersionResource{Group: group, Version: v, Resource: r}
+		kind.namespaced = len(parts) == 3
+	default:
+		return invalid("unknown kind %q, expected one of %s or <resource>.<version>.<group>", parts[0], strings.Join(ResourceGateKinds(), ", "))
+	}
+
+	switch {
+	case len(parts) == 3 && !kind.namespaced:
+		return invalid("%s is not namespaced", parts[0])
+	case len(parts) == 3:
+		g.Namespace, g.Name = parts[1], parts[2]
+	case kind.namespaced:
+		g.Namespace, g.Name = meta.NamespaceDefault, parts[1]
+	default:
+		g.Name = parts[1]
+	}
+	return g, nil
+}
+
+// WaitForResourceGates waits for the conditions of all the resources at once, each up to its own timeout or the given one,
+// returning which of them are still pending, and why, if any of them timed out
+func WaitForResourceGates(dc dynamic.Interface, gates []ResourceGate, timeout time.Duration) error {
+	klog.Infof("waiting up to %v for resources %v ...", timeout, gates)
+	start := time.Now()
+	defer func() {
+		klog.Infof("duration metric: took %s to wait for resources %v ...", time.Since(start), gates)
+	}()
+
+	pending := make([]string, len(gates))
+	var wg sync.WaitGroup
+	for i, g := range gates {
+		wg.Add(1)
+		go func(i int, g ResourceGate) {
+			defer wg.Done()
+			to := timeout
+			if g.Timeout > 0 {
+				to = g.Timeout
+			}
+			if reason := waitResourceGate(dc, g, to); reason != "" {
+				pending[i] = fmt.Sprintf("%s (waited %v): %s", g, to, reason)
+			}
+		}(i, g)
+	}
+	wg.Wait()
+
+	summary := []string{}
+	for _, p := range pending {
+		if p != "" {
+			summary = append(summary, p)
+		}
+	}
+	if len(summary) > 0 {
+		return fmt.Errorf("timed out waiting for %d of %d resources:\n\t%s", len(summary), len(gates), strings.Join(summary, "\n\t"))
+	}
+	return nil
+}
+
+// waitResourceGate waits for the condition of a resource, returning why it is still pending on timeout
+func waitResourceGate(dc dynamic.Interface, g ResourceGate, timeout time.Duration) string {
+	klog.Infof("waiting up to %v for %s ...", timeout, g)
+	reason := ""
+	checkCondition := func(_ context.Context) (bool, error) {
+		var done bool
+		done, reason = resourceGateStatus(dc, g)
+		if !done {
+			klog.V(2).Infof("%s: %s", g, reason)
+		}
+		return done, nil
+	}
+	if err := wait.PollUntilContextTimeout(context.Background(), kconst.APICallRetryInterval, timeout, true, checkCondition); err != nil {
+		return reason
+	}
+	klog.Infof("%s: condition %s met", g, g.Condition)
+	return ""
+}
+
+// resourceGateStatus returns if the condition of a resource is met and verbose reason
+func resourceGateStatus(dc dynamic.Interface, g ResourceGate) (bool, string) {
+	var ri dynamic.ResourceInterface = dc.Resource(g.Resource)
+	if g.Namespace != "" {
+		ri = dc.Resource(g.Resource).Namespace(g.Namespace)
+	}
+	obj, err := ri.Get(context.Background(), g.Name, meta.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, "not found"
+	}
+	if err != nil {
+		return false, fmt.Sprintf("error getting resource: %v", err)
+	}
+
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false, fmt.Sprintf("invalid status conditions: %v", err)
+	}
+	for _, c := range conditions {
+		cm, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _ := cm["type"].(string); !strings.EqualFold(t, g.Condition) {
+			continue
+		}
+		status, _ := cm["status"].(string)
+		if status == string(meta.ConditionTrue) {
+			return true, ""
+		}
+		reason := fmt.Sprintf("condition %s is %q", g.Condition, status)
+		if r, _ := cm["reason"].(string); r != "" {
+			reason += ", reason " + r
+		}
+		if m, _ := cm["message"].(string); m != "" {
+			reason += ": " + m
+		}
+		return false, reason
+	}
+	return false, fmt.Sprintf("no %s condition reported yet", g.Condition)
+}
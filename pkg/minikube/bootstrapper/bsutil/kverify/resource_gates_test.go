@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kverify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestParseResourceGate(t *testing.T) {
+	tests := []struct {
+		expr      string
+		want      ResourceGate
+		shouldErr bool
+	}{
+		{
+			expr: "deployment/operators/foo-operator=Available",
+			want: ResourceGate{Resource: gateKinds["deployment"].resource, Namespace: "operators", Name: "foo-operator", Condition: "Available"},
+		},
+		{
+			expr: "job/migrate=Complete@90s",
+			want: ResourceGate{Resource: gateKinds["job"].resource, Namespace: "default", Name: "migrate", Condition: "Complete", Timeout: 90 * time.Second},
+		},
+		{
+			expr: "crd/foos.example.com=Established",
+			want: ResourceGate{Resource: gateKinds["crd"].resource, Name: "foos.example.com", Condition: "Established"},
+		},
+		{
+			expr: "foos.v1alpha1.example.com/ns/bar=Ready",
+			want: ResourceGate{Resource: schema.GroupVersionResource{Group: "example.com", Version: "v1alpha1", Resource: "foos"}, Namespace: "ns", Name: "bar", Condition: "Ready"},
+		},
+		{expr: "deployment/ns/name", shouldErr: true},
+		{expr: "deployment/ns/name=", shouldErr: true},
+		{expr: "deployment=Available", shouldErr: true},
+		{expr: "crd/ns/foos.example.com=Established", shouldErr: true},
+		{expr: "widget/ns/name=Ready", shouldErr: true},
+		{expr: "job/ns/name=Complete@soon", shouldErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			got, err := ParseResourceGate(tc.expr)
+			if tc.shouldErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tc.want.Expression = tc.expr
+			if got != tc.want {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWaitForResourceGates(t *testing.T) {
+	deployment := func(name, status string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "ns"},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": status, "reason": "MinimumReplicasUnavailable"},
+				},
+			},
+		}}
+	}
+	dc := fake.NewSimpleDynamicClient(runtime.NewScheme(), deployment("ready", "True"), deployment("unready", "False"))
+
+	gates, err := ParseResourceGates([]string{"deployment/ns/ready=Available", "deployment/ns/unready=Available@10ms", "deployment/ns/missing=Available"})
+	if err != nil {
+		t.Fatalf("ParseResourceGates: %v", err)
+	}
+	err = WaitForResourceGates(dc, gates, 10*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected the unready and missing deployments to time out")
+	}
+	for _, want := range []string{"2 of 3", "deployment/ns/unready=Available@10ms (waited 10ms): condition Available is \"False\", reason MinimumReplicasUnavailable", "deployment/ns/missing=Available (waited 10ms): not found"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to contain %q, got: %v", want, err)
+		}
+	}
+
+	if err := WaitForResourceGates(dc, gates[:1], 10*time.Millisecond); err != nil {
+		t.Errorf("expected the ready deployment not to time out, got: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// HostLocation is the location of the certificates kept on the host rather than on a node
+const HostLocation = "host"
+
+// nodeCertPaths are where the certificates are kept on the nodes
+var nodeCertPaths = []string{vmpath.GuestKubernetesCertsDir, "/etc/kubernetes", "/var/lib/kubelet/pki"}
+
+// CertInfo describes a certificate of a cluster
+type CertInfo struct {
+	// Location is the machine name of the node the certificate is on, or HostLocation
+	Location string    `json:"location"`
+	Path     string    `json:"path"`
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	SANs     []string  `json:"sans,omitempty"`
+	IsCA     bool      `json:"isCA"`
+	NotAfter time.Time `json:"notAfter"`
+}
+
+// ExpiresIn returns how long until the certificate expires
+func (c CertInfo) ExpiresIn() time.Duration {
+	return time.Until(c.NotAfter)
+}
+
+// parseCertInfo parses the first certificate of a PEM file
+func parseCertInfo(data []byte, location, p string) (CertInfo, error) {
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			return CertInfo{}, fmt.Errorf("no certificate found in %s", p)
+		}
+		data = rest
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return CertInfo{}, errors.Wrapf(err, "parse %s", p)
+		}
+		sans := append([]string{}, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		return CertInfo{
+			Location: location,
+			Path:     p,
+			Subject:  cert.Subject.String(),
+			Issuer:   cert.Issuer.String(),
+			SANs:     sans,
+			IsCA:     cert.IsCA,
+			NotAfter: cert.NotAfter,
+		}, nil
+	}
+}
+
+// kubeconfigCertInfo parses the client certificates embedded in a kubeconfig file
+func kubeconfigCertInfo(data []byte, location, p string) ([]CertInfo, error) {
+	kcfg, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load %s", p)
+	}
+	certs := []CertInfo{}
+	for name, user := range kcfg.AuthInfos {
+		if len(user.ClientCertificateData) == 0 {
+			continue
+		}
+		c, err := parseCertInfo(user.ClientCertificateData, location, fmt.Sprintf("%s (%s)", p, name))
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+	return certs, nil
+}
+
+// HostCerts returns the shared CA certs and the certs of a profile kept on the host
+func HostCerts(cc config.ClusterConfig) ([]CertInfo, error) {
+	profilePath := localpath.Profile(cc.Name)
+	paths := []string{
+		CACert(cc),
+		filepath.Join(localpath.MiniPath(), "proxy-client-ca.crt"),
+		localpath.ClientCert(cc.Name),
+		filepath.Join(profilePath, "apiserver.crt"),
+		filepath.Join(profilePath, "proxy-client.crt"),
+	}
+
+	certs := []CertInfo{}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				klog.Infof("skipping missing cert %s", p)
+				continue
+			}
+			return nil, errors.Wrapf(err, "read %s", p)
+		}
+		c, err := parseCertInfo(data, HostLocation, p)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+	return certs, nil
+}
+
+// NodeCerts returns the certs on a node: the ones kubeadm and minikube put in the certs dir,
+// the client certs embedded in the kubeconfig files of the control plane, and the kubelet certs.
+func NodeCerts(cr command.Runner, machineName string) ([]CertInfo, error) {
+	args := append([]string{"find"}, nodeCertPaths...)
+	args = append(args, "-maxdepth", "2", "(", "-name", "*.crt", "-o", "-name", "*.conf", "-o", "-name", "kubelet-client-current.pem", ")")
+	rr, err := cr.RunCmd(exec.Command("sudo", args...))
+	if err != nil {
+		return nil, errors.Wrap(err, "find certs")
+	}
+	paths := strings.Fields(rr.Stdout.String())
+	sort.Strings(paths)
+
+	certs := []CertInfo{}
+	for _, p := range paths {
+		rr, err := cr.RunCmd(exec.Command("sudo", "cat", p))
+		if err != nil {
+			return nil, errors.Wrapf(err, "read %s", p)
+		}
+		if strings.HasSuffix(p, ".conf") {
+			kc, err := kubeconfigCertInfo(rr.Stdout.Bytes(), machineName, p)
+			if err != nil {
+				klog.Warningf("skipping %s: %v", p, err)
+				continue
+			}
+			certs = append(certs, kc...)
+			continue
+		}
+		c, err := parseCertInfo(rr.Stdout.Bytes(), machineName, p)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+	return certs, nil
+}
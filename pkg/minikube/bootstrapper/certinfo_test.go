@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestHostCertsAndRemoveCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir(t)
+	cc := config.ClusterConfig{Name: "certs"}
+	profilePath := localpath.Profile(cc.Name)
+	if err := os.MkdirAll(profilePath, 0777); err != nil {
+		t.Fatalf("error creating profile directory: %v", err)
+	}
+
+	caKey := filepath.Join(tempDir, "ca.key")
+	if err := util.GenerateCACert(localpath.CACert(), caKey, "minikubeCA"); err != nil {
+		t.Fatalf("error generating ca cert: %v", err)
+	}
+	apiserverCert := filepath.Join(profilePath, "apiserver.crt")
+	if err := util.GenerateSignedCert(apiserverCert, filepath.Join(profilePath, "apiserver.key"), "minikube",
+		[]net.IP{net.ParseIP("192.168.49.2")}, []string{"control-plane.minikube.internal"},
+		localpath.CACert(), caKey, 24*time.Hour); err != nil {
+		t.Fatalf("error generating apiserver cert: %v", err)
+	}
+	// a copy of the apiserver cert under the hash of its IPs and names
+	if err := os.WriteFile(apiserverCert+".1a2b3c4d", []byte{}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	certs, err := HostCerts(cc)
+	if err != nil {
+		t.Fatalf("HostCerts() error: %v", err)
+	}
+	if len(certs) != 2 {
+		t.Fatalf("HostCerts() = %v, want the ca and apiserver certs", certs)
+	}
+	if !certs[0].IsCA || certs[0].Subject != "CN=minikubeCA" {
+		t.Errorf("HostCerts()[0] = %+v, want the minikube CA", certs[0])
+	}
+	apiserver := certs[1]
+	if apiserver.Location != HostLocation || apiserver.Issuer != "CN=minikubeCA" {
+		t.Errorf("HostCerts()[1] = %+v, want the apiserver cert on the host issued by the minikube CA", apiserver)
+	}
+	if !slices.Contains(apiserver.SANs, "control-plane.minikube.internal") || !slices.Contains(apiserver.SANs, "192.168.49.2") {
+		t.Errorf("HostCerts()[1].SANs = %v, want the name and IP of the control plane", apiserver.SANs)
+	}
+	if d := apiserver.ExpiresIn(); d <= 0 || d > 24*time.Hour {
+		t.Errorf("HostCerts()[1].ExpiresIn() = %s, want within a day", d)
+	}
+
+	if err := RemoveCerts(cc, false); err != nil {
+		t.Fatalf("RemoveCerts() error: %v", err)
+	}
+	for _, f := range []string{apiserverCert, apiserverCert + ".1a2b3c4d"} {
+		if _, err := os.Stat(f); !os.IsNotExist(err) {
+			t.Errorf("RemoveCerts() kept %s", f)
+		}
+	}
+	if _, err := os.Stat(localpath.CACert()); err != nil {
+		t.Errorf("RemoveCerts() without ca removed the ca cert: %v", err)
+	}
+
+	if err := RemoveCerts(cc, true); err != nil {
+		t.Fatalf("RemoveCerts() error: %v", err)
+	}
+	if _, err := os.Stat(localpath.CACert()); !os.IsNotExist(err) {
+		t.Errorf("RemoveCerts() with ca kept the ca cert")
+	}
+}
+
+func TestKubeconfigCertInfo(t *testing.T) {
+	tempDir := t.TempDir()
+	cert := filepath.Join(tempDir, "admin.crt")
+	if err := util.GenerateCACert(cert, filepath.Join(tempDir, "admin.key"), "kubernetes-admin"); err != nil {
+		t.Fatalf("error generating cert: %v", err)
+	}
+	data, err := os.ReadFile(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kcfg := api.NewConfig()
+	kcfg.AuthInfos["kubernetes-admin"] = &api.AuthInfo{ClientCertificateData: data}
+	kcfg.AuthInfos["token"] = &api.AuthInfo{Token: "abc"}
+	b, err := clientcmd.Write(*kcfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certs, err := kubeconfigCertInfo(b, "minikube", "/etc/kubernetes/admin.conf")
+	if err != nil {
+		t.Fatalf("kubeconfigCertInfo() error: %v", err)
+	}
+	if len(certs) != 1 || certs[0].Subject != "CN=kubernetes-admin" || certs[0].Path != "/etc/kubernetes/admin.conf (kubernetes-admin)" {
+		t.Errorf("kubeconfigCertInfo() = %+v, want only the cert of kubernetes-admin", certs)
+	}
+}
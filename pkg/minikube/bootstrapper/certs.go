@@ -54,6 +54,9 @@ import (
 	"k8s.io/minikube/pkg/util/lock"
 )
 
+// KubeadmCerts are the certs kubeadm issues to the control-plane nodes, as opposed to the ones minikube provides
+var KubeadmCerts = []string{"apiserver-etcd-client", "apiserver-kubelet-client", "etcd-server", "etcd-healthcheck-client", "etcd-peer", "front-proxy-client"}
+
 // sharedCACerts represents minikube Root CA and Proxy Client CA certs and keys shared among profiles.
 type sharedCACerts struct {
 	caCert    string
@@ -67,7 +70,7 @@ func SetupCerts(k8s config.ClusterConfig, n config.Node, pcpCmd command.Runner,
 	localPath := localpath.Profile(k8s.KubernetesConfig.ClusterName)
 	klog.Infof("Setting up %s for IP: %s", localPath, n.IP)
 
-	sharedCerts, regen, err := generateSharedCACerts()
+	sharedCerts, regen, err := generateSharedCACerts(k8s)
 	if err != nil {
 		return errors.Wrap(err, "generate shared ca certs")
 	}
@@ -106,7 +109,7 @@ func SetupCerts(k8s config.ClusterConfig, n config.Node, pcpCmd command.Runner,
 		copyableFiles = append(copyableFiles, certFile)
 	}
 
-	caCerts, err := collectCACerts()
+	caCerts, err := collectCACerts(k8s)
 	if err != nil {
 		return errors.Wrap(err, "collect ca certs")
 	}
@@ -125,17 +128,25 @@ func SetupCerts(k8s config.ClusterConfig, n config.Node, pcpCmd command.Runner,
 		// copy essential certs from primary control-plane node to secondaries
 		// ref: https://kubernetes.io/docs/setup/production-environment/tools/kubeadm/high-availability/#manual-certs
 		if !config.IsPrimaryControlPlane(k8s, n) {
-			pcpCerts := []struct {
+			type pcpCert struct {
 				srcDir  string
 				srcFile string
 				dstFile string
-			}{
+			}
+			pcpCerts := []pcpCert{
 				{vmpath.GuestKubernetesCertsDir, "sa.pub", "sa.pub"},
 				{vmpath.GuestKubernetesCertsDir, "sa.key", "sa.key"},
 				{vmpath.GuestKubernetesCertsDir, "front-proxy-ca.crt", "front-proxy-ca.crt"},
 				{vmpath.GuestKubernetesCertsDir, "front-proxy-ca.key", "front-proxy-ca.key"},
 				{vmpath.GuestKubernetesCertsDir + "/etcd", "ca.crt", "etcd-ca.crt"},
-				{vmpath.GuestKubernetesCertsDir + "/etcd", "ca.key", "etcd-ca.key"},
+			}
+			if config.IsExternalEtcd(k8s) {
+				// the apiserver authenticates to the external etcd, whose CA key stays on the etcd nodes
+				pcpCerts = append(pcpCerts,
+					pcpCert{vmpath.GuestKubernetesCertsDir, "apiserver-etcd-client.crt", "apiserver-etcd-client.crt"},
+					pcpCert{vmpath.GuestKubernetesCertsDir, "apiserver-etcd-client.key", "apiserver-etcd-client.key"})
+			} else {
+				pcpCerts = append(pcpCerts, pcpCert{vmpath.GuestKubernetesCertsDir + "/etcd", "ca.key", "etcd-ca.key"})
 			}
 			for _, c := range pcpCerts {
 				// get cert from primary control-plane node
@@ -189,8 +200,38 @@ func SetupCerts(k8s config.ClusterConfig, n config.Node, pcpCmd command.Runner,
 	return nil
 }
 
+// CACert returns the path on the host of the CA cert which signs the certs of a cluster
+func CACert(cc config.ClusterConfig) string {
+	if cc.CustomCACert != "" {
+		return filepath.Join(localpath.Profile(cc.Name), "ca.crt")
+	}
+	return localpath.CACert()
+}
+
+// copyCustomCA copies the CA a cluster was started with into its profile, where it is kept for the lifetime of the cluster.
+// The copy is named after the minikube CA, as it is installed in its place on the nodes.
+func copyCustomCA(cc config.ClusterConfig) (string, string, error) {
+	if err := util.ValidateCACert(cc.CustomCACert, cc.CustomCAKey); err != nil {
+		return "", "", errors.Wrap(err, "validate ca cert")
+	}
+	certPath := CACert(cc)
+	keyPath := filepath.Join(localpath.Profile(cc.Name), "ca.key")
+	klog.Infof("copying custom ca cert %s -> %s", cc.CustomCACert, certPath)
+	if err := copy.Copy(cc.CustomCACert, certPath, copy.Options{PermissionControl: copy.AddPermission(0644)}); err != nil {
+		return "", "", errors.Wrap(err, "copy ca cert")
+	}
+	if err := copy.Copy(cc.CustomCAKey, keyPath); err != nil {
+		return "", "", errors.Wrap(err, "copy ca key")
+	}
+	if err := os.Chmod(keyPath, 0600); err != nil {
+		return "", "", errors.Wrap(err, "chmod ca key")
+	}
+	return certPath, keyPath, nil
+}
+
 // generateSharedCACerts generates minikube Root CA and Proxy Client CA certs, but only if missing or expired.
-func generateSharedCACerts() (sharedCACerts, bool, error) {
+// The minikube Root CA is replaced by the custom CA of the cluster if it was started with one.
+func generateSharedCACerts(k8s config.ClusterConfig) (sharedCACerts, bool, error) {
 	klog.Info("generating shared ca certs ...")
 
 	regenProfileCerts := false
@@ -230,6 +271,14 @@ func generateSharedCACerts() (sharedCACerts, bool, error) {
 	}
 	defer releaser.Release()
 
+	if k8s.CustomCACert != "" {
+		// the custom CA is provided rather than generated, profile certs signed by another CA are replaced by generateProfileCerts
+		caCertSpecs = caCertSpecs[1:]
+		if cc.caCert, cc.caKey, err = copyCustomCA(k8s); err != nil {
+			return cc, false, err
+		}
+	}
+
 	for _, ca := range caCertSpecs {
 		if isValid(ca.certPath, ca.keyPath) {
 			klog.Infof("skipping valid %q ca cert: %s", ca.subject, ca.keyPath)
@@ -355,7 +404,7 @@ func generateProfileCerts(cfg config.ClusterConfig, n config.Node, shared shared
 			kp = kp + "." + spec.hash
 		}
 
-		if !regen && isValid(cp, kp) {
+		if !regen && isValid(cp, kp) && isSignedBy(cp, spec.caCertPath) {
 			klog.Infof("skipping valid signed profile cert regeneration for %q: %s", spec.subject, kp)
 			continue
 		}
@@ -392,6 +441,50 @@ func generateProfileCerts(cfg config.ClusterConfig, n config.Node, shared shared
 	return xfer, nil
 }
 
+// RemoveCerts removes the certs of a profile from the host, so that SetupCerts issues new ones.
+// If ca is set, the shared CA certs are removed as well, so that every profile gets new certs on its next SetupCerts.
+func RemoveCerts(cc config.ClusterConfig, ca bool) error {
+	profilePath := localpath.Profile(cc.Name)
+	files := []string{}
+	for _, name := range []string{"client", "apiserver", "proxy-client"} {
+		files = append(files, filepath.Join(profilePath, name+".crt"), filepath.Join(profilePath, name+".key"))
+	}
+	// the apiserver cert is also kept under a hash of its IPs and names
+	for _, pattern := range []string{"apiserver.crt.*", "apiserver.key.*"} {
+		hashed, err := filepath.Glob(filepath.Join(profilePath, pattern))
+		if err != nil {
+			return errors.Wrapf(err, "glob %s", pattern)
+		}
+		files = append(files, hashed...)
+	}
+
+	if ca && cc.CustomCACert != "" {
+		klog.Infof("not removing the shared ca certs, %s signs the certs of %s", cc.CustomCACert, cc.Name)
+	} else if ca {
+		globalPath := localpath.MiniPath()
+		hold := filepath.Join(globalPath, "ca-certs")
+		spec := lock.PathMutexSpec(hold)
+		spec.Timeout = 1 * time.Minute
+		klog.Infof("acquiring lock for ca certs: %+v", spec)
+		releaser, err := mutex.Acquire(spec)
+		if err != nil {
+			return errors.Wrapf(err, "acquire lock for ca certs %+v", spec)
+		}
+		defer releaser.Release()
+
+		files = append(files, localpath.CACert(), filepath.Join(globalPath, "ca.key"),
+			filepath.Join(globalPath, "proxy-client-ca.crt"), filepath.Join(globalPath, "proxy-client-ca.key"))
+	}
+
+	for _, f := range files {
+		klog.Infof("removing %s", f)
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "remove %s", f)
+		}
+	}
+	return nil
+}
+
 // renewExpiredKubeadmCerts checks if kubeadm certs already exists and are still valid, then renews them if needed.
 // if certs don't exist already (eg, kubeadm hasn't run yet), then checks are skipped.
 func renewExpiredKubeadmCerts(cmd command.Runner, cc config.ClusterConfig) error {
@@ -401,8 +494,7 @@ func renewExpiredKubeadmCerts(cmd command.Runner, cc config.ClusterConfig) error
 	}
 
 	expiredCerts := false
-	certs := []string{"apiserver-etcd-client", "apiserver-kubelet-client", "etcd-server", "etcd-healthcheck-client", "etcd-peer", "front-proxy-client"}
-	for _, cert := range certs {
+	for _, cert := range KubeadmCerts {
 		certPath := []string{vmpath.GuestPersistentDir, "certs"}
 		// certs starting with "etcd-" are in the "etcd" dir
 		// ex: etcd-server => etcd/server
@@ -452,8 +544,9 @@ func isValidPEMCertificate(filePath string) (bool, error) {
 // collectCACerts looks up all public pem certificates with .crt or .pem extension
 // in ~/.minikube/certs or ~/.minikube/files/etc/ssl/certs
 // to copy them to the vmpath.GuestCertAuthDir ("/usr/share/ca-certificates") in host.
-// minikube root CA is also included but libmachine certificates (ca.pem/cert.pem) are excluded.
-func collectCACerts() (map[string]string, error) {
+// minikube root CA (or the custom CA of the cluster) and the CA of the OIDC provider are also included,
+// but libmachine certificates (ca.pem/cert.pem) are excluded.
+func collectCACerts(cc config.ClusterConfig) (map[string]string, error) {
 	localPath := localpath.MiniPath()
 	// note: certFiles map's key is user os' path, whereas map's value is kic/iso (linux) path
 	certFiles := map[string]string{}
@@ -508,7 +601,12 @@ func collectCACerts() (map[string]string, error) {
 	}
 
 	// include minikube CA
-	certFiles[localpath.CACert()] = path.Join(vmpath.GuestCertAuthDir, "minikubeCA.pem")
+	certFiles[CACert(cc)] = path.Join(vmpath.GuestCertAuthDir, "minikubeCA.pem")
+
+	// include the CA of the OIDC provider, which the apiserver verifies the provider with
+	if oidcCA := cc.KubernetesConfig.OIDC.CAFile; oidcCA != "" {
+		certFiles[oidcCA] = vmpath.GuestOIDCCACert
+	}
 
 	filtered := map[string]string{}
 	for k, v := range certFiles {
@@ -630,6 +728,36 @@ func isValid(certPath, keyPath string) bool {
 	return true
 }
 
+// isSignedBy checks that a cert was signed by a CA, so that certs signed by a replaced CA are issued again
+func isSignedBy(certPath, caCertPath string) bool {
+	parse := func(p string) (*x509.Certificate, error) {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(b)
+		if block == nil {
+			return nil, fmt.Errorf("failed to decode %s", p)
+		}
+		return x509.ParseCertificate(block.Bytes)
+	}
+	cert, err := parse(certPath)
+	if err != nil {
+		klog.Infof("failed to parse cert %s: %v", certPath, err)
+		return false
+	}
+	ca, err := parse(caCertPath)
+	if err != nil {
+		klog.Infof("failed to parse ca cert %s: %v", caCertPath, err)
+		return false
+	}
+	if err := cert.CheckSignatureFrom(ca); err != nil {
+		klog.Infof("cert %s was not signed by %s: %v", certPath, caCertPath, err)
+		return false
+	}
+	return true
+}
+
 func isKubeadmCertValid(cmd command.Runner, certPath string) bool {
 	_, err := cmd.RunCmd(exec.Command("openssl", "x509", "-noout", "-in", certPath, "-checkend", "86400"))
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/tests"
 	"k8s.io/minikube/pkg/util"
 )
@@ -68,3 +69,46 @@ func TestSetupCerts(t *testing.T) {
 		t.Fatalf("Error starting cluster: %v", err)
 	}
 }
+
+func TestCustomCA(t *testing.T) {
+	tempDir := tests.MakeTempDir(t)
+
+	cc := config.ClusterConfig{
+		Name:           "byo-ca",
+		CertExpiration: constants.DefaultCertExpiration,
+		CustomCACert:   filepath.Join(tempDir, "corp-ca.crt"),
+		CustomCAKey:    filepath.Join(tempDir, "corp-ca.key"),
+		KubernetesConfig: config.KubernetesConfig{
+			ClusterName:   "byo-ca",
+			APIServerName: constants.APIServerName,
+			DNSDomain:     constants.ClusterDNSDomain,
+			ServiceCIDR:   constants.DefaultServiceCIDR,
+		},
+	}
+	if err := os.MkdirAll(localpath.Profile(cc.Name), 0777); err != nil {
+		t.Fatalf("error creating profile directory: %v", err)
+	}
+	if err := util.GenerateCACert(cc.CustomCACert, cc.CustomCAKey, "Corp CA"); err != nil {
+		t.Fatalf("error generating ca cert: %v", err)
+	}
+
+	shared, _, err := generateSharedCACerts(cc)
+	if err != nil {
+		t.Fatalf("generateSharedCACerts() error: %v", err)
+	}
+	if shared.caCert != CACert(cc) {
+		t.Errorf("generateSharedCACerts() ca = %s, want the copy of the custom ca %s", shared.caCert, CACert(cc))
+	}
+	if _, err := os.Stat(localpath.CACert()); !os.IsNotExist(err) {
+		t.Errorf("generateSharedCACerts() generated the minikube CA although a custom one was given")
+	}
+
+	if _, err := generateProfileCerts(cc, config.Node{ControlPlane: true, IP: "192.168.49.2"}, shared, false); err != nil {
+		t.Fatalf("generateProfileCerts() error: %v", err)
+	}
+	for _, c := range []string{localpath.ClientCert(cc.Name), filepath.Join(localpath.Profile(cc.Name), "apiserver.crt")} {
+		if !isSignedBy(c, cc.CustomCACert) {
+			t.Errorf("%s was not signed by the custom ca", c)
+		}
+	}
+}
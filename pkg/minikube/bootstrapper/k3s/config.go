@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k3s
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/cni"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+// k3sConfig is the configuration file of k3s, whose keys are the flags of the k3s server and agent commands
+// ref: https://docs.k3s.io/installation/configuration#configuration-file
+type k3sConfig struct {
+	Server                   string   `yaml:"server,omitempty"`
+	Token                    string   `yaml:"token,omitempty"`
+	DataDir                  string   `yaml:"data-dir"`
+	NodeName                 string   `yaml:"node-name"`
+	NodeIP                   string   `yaml:"node-ip,omitempty"`
+	ContainerRuntimeEndpoint string   `yaml:"container-runtime-endpoint"`
+	WriteKubeconfig          string   `yaml:"write-kubeconfig,omitempty"`
+	HTTPSListenPort          int      `yaml:"https-listen-port,omitempty"`
+	TLSSAN                   []string `yaml:"tls-san,omitempty"`
+	ClusterCIDR              string   `yaml:"cluster-cidr,omitempty"`
+	ServiceCIDR              string   `yaml:"service-cidr,omitempty"`
+	ClusterDomain            string   `yaml:"cluster-domain,omitempty"`
+	FlannelBackend           string   `yaml:"flannel-backend,omitempty"`
+	DisableNetworkPolicy     bool     `yaml:"disable-network-policy,omitempty"`
+	Disable                  []string `yaml:"disable,omitempty"`
+	APIServerArgs            []string `yaml:"kube-apiserver-arg,omitempty"`
+	ControllerManagerArgs    []string `yaml:"kube-controller-manager-arg,omitempty"`
+	SchedulerArgs            []string `yaml:"kube-scheduler-arg,omitempty"`
+	EtcdArgs                 []string `yaml:"etcd-arg,omitempty"`
+	KubeletArgs              []string `yaml:"kubelet-arg,omitempty"`
+	KubeProxyArgs            []string `yaml:"kube-proxy-arg,omitempty"`
+}
+
+// disabledComponents are the packaged components of k3s which minikube replaces with its addons and CNIs
+var disabledComponents = []string{"traefik", "servicelb", "local-storage", "metrics-server"}
+
+// serverConfig generates the k3s config of the primary control-plane node
+func serverConfig(cc config.ClusterConfig, n config.Node, r cruntime.Manager) ([]byte, error) {
+	k8s := cc.KubernetesConfig
+	c, err := nodeConfig(cc, n, r)
+	if err != nil {
+		return nil, err
+	}
+
+	cnm, err := cni.New(&cc)
+	if err != nil {
+		return nil, errors.Wrap(err, "cni")
+	}
+	c.ClusterCIDR = cnm.CIDR()
+	if cidr := k8s.ExtraOptions.Get("pod-network-cidr", bsutil.Kubeadm); cidr != "" {
+		c.ClusterCIDR = cidr
+	}
+	c.ServiceCIDR = k8s.ServiceCIDR
+	c.ClusterDomain = k8s.DNSDomain
+	c.HTTPSListenPort = n.Port
+	if c.HTTPSListenPort <= 0 {
+		c.HTTPSListenPort = constants.APIServerPort
+	}
+	c.TLSSAN = tlsSANs(cc, n)
+	c.WriteKubeconfig = kubeconfigPath
+	// minikube applies its own CNI, as it does with kubeadm
+	c.FlannelBackend = "none"
+	c.DisableNetworkPolicy = true
+	c.Disable = disabledComponents
+
+	// kubeadm clusters serve the health checks of the apiserver anonymously, which minikube relies on
+	opts := config.ExtraOptionSlice{{Component: bsutil.Apiserver, Key: "anonymous-auth", Value: "true"}}
+	// options given with --extra-config come last, so they override the OIDC, audit and admission ones
+	opts = append(opts, bsutil.APIServerOptions(k8s)...)
+	opts = append(opts, k8s.ExtraOptions...)
+	c.APIServerArgs = componentArgs(c.APIServerArgs, opts, bsutil.Apiserver)
+	c.ControllerManagerArgs = componentArgs(c.ControllerManagerArgs, opts, bsutil.ControllerManager)
+	c.SchedulerArgs = componentArgs(c.SchedulerArgs, opts, bsutil.Scheduler)
+	c.EtcdArgs = componentArgs(c.EtcdArgs, opts, bsutil.Etcd)
+
+	b, err := marshal(c)
+	if err == nil {
+		klog.Infof("k3s server config:\n%s", b)
+	}
+	return b, err
+}
+
+// agentConfig generates the k3s config of a worker node, joining the cluster with the token of the primary control-plane node
+func agentConfig(cc config.ClusterConfig, n config.Node, r cruntime.Manager, token string) ([]byte, error) {
+	c, err := nodeConfig(cc, n, r)
+	if err != nil {
+		return nil, err
+	}
+	cp, err := config.ControlPlane(cc)
+	if err != nil {
+		return nil, errors.Wrap(err, "get control-plane node")
+	}
+	port := cp.Port
+	if port <= 0 {
+		port = constants.APIServerPort
+	}
+	c.Server = fmt.Sprintf("https://%s", net.JoinHostPort(constants.ControlPlaneAlias, strconv.Itoa(port)))
+	c.Token = token
+	return marshal(c)
+}
+
+// nodeConfig returns the settings shared by the k3s servers and agents
+func nodeConfig(cc config.ClusterConfig, n config.Node, r cruntime.Manager) (*k3sConfig, error) {
+	k8s := cc.KubernetesConfig
+	cgroupDriver, err := r.CGroupDriver()
+	if err != nil {
+		if !r.Active() {
+			return nil, cruntime.ErrContainerRuntimeNotRunning
+		}
+		return nil, errors.Wrap(err, "getting cgroup driver")
+	}
+
+	endpoint := k8s.ExtraOptions.Get("container-runtime-endpoint", bsutil.Kubelet)
+	if endpoint == "" {
+		endpoint = r.KubeletOptions()["container-runtime-endpoint"]
+	}
+
+	c := &k3sConfig{
+		DataDir:                  dataDir,
+		NodeName:                 bsutil.KubeNodeName(cc, n),
+		NodeIP:                   n.IP,
+		ContainerRuntimeEndpoint: endpoint,
+		KubeletArgs:              []string{"cgroup-driver=" + cgroupDriver, "fail-swap-on=false"},
+	}
+	c.KubeletArgs = componentArgs(c.KubeletArgs, k8s.ExtraOptions, bsutil.Kubelet)
+	c.KubeProxyArgs = componentArgs(c.KubeProxyArgs, k8s.ExtraOptions, bsutil.Kubeproxy)
+	return c, nil
+}
+
+// componentArgs appends the options of a component to its k3s args, as key=value
+func componentArgs(args []string, opts config.ExtraOptionSlice, component string) []string {
+	for _, o := range opts {
+		if o.Component != component {
+			continue
+		}
+		// set by nodeConfig, rather than passed to the kubelet
+		if component == bsutil.Kubelet && o.Key == "container-runtime-endpoint" {
+			continue
+		}
+		args = append(args, fmt.Sprintf("%s=%s", o.Key, o.Value))
+	}
+	return args
+}
+
+// tlsSANs returns the names and IPs the apiserver is reached at besides the ones k3s adds itself, as for the minikube apiserver cert
+func tlsSANs(cc config.ClusterConfig, n config.Node) []string {
+	k8s := cc.KubernetesConfig
+	sans := []string{}
+	for _, name := range append([]string{k8s.APIServerName, constants.ControlPlaneAlias, config.MachineName(cc, n)}, k8s.APIServerNames...) {
+		if name != "" && !config.ContainsParam(sans, name) {
+			sans = append(sans, name)
+		}
+	}
+	for _, ip := range k8s.APIServerIPs {
+		sans = append(sans, ip.String())
+	}
+	if daemonHost := oci.DaemonHost(k8s.ContainerRuntime); daemonHost != oci.DefaultBindIPV4 {
+		sans = append(sans, daemonHost)
+	}
+	return sans
+}
+
+// serviceTemplate is the systemd unit of k3s, after the one of the k3s installer
+var serviceTemplate = template.Must(template.New("k3s.service").Parse(`[Unit]
+Description=k3s: Lightweight Kubernetes
+Documentation=https://docs.k3s.io
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=exec
+ExecStart={{.Binary}} {{.Role}} --config ` + configPath + `
+KillMode=process
+Delegate=yes
+LimitNOFILE=1048576
+LimitNPROC=infinity
+LimitCORE=infinity
+TasksMax=infinity
+TimeoutStartSec=0
+Restart=always
+RestartSec=5s
+
+[Install]
+WantedBy=multi-user.target
+`))
+
+// serviceUnit generates the systemd unit running k3s as a server or an agent
+func serviceUnit(binary, role string) ([]byte, error) {
+	var b bytes.Buffer
+	opts := struct{ Binary, Role string }{Binary: binary, Role: role}
+	if err := serviceTemplate.Execute(&b, opts); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// marshal encodes the k3s config
+func marshal(c *k3sConfig) ([]byte, error) {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal k3s config")
+	}
+	return b, nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k3s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+func testConfig() (config.ClusterConfig, cruntime.Manager, error) {
+	fcr := command.NewFakeCommandRunner()
+	fcr.SetCommandToOutput(map[string]string{
+		"docker info --format {{.CgroupDriver}}": "systemd\n",
+	})
+	r, err := cruntime.New(cruntime.Config{Type: "docker", Runner: fcr, Socket: "/var/run/cri-dockerd.sock"})
+	cc := config.ClusterConfig{
+		Name:         "mk",
+		Driver:       "kvm2",
+		Bootstrapper: "k3s",
+		KubernetesConfig: config.KubernetesConfig{
+			KubernetesVersion: "v1.30.0",
+			ContainerRuntime:  "docker",
+			ServiceCIDR:       "10.96.0.0/12",
+			DNSDomain:         "cluster.local",
+			ExtraOptions: config.ExtraOptionSlice{
+				{Component: bsutil.Apiserver, Key: "anonymous-auth", Value: "false"},
+				{Component: bsutil.Kubelet, Key: "max-pods", Value: "50"},
+			},
+		},
+		Nodes: []config.Node{
+			{Name: "", IP: "192.168.39.2", Port: 8443, ControlPlane: true, Worker: true},
+			{Name: "m02", IP: "192.168.39.3", Worker: true},
+		},
+	}
+	return cc, r, err
+}
+
+func TestServerConfig(t *testing.T) {
+	cc, r, err := testConfig()
+	if err != nil {
+		t.Fatalf("runtime: %v", err)
+	}
+	b, err := serverConfig(cc, cc.Nodes[0], r)
+	if err != nil {
+		t.Fatalf("serverConfig: %v", err)
+	}
+	var got k3sConfig
+	if err := yaml.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unmarshal %s: %v", b, err)
+	}
+
+	if got.Server != "" || got.Token != "" {
+		t.Errorf("expected the server not to join another one, got server %q and token %q", got.Server, got.Token)
+	}
+	if got.HTTPSListenPort != 8443 || got.ServiceCIDR != "10.96.0.0/12" || got.FlannelBackend != "none" {
+		t.Errorf("unexpected server config:\n%s", b)
+	}
+	// options given with --extra-config override the defaults of minikube
+	wantAPIServer := []string{"anonymous-auth=true", "anonymous-auth=false"}
+	if diff := cmp.Diff(wantAPIServer, got.APIServerArgs); diff != "" {
+		t.Errorf("apiserver args mismatch (-want +got):\n%s", diff)
+	}
+	wantKubelet := []string{"cgroup-driver=systemd", "fail-swap-on=false", "max-pods=50"}
+	if diff := cmp.Diff(wantKubelet, got.KubeletArgs); diff != "" {
+		t.Errorf("kubelet args mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAgentConfig(t *testing.T) {
+	cc, r, err := testConfig()
+	if err != nil {
+		t.Fatalf("runtime: %v", err)
+	}
+	b, err := agentConfig(cc, cc.Nodes[1], r, "K10secret")
+	if err != nil {
+		t.Fatalf("agentConfig: %v", err)
+	}
+	var got k3sConfig
+	if err := yaml.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unmarshal %s: %v", b, err)
+	}
+	if got.Server != "https://control-plane.minikube.internal:8443" {
+		t.Errorf("expected the agent to join the primary control-plane node, got %q", got.Server)
+	}
+	if got.Token != "K10secret" || got.NodeName != "mk-m02" || got.NodeIP != "192.168.39.3" {
+		t.Errorf("unexpected agent config:\n%s", b)
+	}
+	if len(got.APIServerArgs) != 0 || len(got.TLSSAN) != 0 {
+		t.Errorf("expected no control-plane settings on an agent, got:\n%s", b)
+	}
+}
+
+func TestServiceUnit(t *testing.T) {
+	b, err := serviceUnit("/var/lib/minikube/binaries/v1.30.0/k3s", "agent")
+	if err != nil {
+		t.Fatalf("serviceUnit: %v", err)
+	}
+	want := "ExecStart=/var/lib/minikube/binaries/v1.30.0/k3s agent --config " + configPath + "\n"
+	if !strings.Contains(string(b), want) {
+		t.Errorf("expected the unit to contain %q, got:\n%s", want, b)
+	}
+}
@@ -0,0 +1,632 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package k3s bootstraps clusters with k3s, which runs the control plane and the kubelet of a node as a single process
+package k3s
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	// WARNING: Do not use path/filepath in this package unless you want bizarre Windows paths
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/cni"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/retry"
+	"k8s.io/minikube/pkg/version"
+	kconst "k8s.io/minikube/third_party/kubeadm/app/constants"
+)
+
+const (
+	// dataDir is where k3s keeps its state, on the persistent disk of the node
+	dataDir = vmpath.GuestPersistentDir + "/k3s"
+	// configPath is the k3s config file, read by both the server and the agent
+	configPath = "/etc/rancher/k3s/config.yaml"
+	// tokenPath is where the k3s server writes the token the other nodes join with
+	tokenPath = dataDir + "/server/token"
+	// tlsDir is where the k3s server keeps its CAs and certs
+	tlsDir = dataDir + "/server/tls"
+	// kubeconfigPath is where the k3s server writes its admin kubeconfig, minikube uses its own one
+	kubeconfigPath = dataDir + "/k3s.yaml"
+
+	// service is the systemd unit k3s runs as. It is named after the kubelet, so that the commands which
+	// stop, pause and check the kubelet of a node manage k3s the same way.
+	service = "kubelet"
+)
+
+// cas maps the CAs minikube issues its certs with to the CAs of the k3s server, so that k3s trusts and issues the same certs
+var cas = map[string][]string{
+	"ca":              {"server-ca", "client-ca"},
+	"proxy-client-ca": {"request-header-ca"},
+}
+
+// Bootstrapper is a bootstrapper using k3s
+type Bootstrapper struct {
+	c           command.Runner
+	k8sClient   *kubernetes.Clientset // Kubernetes client used to verify pods inside cluster
+	contextName string
+}
+
+// NewBootstrapper creates a new k3s.Bootstrapper
+func NewBootstrapper(_ libmachine.API, cc config.ClusterConfig, r command.Runner) (*Bootstrapper, error) {
+	return &Bootstrapper{c: r, contextName: cc.Name, k8sClient: nil}, nil
+}
+
+// binaryPath returns the path of the k3s binary on the node
+func binaryPath(cfg config.ClusterConfig) string {
+	return path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "k3s")
+}
+
+// kubectlPath returns the path of kubectl on the node, which is a link to the k3s binary
+func kubectlPath(cfg config.ClusterConfig) string {
+	return kapi.KubectlBinaryPath(cfg.KubernetesConfig.KubernetesVersion)
+}
+
+// kubectl returns a command running kubectl on the node with the minikube kubeconfig
+func kubectl(ctx context.Context, cfg config.ClusterConfig, args ...string) *exec.Cmd {
+	args = append([]string{kubectlPath(cfg), fmt.Sprintf("--kubeconfig=%s", path.Join(vmpath.GuestPersistentDir, "kubeconfig"))}, args...)
+	return exec.CommandContext(ctx, "sudo", args...)
+}
+
+// GetAPIServerStatus returns the api-server status
+func (k *Bootstrapper) GetAPIServerStatus(hostname string, port int) (string, error) {
+	s, err := kverify.APIServerStatus(k.c, k.contextName, hostname, port)
+	if err != nil {
+		return state.Error.String(), err
+	}
+	return s.String(), nil
+}
+
+// LogCommands returns a map of log type to a command which will display that log.
+func (k *Bootstrapper) LogCommands(cfg config.ClusterConfig, o bootstrapper.LogOptions) map[string]string {
+	var k3s strings.Builder
+	k3s.WriteString("sudo journalctl -u " + service)
+	if o.Lines > 0 {
+		k3s.WriteString(fmt.Sprintf(" -n %d", o.Lines))
+	}
+	if o.Follow {
+		k3s.WriteString(" -f")
+	}
+
+	var dmesg strings.Builder
+	dmesg.WriteString("sudo dmesg -PH -L=never --level warn,err,crit,alert,emerg")
+	if o.Follow {
+		dmesg.WriteString(" --follow")
+	}
+	if o.Lines > 0 {
+		dmesg.WriteString(fmt.Sprintf(" | tail -n %d", o.Lines))
+	}
+
+	describeNodes := fmt.Sprintf("sudo %s describe nodes --kubeconfig=%s", kubectlPath(cfg),
+		path.Join(vmpath.GuestPersistentDir, "kubeconfig"))
+
+	return map[string]string{
+		"k3s":            k3s.String(),
+		"dmesg":          dmesg.String(),
+		"describe nodes": describeNodes,
+	}
+}
+
+// StartCluster starts the cluster
+func (k *Bootstrapper) StartCluster(cfg config.ClusterConfig) error {
+	start := time.Now()
+	klog.Infof("StartCluster: %+v", cfg)
+	defer func() {
+		klog.Infof("duration metric: took %s to StartCluster", time.Since(start))
+	}()
+
+	// k3s generates the CAs it does not find, so they have to be in place before its first start
+	if err := k.installCAs(); err != nil {
+		return errors.Wrap(err, "install CAs")
+	}
+
+	register.Reg.SetStep(register.PreparingKubernetesControlPlane)
+	out.Step(style.SubStep, "Booting up control plane ...")
+	// restarted rather than started, to pick up the config of an existing node
+	if err := sysinit.New(k.c).Restart(service); err != nil {
+		return errors.Wrap(err, "start k3s")
+	}
+
+	if err := k.waitForAPIServer(cfg); err != nil {
+		return err
+	}
+
+	if err := k.elevateKubeSystemPrivileges(cfg); err != nil {
+		klog.Errorf("unable to create cluster role binding, some addons might not work: %v", err)
+	}
+
+	return k.applyCNI(cfg)
+}
+
+// installCAs copies the CAs minikube transferred to the node to the k3s data dir, under the names k3s looks for them
+func (k *Bootstrapper) installCAs() error {
+	cmds := []string{fmt.Sprintf("sudo mkdir -p %s", tlsDir)}
+	for src, dsts := range cas {
+		for _, dst := range dsts {
+			for _, ext := range []string{".crt", ".key"} {
+				cmds = append(cmds, fmt.Sprintf("sudo cp %s %s", path.Join(vmpath.GuestKubernetesCertsDir, src+ext), path.Join(tlsDir, dst+ext)))
+			}
+		}
+	}
+	if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", strings.Join(cmds, " && "))); err != nil {
+		return errors.Wrap(err, "copy CAs")
+	}
+	return nil
+}
+
+// waitForAPIServer waits for the apiserver embedded in k3s to serve requests
+func (k *Bootstrapper) waitForAPIServer(cfg config.ClusterConfig) error {
+	start := time.Now()
+	ready := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := k.c.RunCmd(kubectl(ctx, cfg, "get", "--raw=/readyz"))
+		return err
+	}
+	if err := retry.Local(ready, kconst.DefaultControlPlaneTimeout); err != nil {
+		return errors.Wrap(err, "apiserver never became ready")
+	}
+	klog.Infof("duration metric: took %s to wait for the k3s apiserver", time.Since(start))
+	return nil
+}
+
+// elevateKubeSystemPrivileges gives the kube-system service account cluster admin privileges, as in the kubeadm clusters
+func (k *Bootstrapper) elevateKubeSystemPrivileges(cfg config.ClusterConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	rr, err := k.c.RunCmd(kubectl(ctx, cfg, "create", "clusterrolebinding", "minikube-rbac", "--clusterrole=cluster-admin", "--serviceaccount=kube-system:default"))
+	if err != nil && !strings.Contains(rr.Output(), "AlreadyExists") {
+		return errors.Wrap(err, "create cluster role binding")
+	}
+	return nil
+}
+
+// applyCNI applies the CNI of the cluster, as k3s is started without flannel
+func (k *Bootstrapper) applyCNI(cfg config.ClusterConfig) error {
+	cnm, err := cni.New(&cfg)
+	if err != nil {
+		return errors.Wrap(err, "cni config")
+	}
+	if _, ok := cnm.(cni.Disabled); ok {
+		return nil
+	}
+
+	register.Reg.SetStep(register.ConfiguringCNI)
+	out.Step(style.CNI, "Configuring {{.name}} (Container Networking Interface) ...", out.V{"name": cnm.String()})
+	if err := cnm.Apply(k.c); err != nil {
+		return errors.Wrap(err, "cni apply")
+	}
+	return nil
+}
+
+// client sets and returns a Kubernetes client to use to speak to the k3s apiserver
+func (k *Bootstrapper) client(ip string, port int) (*kubernetes.Clientset, error) {
+	if k.k8sClient != nil {
+		return k.k8sClient, nil
+	}
+
+	cc, err := kapi.ClientConfig(k.contextName)
+	if err != nil {
+		return nil, errors.Wrap(err, "client config")
+	}
+
+	endpoint := fmt.Sprintf("https://%s", net.JoinHostPort(ip, strconv.Itoa(port)))
+	if cc.Host != endpoint {
+		klog.Warningf("Overriding stale ClientConfig host %s with %s", cc.Host, endpoint)
+		cc.Host = endpoint
+	}
+	c, err := kubernetes.NewForConfig(cc)
+	if err == nil {
+		k.k8sClient = c
+	}
+	return c, err
+}
+
+// WaitForNode blocks until the node appears to be healthy.
+// The control plane of k3s runs within its process rather than as pods, so only CoreDNS is waited for among the system pods.
+func (k *Bootstrapper) WaitForNode(cfg config.ClusterConfig, n config.Node, timeout time.Duration) error {
+	start := time.Now()
+	register.Reg.SetStep(register.VerifyingKubernetes)
+	out.Step(style.HealthCheck, "Verifying Kubernetes components...")
+	// the node might have been stopped while k3s was not, as with a hibernated container
+	if err := sysinit.New(k.c).Start(service); err != nil {
+		klog.Warningf("Couldn't ensure k3s is started this might cause issues: %v", err)
+	}
+
+	cp, err := config.ControlPlane(cfg)
+	if err != nil {
+		return errors.Wrap(err, "get control-plane node")
+	}
+	hostname, _, port, err := driver.ControlPlaneEndpoint(&cfg, &cp, cfg.Driver)
+	if err != nil {
+		return errors.Wrap(err, "get control-plane endpoint")
+	}
+
+	client, err := k.client(hostname, port)
+	if err != nil {
+		return errors.Wrap(err, "kubernetes client")
+	}
+
+	if n.ControlPlane && cfg.VerifyComponents[kverify.APIServerWaitKey] {
+		apiServer := func() error {
+			st, err := kverify.APIServerStatus(k.c, k.contextName, hostname, port)
+			if err != nil {
+				return err
+			}
+			if st != state.Running {
+				return fmt.Errorf("apiserver is %s", st)
+			}
+			return nil
+		}
+		if err := retry.Local(apiServer, timeout); err != nil {
+			return errors.Wrap(err, "wait for healthy API server")
+		}
+	}
+
+	if cfg.VerifyComponents[kverify.NodeReadyKey] {
+		if err := kverify.WaitNodeCondition(client, bsutil.KubeNodeName(cfg, n), core.NodeReady, timeout); err != nil {
+			return errors.Wrap(err, "waiting for node to be ready")
+		}
+	}
+
+	if n.ControlPlane {
+		if cfg.VerifyComponents[kverify.SystemPodsWaitKey] || cfg.VerifyComponents[kverify.AppsRunningKey] {
+			if err := kverify.WaitForAppsRunning(client, []string{"kube-dns"}, timeout); err != nil {
+				return errors.Wrap(err, "waiting for system pods")
+			}
+		}
+		if cfg.VerifyComponents[kverify.DefaultSAWaitKey] {
+			if err := kverify.WaitForDefaultSA(client, timeout); err != nil {
+				return errors.Wrap(err, "waiting for default service account")
+			}
+		}
+	}
+
+	if cfg.VerifyComponents[kverify.ExtraKey] {
+		if err := kverify.WaitExtra(client, []string{"k8s-app=kube-dns"}, timeout); err != nil {
+			return errors.Wrap(err, "extra waiting")
+		}
+	}
+
+	if cfg.VerifyComponents[kverify.KubeletKey] {
+		if err := kverify.WaitForService(k.c, service, timeout); err != nil {
+			return errors.Wrap(err, "waiting for k3s")
+		}
+	}
+
+	klog.Infof("duration metric: took %s to wait for: %+v", time.Since(start), cfg.VerifyComponents)
+	return kverify.NodePressure(client)
+}
+
+// UpdateCluster updates the control plane with cluster-level info.
+func (k *Bootstrapper) UpdateCluster(cfg config.ClusterConfig) error {
+	klog.Infof("updating cluster %+v ...", cfg)
+
+	version, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing Kubernetes version")
+	}
+	r, err := cruntime.New(cruntime.Config{
+		Type:              cfg.KubernetesConfig.ContainerRuntime,
+		Runner:            k.c,
+		Socket:            cfg.KubernetesConfig.CRISocket,
+		KubernetesVersion: version,
+	})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+
+	pcp, err := config.ControlPlane(cfg)
+	if err != nil || !config.IsPrimaryControlPlane(cfg, pcp) {
+		return errors.Wrap(err, "get primary control-plane node")
+	}
+
+	if err := k.UpdateNode(cfg, pcp, r); err != nil {
+		return errors.Wrap(err, "update primary control-plane node")
+	}
+	return nil
+}
+
+// UpdateNode updates new or existing node.
+// The k3s config of the worker nodes is only written when they join, as it holds the join token.
+func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cruntime.Manager) error {
+	klog.Infof("updating node %v ...", n)
+
+	role := "agent"
+	files := []assets.CopyableFile{}
+	if n.ControlPlane {
+		role = "server"
+		conf, err := serverConfig(cfg, n, r)
+		if err != nil {
+			return errors.Wrap(err, "generating k3s config")
+		}
+		files = append(files, assets.NewMemoryAssetTarget(conf, configPath, "0600"))
+		apiServerFiles, err := bsutil.APIServerConfigFiles(cfg.KubernetesConfig)
+		if err != nil {
+			return errors.Wrap(err, "apiserver config files")
+		}
+		files = append(files, apiServerFiles...)
+	}
+	unit, err := serviceUnit(binaryPath(cfg), role)
+	if err != nil {
+		return errors.Wrap(err, "generating k3s service")
+	}
+	files = append(files, assets.NewMemoryAssetTarget(unit, bsutil.KubeletServiceFile, "0644"))
+
+	if err := k.transferBinary(cfg); err != nil {
+		return errors.Wrap(err, "transfer k3s")
+	}
+	k.loadImages(cfg, r)
+
+	// k3s takes the place of the kubelet, whose kubeadm drop-in would override its command
+	if _, err := k.c.RunCmd(exec.Command("sudo", "rm", "-f", bsutil.KubeletSystemdConfFile)); err != nil {
+		return errors.Wrap(err, "remove kubelet drop-in")
+	}
+	if err := bsutil.CopyFiles(k.c, files); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+
+	cp, err := config.ControlPlane(cfg)
+	if err != nil {
+		return errors.Wrap(err, "get control-plane node")
+	}
+	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cp.IP)); err != nil {
+		return errors.Wrap(err, "add control-plane alias")
+	}
+
+	// "ensure" k3s is started on nodes which joined already, intentionally non-fatal in case of an error
+	if _, err := k.c.RunCmd(exec.Command("sudo", "test", "-f", configPath)); err == nil {
+		if err := sysinit.New(k.c).Start(service); err != nil {
+			klog.Errorf("Couldn't ensure k3s is started this might cause issues (will continue): %v", err)
+		}
+	}
+	return nil
+}
+
+// transferBinary copies the k3s binary to the node, along with the kubectl it also serves as
+func (k *Bootstrapper) transferBinary(cfg config.ClusterConfig) error {
+	dst := binaryPath(cfg)
+	if _, err := k.c.RunCmd(exec.Command("sudo", "test", "-x", dst)); err == nil {
+		klog.Info("Found k3s binary, skipping transfer")
+		return nil
+	}
+
+	src, err := download.K3sBinary(cfg.KubernetesConfig.KubernetesVersion, config.GuestArch(cfg))
+	if err != nil {
+		return errors.Wrap(err, "downloading k3s")
+	}
+	if _, err := k.c.RunCmd(exec.Command("sudo", "mkdir", "-p", path.Dir(dst))); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if err := machine.CopyBinary(k.c, src, dst); err != nil {
+		return errors.Wrapf(err, "copybinary %s -> %s", src, dst)
+	}
+	if _, err := k.c.RunCmd(exec.Command("sudo", "ln", "-sf", dst, kubectlPath(cfg))); err != nil {
+		return errors.Wrap(err, "link kubectl")
+	}
+	return nil
+}
+
+// loadImages loads the images k3s runs into the container runtime of the node, the k3s equivalent of the preload.
+// It is intentionally non-fatal, as k3s pulls the images it misses.
+func (k *Bootstrapper) loadImages(cfg config.ClusterConfig, r cruntime.Manager) {
+	marker := path.Join(path.Dir(binaryPath(cfg)), "k3s-images.loaded")
+	if _, err := k.c.RunCmd(exec.Command("sudo", "test", "-f", marker)); err == nil {
+		klog.Info("k3s images already loaded, skipping")
+		return
+	}
+
+	src, err := download.K3sImages(cfg.KubernetesConfig.KubernetesVersion, config.GuestArch(cfg))
+	if err != nil {
+		klog.Warningf("unable to download k3s images, k3s will pull them: %v", err)
+		return
+	}
+	f, err := assets.NewFileAsset(src, "/tmp", path.Base(src), "0644")
+	if err != nil {
+		klog.Warningf("unable to load k3s images: %v", err)
+		return
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			klog.Warningf("error closing the file %s: %v", f.GetSourcePath(), err)
+		}
+	}()
+	if err := k.c.Copy(f); err != nil {
+		klog.Warningf("unable to copy k3s images: %v", err)
+		return
+	}
+	dst := path.Join(f.GetTargetDir(), f.GetTargetName())
+	defer func() {
+		if _, err := k.c.RunCmd(exec.Command("sudo", "rm", "-f", dst)); err != nil {
+			klog.Warningf("unable to remove %s: %v", dst, err)
+		}
+	}()
+	if err := r.LoadImage(dst); err != nil {
+		klog.Warningf("unable to load k3s images: %v", err)
+		return
+	}
+	if _, err := k.c.RunCmd(exec.Command("sudo", "touch", marker)); err != nil {
+		klog.Warningf("unable to mark k3s images as loaded: %v", err)
+	}
+}
+
+// JoinCluster adds new node to an existing cluster.
+func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, token string) error {
+	if n.ControlPlane {
+		return fmt.Errorf("the k3s bootstrapper does not support additional control-plane nodes")
+	}
+
+	r, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: k.c, Socket: cc.KubernetesConfig.CRISocket})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	conf, err := agentConfig(cc, n, r, token)
+	if err != nil {
+		return errors.Wrap(err, "generating k3s config")
+	}
+	if err := k.c.Copy(assets.NewMemoryAssetTarget(conf, configPath, "0600")); err != nil {
+		return errors.Wrap(err, "copy k3s config")
+	}
+
+	if err := sysinit.New(k.c).Restart(service); err != nil {
+		return errors.Wrap(err, "starting k3s")
+	}
+	return nil
+}
+
+// GenerateToken returns the token the k3s server generated for the nodes to join with
+func (k *Bootstrapper) GenerateToken(_ config.ClusterConfig) (string, error) {
+	rr, err := k.c.RunCmd(exec.Command("sudo", "cat", tokenPath))
+	if err != nil {
+		return "", errors.Wrap(err, "reading join token")
+	}
+	return strings.TrimSpace(rr.Stdout.String()), nil
+}
+
+// DeleteCluster removes the components that were started earlier
+func (k *Bootstrapper) DeleteCluster(k8s config.KubernetesConfig) error {
+	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: k.c, Socket: k8s.CRISocket})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+
+	kubeadm.StopKubernetes(k.c, cr)
+	if _, err := k.c.RunCmd(exec.Command("sudo", "rm", "-rf", dataDir, configPath)); err != nil {
+		return errors.Wrap(err, "remove k3s data")
+	}
+	return nil
+}
+
+// SetupCerts sets up certificates within the cluster.
+func (k *Bootstrapper) SetupCerts(k8s config.ClusterConfig, n config.Node, pcpCmd cruntime.CommandRunner) error {
+	return bootstrapper.SetupCerts(k8s, n, pcpCmd, k.c)
+}
+
+// RotateCerts issues new certs to a node of a running cluster, and restarts k3s to use them.
+// The minikube certs must have been removed from the host with bootstrapper.RemoveCerts before.
+func (k *Bootstrapper) RotateCerts(cfg config.ClusterConfig, n config.Node, pcpCmd cruntime.CommandRunner, ca bool) error {
+	klog.Infof("rotating certs of node %s (ca: %v) ...", n.Name, ca)
+
+	if err := k.SetupCerts(cfg, n, pcpCmd); err != nil {
+		return errors.Wrap(err, "setup certs")
+	}
+
+	sm := sysinit.New(k.c)
+	if !n.ControlPlane {
+		if ca {
+			// the join token pins the hash of the CA, so the agent needs the one of the new CA
+			token, err := k.tokenFrom(pcpCmd)
+			if err != nil {
+				return err
+			}
+			return k.JoinCluster(cfg, n, token)
+		}
+		// the agent requests new client certs from the server as it starts
+		return sm.Restart(service)
+	}
+
+	if err := sm.Stop(service); err != nil {
+		return errors.Wrap(err, "stop k3s")
+	}
+	if ca {
+		if err := k.installCAs(); err != nil {
+			return errors.Wrap(err, "install CAs")
+		}
+	}
+	// k3s issues the certs it finds missing as it starts
+	if _, err := k.c.RunCmd(exec.Command("sudo", binaryPath(cfg), "certificate", "rotate", "--data-dir", dataDir)); err != nil {
+		return errors.Wrap(err, "k3s certificate rotate")
+	}
+	if err := sm.Start(service); err != nil {
+		return errors.Wrap(err, "start k3s")
+	}
+	return k.waitForAPIServer(cfg)
+}
+
+// tokenFrom reads the join token on the primary control-plane node
+func (k *Bootstrapper) tokenFrom(pcpCmd command.Runner) (string, error) {
+	rr, err := pcpCmd.RunCmd(exec.Command("sudo", "cat", tokenPath))
+	if err != nil {
+		return "", errors.Wrap(err, "reading join token")
+	}
+	return strings.TrimSpace(rr.Stdout.String()), nil
+}
+
+// LabelAndUntaintNode applies minikube labels to node. k3s does not taint its server nodes, so there is nothing to untaint.
+func (k *Bootstrapper) LabelAndUntaintNode(cfg config.ClusterConfig, n config.Node) error {
+	// converting - and : to _ because of Kubernetes label restriction
+	createdAtLbl := "minikube.k8s.io/updated_at=" + time.Now().Format("2006_01_02T15_04_05_0700")
+	verLbl := "minikube.k8s.io/version=" + version.GetVersion()
+	commitLbl := "minikube.k8s.io/commit=" + version.GetGitCommitID()
+	profileNameLbl := "minikube.k8s.io/name=" + cfg.Name
+	primaryLbl := "minikube.k8s.io/primary=false"
+	if config.IsPrimaryControlPlane(cfg, n) {
+		primaryLbl = "minikube.k8s.io/primary=true"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	args := append([]string{"label", "--overwrite", "nodes", bsutil.KubeNodeName(cfg, n), createdAtLbl, verLbl, commitLbl, profileNameLbl, primaryLbl}, config.NodeLabels(n)...)
+	cmd := kubectl(ctx, cfg, args...)
+	if _, err := k.c.RunCmd(cmd); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.Wrapf(err, "timeout apply node labels")
+		}
+		return errors.Wrapf(err, "apply node labels")
+	}
+
+	// taints of the node pool of the node, if any
+	if len(n.Taints) > 0 {
+		cmd := kubectl(ctx, cfg, append([]string{"taint", "--overwrite", "nodes", bsutil.KubeNodeName(cfg, n)}, n.Taints...)...)
+		if _, err := k.c.RunCmd(cmd); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return errors.Wrapf(err, "timeout apply node taints")
+			}
+			return errors.Wrapf(err, "apply node taints")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+// kubeconfigDir is where kubeadm writes the kubeconfig files of the control plane and the kubelet
+const kubeconfigDir = "/etc/kubernetes"
+
+var (
+	// kubeadmCAs are the certificate authorities kubeadm generates on the primary control-plane node, in addition to the minikube CA
+	kubeadmCAs = []string{"etcd-ca", "front-proxy-ca"}
+	// kubeadmKubeconfigs are the kubeconfig files of the control plane with client certs kubeadm renews
+	kubeadmKubeconfigs = []string{"admin.conf", "super-admin.conf", "controller-manager.conf", "scheduler.conf"}
+	// certComponents are the control-plane components which only load their certs at startup
+	certComponents = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd"}
+	// etcdMemberCerts are the certs kubeadm issues to the members of an external etcd
+	etcdMemberCerts = []string{"etcd-server", "etcd-peer", "etcd-healthcheck-client"}
+)
+
+// RotateCerts issues new certs to a node of a running cluster, and restarts the components which use them.
+// The minikube certs must have been removed from the host with bootstrapper.RemoveCerts before.
+// The members of an external etcd have the CA of etcd instead: pcpCmd is the runner of the first etcd node for them.
+func (k *Bootstrapper) RotateCerts(cfg config.ClusterConfig, n config.Node, pcpCmd cruntime.CommandRunner, ca bool) error {
+	klog.Infof("rotating certs of node %s (ca: %v) ...", n.Name, ca)
+
+	if n.Etcd {
+		return k.rotateEtcdMemberCerts(cfg, n, pcpCmd, ca)
+	}
+
+	if ca && config.IsPrimaryControlPlane(cfg, n) {
+		if err := k.regenerateKubeadmCAs(cfg); err != nil {
+			return errors.Wrap(err, "regenerate kubeadm CAs")
+		}
+	}
+
+	// issues the minikube certs, and copies the CAs of the primary control-plane node to the other ones
+	if err := k.SetupCerts(cfg, n, pcpCmd); err != nil {
+		return errors.Wrap(err, "setup certs")
+	}
+
+	if n.ControlPlane {
+		if err := k.renewKubeadmCerts(cfg, n); err != nil {
+			return errors.Wrap(err, "renew kubeadm certs")
+		}
+		if err := k.restartCertComponents(cfg); err != nil {
+			return errors.Wrap(err, "restart control plane")
+		}
+	}
+
+	if ca {
+		// the kubelet client cert was signed by the old CA, so it has to be replaced rather than rotated by the kubelet
+		if err := k.replaceKubeletKubeconfig(cfg, n, pcpCmd); err != nil {
+			return errors.Wrap(err, "replace kubelet kubeconfig")
+		}
+		if config.IsPrimaryControlPlane(cfg, n) {
+			if err := k.updateClusterInfo(cfg); err != nil {
+				return errors.Wrap(err, "update cluster-info")
+			}
+		}
+	}
+	return nil
+}
+
+// rotateEtcdMemberCerts issues new certs to a member of an external etcd, signed by the CA of etcd of the first member,
+// which replaces it first if ca is set, and restarts the member
+func (k *Bootstrapper) rotateEtcdMemberCerts(cfg config.ClusterConfig, n config.Node, firstCmd cruntime.CommandRunner, ca bool) error {
+	first := config.IsFirstEtcdNode(cfg, n)
+	kubeadm := bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion)
+	if ca {
+		if first {
+			if _, err := k.c.RunCmd(exec.Command("sudo", "rm", "-f", bsutil.EtcdCACert, bsutil.EtcdCAKey)); err != nil {
+				return errors.Wrap(err, "remove etcd-ca")
+			}
+			cmd := fmt.Sprintf("%s init phase certs etcd-ca --config %s", kubeadm, constants.KubeadmYamlPath)
+			if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", cmd)); err != nil {
+				return errors.Wrap(err, "generate etcd-ca")
+			}
+		} else {
+			for _, f := range []string{bsutil.EtcdCACert, bsutil.EtcdCAKey} {
+				if err := copyFrom(firstCmd, k.c, f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	certs := etcdMemberCerts
+	if first {
+		// the first member issued the cert the apiservers authenticate with, which they copy from it
+		certs = append(append([]string{}, certs...), "apiserver-etcd-client")
+	}
+	for _, c := range certs {
+		cmd := fmt.Sprintf("%s certs renew %s --config %s", kubeadm, c, constants.KubeadmYamlPath)
+		if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", cmd)); err != nil {
+			return errors.Wrapf(err, "renew %s", c)
+		}
+	}
+	return k.restartCertComponents(cfg)
+}
+
+// copyFrom copies a file of another node to the same path on this one
+func copyFrom(from, to cruntime.CommandRunner, file string) error {
+	f := assets.NewMemoryAsset(nil, path.Dir(file), path.Base(file), "0600")
+	if err := from.CopyFrom(f); err != nil {
+		return errors.Wrapf(err, "copy %s from node", file)
+	}
+	if err := to.Copy(f); err != nil {
+		return errors.Wrapf(err, "copy %s to node", file)
+	}
+	return nil
+}
+
+// regenerateKubeadmCAs replaces the CAs kubeadm generated on the primary control-plane node. The CA of an external etcd
+// is on the etcd nodes, which replace it themselves
+func (k *Bootstrapper) regenerateKubeadmCAs(cfg config.ClusterConfig) error {
+	kubeadm := bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion)
+	for _, c := range kubeadmCAs {
+		if c == "etcd-ca" && config.IsExternalEtcd(cfg) {
+			continue
+		}
+		base := path.Join(vmpath.GuestKubernetesCertsDir, c)
+		if c == "etcd-ca" {
+			base = path.Join(vmpath.GuestKubernetesCertsDir, "etcd", "ca")
+		}
+		if _, err := k.c.RunCmd(exec.Command("sudo", "rm", "-f", base+".crt", base+".key")); err != nil {
+			return errors.Wrapf(err, "remove %s", c)
+		}
+		cmd := fmt.Sprintf("%s init phase certs %s --config %s", kubeadm, c, constants.KubeadmYamlPath)
+		if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", cmd)); err != nil {
+			return errors.Wrapf(err, "generate %s", c)
+		}
+	}
+	return nil
+}
+
+// renewKubeadmCerts renews the certs kubeadm issued to a control-plane node, signed by the CAs currently on the node.
+// kubeadm reads the cluster configuration from the cluster on the other control-plane nodes, as only the primary one has the kubeadm config.
+// With an external etcd, the etcd certs are renewed on the etcd nodes instead.
+func (k *Bootstrapper) renewKubeadmCerts(cfg config.ClusterConfig, n config.Node) error {
+	kubeadm := bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion)
+	conf := ""
+	if config.IsPrimaryControlPlane(cfg, n) {
+		conf = " --config " + constants.KubeadmYamlPath
+	}
+
+	for _, c := range append(append([]string{}, bootstrapper.KubeadmCerts...), kubeadmKubeconfigs...) {
+		if config.IsExternalEtcd(cfg) && (strings.HasPrefix(c, "etcd-") || c == "apiserver-etcd-client") {
+			continue
+		}
+		if path.Ext(c) == ".conf" {
+			// super-admin.conf only exists from Kubernetes v1.29
+			if _, err := k.c.RunCmd(exec.Command("sudo", "test", "-f", path.Join(kubeconfigDir, c))); err != nil {
+				klog.Infof("skipping missing %s", c)
+				continue
+			}
+		}
+		cmd := fmt.Sprintf("%s certs renew %s%s", kubeadm, c, conf)
+		if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", cmd)); err != nil {
+			return errors.Wrapf(err, "renew %s", c)
+		}
+	}
+	return nil
+}
+
+// restartCertComponents stops the control-plane containers, which the kubelet starts again with the new certs
+func (k *Bootstrapper) restartCertComponents(cfg config.ClusterConfig) error {
+	cr, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Socket: cfg.KubernetesConfig.CRISocket, Runner: k.c})
+	if err != nil {
+		return errors.Wrap(err, "new cruntime")
+	}
+
+	for _, name := range certComponents {
+		ids, err := cr.ListContainers(cruntime.ListContainersOptions{State: cruntime.Running, Name: name})
+		if err != nil {
+			return errors.Wrapf(err, "list %s", name)
+		}
+		if len(ids) == 0 {
+			klog.Warningf("no running %s container found", name)
+			continue
+		}
+		if err := cr.StopContainers(ids); err != nil {
+			return errors.Wrapf(err, "stop %s", name)
+		}
+	}
+	return nil
+}
+
+// replaceKubeletKubeconfig issues a new client cert to the kubelet of a node, signed by the CA of the primary control-plane node
+func (k *Bootstrapper) replaceKubeletKubeconfig(cfg config.ClusterConfig, n config.Node, pcpCmd cruntime.CommandRunner) error {
+	kv, err := semver.ParseTolerant(cfg.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return errors.Wrapf(err, "parsing kubernetes version %q", cfg.KubernetesConfig.KubernetesVersion)
+	}
+	kubeconfigCmd := "kubeconfig user"
+	if kv.LT(semver.Version{Major: 1, Minor: 22}) {
+		kubeconfigCmd = "alpha kubeconfig user"
+	}
+	cmd := fmt.Sprintf("%s %s --client-name=system:node:%s --org=system:nodes --config %s",
+		bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion), kubeconfigCmd, bsutil.KubeNodeName(cfg, n), constants.KubeadmYamlPath)
+	rr, err := pcpCmd.RunCmd(exec.Command("/bin/bash", "-c", cmd))
+	if err != nil {
+		return errors.Wrap(err, "kubeadm kubeconfig user")
+	}
+
+	kubeletConf := assets.NewMemoryAssetTarget(rr.Stdout.Bytes(), path.Join(kubeconfigDir, "kubelet.conf"), "0600")
+	if err := k.c.Copy(kubeletConf); err != nil {
+		return errors.Wrap(err, "copy kubelet.conf")
+	}
+	// the kubelet prefers its rotated client cert over the one of its kubeconfig
+	if _, err := k.c.RunCmd(exec.Command("sudo", "rm", "-f", "/var/lib/kubelet/pki/kubelet-client-current.pem")); err != nil {
+		return errors.Wrap(err, "remove kubelet client cert")
+	}
+	return sysinit.New(k.c).Restart("kubelet")
+}
+
+// updateClusterInfo publishes the new CA in the cluster-info ConfigMap, which nodes joining the cluster trust
+func (k *Bootstrapper) updateClusterInfo(cfg config.ClusterConfig) error {
+	cmd := fmt.Sprintf("%s init phase bootstrap-token --config %s", bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion), constants.KubeadmYamlPath)
+	// the apiserver is restarting with its new certs
+	publish := func() error {
+		_, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", cmd))
+		return err
+	}
+	return retry.Expo(publish, 1*time.Second, 2*time.Minute)
+}
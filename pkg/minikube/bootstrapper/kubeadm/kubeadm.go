@@ -85,7 +85,7 @@ func NewBootstrapper(_ libmachine.API, cc config.ClusterConfig, r command.Runner
 
 // GetAPIServerStatus returns the api-server status
 func (k *Bootstrapper) GetAPIServerStatus(hostname string, port int) (string, error) {
-	s, err := kverify.APIServerStatus(k.c, hostname, port)
+	s, err := kverify.APIServerStatus(k.c, k.contextName, hostname, port)
 	if err != nil {
 		return state.Error.String(), err
 	}
@@ -210,7 +210,7 @@ func (k *Bootstrapper) init(cfg config.ClusterConfig) error {
 		}
 	}
 	// For kic on linux example error: "modprobe: FATAL: Module configs not found in directory /lib/modules/5.2.17-1rodete3-amd64"
-	if driver.IsKIC(cfg.Driver) {
+	if driver.IsKIC(cfg.Driver) || driver.IsNspawn(cfg.Driver) {
 		klog.Infof("ignoring SystemVerification for kubeadm because of %s driver", cfg.Driver)
 		skipSystemVerification = true
 	}
@@ -218,7 +218,7 @@ func (k *Bootstrapper) init(cfg config.ClusterConfig) error {
 		ignore = append(ignore, "SystemVerification")
 	}
 
-	if driver.IsKIC(cfg.Driver) { // to bypass this error: /proc/sys/net/bridge/bridge-nf-call-iptables does not exist
+	if driver.IsKIC(cfg.Driver) || driver.IsNspawn(cfg.Driver) { // to bypass this error: /proc/sys/net/bridge/bridge-nf-call-iptables does not exist
 		ignore = append(ignore, "FileContent--proc-sys-net-bridge-bridge-nf-call-iptables")
 	}
 
@@ -460,6 +460,19 @@ func (k *Bootstrapper) tunnelToAPIServer(cfg config.ClusterConfig) error {
 		return errors.Wrapf(err, "get control-plane node")
 	}
 
+	// the driver forwards the port itself if it can, which takes no process on the host to keep running
+	h, err := machine.LoadHost(m, config.MachineName(cfg, cp))
+	if err != nil {
+		return errors.Wrap(err, "load host")
+	}
+	if f, ok := h.Driver.(machine.PortForwarder); ok {
+		err := f.ForwardPort(cfg.APIServerPort, cp.Port)
+		if err == nil {
+			return nil
+		}
+		klog.Warningf("forwarding apiserver port failed, tunneling over ssh instead: %v", err)
+	}
+
 	args := []string{"-f", "-NTL", fmt.Sprintf("%d:localhost:8443", cfg.APIServerPort)}
 	if err = machine.CreateSSHShell(m, cfg, cp, args, false); err != nil {
 		return errors.Wrapf(err, "ssh command")
@@ -910,7 +923,8 @@ func (k *Bootstrapper) UpdateCluster(cfg config.ClusterConfig) error {
 		}
 	}
 
-	if cfg.KubernetesConfig.ShouldLoadCachedImages {
+	// the cached images are of the architecture of the host, emulated guests pull their own
+	if cfg.KubernetesConfig.ShouldLoadCachedImages && config.GuestArch(cfg) == runtime.GOARCH {
 		if err := machine.LoadCachedImages(&cfg, k.c, images, detect.ImageCacheDir(), false); err != nil {
 			out.FailureT("Unable to load cached images: {{.error}}", out.V{"error": err})
 		}
@@ -961,8 +975,14 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 			}
 			files = append(files, assets.NewMemoryAssetTarget(kubeadmCfg, constants.KubeadmYamlPath+".new", "0640"))
 		}
-		// deploy kube-vip for ha (multi-control plane) cluster
-		if config.IsHA(cfg) {
+		// the other control-plane nodes join with the apiserver options of the primary one, so they need its files too
+		apiServerFiles, err := bsutil.APIServerConfigFiles(cfg.KubernetesConfig)
+		if err != nil {
+			return errors.Wrap(err, "apiserver config files")
+		}
+		files = append(files, apiServerFiles...)
+		// deploy kube-vip for ha (multi-control plane) cluster, unless served by the haproxy load balancer node
+		if config.IsHA(cfg) && !config.UsesHAProxy(cfg) {
 			// workaround for kube-vip
 			// only applicable for k8s v1.29+ during primary control-plane node's kubeadm init (ie, first boot)
 			// TODO (prezha): remove when fixed upstream - ref: https://github.com/kube-vip/kube-vip/issues/684#issuecomment-1864855405
@@ -982,7 +1002,7 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 
 	sm := sysinit.New(k.c)
 
-	if err := bsutil.TransferBinaries(cfg.KubernetesConfig, k.c, sm, cfg.BinaryMirror); err != nil {
+	if err := bsutil.TransferBinaries(cfg.KubernetesConfig, config.GuestArch(cfg), k.c, sm, cfg.BinaryMirror); err != nil {
 		return errors.Wrap(err, "downloading binaries")
 	}
 
@@ -1080,8 +1100,8 @@ func (k *Bootstrapper) labelAndUntaintNode(cfg config.ClusterConfig, n config.No
 
 	// example:
 	// sudo /var/lib/minikube/binaries/<version>/kubectl --kubeconfig=/var/lib/minikube/kubeconfig label --overwrite nodes test-357 minikube.k8s.io/version=<version> minikube.k8s.io/commit=aa91f39ffbcf27dcbb93c4ff3f457c54e585cf4a-dirty minikube.k8s.io/name=p1 minikube.k8s.io/updated_at=2020_02_20T12_05_35_0700
-	cmd := exec.CommandContext(ctx, "sudo", kubectlPath(cfg), fmt.Sprintf("--kubeconfig=%s", path.Join(vmpath.GuestPersistentDir, "kubeconfig")),
-		"label", "--overwrite", "nodes", nodeName, createdAtLbl, verLbl, commitLbl, profileNameLbl, primaryLbl)
+	args := append([]string{"label", "--overwrite", "nodes", nodeName, createdAtLbl, verLbl, commitLbl, profileNameLbl, primaryLbl}, config.NodeLabels(n)...)
+	cmd := exec.CommandContext(ctx, "sudo", append([]string{kubectlPath(cfg), fmt.Sprintf("--kubeconfig=%s", path.Join(vmpath.GuestPersistentDir, "kubeconfig"))}, args...)...)
 	if _, err := k.c.RunCmd(cmd); err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return errors.Wrapf(err, "timeout apply node labels")
@@ -1089,6 +1109,18 @@ func (k *Bootstrapper) labelAndUntaintNode(cfg config.ClusterConfig, n config.No
 		return errors.Wrapf(err, "apply node labels")
 	}
 
+	// taints of the node pool of the node, if any
+	if len(n.Taints) > 0 {
+		args := append([]string{"taint", "--overwrite", "nodes", nodeName}, n.Taints...)
+		cmd := exec.CommandContext(ctx, "sudo", append([]string{kubectlPath(cfg), fmt.Sprintf("--kubeconfig=%s", path.Join(vmpath.GuestPersistentDir, "kubeconfig"))}, args...)...)
+		if _, err := k.c.RunCmd(cmd); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return errors.Wrapf(err, "timeout apply node taints")
+			}
+			return errors.Wrapf(err, "apply node taints")
+		}
+	}
+
 	// primary control-plane and worker nodes should be untainted by default
 	if n.ControlPlane && !config.IsPrimaryControlPlane(cfg, n) {
 		// example:
@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/k3s"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
@@ -38,8 +39,11 @@ func init() {
 	ssh.SetDefaultClient(ssh.Native)
 }
 
-// Bootstrapper returns a new bootstrapper for the cluster
+// Bootstrapper returns a new bootstrapper for the cluster, the one the cluster was created with if it is recorded in its config
 func Bootstrapper(api libmachine.API, bootstrapperName string, cc config.ClusterConfig, r command.Runner) (bootstrapper.Bootstrapper, error) {
+	if cc.Bootstrapper != "" {
+		bootstrapperName = cc.Bootstrapper
+	}
 	var b bootstrapper.Bootstrapper
 	var err error
 	switch bootstrapperName {
@@ -48,6 +52,11 @@ func Bootstrapper(api libmachine.API, bootstrapperName string, cc config.Cluster
 		if err != nil {
 			return nil, errors.Wrap(err, "getting a new kubeadm bootstrapper")
 		}
+	case bootstrapper.K3s:
+		b, err = k3s.NewBootstrapper(api, cc, r)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting a new k3s bootstrapper")
+		}
 	default:
 		return nil, fmt.Errorf("unknown bootstrapper: %s", bootstrapperName)
 	}
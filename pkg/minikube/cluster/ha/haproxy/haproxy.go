@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package haproxy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// Manifest is the static pod running haproxy on the load balancer node
+const Manifest = "haproxy.yaml"
+
+// ConfigPath is where the haproxy config is on the load balancer node
+var ConfigPath = path.Join(vmpath.GuestPersistentDir, "haproxy.cfg")
+
+// configTemplate balances the apiserver port of the load balancer node between the apiservers of the control-plane nodes,
+// taking the ones failing their health check out quickly, so they fail over within a few seconds
+// ref: https://github.com/kubernetes/kubeadm/blob/main/docs/ha-considerations.md#haproxy-configuration
+var configTemplate = template.Must(template.New("haproxyConfigTemplate").Parse(`global
+    log stdout format raw local0 info
+
+defaults
+    mode http
+    log global
+    option httplog
+    option dontlognull
+    timeout connect 5s
+    timeout client 35s
+    timeout server 35s
+    timeout http-request 10s
+    timeout http-keep-alive 10s
+    timeout check 2s
+    retries 1
+
+frontend apiserver
+    bind *:{{.Port}}
+    mode tcp
+    option tcplog
+    default_backend apiserver
+
+backend apiserver
+    option httpchk
+    http-check connect ssl
+    http-check send meth GET uri /healthz
+    http-check expect status 200
+    mode tcp
+    balance roundrobin
+    default-server inter 1s fall 2 rise 2
+{{- range .Backends}}
+    server {{.Name}} {{.Address}} check verify none
+{{- end}}
+`))
+
+// manifestTemplate is the static pod of haproxy, annotated with the hash of its config for kubelet to recreate it when the config changes
+var manifestTemplate = template.Must(template.New("haproxyManifestTemplate").Parse(`apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    minikube.sigs.k8s.io/haproxy-config-hash: "{{.ConfigHash}}"
+  creationTimestamp: null
+  name: haproxy
+  namespace: kube-system
+spec:
+  containers:
+  - image: docker.io/library/haproxy:2.8-alpine
+    imagePullPolicy: IfNotPresent
+    name: haproxy
+    resources: {}
+    volumeMounts:
+    - mountPath: /usr/local/etc/haproxy/haproxy.cfg
+      name: haproxyconf
+      readOnly: true
+  hostNetwork: true
+  volumes:
+  - hostPath:
+      path: {{.ConfigPath}}
+      type: FileOrCreate
+    name: haproxyconf
+status: {}
+`))
+
+// backend is the apiserver of a control-plane node
+type backend struct {
+	Name    string
+	Address string
+}
+
+// Configure generates the haproxy config balancing the apiservers of the control-plane nodes of a cluster, and the static pod running haproxy with it
+func Configure(cc config.ClusterConfig) ([]byte, []byte, error) {
+	klog.Info("generating haproxy config ...")
+
+	params := struct {
+		Port     int
+		Backends []backend
+	}{
+		Port: cc.APIServerPort,
+	}
+	for _, n := range config.ControlPlanes(cc) {
+		// control-plane nodes which are not provisioned yet are added to the config once they are
+		if n.IP == "" {
+			continue
+		}
+		port := n.Port
+		if port == 0 {
+			port = cc.APIServerPort
+		}
+		params.Backends = append(params.Backends, backend{Name: config.MachineName(cc, n), Address: fmt.Sprintf("%s:%d", n.IP, port)})
+	}
+
+	cfg := bytes.Buffer{}
+	if err := configTemplate.Execute(&cfg, params); err != nil {
+		return nil, nil, errors.Wrap(err, "haproxy config template")
+	}
+	klog.Infof("haproxy config:\n%s", cfg.String())
+
+	manifest := bytes.Buffer{}
+	mparams := struct {
+		ConfigHash string
+		ConfigPath string
+	}{
+		ConfigHash: fmt.Sprintf("%x", sha256.Sum256(cfg.Bytes())),
+		ConfigPath: ConfigPath,
+	}
+	if err := manifestTemplate.Execute(&manifest, mparams); err != nil {
+		return nil, nil, errors.Wrap(err, "haproxy manifest template")
+	}
+	return cfg.Bytes(), manifest.Bytes(), nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package haproxy
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestConfigure(t *testing.T) {
+	cc := config.ClusterConfig{
+		Name:          "ha",
+		APIServerPort: 8443,
+		Nodes: []config.Node{
+			{Name: "", IP: "192.168.49.2", Port: 8443, ControlPlane: true},
+			{Name: "m02", IP: "192.168.49.3", LoadBalancer: true},
+			{Name: "m03", IP: "192.168.49.4", Port: 8443, ControlPlane: true},
+			{Name: "m04", IP: "192.168.49.5", Worker: true},
+			{Name: "m05", ControlPlane: true},
+		},
+	}
+
+	cfg, manifest, err := Configure(cc)
+	if err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	for _, want := range []string{"bind *:8443", "server ha 192.168.49.2:8443 check verify none", "server ha-m03 192.168.49.4:8443 check verify none"} {
+		if !strings.Contains(string(cfg), want) {
+			t.Errorf("expected the config to contain %q, got:\n%s", want, cfg)
+		}
+	}
+	if n := strings.Count(string(cfg), "check verify none"); n != 2 {
+		t.Errorf("expected 2 backends, the provisioned control-plane nodes, got %d:\n%s", n, cfg)
+	}
+
+	hash := regexp.MustCompile(`haproxy-config-hash: "([0-9a-f]+)"`)
+	m := hash.FindSubmatch(manifest)
+	if m == nil {
+		t.Fatalf("expected the manifest to be annotated with the config hash, got:\n%s", manifest)
+	}
+	if !strings.Contains(string(manifest), "path: "+ConfigPath) {
+		t.Errorf("expected the manifest to mount %s, got:\n%s", ConfigPath, manifest)
+	}
+
+	// kubelet recreates haproxy when a control-plane node is added
+	cc.Nodes[4].IP = "192.168.49.6"
+	_, updated, err := Configure(cc)
+	if err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	if u := hash.FindSubmatch(updated); u == nil || string(u[1]) == string(m[1]) {
+		t.Errorf("expected the config hash to change with the backends, got:\n%s", updated)
+	}
+}
@@ -128,6 +128,8 @@ type Status struct {
 	TimeToStop string `json:",omitempty"`
 	DockerEnv  string `json:",omitempty"`
 	PodManEnv  string `json:",omitempty"`
+	// DiskPressure is set when /var is used above the image garbage collection threshold of the cluster
+	DiskPressure string `json:",omitempty"`
 }
 
 // State holds a cluster state representation
@@ -418,6 +420,9 @@ func NodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 		st.Host = state.Error.String()
 		return st, err
 	}
+	if machine.ImageGCNeeded(cc, p) {
+		st.DiskPressure = fmt.Sprintf("%d%% of /var used, above the image garbage collection threshold of %d%%", p, cc.ImageGCThreshold)
+	}
 	if p >= 99 {
 		st.Host = codeNames[InsufficientStorage]
 	}
@@ -459,7 +464,7 @@ func NodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 		st.Kubeconfig = Misconfigured
 	}
 
-	sta, err := kverify.APIServerStatus(cr, hostname, port)
+	sta, err := kverify.APIServerStatus(cr, cc.Name, hostname, port)
 	klog.Infof("%s apiserver status = %s (err=%v)", name, stk, err)
 
 	if err != nil {
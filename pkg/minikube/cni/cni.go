@@ -139,7 +139,7 @@ func chooseDefault(cc config.ClusterConfig) Manager {
 
 	if cc.KubernetesConfig.ContainerRuntime != constants.Docker {
 		// Always use CNI when running with CRI (without dockershim)
-		if driver.IsKIC(cc.Driver) {
+		if driver.IsKIC(cc.Driver) || driver.IsNspawn(cc.Driver) {
 			klog.Infof("%q driver + %q runtime found, recommending kindnet", cc.Driver, cc.KubernetesConfig.ContainerRuntime)
 			return KindNet{cc: cc}
 		}
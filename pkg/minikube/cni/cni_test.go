@@ -17,8 +17,10 @@ limitations under the License.
 package cni
 
 import (
+	"reflect"
 	"testing"
 
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/config"
 )
 
@@ -52,3 +54,48 @@ func TestChooseDefault(t *testing.T) {
 		}
 	}
 }
+
+func TestImages(t *testing.T) {
+	tests := []struct {
+		cni  string
+		want []string
+	}{
+		{"bridge", nil},
+		{"false", nil},
+		{"kindnet", []string{images.KindNet("")}},
+		{"calico", []string{images.CalicoBin(""), images.CalicoDaemonSet(""), images.CalicoDeployment("")}},
+	}
+	for _, tc := range tests {
+		cc := config.ClusterConfig{
+			Driver: "docker",
+			KubernetesConfig: config.KubernetesConfig{
+				ContainerRuntime:  "containerd",
+				KubernetesVersion: "v1.30.0",
+				CNI:               tc.cni,
+			},
+		}
+		got, err := Images(cc)
+		if err != nil {
+			t.Fatalf("Images(%s) unexpected error: %v", tc.cni, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Images(%s) = %v; want = %v", tc.cni, got, tc.want)
+		}
+	}
+}
+
+func TestManifestImages(t *testing.T) {
+	manifest := []byte(`containers:
+  - name: a
+    image: "quay.io/a:v1@sha256:abc"
+  - image: docker.io/b:v2
+    name: b
+initContainers:
+  - name: c
+    image: 'quay.io/a:v1@sha256:abc'
+`)
+	want := []string{"quay.io/a:v1@sha256:abc", "docker.io/b:v2"}
+	if got := manifestImages(manifest); !reflect.DeepEqual(got, want) {
+		t.Errorf("manifestImages() = %v; want = %v", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// imageLine matches the container images of a Kubernetes manifest
+var imageLine = regexp.MustCompile(`(?m)^\s*(?:-\s+)?image:\s*["']?([^"'\s]+)["']?\s*$`)
+
+// Images returns the container images referenced by the CNI manifest applied for cc
+func Images(cc config.ClusterConfig) ([]string, error) {
+	cnm, err := New(&cc)
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	switch c := cnm.(type) {
+	case Calico:
+		b, err = readManifest(c.manifest())
+	case Flannel:
+		b, err = readManifest(c.manifest())
+	case KindNet:
+		b, err = readManifest(c.manifest())
+	case Cilium:
+		b, err = GenerateCiliumYAML()
+	case Custom:
+		b, err = os.ReadFile(c.manifest)
+	default:
+		// bridge and disabled CNIs do not deploy any images
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s manifest", cnm)
+	}
+	return manifestImages(b), nil
+}
+
+// readManifest returns the contents of a generated manifest
+func readManifest(f assets.CopyableFile, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(f)
+}
+
+// manifestImages returns the unique images referenced by a manifest, in order of appearance
+func manifestImages(b []byte) []string {
+	imgs := []string{}
+	for _, m := range imageLine.FindAllSubmatch(b, -1) {
+		img := string(m[1])
+		if !slices.Contains(imgs, img) {
+			imgs = append(imgs, img)
+		}
+	}
+	return imgs
+}
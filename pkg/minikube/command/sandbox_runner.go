@@ -0,0 +1,589 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/syncmap"
+
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+// Response is the recorded outcome of a command run in a sandbox
+type Response struct {
+	Stdout   string `json:"stdout,omitempty"`
+	Stderr   string `json:"stderr,omitempty"`
+	ExitCode int    `json:"exitCode,omitempty"`
+}
+
+// SandboxRunner runs commands against a directory standing in for the root filesystem of a machine.
+//
+// Nothing is ever executed: recorded responses win, file operations (mkdir, cp, tee, ...) are applied
+// below the root, systemd units are tracked as files, and any other command silently succeeds.
+// It implements the CommandRunner interface and backs the fake driver.
+type SandboxRunner struct {
+	root      string
+	responses syncmap.Map
+}
+
+// sandboxServicesDir is where the sandbox keeps the state of the systemd units it was asked to manage
+const sandboxServicesDir = "/run/sandbox/services"
+
+// NewSandboxRunner returns a SandboxRunner rooted at the given directory
+func NewSandboxRunner(root string) *SandboxRunner {
+	return &SandboxRunner{root: root}
+}
+
+// SetCommandToResponse records the response to give for a command, as it is logged by the runner
+func (s *SandboxRunner) SetCommandToResponse(cmdToResponse map[string]Response) {
+	for k, v := range cmdToResponse {
+		klog.Infof("sandbox command %q -> %+v", k, v)
+		s.responses.Store(k, v)
+	}
+}
+
+// LoadResponses records the responses stored as a JSON object of command to Response in a file, if it exists
+func (s *SandboxRunner) LoadResponses(file string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "read responses")
+	}
+	responses := map[string]Response{}
+	if err := json.Unmarshal(b, &responses); err != nil {
+		return errors.Wrapf(err, "parse responses %s", file)
+	}
+	s.SetCommandToResponse(responses)
+	return nil
+}
+
+// RunCmd implements the Command Runner interface to run a exec.Cmd object
+func (s *SandboxRunner) RunCmd(cmd *exec.Cmd) (*RunResult, error) {
+	rr := &RunResult{Args: cmd.Args}
+	klog.Infof("(SandboxRunner) Run: %v", rr.Command())
+	s.runInto(rr, cmd)
+	return rr, sandboxError(rr)
+}
+
+// StartCmd implements the Command Runner interface to start a exec.Cmd object
+func (s *SandboxRunner) StartCmd(cmd *exec.Cmd) (*StartedCmd, error) {
+	rr := &RunResult{Args: cmd.Args}
+	sc := &StartedCmd{cmd: cmd, rr: rr, wg: &sync.WaitGroup{}}
+	klog.Infof("(SandboxRunner) Start: %v", rr.Command())
+
+	// callers may only start reading the output of the command once it was started
+	sc.wg.Add(1)
+	go func() {
+		defer sc.wg.Done()
+		s.runInto(rr, cmd)
+	}()
+	return sc, nil
+}
+
+// WaitCmd implements the Command Runner interface to wait until a started exec.Cmd object finishes
+func (s *SandboxRunner) WaitCmd(sc *StartedCmd) (*RunResult, error) {
+	sc.wg.Wait()
+	return sc.rr, sandboxError(sc.rr)
+}
+
+// runInto runs a command, recording its outcome
+func (s *SandboxRunner) runInto(rr *RunResult, cmd *exec.Cmd) {
+	var stdin []byte
+	if cmd.Stdin != nil {
+		b, err := io.ReadAll(cmd.Stdin)
+		if err != nil {
+			rr.Stderr.WriteString(fmt.Sprintf("reading stdin: %v\n", err))
+			rr.ExitCode = 1
+			return
+		}
+		stdin = b
+	}
+
+	resp, ok := s.recorded(rr.Command())
+	if !ok {
+		resp = s.run(cmd.Args, stdin)
+	}
+	rr.Stdout.WriteString(resp.Stdout)
+	rr.Stderr.WriteString(resp.Stderr)
+	rr.ExitCode = resp.ExitCode
+	if cmd.Stdout != nil {
+		if _, err := io.WriteString(cmd.Stdout, resp.Stdout); err != nil {
+			klog.Warningf("unable to write stdout of %s: %v", rr.Command(), err)
+		}
+	}
+	if cmd.Stderr != nil {
+		if _, err := io.WriteString(cmd.Stderr, resp.Stderr); err != nil {
+			klog.Warningf("unable to write stderr of %s: %v", rr.Command(), err)
+		}
+	}
+}
+
+// sandboxError returns the error of a command which did not exit successfully
+func sandboxError(rr *RunResult) error {
+	if rr.ExitCode == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: exit status %d\nstdout:\n%s\nstderr:\n%s", rr.Command(), rr.ExitCode, rr.Stdout.String(), rr.Stderr.String())
+}
+
+// Copy writes a file below the root of the sandbox
+func (s *SandboxRunner) Copy(f assets.CopyableFile) error {
+	dst := s.path(path.Join(f.GetTargetDir(), f.GetTargetName()))
+	klog.Infof("(SandboxRunner) cp: %s --> %s (%d bytes)", f.GetSourcePath(), dst, f.GetLength())
+
+	perms, err := strconv.ParseInt(f.GetPermissions(), 8, 0)
+	if err != nil || perms > 07777 {
+		return errors.Wrapf(err, "error converting permissions %s to integer", f.GetPermissions())
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error removing file %s", dst)
+	}
+	return writeFile(dst, f, os.FileMode(perms))
+}
+
+// CopyFrom copies a file from below the root of the sandbox to the host
+func (s *SandboxRunner) CopyFrom(f assets.CopyableFile) error {
+	src := s.path(f.GetTargetPath())
+	klog.Infof("(SandboxRunner) cp: %s --> %s", src, f.GetSourcePath())
+
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return errors.Wrapf(err, "error reading file %s", src)
+	}
+	return os.WriteFile(f.GetSourcePath(), b, 0644)
+}
+
+// Remove removes a file from below the root of the sandbox
+func (s *SandboxRunner) Remove(f assets.CopyableFile) error {
+	dst := s.path(path.Join(f.GetTargetDir(), f.GetTargetName()))
+	klog.Infof("(SandboxRunner) rm: %s", dst)
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ReadableFile implements interface (without implementation)
+func (s *SandboxRunner) ReadableFile(_ string) (assets.ReadableFile, error) {
+	return nil, fmt.Errorf("SandboxRunner does not support ReadableFile")
+}
+
+// StopServices marks every unit of the sandbox as inactive, as a shutdown would
+func (s *SandboxRunner) StopServices() error {
+	return os.RemoveAll(s.path(sandboxServicesDir))
+}
+
+// Running returns whether any unit of the sandbox is active, which keeps all the processes of the machine running
+func (s *SandboxRunner) Running() bool {
+	entries, err := os.ReadDir(s.path(sandboxServicesDir))
+	return err == nil && len(entries) > 0
+}
+
+// recorded returns the recorded response for a command, if there is one
+func (s *SandboxRunner) recorded(command string) (Response, bool) {
+	v, ok := s.responses.Load(command)
+	if !ok {
+		return Response{}, false
+	}
+	return v.(Response), true
+}
+
+// path maps a path of the machine to the host, never escaping the root (which is also the working directory)
+func (s *SandboxRunner) path(p string) string {
+	return filepath.Join(s.root, filepath.FromSlash(path.Clean("/"+p)))
+}
+
+// run simulates a command which has no recorded response
+func (s *SandboxRunner) run(args []string, stdin []byte) Response {
+	args = commandArgs(args)
+	if len(args) == 0 {
+		return Response{}
+	}
+	if (args[0] == "/bin/bash" || args[0] == "bash" || args[0] == "/bin/sh" || args[0] == "sh") && len(args) > 2 && args[1] == "-c" {
+		return s.script(args[2], stdin)
+	}
+
+	switch path.Base(args[0]) {
+	case "mkdir":
+		for _, p := range operands(args) {
+			if err := os.MkdirAll(s.path(p), 0755); err != nil {
+				return failure(1, err)
+			}
+		}
+	case "rm":
+		for _, p := range operands(args) {
+			if err := os.RemoveAll(s.path(p)); err != nil {
+				return failure(1, err)
+			}
+		}
+	case "test", "[":
+		return s.test(args[1:])
+	case "cat":
+		var out strings.Builder
+		for _, p := range operands(args) {
+			b, err := os.ReadFile(s.path(p))
+			if err != nil {
+				return failure(1, fmt.Errorf("cat: %s: No such file or directory", p))
+			}
+			out.Write(b)
+		}
+		return Response{Stdout: out.String()}
+	case "ls":
+		paths := operands(args)
+		if len(paths) == 0 {
+			paths = []string{"."}
+		}
+		return s.ls(paths)
+	case "touch":
+		for _, p := range operands(args) {
+			if err := writeSandboxFile(s.path(p), nil, true); err != nil {
+				return failure(1, err)
+			}
+		}
+	case "cp", "mv", "ln":
+		return s.copy(path.Base(args[0]), operands(args))
+	case "tee":
+		for _, p := range operands(args) {
+			if err := writeSandboxFile(s.path(p), stdin, hasFlag(args, "-a")); err != nil {
+				return failure(1, err)
+			}
+		}
+		return Response{Stdout: string(stdin)}
+	case "systemctl":
+		return s.systemctl(args[1:])
+	case "grep", "egrep":
+		return s.grep(operands(args))
+	case "pgrep":
+		// every process minikube looks for is a daemon or a static pod, which keep running (if maybe frozen) for as long as any unit does
+		if !s.Running() {
+			return Response{ExitCode: 1}
+		}
+		return Response{Stdout: "1\n"}
+	}
+	return Response{}
+}
+
+// script simulates a shell script, as long as it is a plain list of commands joined by &&
+func (s *SandboxRunner) script(script string, stdin []byte) Response {
+	if strings.ContainsAny(strings.ReplaceAll(script, "&&", ""), "|;&<>$`()\n") {
+		return Response{}
+	}
+	var out, errs strings.Builder
+	for _, c := range strings.Split(script, "&&") {
+		resp := s.run(splitWords(c), stdin)
+		out.WriteString(resp.Stdout)
+		errs.WriteString(resp.Stderr)
+		if resp.ExitCode != 0 {
+			return Response{Stdout: out.String(), Stderr: errs.String(), ExitCode: resp.ExitCode}
+		}
+	}
+	return Response{Stdout: out.String(), Stderr: errs.String()}
+}
+
+// grep simulates grep -E, printing the lines of files which match a pattern
+func (s *SandboxRunner) grep(args []string) Response {
+	if len(args) < 2 {
+		return Response{}
+	}
+	re, err := regexp.Compile(args[0])
+	if err != nil {
+		return failure(2, err)
+	}
+	var out strings.Builder
+	for _, p := range args[1:] {
+		b, err := os.ReadFile(s.path(p))
+		if err != nil {
+			return failure(2, fmt.Errorf("grep: %s: No such file or directory", p))
+		}
+		for _, l := range strings.SplitAfter(string(b), "\n") {
+			if l != "" && re.MatchString(strings.TrimSuffix(l, "\n")) {
+				out.WriteString(strings.TrimSuffix(l, "\n") + "\n")
+			}
+		}
+	}
+	if out.Len() == 0 {
+		return Response{ExitCode: 1}
+	}
+	return Response{Stdout: out.String()}
+}
+
+// test simulates the file tests of test(1)
+func (s *SandboxRunner) test(args []string) Response {
+	args = trimSuffix(args, "]")
+	if len(args) != 2 {
+		return Response{}
+	}
+	fi, err := os.Stat(s.path(args[1]))
+	ok := err == nil
+	switch args[0] {
+	case "-d":
+		ok = ok && fi.IsDir()
+	case "-f":
+		ok = ok && fi.Mode().IsRegular()
+	case "-s":
+		ok = ok && fi.Size() > 0
+	}
+	if !ok {
+		return Response{ExitCode: 1}
+	}
+	return Response{}
+}
+
+// ls lists directories, one entry per line
+func (s *SandboxRunner) ls(paths []string) Response {
+	var out strings.Builder
+	for _, p := range paths {
+		fi, err := os.Stat(s.path(p))
+		if err != nil {
+			return failure(2, fmt.Errorf("ls: cannot access '%s': No such file or directory", p))
+		}
+		if !fi.IsDir() {
+			fmt.Fprintln(&out, p)
+			continue
+		}
+		entries, err := os.ReadDir(s.path(p))
+		if err != nil {
+			return failure(2, err)
+		}
+		names := []string{}
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Fprintln(&out, n)
+		}
+	}
+	return Response{Stdout: out.String()}
+}
+
+// copy simulates cp, mv and ln within the sandbox, links becoming copies
+func (s *SandboxRunner) copy(op string, args []string) Response {
+	if len(args) != 2 {
+		return Response{}
+	}
+	src, dst := s.path(args[0]), s.path(args[1])
+	if fi, err := os.Stat(dst); err == nil && fi.IsDir() {
+		dst = filepath.Join(dst, filepath.Base(src))
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		if op == "ln" {
+			// links to files outside of the sandbox are fine, there is nothing to follow them
+			return Response{}
+		}
+		return failure(1, fmt.Errorf("%s: cannot stat '%s': No such file or directory", op, args[0]))
+	}
+	if err := writeSandboxFile(dst, b, false); err != nil {
+		return failure(1, err)
+	}
+	if op == "mv" {
+		if err := os.Remove(src); err != nil {
+			return failure(1, err)
+		}
+	}
+	return Response{}
+}
+
+// writeSandboxFile writes (or appends) to a file of the sandbox
+func writeSandboxFile(dst string, b []byte, appendTo bool) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendTo {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(dst, flags, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// systemctl tracks whether units are active, as files of the sandbox
+func (s *SandboxRunner) systemctl(args []string) Response {
+	verb := ""
+	units := []string{}
+	for _, a := range args {
+		switch {
+		case strings.HasPrefix(a, "-"):
+		case verb == "":
+			verb = a
+		case a != "service":
+			units = append(units, strings.TrimSuffix(a, ".service"))
+		}
+	}
+
+	switch verb {
+	case "start", "restart", "reload-or-restart", "try-restart":
+		return s.setActive(units, true)
+	case "stop", "kill":
+		return s.setActive(units, false)
+	case "enable", "disable":
+		if hasFlag(args, "--now") {
+			return s.setActive(units, verb == "enable")
+		}
+	case "is-active":
+		for _, u := range units {
+			if !s.active(u) {
+				if hasFlag(args, "--quiet") {
+					return Response{ExitCode: 3}
+				}
+				return Response{Stdout: "inactive\n", ExitCode: 3}
+			}
+		}
+		if hasFlag(args, "--quiet") {
+			return Response{}
+		}
+		return Response{Stdout: "active\n"}
+	}
+	return Response{}
+}
+
+// setActive marks units as active or not
+func (s *SandboxRunner) setActive(units []string, active bool) Response {
+	for _, u := range units {
+		p := path.Join(sandboxServicesDir, u)
+		if !active {
+			if err := os.Remove(s.path(p)); err != nil && !os.IsNotExist(err) {
+				return failure(1, err)
+			}
+			continue
+		}
+		if err := writeSandboxFile(s.path(p), []byte("active\n"), false); err != nil {
+			return failure(1, err)
+		}
+	}
+	return Response{}
+}
+
+// active returns whether a unit is active
+func (s *SandboxRunner) active(unit string) bool {
+	_, err := os.Stat(s.path(path.Join(sandboxServicesDir, unit)))
+	return err == nil
+}
+
+// failure returns the response of a failed command
+func failure(code int, err error) Response {
+	return Response{Stderr: err.Error() + "\n", ExitCode: code}
+}
+
+// commandArgs strips sudo and environment assignments from a command line
+func commandArgs(args []string) []string {
+	for len(args) > 0 {
+		switch {
+		case args[0] == "sudo" || args[0] == "env":
+			args = args[1:]
+			for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+				args = args[1:]
+			}
+		case strings.Contains(args[0], "=") && !strings.HasPrefix(args[0], "-"):
+			args = args[1:]
+		default:
+			return args
+		}
+	}
+	return args
+}
+
+// operands returns the arguments of a command which are not flags
+func operands(args []string) []string {
+	ops := []string{}
+	for _, a := range args[1:] {
+		if !strings.HasPrefix(a, "-") {
+			ops = append(ops, a)
+		}
+	}
+	return ops
+}
+
+// hasFlag returns whether a flag was given to a command
+func hasFlag(args []string, flag string) bool {
+	for _, a := range args {
+		if a == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// trimSuffix removes the last argument if it is the given one
+func trimSuffix(args []string, last string) []string {
+	if len(args) > 0 && args[len(args)-1] == last {
+		return args[:len(args)-1]
+	}
+	return args
+}
+
+// splitWords splits a shell command line into words, honoring quotes
+func splitWords(line string) []string {
+	words := []string{}
+	var word bytes.Buffer
+	inWord := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+func TestSandboxRunnerFiles(t *testing.T) {
+	root := t.TempDir()
+	r := NewSandboxRunner(root)
+
+	run := func(args ...string) *RunResult {
+		t.Helper()
+		rr, err := r.RunCmd(exec.Command(args[0], args[1:]...))
+		if err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		return rr
+	}
+
+	run("sudo", "mkdir", "-p", "/etc/kubernetes/manifests")
+	if fi, err := os.Stat(filepath.Join(root, "etc", "kubernetes", "manifests")); err != nil || !fi.IsDir() {
+		t.Fatalf("mkdir did not create the directory below the root: %v", err)
+	}
+
+	c := exec.Command("sudo", "tee", "/etc/hosts")
+	c.Stdin = bytes.NewBufferString("127.0.0.1 localhost\n")
+	if _, err := r.RunCmd(c); err != nil {
+		t.Fatal(err)
+	}
+	if got := run("cat", "/etc/hosts").Stdout.String(); got != "127.0.0.1 localhost\n" {
+		t.Errorf("cat /etc/hosts = %q", got)
+	}
+
+	run("sudo", "cp", "/etc/hosts", "/etc/kubernetes/hosts")
+	run("sudo", "mv", "/etc/kubernetes/hosts", "/etc/kubernetes/manifests")
+	if got := run("ls", "/etc/kubernetes/manifests").Stdout.String(); got != "hosts\n" {
+		t.Errorf("ls = %q, want the moved file", got)
+	}
+
+	if _, err := r.RunCmd(exec.Command("test", "-f", "/etc/kubernetes/hosts")); err == nil {
+		t.Errorf("moved file still exists")
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "test", "-d", "/etc/kubernetes")); err != nil {
+		t.Errorf("test -d: %v", err)
+	}
+
+	// paths never escape the root
+	run("sudo", "touch", "../../outside")
+	if _, err := os.Stat(filepath.Join(root, "outside")); err != nil {
+		t.Errorf("touch did not stay below the root: %v", err)
+	}
+
+	f := assets.NewMemoryAssetTarget([]byte("config"), "/var/lib/kubelet/config.yaml", "0644")
+	if err := r.Copy(f); err != nil {
+		t.Fatal(err)
+	}
+	if got := run("sudo", "cat", "/var/lib/kubelet/config.yaml").Stdout.String(); got != "config" {
+		t.Errorf("cat copied file = %q", got)
+	}
+	if err := r.Remove(f); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.RunCmd(exec.Command("cat", "/var/lib/kubelet/config.yaml")); err == nil {
+		t.Errorf("removed file can still be read")
+	}
+}
+
+func TestSandboxRunnerServices(t *testing.T) {
+	r := NewSandboxRunner(t.TempDir())
+
+	active := func(unit string) bool {
+		_, err := r.RunCmd(exec.Command("sudo", "systemctl", "is-active", "--quiet", "service", unit))
+		return err == nil
+	}
+	running := func() bool {
+		_, err := r.RunCmd(exec.Command("sudo", "pgrep", "-xnf", "kube-apiserver.*minikube.*"))
+		return err == nil
+	}
+
+	if active("kubelet") || running() {
+		t.Fatalf("fresh sandbox has active units")
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "systemctl", "start", "kubelet")); err != nil {
+		t.Fatal(err)
+	}
+	if !active("kubelet") || !running() || !r.Running() {
+		t.Errorf("kubelet is not active after start")
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "systemctl", "disable", "--now", "kubelet.service")); err != nil {
+		t.Fatal(err)
+	}
+	if active("kubelet") {
+		t.Errorf("kubelet is active after disable --now")
+	}
+
+	if _, err := r.RunCmd(exec.Command("sudo", "systemctl", "enable", "--now", "docker")); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.StopServices(); err != nil {
+		t.Fatal(err)
+	}
+	if active("docker") || running() {
+		t.Errorf("units are still active once the services were stopped")
+	}
+}
+
+func TestSandboxRunnerGrep(t *testing.T) {
+	r := NewSandboxRunner(t.TempDir())
+	c := exec.Command("sudo", "tee", "/proc/1/cgroup")
+	c.Stdin = strings.NewReader("3:freezer:/kubepods\n0::/\n")
+	if _, err := r.RunCmd(c); err != nil {
+		t.Fatal(err)
+	}
+
+	rr, err := r.RunCmd(exec.Command("sudo", "egrep", "^[0-9]+:freezer:", "/proc/1/cgroup"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rr.Stdout.String(); got != "3:freezer:/kubepods\n" {
+		t.Errorf("egrep stdout = %q, want the freezer line", got)
+	}
+	if _, err := r.RunCmd(exec.Command("grep", "memory", "/proc/1/cgroup")); err == nil {
+		t.Errorf("grep without a match succeeded")
+	}
+	if _, err := r.RunCmd(exec.Command("grep", "freezer", "/proc/2/cgroup")); err == nil {
+		t.Errorf("grep of a missing file succeeded")
+	}
+}
+
+func TestSandboxRunnerScript(t *testing.T) {
+	r := NewSandboxRunner(t.TempDir())
+
+	rr, err := r.RunCmd(exec.Command("/bin/bash", "-c", "sudo mkdir -p /data && sudo touch /data/a && ls /data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rr.Stdout.String(); got != "a\n" {
+		t.Errorf("script output = %q", got)
+	}
+
+	if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", "test -d /missing && sudo touch /data/b")); err == nil {
+		t.Errorf("script did not stop at the first failing command")
+	}
+	if _, err := r.RunCmd(exec.Command("test", "-f", "/data/b")); err == nil {
+		t.Errorf("script ran past the failing command")
+	}
+
+	// anything beyond a list of commands is not simulated, and succeeds
+	if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", "test -d /missing || exit 1")); err != nil {
+		t.Errorf("unsimulated script failed: %v", err)
+	}
+}
+
+func TestSandboxRunnerResponses(t *testing.T) {
+	r := NewSandboxRunner(t.TempDir())
+	r.SetCommandToResponse(map[string]Response{
+		"docker version --format {{.Server.Version}}": {Stdout: "27.3.1\n"},
+	})
+
+	file := filepath.Join(t.TempDir(), "responses.json")
+	if err := r.LoadResponses(file); err != nil {
+		t.Errorf("missing responses file: %v", err)
+	}
+	if err := os.WriteFile(file, []byte(`{"sudo crictl info": {"stderr": "no runtime", "exitCode": 1}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.LoadResponses(file); err != nil {
+		t.Fatal(err)
+	}
+
+	rr, err := r.RunCmd(exec.Command("docker", "version", "--format", "{{.Server.Version}}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rr.Stdout.String(); got != "27.3.1\n" {
+		t.Errorf("stdout = %q, want the recorded one", got)
+	}
+
+	rr, err = r.RunCmd(exec.Command("sudo", "crictl", "info"))
+	if err == nil || rr.ExitCode != 1 || !strings.Contains(rr.Stderr.String(), "no runtime") {
+		t.Errorf("recorded failure was not returned: %v (exit code %d)", err, rr.ExitCode)
+	}
+
+	if err := os.WriteFile(file, []byte(`not json`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.LoadResponses(file); err == nil {
+		t.Errorf("invalid responses file was loaded")
+	}
+}
+
+func TestSandboxRunnerStartCmd(t *testing.T) {
+	r := NewSandboxRunner(t.TempDir())
+	r.SetCommandToResponse(map[string]Response{"kubeadm init": {Stdout: "initialized\n"}})
+
+	var out bytes.Buffer
+	c := exec.Command("kubeadm", "init")
+	c.Stdout = &out
+	sc, err := r.StartCmd(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr, err := r.WaitCmd(sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr.Stdout.String() != "initialized\n" || out.String() != "initialized\n" {
+		t.Errorf("output = %q (written %q)", rr.Stdout.String(), out.String())
+	}
+}
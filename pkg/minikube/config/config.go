@@ -58,6 +58,8 @@ const (
 	EmbedCerts = "EmbedCerts"
 	// MaxAuditEntries is the maximum number of audit entries to retain
 	MaxAuditEntries = "MaxAuditEntries"
+	// ArtifactMirror is the key for the location to download the ISO, kicbase, preload, binaries and drivers from
+	ArtifactMirror = "artifact-mirror"
 )
 
 var (
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// PoolLabel is the Kubernetes label of the nodes of a node pool, whose value is the name of the pool
+const PoolLabel = "minikube.k8s.io/pool"
+
+// FindNodePool returns the node pool of a cluster by name
+func FindNodePool(cc ClusterConfig, name string) (NodePool, bool) {
+	for _, p := range cc.NodePools {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return NodePool{}, false
+}
+
+// PoolNodes returns the nodes of a node pool
+func PoolNodes(cc ClusterConfig, name string) []Node {
+	nodes := []Node{}
+	for _, n := range cc.Nodes {
+		if n.Pool == name {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// ApplyNodePool sets the pool of a node, with the resources, labels and taints of the pool
+func ApplyNodePool(n *Node, p NodePool) {
+	n.Pool = p.Name
+	n.CPUs = p.CPUs
+	n.Memory = p.Memory
+	n.DiskSize = p.DiskSize
+	n.Labels = append([]string{}, p.Labels...)
+	n.Taints = append([]string{}, p.Taints...)
+}
+
+// NodeLabels returns the Kubernetes labels of a node, including the one of its pool
+func NodeLabels(n Node) []string {
+	labels := append([]string{}, n.Labels...)
+	if n.Pool != "" {
+		labels = append(labels, PoolLabel+"="+n.Pool)
+	}
+	return labels
+}
+
+// NodeResources returns the cluster config with the driver and resources of a node, which override the cluster-wide ones when set
+func NodeResources(cc ClusterConfig, n Node) ClusterConfig {
+	if drv := NodeDriver(cc, n); drv != cc.Driver {
+		// the network of the cluster is one of its own driver
+		cc.Driver = drv
+		cc.Network = n.Network
+	}
+	if n.CPUs != 0 {
+		cc.CPUs = n.CPUs
+	}
+	if n.Memory != 0 {
+		cc.Memory = n.Memory
+	}
+	if n.DiskSize != 0 {
+		cc.DiskSize = n.DiskSize
+	}
+	return cc
+}
+
+// ValidateNodePool validates the name, labels and taints of a node pool
+func ValidateNodePool(p NodePool) error {
+	if errs := validation.IsDNS1123Label(p.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid pool name %q: %s", p.Name, strings.Join(errs, "; "))
+	}
+	if err := ValidateLabels(p.Labels); err != nil {
+		return err
+	}
+	return ValidateTaints(p.Taints)
+}
+
+// ValidateLabels validates Kubernetes labels formatted as key=value
+func ValidateLabels(labels []string) error {
+	for _, l := range labels {
+		k, v, ok := strings.Cut(l, "=")
+		if !ok {
+			return fmt.Errorf("invalid label %q, expected key=value", l)
+		}
+		if k == PoolLabel {
+			return fmt.Errorf("label %q is reserved for node pools, use --pool", k)
+		}
+		errs := append(validation.IsQualifiedName(k), validation.IsValidLabelValue(v)...)
+		if len(errs) > 0 {
+			return fmt.Errorf("invalid label %q: %s", l, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// ValidateTaints validates Kubernetes taints formatted as key[=value]:effect
+func ValidateTaints(taints []string) error {
+	for _, t := range taints {
+		kv, effect, ok := strings.Cut(t, ":")
+		if !ok {
+			return fmt.Errorf("invalid taint %q, expected key[=value]:effect", t)
+		}
+		switch core.TaintEffect(effect) {
+		case core.TaintEffectNoSchedule, core.TaintEffectPreferNoSchedule, core.TaintEffectNoExecute:
+		default:
+			return fmt.Errorf("invalid taint %q, effect must be one of %s, %s, %s", t, core.TaintEffectNoSchedule, core.TaintEffectPreferNoSchedule, core.TaintEffectNoExecute)
+		}
+		k, v, _ := strings.Cut(kv, "=")
+		errs := validation.IsQualifiedName(k)
+		if v != "" {
+			errs = append(errs, validation.IsValidLabelValue(v)...)
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("invalid taint %q: %s", t, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateNodePool(t *testing.T) {
+	tests := []struct {
+		pool        NodePool
+		shouldError bool
+	}{
+		{NodePool{Name: "gpu"}, false},
+		{NodePool{Name: "gpu", Labels: []string{"accelerator=nvidia", "example.com/tier="}, Taints: []string{"nvidia.com/gpu=present:NoSchedule", "dedicated:NoExecute"}}, false},
+		{NodePool{Name: "GPU"}, true},
+		{NodePool{Name: ""}, true},
+		{NodePool{Name: "gpu", Labels: []string{"accelerator"}}, true},
+		{NodePool{Name: "gpu", Labels: []string{"bad key=value"}}, true},
+		{NodePool{Name: "gpu", Labels: []string{PoolLabel + "=cpu"}}, true},
+		{NodePool{Name: "gpu", Taints: []string{"dedicated=gpu"}}, true},
+		{NodePool{Name: "gpu", Taints: []string{"dedicated=gpu:Sometimes"}}, true},
+		{NodePool{Name: "gpu", Taints: []string{"=gpu:NoSchedule"}}, true},
+	}
+	for _, tc := range tests {
+		err := ValidateNodePool(tc.pool)
+		if err != nil && !tc.shouldError {
+			t.Errorf("pool %+v failed validation; expected it to pass: %v", tc.pool, err)
+		}
+		if err == nil && tc.shouldError {
+			t.Errorf("pool %+v passed validation; expected it to fail", tc.pool)
+		}
+	}
+}
+
+func TestNodePools(t *testing.T) {
+	gpu := NodePool{Name: "gpu", CPUs: 4, Memory: 8192, Labels: []string{"accelerator=nvidia"}, Taints: []string{"nvidia.com/gpu=present:NoSchedule"}}
+	cc := ClusterConfig{
+		CPUs:      2,
+		Memory:    2200,
+		DiskSize:  20000,
+		NodePools: []NodePool{gpu},
+		Nodes:     []Node{{Name: ""}, {Name: "m02"}},
+	}
+
+	n := Node{Name: "m03"}
+	ApplyNodePool(&n, gpu)
+	cc.Nodes = append(cc.Nodes, n)
+
+	if p, ok := FindNodePool(cc, "gpu"); !ok || !reflect.DeepEqual(p, gpu) {
+		t.Errorf("FindNodePool(gpu) = %+v, %v", p, ok)
+	}
+	if _, ok := FindNodePool(cc, "cpu"); ok {
+		t.Errorf("expected pool cpu not to be found")
+	}
+	if got := PoolNodes(cc, "gpu"); len(got) != 1 || got[0].Name != "m03" {
+		t.Errorf("PoolNodes(gpu) = %+v", got)
+	}
+
+	if got, want := NodeLabels(n), []string{"accelerator=nvidia", PoolLabel + "=gpu"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NodeLabels() = %v, want %v", got, want)
+	}
+	if got := NodeLabels(cc.Nodes[1]); len(got) != 0 {
+		t.Errorf("expected a node without pool to have no labels, got %v", got)
+	}
+
+	r := NodeResources(cc, n)
+	if r.CPUs != 4 || r.Memory != 8192 || r.DiskSize != 20000 {
+		t.Errorf("NodeResources() = CPUs %d, Memory %d, DiskSize %d, want 4, 8192, 20000", r.CPUs, r.Memory, r.DiskSize)
+	}
+	if r := NodeResources(cc, cc.Nodes[1]); r.CPUs != 2 || r.Memory != 2200 {
+		t.Errorf("expected a node without resources to have the cluster-wide ones, got CPUs %d, Memory %d", r.CPUs, r.Memory)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -54,6 +55,69 @@ func ControlPlanes(cc ClusterConfig) []Node {
 	return cps
 }
 
+// KubernetesNodes returns the nodes which join the Kubernetes cluster, leaving out the dedicated etcd and load balancer nodes.
+func KubernetesNodes(cc ClusterConfig) []Node {
+	nodes := []Node{}
+	for _, n := range cc.Nodes {
+		if !n.Etcd && !n.LoadBalancer {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// Topologies of the control plane of ha (multi-control plane) clusters
+const (
+	// EtcdStacked runs an etcd member on every control-plane node, as kubeadm does by default
+	EtcdStacked = "stacked"
+	// EtcdExternal runs the etcd members on dedicated etcd nodes
+	EtcdExternal = "external"
+	// KubeVIP serves the apiservers from a virtual IP which kube-vip moves between the control-plane nodes
+	KubeVIP = "kube-vip"
+	// HAProxy serves the apiservers from a dedicated load balancer node running haproxy
+	HAProxy = "haproxy"
+)
+
+// IsExternalEtcd returns if etcd runs on dedicated etcd nodes rather than on the control-plane nodes.
+func IsExternalEtcd(cc ClusterConfig) bool {
+	return cc.KubernetesConfig.EtcdTopology == EtcdExternal
+}
+
+// EtcdNodes returns the nodes running an etcd member: the dedicated etcd nodes with external etcd, the control-plane nodes otherwise.
+func EtcdNodes(cc ClusterConfig) []Node {
+	if !IsExternalEtcd(cc) {
+		return ControlPlanes(cc)
+	}
+	nodes := []Node{}
+	for _, n := range cc.Nodes {
+		if n.Etcd {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// IsFirstEtcdNode returns if the node runs the first etcd member, which generated the CA of etcd.
+func IsFirstEtcdNode(cc ClusterConfig, n Node) bool {
+	ens := EtcdNodes(cc)
+	return len(ens) > 0 && ens[0].Name == n.Name
+}
+
+// UsesHAProxy returns if the apiservers are served by a dedicated haproxy load balancer node rather than by kube-vip.
+func UsesHAProxy(cc ClusterConfig) bool {
+	return cc.KubernetesConfig.HALoadBalancer == HAProxy
+}
+
+// LoadBalancerNode returns the dedicated load balancer node or error, if none found.
+func LoadBalancerNode(cc ClusterConfig) (Node, error) {
+	for _, n := range cc.Nodes {
+		if n.LoadBalancer {
+			return n, nil
+		}
+	}
+	return Node{}, fmt.Errorf("no load balancer node found")
+}
+
 // IsPrimaryControlPlane returns if node is primary control-plane node.
 func IsPrimaryControlPlane(cc ClusterConfig, node Node) bool {
 	// TODO (prezha): find where, for "none" driver, we set first (ie, primary control-plane) node name to "m01" - that should not happen but it's happening before pr #17909
@@ -334,6 +398,57 @@ func ProfileFolderPath(profile string, miniHome ...string) string {
 	return filepath.Join(miniPath, "profiles", profile)
 }
 
+// GuestArch returns the architecture of the machines of the cluster, which is the one of the host unless the driver emulates another
+func GuestArch(cc ClusterConfig) string {
+	if cc.QemuArch != "" {
+		return cc.QemuArch
+	}
+	return runtime.GOARCH
+}
+
+// NodeDriver returns the driver of a node, the one of the cluster unless the node was added with another
+func NodeDriver(cc ClusterConfig, n Node) string {
+	if n.Driver != "" {
+		return n.Driver
+	}
+	return cc.Driver
+}
+
+// IsHybrid returns whether some nodes of the cluster run with another driver than the one of the cluster
+func IsHybrid(cc ClusterConfig) bool {
+	for _, n := range cc.Nodes {
+		if NodeDriver(cc, n) != cc.Driver {
+			return true
+		}
+	}
+	return false
+}
+
+// SSHHost returns the host of the node with the given ID with the ssh driver, or "" if none was given
+func SSHHost(cc ClusterConfig, id int) string {
+	if id == 1 && len(cc.SSHIPAddresses) == 0 {
+		return cc.SSHIPAddress
+	}
+	if id < 1 || id > len(cc.SSHIPAddresses) {
+		return ""
+	}
+	return cc.SSHIPAddresses[id-1]
+}
+
+// SetSSHHost sets the host of the node with the given ID with the ssh driver
+func SetSSHHost(cc *ClusterConfig, id int, host string) {
+	if len(cc.SSHIPAddresses) == 0 && cc.SSHIPAddress != "" {
+		cc.SSHIPAddresses = []string{cc.SSHIPAddress}
+	}
+	for len(cc.SSHIPAddresses) < id {
+		cc.SSHIPAddresses = append(cc.SSHIPAddresses, "")
+	}
+	cc.SSHIPAddresses[id-1] = host
+	if id == 1 {
+		cc.SSHIPAddress = host
+	}
+}
+
 // MachineName returns the name of the machine, as seen by the hypervisor given the cluster and node names
 func MachineName(cc ClusterConfig, n Node) string {
 	// For single node cluster, default to back to old naming
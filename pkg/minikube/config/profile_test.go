@@ -18,6 +18,7 @@ package config
 
 import (
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -309,3 +310,94 @@ func TestGetPrimaryControlPlane(t *testing.T) {
 		})
 	}
 }
+
+func TestEtcdNodes(t *testing.T) {
+	nodes := []Node{
+		{Name: "", ControlPlane: true, Worker: true},
+		{Name: "m02", Etcd: true},
+		{Name: "m03", LoadBalancer: true},
+		{Name: "m04", ControlPlane: true, Worker: true},
+		{Name: "m05", Worker: true},
+	}
+	names := func(nodes []Node) []string {
+		names := []string{}
+		for _, n := range nodes {
+			names = append(names, n.Name)
+		}
+		return names
+	}
+
+	var tests = []struct {
+		topology string
+		expected []string
+	}{
+		{"", []string{"", "m04"}},
+		{EtcdStacked, []string{"", "m04"}},
+		{EtcdExternal, []string{"m02"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.topology, func(t *testing.T) {
+			cc := ClusterConfig{Nodes: nodes, KubernetesConfig: KubernetesConfig{EtcdTopology: tc.topology}}
+			if got := names(EtcdNodes(cc)); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("EtcdNodes() = %q, expected %q", got, tc.expected)
+			}
+		})
+	}
+
+	if got, expected := names(KubernetesNodes(ClusterConfig{Nodes: nodes})), []string{"", "m04", "m05"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("KubernetesNodes() = %q, expected %q", got, expected)
+	}
+
+	lb, err := LoadBalancerNode(ClusterConfig{Nodes: nodes})
+	if err != nil || lb.Name != "m03" {
+		t.Errorf("LoadBalancerNode() = %+v, %v, expected m03", lb, err)
+	}
+	if _, err := LoadBalancerNode(ClusterConfig{Nodes: nodes[:1]}); err == nil {
+		t.Error("expected an error without a load balancer node")
+	}
+}
+
+func TestSSHHost(t *testing.T) {
+	// profiles from before the ssh driver took several hosts only have the one of the primary control-plane node
+	old := ClusterConfig{SSHIPAddress: "10.0.0.1"}
+	if got := SSHHost(old, 1); got != "10.0.0.1" {
+		t.Errorf("SSHHost(old, 1) = %q, expected 10.0.0.1", got)
+	}
+	if got := SSHHost(old, 2); got != "" {
+		t.Errorf("SSHHost(old, 2) = %q, expected none", got)
+	}
+
+	SetSSHHost(&old, 3, "10.0.0.3")
+	expected := []string{"10.0.0.1", "", "10.0.0.3"}
+	if !reflect.DeepEqual(old.SSHIPAddresses, expected) {
+		t.Errorf("SSHIPAddresses = %q, expected %q", old.SSHIPAddresses, expected)
+	}
+	if got := SSHHost(old, 3); got != "10.0.0.3" {
+		t.Errorf("SSHHost(cc, 3) = %q, expected 10.0.0.3", got)
+	}
+
+	SetSSHHost(&old, 1, "10.0.0.10")
+	if old.SSHIPAddress != "10.0.0.10" || SSHHost(old, 1) != "10.0.0.10" {
+		t.Errorf("SSHIPAddress = %q, expected 10.0.0.10", old.SSHIPAddress)
+	}
+}
+
+func TestNodeDriver(t *testing.T) {
+	cc := ClusterConfig{Driver: "docker", Network: "mk-net", Nodes: []Node{{Name: ""}, {Name: "m02"}}}
+	if IsHybrid(cc) {
+		t.Errorf("IsHybrid() = true for nodes all of the cluster driver")
+	}
+	cc.Nodes = append(cc.Nodes, Node{Name: "m03", Driver: "qemu2", Network: "socket_vmnet"})
+	if !IsHybrid(cc) {
+		t.Errorf("IsHybrid() = false with a qemu2 node in a docker cluster")
+	}
+	if got := NodeDriver(cc, cc.Nodes[1]); got != "docker" {
+		t.Errorf("NodeDriver(m02) = %q, expected docker", got)
+	}
+	if got := NodeResources(cc, cc.Nodes[2]); got.Driver != "qemu2" || got.Network != "socket_vmnet" {
+		t.Errorf("NodeResources(m03) = driver %q, network %q, expected qemu2, socket_vmnet", got.Driver, got.Network)
+	}
+	if got := NodeResources(cc, cc.Nodes[1]); got.Network != "mk-net" {
+		t.Errorf("NodeResources(m02).Network = %q, expected mk-net", got.Network)
+	}
+}
@@ -63,31 +63,38 @@ type ClusterConfig struct {
 	DisableDriverMounts     bool     // Only used by virtualbox
 	NFSShare                []string
 	NFSSharesRoot           string
-	UUID                    string // Only used by hyperkit to restore the mac address
-	NoVTXCheck              bool   // Only used by virtualbox
-	DNSProxy                bool   // Only used by virtualbox
-	HostDNSResolver         bool   // Only used by virtualbox
-	HostOnlyNicType         string // Only used by virtualbox
-	NatNicType              string // Only used by virtualbox
-	SSHIPAddress            string // Only used by ssh driver
-	SSHUser                 string // Only used by ssh driver
-	SSHKey                  string // Only used by ssh driver
-	SSHPort                 int    // Only used by ssh driver
+	UUID                    string   // Only used by hyperkit to restore the mac address
+	NoVTXCheck              bool     // Only used by virtualbox
+	DNSProxy                bool     // Only used by virtualbox
+	HostDNSResolver         bool     // Only used by virtualbox
+	HostOnlyNicType         string   // Only used by virtualbox
+	NatNicType              string   // Only used by virtualbox
+	SSHIPAddress            string   // Only used by ssh driver
+	SSHIPAddresses          []string // Only used by ssh driver, the hosts of the nodes by node ID, starting with SSHIPAddress
+	SSHUser                 string   // Only used by ssh driver
+	SSHKey                  string   // Only used by ssh driver
+	SSHPort                 int      // Only used by ssh driver
+	SSHPrepareHost          bool     // Only used by ssh driver
+	Bootstrapper            string   // bootstrapper the cluster was created with, kubeadm if empty
 	KubernetesConfig        KubernetesConfig
 	Nodes                   []Node
+	NodePools               []NodePool // groups of worker nodes added with 'minikube node add --pool'
 	Addons                  map[string]bool
 	CustomAddonImages       map[string]string // Maps image names to the image to use for addons. e.g. Dashboard -> registry.k8s.io/echoserver:1.4 makes dashboard addon use echoserver for its Dashboard deployment.
 	CustomAddonRegistries   map[string]string // Maps image names to the registry to use for addons. See CustomAddonImages for example.
 	VerifyComponents        map[string]bool   // map of components to verify and wait for after start.
+	WaitFor                 []string          // resource conditions to wait for after start, such as deployment/ns/name=Available
 	StartHostTimeout        time.Duration
 	ScheduledStop           *ScheduledStopConfig
-	ExposedPorts            []string // Only used by the docker and podman driver
+	ExposedPorts            []string // Only used by the docker and podman driver, and the qemu driver with the builtin network
 	ListenAddress           string   // Only used by the docker and podman driver
 	Network                 string   // only used by docker driver
 	Subnet                  string   // only used by the docker and podman driver
 	MultiNodeRequested      bool
-	ExtraDisks              int // currently only implemented for hyperkit and kvm2
+	ExtraDisks              int // currently only implemented for hyperkit, kvm2, qemu2, vfkit, docker and podman
 	CertExpiration          time.Duration
+	CustomCACert            string // CA cert which signs the certs of the cluster instead of the minikube CA shared by all profiles
+	CustomCAKey             string // key of CustomCACert
 	Mount                   bool
 	MountString             string
 	Mount9PVersion          string
@@ -102,6 +109,8 @@ type ClusterConfig struct {
 	DisableOptimizations    bool
 	DisableMetrics          bool
 	CustomQemuFirmwarePath  string
+	QemuAccel               string // Only used by the QEMU driver: the accelerator, or empty for the fastest the host has
+	QemuArch                string // Only used by the QEMU driver: the architecture of the guests, or empty for the one of the host
 	SocketVMnetClientPath   string
 	SocketVMnetPath         string
 	StaticIP                string
@@ -109,6 +118,8 @@ type ClusterConfig struct {
 	SSHAgentPID             int
 	GPUs                    string
 	AutoPauseInterval       time.Duration // Specifies interval of time to wait before checking if cluster should be paused
+	ImageGCThreshold        int           // Percentage of /var usage on a node above which dangling images are pruned, 0 disables pruning
+	ImageGCOlderThan        time.Duration // Only images older than this are pruned when ImageGCThreshold is exceeded
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -132,6 +143,12 @@ type KubernetesConfig struct {
 	CustomIngressCert   string // used by Ingress addon
 	RegistryAliases     string // currently only used by registry-aliases addon
 	ExtraOptions        ExtraOptionSlice
+	OIDC                OIDCConfig // authenticates users with an OpenID Connect provider
+	AuditPolicy         string     // audit policy preset, or path of an audit policy on the host
+	AdmissionPlugins    []string   // admission plugins enabled in addition to the default ones
+	AdmissionConfig     string     // path of an AdmissionConfiguration on the host
+	EtcdTopology        string     // stacked on the control-plane nodes, or external on dedicated etcd nodes
+	HALoadBalancer      string     // kube-vip on the control-plane nodes, or haproxy on a dedicated load balancer node
 
 	ShouldLoadCachedImages bool
 
@@ -139,6 +156,23 @@ type KubernetesConfig struct {
 	CNI              string // CNI to use
 }
 
+// OIDCConfig configures the apiserver to authenticate users with the ID tokens of an OpenID Connect provider
+type OIDCConfig struct {
+	IssuerURL      string
+	ClientID       string
+	ClientSecret   string // only passed to the credential plugin of the kubeconfig user
+	UsernameClaim  string
+	UsernamePrefix string
+	GroupsClaim    string
+	GroupsPrefix   string
+	CAFile         string // CA cert of the provider on the host
+}
+
+// Enabled returns whether OIDC authentication is configured
+func (o OIDCConfig) Enabled() bool {
+	return o.IssuerURL != ""
+}
+
 // Node contains information about specific nodes in a cluster
 type Node struct {
 	Name              string
@@ -148,6 +182,26 @@ type Node struct {
 	ContainerRuntime  string
 	ControlPlane      bool
 	Worker            bool
+	Etcd              bool     // runs a member of the external etcd, instead of joining the Kubernetes cluster
+	LoadBalancer      bool     // runs the haproxy load balancer in front of the control-plane nodes, instead of joining the Kubernetes cluster
+	Pool              string   // node pool the node belongs to, if any
+	CPUs              int      // the cluster-wide CPUs if zero
+	Memory            int      // the cluster-wide Memory if zero
+	DiskSize          int      // the cluster-wide DiskSize if zero
+	Driver            string   // the cluster-wide Driver if empty
+	Network           string   // network of the node when its Driver is not the cluster-wide one
+	Labels            []string // Kubernetes labels of the node, each formatted as key=value
+	Taints            []string // Kubernetes taints of the node, each formatted as key[=value]:effect
+}
+
+// NodePool is a group of worker nodes sharing the same resources, labels and taints
+type NodePool struct {
+	Name     string
+	CPUs     int      // the cluster-wide CPUs if zero
+	Memory   int      // the cluster-wide Memory if zero
+	DiskSize int      // the cluster-wide DiskSize if zero
+	Labels   []string // each formatted as key=value
+	Taints   []string // each formatted as key[=value]:effect
 }
 
 // VersionedExtraOption holds information on flags to apply to a specific range
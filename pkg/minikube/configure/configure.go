@@ -0,0 +1,366 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configure changes the Kubernetes settings of running clusters in place
+package configure
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"net"
+	"os/exec"
+	"slices"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+// Certs stands for the apiserver serving cert, whose SANs are the apiserver names and IPs
+const Certs = "certs"
+
+// kubeadmConfig is where the kubeadm config the components are regenerated from is written on the control-plane nodes,
+// which is the one minikube compares the running config against on the next start
+var kubeadmConfig = constants.KubeadmYamlPath + ".new"
+
+// componentOrder is the order the components are reconfigured in, the ones the others depend on first
+var componentOrder = []string{bsutil.Etcd, Certs, bsutil.Apiserver, bsutil.ControllerManager, bsutil.Scheduler, bsutil.Kubelet, bsutil.Kubeproxy}
+
+// featureGated are the components the feature gates are passed to
+var featureGated = []string{bsutil.Apiserver, bsutil.ControllerManager, bsutil.Scheduler, bsutil.Kubelet, bsutil.Kubeproxy}
+
+// controlPlaneComponents are the components which only run on the control-plane nodes
+var controlPlaneComponents = []string{bsutil.Etcd, Certs, bsutil.Apiserver, bsutil.ControllerManager, bsutil.Scheduler}
+
+// Affected returns the components affected by changing the Kubernetes settings of a cluster from old to new, in the order they are reconfigured in
+func Affected(old, new config.KubernetesConfig) ([]string, error) {
+	oldOpts, newOpts := old.ExtraOptions.AsMap(), new.ExtraOptions.AsMap()
+	if !maps.Equal(oldOpts.Get(bsutil.Kubeadm), newOpts.Get(bsutil.Kubeadm)) {
+		return nil, fmt.Errorf("the kubeadm options are only used to create the cluster, and cannot be changed in place")
+	}
+
+	affected := map[string]bool{}
+	for _, c := range componentOrder {
+		if !maps.Equal(oldOpts.Get(c), newOpts.Get(c)) {
+			affected[c] = true
+		}
+	}
+	if old.FeatureGates != new.FeatureGates {
+		for _, c := range featureGated {
+			affected[c] = true
+		}
+	}
+	if !slices.Equal(old.APIServerNames, new.APIServerNames) || !slices.EqualFunc(old.APIServerIPs, new.APIServerIPs, net.IP.Equal) {
+		affected[Certs] = true
+	}
+
+	components := []string{}
+	for _, c := range componentOrder {
+		if affected[c] {
+			components = append(components, c)
+		}
+	}
+	return components, nil
+}
+
+// File is a config file of a node, rendered for the old and the new settings
+type File struct {
+	Node string
+	Path string
+	Old  []byte
+	New  []byte
+}
+
+// Diff returns the unified diff of the file between the old and the new settings
+func (f File) Diff() (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(f.Old)),
+		B:        difflib.SplitLines(string(f.New)),
+		FromFile: fmt.Sprintf("%s:%s (current)", f.Node, f.Path),
+		ToFile:   fmt.Sprintf("%s:%s (new)", f.Node, f.Path),
+		Context:  3,
+	})
+}
+
+// Render renders the config files of a node which minikube generates from the Kubernetes settings, returning the ones the change affects
+func Render(old, new config.ClusterConfig, n config.Node, r cruntime.Manager) ([]File, error) {
+	node := config.MachineName(new, n)
+	files := []File{}
+	add := func(path string, o, n []byte) {
+		if !bytes.Equal(o, n) {
+			files = append(files, File{Node: node, Path: path, Old: o, New: n})
+		}
+	}
+
+	oldKubelet, err := bsutil.NewKubeletConfig(old, n, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating current kubelet config")
+	}
+	newKubelet, err := bsutil.NewKubeletConfig(new, n, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating kubelet config")
+	}
+	add(bsutil.KubeletSystemdConfFile, oldKubelet, newKubelet)
+
+	if n.ControlPlane {
+		oldKubeadm, err := bsutil.GenerateKubeadmYAML(old, n, r)
+		if err != nil {
+			return nil, errors.Wrap(err, "generating current kubeadm cfg")
+		}
+		newKubeadm, err := bsutil.GenerateKubeadmYAML(new, n, r)
+		if err != nil {
+			return nil, errors.Wrap(err, "generating kubeadm cfg")
+		}
+		add(constants.KubeadmYamlPath, oldKubeadm, newKubeadm)
+	}
+	return files, nil
+}
+
+// configurer reconfigures the nodes of a cluster one at a time
+type configurer struct {
+	old        config.ClusterConfig
+	new        config.ClusterConfig
+	components []string
+	// runners are the command runners of the nodes, by machine name
+	runners map[string]command.Runner
+}
+
+// Configure changes the Kubernetes settings of a running cluster to k8s in place, the control-plane nodes first, then the workers.
+// Only the config files and the components affected by the change are regenerated and restarted, then the profile is saved.
+func Configure(api libmachine.API, cc *config.ClusterConfig, k8s config.KubernetesConfig) error {
+	components, err := Affected(cc.KubernetesConfig, k8s)
+	if err != nil {
+		return err
+	}
+	c := &configurer{
+		old:        *cc,
+		new:        *cc,
+		components: components,
+		runners:    map[string]command.Runner{},
+	}
+	c.new.KubernetesConfig = k8s
+
+	for _, n := range cc.Nodes {
+		m := config.MachineName(*cc, n)
+		h, err := machine.LoadHost(api, m)
+		if err != nil {
+			return errors.Wrapf(err, "load host %s", m)
+		}
+		r, err := machine.CommandRunner(h)
+		if err != nil {
+			return errors.Wrapf(err, "command runner %s", m)
+		}
+		c.runners[m] = r
+	}
+
+	nodes := config.ControlPlanes(*cc)
+	for _, n := range config.KubernetesNodes(*cc) {
+		if !n.ControlPlane {
+			nodes = append(nodes, n)
+		}
+	}
+	for _, n := range nodes {
+		if err := c.configureNode(n); err != nil {
+			return errors.Wrapf(err, "configure node %s", config.MachineName(*cc, n))
+		}
+	}
+	if slices.Contains(components, bsutil.Kubeproxy) {
+		if err := c.configureKubeProxy(); err != nil {
+			return errors.Wrap(err, "configure kube-proxy")
+		}
+	}
+
+	cc.KubernetesConfig = k8s
+	return config.SaveProfile(cc.Name, cc)
+}
+
+// configureNode writes the new config files of a node and restarts its affected components
+func (c *configurer) configureNode(n config.Node) error {
+	m := config.MachineName(c.new, n)
+	r := c.runners[m]
+	todo := []string{}
+	for _, comp := range c.components {
+		if n.ControlPlane || !slices.Contains(controlPlaneComponents, comp) {
+			todo = append(todo, comp)
+		}
+	}
+	// kube-proxy is configured cluster-wide, by its DaemonSet
+	todo = slices.DeleteFunc(todo, func(comp string) bool { return comp == bsutil.Kubeproxy })
+	// an external etcd runs on its own nodes, which are not reconfigured
+	if config.IsExternalEtcd(c.new) {
+		todo = slices.DeleteFunc(todo, func(comp string) bool { return comp == bsutil.Etcd })
+	}
+	if len(todo) == 0 {
+		return nil
+	}
+	out.Step(style.Option, "Reconfiguring {{.components}} on {{.name}} ...", out.V{"components": todo, "name": m})
+
+	cr, err := c.runtime(r)
+	if err != nil {
+		return err
+	}
+	files, err := Render(c.old, c.new, n, cr)
+	if err != nil {
+		return err
+	}
+	copies := []assets.CopyableFile{}
+	for _, f := range files {
+		dst := f.Path
+		if dst == constants.KubeadmYamlPath {
+			dst = kubeadmConfig
+		}
+		copies = append(copies, assets.NewMemoryAssetTarget(f.New, dst, "0640"))
+	}
+	if n.ControlPlane && len(files) == 0 {
+		// the components are regenerated from the kubeadm config even if only the certs changed
+		kcfg, err := bsutil.GenerateKubeadmYAML(c.new, n, cr)
+		if err != nil {
+			return errors.Wrap(err, "generating kubeadm cfg")
+		}
+		copies = append(copies, assets.NewMemoryAssetTarget(kcfg, kubeadmConfig, "0640"))
+	}
+	if err := bsutil.CopyFiles(r, copies); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+
+	kubeadm := bsutil.InvokeKubeadm(c.new.KubernetesConfig.KubernetesVersion)
+	for _, comp := range todo {
+		klog.Infof("reconfiguring %s on %s", comp, m)
+		switch comp {
+		case bsutil.Etcd:
+			err = runKubeadm(r, fmt.Sprintf("%s init phase etcd local --config %s", kubeadm, kubeadmConfig))
+		case bsutil.Apiserver, bsutil.ControllerManager, bsutil.Scheduler:
+			// kubelet restarts the static pod once its manifest changed
+			err = runKubeadm(r, fmt.Sprintf("%s init phase control-plane %s --config %s", kubeadm, comp, kubeadmConfig))
+		case Certs:
+			err = c.configureCerts(n, r, cr)
+		case bsutil.Kubelet:
+			err = sysinit.New(r).Restart("kubelet")
+		}
+		if err != nil {
+			return errors.Wrapf(err, "reconfigure %s", comp)
+		}
+	}
+
+	if config.IsPrimaryControlPlane(c.new, n) {
+		// so that the next start does not see drift
+		if _, err := r.RunCmd(exec.Command("sudo", "cp", kubeadmConfig, constants.KubeadmYamlPath)); err != nil {
+			return errors.Wrap(err, "cp")
+		}
+	}
+	return nil
+}
+
+// configureCerts issues a new apiserver serving cert to a control-plane node, and restarts the apiserver to serve it
+func (c *configurer) configureCerts(n config.Node, r command.Runner, cr cruntime.Manager) error {
+	pcp, err := config.ControlPlane(c.new)
+	if err != nil {
+		return errors.Wrap(err, "get primary control-plane node")
+	}
+	// the apiserver cert is kept under a hash of its SANs, so a new one is issued for the new names and IPs
+	if err := bootstrapper.SetupCerts(c.new, n, c.runners[config.MachineName(c.new, pcp)], r); err != nil {
+		return errors.Wrap(err, "setup certs")
+	}
+	if slices.Contains(c.components, bsutil.Apiserver) {
+		// restarted with its new manifest anyway
+		return nil
+	}
+	ids, err := cr.ListContainers(cruntime.ListContainersOptions{State: cruntime.Running, Name: "kube-apiserver"})
+	if err != nil {
+		return errors.Wrap(err, "list apiserver containers")
+	}
+	// kubelet starts the apiserver again
+	return cr.StopContainers(ids)
+}
+
+// configureKubeProxy updates the config of kube-proxy, and restarts its pods to use it
+func (c *configurer) configureKubeProxy() error {
+	if c.new.KubernetesConfig.ExtraOptions.Exists("kubeadm.skip-phases=addon/kube-proxy") {
+		klog.Infof("kube-proxy is not deployed by minikube, skipping")
+		return nil
+	}
+	pcp, err := config.ControlPlane(c.new)
+	if err != nil {
+		return errors.Wrap(err, "get primary control-plane node")
+	}
+	m := config.MachineName(c.new, pcp)
+	out.Step(style.Option, "Reconfiguring {{.components}} on {{.name}} ...", out.V{"components": []string{bsutil.Kubeproxy}, "name": c.new.Name})
+	r := c.runners[m]
+	cr, err := c.runtime(r)
+	if err != nil {
+		return err
+	}
+	// the kube-proxy config is part of the kubeadm config, which no other component may have written yet
+	kcfg, err := bsutil.GenerateKubeadmYAML(c.new, pcp, cr)
+	if err != nil {
+		return errors.Wrap(err, "generating kubeadm cfg")
+	}
+	if err := bsutil.CopyFiles(r, []assets.CopyableFile{assets.NewMemoryAssetTarget(kcfg, kubeadmConfig, "0640")}); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+	kubeadm := bsutil.InvokeKubeadm(c.new.KubernetesConfig.KubernetesVersion)
+	if err := runKubeadm(r, fmt.Sprintf("%s init phase addon kube-proxy --config %s", kubeadm, kubeadmConfig)); err != nil {
+		return err
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "cp", kubeadmConfig, constants.KubeadmYamlPath)); err != nil {
+		return errors.Wrap(err, "cp")
+	}
+	kubectl := kapi.KubectlBinaryPath(c.new.KubernetesConfig.KubernetesVersion)
+	restart := func() error {
+		_, err := r.RunCmd(exec.Command("sudo", "KUBECONFIG=/var/lib/minikube/kubeconfig", kubectl, "-n", "kube-system", "rollout", "restart", "daemonset", "kube-proxy"))
+		return err
+	}
+	// the apiserver may be restarting
+	return retry.Expo(restart, time.Second, 2*time.Minute)
+}
+
+// runtime returns the container runtime of a node
+func (c *configurer) runtime(r command.Runner) (cruntime.Manager, error) {
+	v, err := util.ParseKubernetesVersion(c.new.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing Kubernetes version")
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: c.new.KubernetesConfig.ContainerRuntime, Runner: r, Socket: c.new.KubernetesConfig.CRISocket, KubernetesVersion: v})
+	if err != nil {
+		return nil, errors.Wrap(err, "runtime")
+	}
+	return cr, nil
+}
+
+// runKubeadm runs a kubeadm command on a node
+func runKubeadm(r command.Runner, c string) error {
+	if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", c)); err != nil {
+		return errors.Wrap(err, "kubeadm")
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configure
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestAffected(t *testing.T) {
+	base := config.KubernetesConfig{
+		ExtraOptions: config.ExtraOptionSlice{
+			{Component: "apiserver", Key: "v", Value: "2"},
+			{Component: "kubeadm", Key: "pod-network-cidr", Value: "10.244.0.0/16"},
+		},
+		APIServerNames: []string{"foo"},
+	}
+	tests := []struct {
+		name      string
+		change    func(k *config.KubernetesConfig)
+		want      []string
+		shouldErr bool
+	}{
+		{"nothing", func(_ *config.KubernetesConfig) {}, []string{}, false},
+		{"apiserver option", func(k *config.KubernetesConfig) {
+			k.ExtraOptions = config.ExtraOptionSlice{{Component: "apiserver", Key: "v", Value: "5"}, k.ExtraOptions[1]}
+		}, []string{"apiserver"}, false},
+		{"kubelet and etcd options", func(k *config.KubernetesConfig) {
+			k.ExtraOptions = append(k.ExtraOptions, config.ExtraOption{Component: "kubelet", Key: "max-pods", Value: "50"}, config.ExtraOption{Component: "etcd", Key: "quota-backend-bytes", Value: "8589934592"})
+		}, []string{"etcd", "kubelet"}, false},
+		{"feature gates", func(k *config.KubernetesConfig) {
+			k.FeatureGates = "InPlacePodVerticalScaling=true"
+		}, []string{"apiserver", "controller-manager", "scheduler", "kubelet", "kube-proxy"}, false},
+		{"apiserver names", func(k *config.KubernetesConfig) {
+			k.APIServerNames = []string{"foo", "bar"}
+		}, []string{"certs"}, false},
+		{"apiserver ips", func(k *config.KubernetesConfig) {
+			k.APIServerIPs = []net.IP{net.ParseIP("10.0.0.10")}
+		}, []string{"certs"}, false},
+		{"kubeadm option", func(k *config.KubernetesConfig) {
+			k.ExtraOptions = config.ExtraOptionSlice{k.ExtraOptions[0]}
+		}, nil, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k := base
+			k.ExtraOptions = append(config.ExtraOptionSlice{}, base.ExtraOptions...)
+			tc.change(&k)
+			got, err := Affected(base, k)
+			if tc.shouldErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("affected components mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFileDiff(t *testing.T) {
+	f := File{Node: "minikube", Path: "/var/tmp/minikube/kubeadm.yaml", Old: []byte("a: 1\nb: 2\n"), New: []byte("a: 1\nb: 3\n")}
+	diff, err := f.Diff()
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	for _, want := range []string{"--- minikube:/var/tmp/minikube/kubeadm.yaml (current)", "+++ minikube:/var/tmp/minikube/kubeadm.yaml (new)", "-b: 2", "+b: 3"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected the diff to contain %q, got:\n%s", want, diff)
+		}
+	}
+}
@@ -310,6 +310,21 @@ func (r *Containerd) RemoveImage(name string) error {
 	return removeCRIImage(r.Runner, name)
 }
 
+// InspectImage returns detailed information about an image
+func (r *Containerd) InspectImage(name string) (*ImageInspect, error) {
+	return inspectCRIImage(r.Runner, name)
+}
+
+// ImageHistory returns the layer history of an image
+func (r *Containerd) ImageHistory(name string) ([]ImageHistoryEntry, error) {
+	return criImageHistory(r.Runner, name)
+}
+
+// PruneImages removes images not used by any container
+func (r *Containerd) PruneImages(o PruneImagesOptions) ([]string, error) {
+	return pruneCRIImages(r.Runner, o)
+}
+
 // TagImage tags an image in this runtime
 func (r *Containerd) TagImage(source string, target string) error {
 	klog.Infof("Tagging image %s: %s", source, target)
@@ -518,7 +533,7 @@ func (r *Containerd) SystemLogCmd(len int) string {
 
 // Preload preloads the container runtime with k8s images
 func (r *Containerd) Preload(cc config.ClusterConfig) error {
-	if !download.PreloadExists(cc.KubernetesConfig.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime, cc.Driver) {
+	if !preloadExists(cc) {
 		return nil
 	}
 
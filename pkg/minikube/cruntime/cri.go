@@ -25,6 +25,7 @@ import (
 	"os/exec"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
@@ -49,6 +50,45 @@ type crictlImages struct {
 	} `json:"images"`
 }
 
+// ociImageConfig maps to the config section of an OCI image spec
+type ociImageConfig struct {
+	User         string              `json:"User"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+	Env          []string            `json:"Env"`
+	Entrypoint   []string            `json:"Entrypoint"`
+	Cmd          []string            `json:"Cmd"`
+	WorkingDir   string              `json:"WorkingDir"`
+	Labels       map[string]string   `json:"Labels"`
+}
+
+// crictlInspectImage maps to 'crictl inspecti -o json'
+type crictlInspectImage struct {
+	Status struct {
+		ID          string   `json:"id"`
+		RepoTags    []string `json:"repoTags"`
+		RepoDigests []string `json:"repoDigests"`
+		Size        string   `json:"size"`
+	} `json:"status"`
+	Info struct {
+		ImageSpec struct {
+			Created      string         `json:"created"`
+			Architecture string         `json:"architecture"`
+			OS           string         `json:"os"`
+			Variant      string         `json:"variant"`
+			Config       ociImageConfig `json:"config"`
+			RootFS       struct {
+				DiffIDs []string `json:"diff_ids"`
+			} `json:"rootfs"`
+			History []struct {
+				Created    string `json:"created"`
+				CreatedBy  string `json:"created_by"`
+				Comment    string `json:"comment"`
+				EmptyLayer bool   `json:"empty_layer"`
+			} `json:"history"`
+		} `json:"imageSpec"`
+	} `json:"info"`
+}
+
 // crictlList returns the output of 'crictl ps' in an efficient manner
 func crictlList(cr CommandRunner, root string, o ListContainersOptions) (*command.RunResult, error) {
 	klog.Infof("listing CRI containers in root %s: %+v", root, o)
@@ -328,6 +368,195 @@ func listCRIImages(cr CommandRunner) ([]ListImage, error) {
 	return images, nil
 }
 
+// crictlInspectImageJSON returns the output of 'crictl inspecti' for an image
+func crictlInspectImageJSON(cr CommandRunner, name string) (*crictlInspectImage, error) {
+	crictl := getCrictlPath(cr)
+	rr, err := cr.RunCmd(exec.Command("sudo", crictl, "inspecti", "-o", "json", name))
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl inspecti")
+	}
+	var ii crictlInspectImage
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &ii); err != nil {
+		return nil, errors.Wrap(err, "unmarshal crictl inspecti")
+	}
+	return &ii, nil
+}
+
+// inspectCRIImage inspects an image using crictl
+func inspectCRIImage(cr CommandRunner, name string) (*ImageInspect, error) {
+	ii, err := crictlInspectImageJSON(cr, name)
+	if err != nil {
+		return nil, err
+	}
+	return criImageInspect(ii), nil
+}
+
+// criImageInspect converts the crictl representation of an image into an ImageInspect
+func criImageInspect(ii *crictlInspectImage) *ImageInspect {
+	spec := ii.Info.ImageSpec
+	return &ImageInspect{
+		ID:          ii.Status.ID,
+		RepoTags:    ii.Status.RepoTags,
+		RepoDigests: ii.Status.RepoDigests,
+		Size:        ii.Status.Size,
+		Created:     spec.Created,
+		Platform:    imagePlatform(spec.OS, spec.Architecture, spec.Variant),
+		Labels:      spec.Config.Labels,
+		Layers:      spec.RootFS.DiffIDs,
+		Config: ImageConfig{
+			User:         spec.Config.User,
+			Env:          spec.Config.Env,
+			Entrypoint:   spec.Config.Entrypoint,
+			Cmd:          spec.Config.Cmd,
+			WorkingDir:   spec.Config.WorkingDir,
+			ExposedPorts: sortedKeys(spec.Config.ExposedPorts),
+		},
+	}
+}
+
+// criImageHistory returns the history of an image using crictl
+func criImageHistory(cr CommandRunner, name string) ([]ImageHistoryEntry, error) {
+	ii, err := crictlInspectImageJSON(cr, name)
+	if err != nil {
+		return nil, err
+	}
+	history := []ImageHistoryEntry{}
+	// the OCI spec lists history oldest first, but docker (and users) expect newest first
+	for i := len(ii.Info.ImageSpec.History) - 1; i >= 0; i-- {
+		h := ii.Info.ImageSpec.History[i]
+		history = append(history, ImageHistoryEntry{
+			Created:    h.Created,
+			CreatedBy:  h.CreatedBy,
+			Comment:    h.Comment,
+			EmptyLayer: h.EmptyLayer,
+		})
+	}
+	return history, nil
+}
+
+// pruneCRIImages removes images which are not used by any container using crictl
+func pruneCRIImages(cr CommandRunner, o PruneImagesOptions) ([]string, error) {
+	images, err := listCRIImages(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := criImagesInUse(cr)
+	if err != nil {
+		return nil, err
+	}
+	keep := criImageIDs(cr, images, append(refs, o.Keep...))
+
+	crictl := getCrictlPath(cr)
+	removed := []string{}
+	for _, img := range images {
+		if keep[img.ID] || (!o.All && len(img.RepoTags) > 0) {
+			continue
+		}
+		if o.OlderThan > 0 {
+			ii, err := crictlInspectImageJSON(cr, img.ID)
+			if err != nil {
+				klog.Warningf("unable to inspect %s, skipping: %v", img.ID, err)
+				continue
+			}
+			created, err := time.Parse(time.RFC3339Nano, ii.Info.ImageSpec.Created)
+			if err != nil || time.Since(created) < o.OlderThan {
+				continue
+			}
+		}
+		if _, err := cr.RunCmd(exec.Command("sudo", crictl, "rmi", img.ID)); err != nil {
+			// the image may have been picked up by a new container in the meantime
+			klog.Warningf("unable to remove %s: %v", img.ID, err)
+			continue
+		}
+		removed = append(removed, img.ID)
+	}
+	return removed, nil
+}
+
+// criImagesInUse returns references to the images of all containers, and of the sandboxes of all pods
+func criImagesInUse(cr CommandRunner) ([]string, error) {
+	crictl := getCrictlPath(cr)
+	rr, err := cr.RunCmd(exec.Command("sudo", crictl, "ps", "-a", "-o", "json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl ps")
+	}
+	var ps struct {
+		Containers []struct {
+			ImageRef string `json:"imageRef"`
+			Image    struct {
+				Image string `json:"image"`
+			} `json:"image"`
+		} `json:"containers"`
+	}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &ps); err != nil {
+		return nil, errors.Wrap(err, "unmarshal crictl ps")
+	}
+	refs := []string{}
+	for _, c := range ps.Containers {
+		refs = append(refs, c.ImageRef, c.Image.Image)
+	}
+
+	rr, err = cr.RunCmd(exec.Command("sudo", crictl, "pods", "-q"))
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl pods")
+	}
+	pods := strings.Fields(rr.Stdout.String())
+	if len(pods) == 0 {
+		return refs, nil
+	}
+	rr, err = cr.RunCmd(exec.Command("sudo", append([]string{crictl, "inspectp", "-o", "json"}, pods...)...))
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl inspectp")
+	}
+	// crictl prints one document per pod
+	d := json.NewDecoder(&rr.Stdout)
+	for d.More() {
+		var sandbox struct {
+			Info struct {
+				Image string `json:"image"`
+			} `json:"info"`
+		}
+		if err := d.Decode(&sandbox); err != nil {
+			return nil, errors.Wrap(err, "unmarshal crictl inspectp")
+		}
+		refs = append(refs, sandbox.Info.Image)
+	}
+	return refs, nil
+}
+
+// criImageIDs returns the IDs of the images named by refs, which may be IDs, tags or digests
+func criImageIDs(cr CommandRunner, images []ListImage, refs []string) map[string]bool {
+	byRef := map[string]string{}
+	for _, img := range images {
+		byRef[img.ID] = img.ID
+		for _, t := range img.RepoTags {
+			byRef[t] = img.ID
+		}
+		for _, d := range img.RepoDigests {
+			byRef[d] = img.ID
+		}
+	}
+	ids := map[string]bool{}
+	for _, ref := range refs {
+		if ref == "" {
+			continue
+		}
+		if id, ok := byRef[ref]; ok {
+			ids[id] = true
+			continue
+		}
+		// short names, and references in a form crictl images does not list, are left to the runtime to resolve
+		ii, err := crictlInspectImageJSON(cr, ref)
+		if err != nil {
+			klog.Infof("unable to resolve image %s: %v", ref, err)
+			continue
+		}
+		ids[ii.Status.ID] = true
+	}
+	return ids
+}
+
 // criContainerLogCmd returns the command to retrieve the log for a container based on ID
 func criContainerLogCmd(cr CommandRunner, id string, len int, follow bool) string {
 	crictl := getCrictlPath(cr)
@@ -300,6 +300,21 @@ func (r *CRIO) RemoveImage(name string) error {
 	return removeCRIImage(r.Runner, name)
 }
 
+// InspectImage returns detailed information about an image
+func (r *CRIO) InspectImage(name string) (*ImageInspect, error) {
+	return inspectCRIImage(r.Runner, name)
+}
+
+// ImageHistory returns the layer history of an image
+func (r *CRIO) ImageHistory(name string) ([]ImageHistoryEntry, error) {
+	return criImageHistory(r.Runner, name)
+}
+
+// PruneImages removes images not used by any container
+func (r *CRIO) PruneImages(o PruneImagesOptions) ([]string, error) {
+	return pruneCRIImages(r.Runner, o)
+}
+
 // TagImage tags an image in this runtime
 func (r *CRIO) TagImage(source string, target string) error {
 	klog.Infof("Tagging image %s: %s", source, target)
@@ -417,7 +432,7 @@ func (r *CRIO) SystemLogCmd(len int) string {
 
 // Preload preloads the container runtime with k8s images
 func (r *CRIO) Preload(cc config.ClusterConfig) error {
-	if !download.PreloadExists(cc.KubernetesConfig.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime, cc.Driver) {
+	if !preloadExists(cc) {
 		return nil
 	}
 
@@ -20,7 +20,10 @@ package cruntime
 import (
 	"fmt"
 	"os/exec"
+	"runtime"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
@@ -28,6 +31,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/sysinit"
 )
@@ -118,6 +122,12 @@ type Manager interface {
 
 	// RemoveImage remove image based on name
 	RemoveImage(string) error
+	// InspectImage returns detailed information about an image
+	InspectImage(string) (*ImageInspect, error)
+	// ImageHistory returns the layer history of an image
+	ImageHistory(string) ([]ImageHistoryEntry, error)
+	// PruneImages removes images not used by any container, returning the IDs removed
+	PruneImages(PruneImagesOptions) ([]string, error)
 
 	// ListContainers returns a list of containers managed by this container runtime
 	ListContainers(ListContainersOptions) ([]string, error)
@@ -173,6 +183,17 @@ type ListContainersOptions struct {
 type ListImagesOptions struct {
 }
 
+// PruneImagesOptions are the options to use for pruning images
+type PruneImagesOptions struct {
+	// All removes every image not used by a container, not only dangling (untagged) ones
+	All bool
+	// OlderThan only removes images created more than this long ago
+	OlderThan time.Duration
+	// Keep are references to images never to remove, such as the pause image. Docker, which prunes with
+	// 'docker image prune', only keeps the images of its containers
+	Keep []string
+}
+
 type ListImage struct {
 	ID          string   `json:"id" yaml:"id"`
 	RepoDigests []string `json:"repoDigests" yaml:"repoDigests"`
@@ -180,6 +201,60 @@ type ListImage struct {
 	Size        string   `json:"size" yaml:"size"`
 }
 
+// ImageInspect is the detailed information about an image reported by a container runtime
+type ImageInspect struct {
+	ID          string            `json:"id" yaml:"id"`
+	RepoTags    []string          `json:"repoTags" yaml:"repoTags"`
+	RepoDigests []string          `json:"repoDigests" yaml:"repoDigests"`
+	Size        string            `json:"size" yaml:"size"`
+	Created     string            `json:"created" yaml:"created"`
+	Platform    string            `json:"platform" yaml:"platform"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Layers      []string          `json:"layers" yaml:"layers"`
+	Config      ImageConfig       `json:"config" yaml:"config"`
+}
+
+// ImageConfig is the runtime configuration baked into an image
+type ImageConfig struct {
+	User         string   `json:"user,omitempty" yaml:"user,omitempty"`
+	Env          []string `json:"env,omitempty" yaml:"env,omitempty"`
+	Entrypoint   []string `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
+	Cmd          []string `json:"cmd,omitempty" yaml:"cmd,omitempty"`
+	WorkingDir   string   `json:"workingDir,omitempty" yaml:"workingDir,omitempty"`
+	ExposedPorts []string `json:"exposedPorts,omitempty" yaml:"exposedPorts,omitempty"`
+}
+
+// ImageHistoryEntry is a single layer in the history of an image
+type ImageHistoryEntry struct {
+	ID         string `json:"id,omitempty" yaml:"id,omitempty"`
+	Created    string `json:"created" yaml:"created"`
+	CreatedBy  string `json:"createdBy" yaml:"createdBy"`
+	Size       string `json:"size,omitempty" yaml:"size,omitempty"`
+	Comment    string `json:"comment,omitempty" yaml:"comment,omitempty"`
+	EmptyLayer bool   `json:"emptyLayer,omitempty" yaml:"emptyLayer,omitempty"`
+}
+
+// imagePlatform formats os, architecture and variant the way registries do, eg. linux/arm64/v8
+func imagePlatform(os, arch, variant string) string {
+	p := []string{}
+	for _, s := range []string{os, arch, variant} {
+		if s != "" {
+			p = append(p, s)
+		}
+	}
+	return strings.Join(p, "/")
+}
+
+// sortedKeys returns the keys of an exposed ports style set in order
+func sortedKeys(m map[string]struct{}) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // ErrContainerRuntimeNotRunning is thrown when container runtime is not running
 var ErrContainerRuntimeNotRunning = errors.New("container runtime is not running")
 
@@ -259,6 +334,15 @@ func ContainerStatusCommand() string {
 	return "sudo `which crictl || echo crictl` ps -a || sudo docker ps -a"
 }
 
+// preloadExists returns whether there is a preload for the cluster, which is never the case for guests
+// of another architecture than the host as the preload is of the one of the host
+func preloadExists(cc config.ClusterConfig) bool {
+	if config.GuestArch(cc) != runtime.GOARCH {
+		return false
+	}
+	return download.PreloadExists(cc.KubernetesConfig.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime, cc.Driver)
+}
+
 // disableOthers disables all other runtimes except for me.
 func disableOthers(me Manager, cr CommandRunner) error {
 	// valid values returned by manager.Name()
@@ -18,6 +18,7 @@ package cruntime
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -808,3 +809,61 @@ func TestContainerFunctions(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDockerImageInspect(t *testing.T) {
+	b := []byte(`{"Id":"sha256:abc","RepoTags":["busybox:latest"],"RepoDigests":["busybox@sha256:def"],"Created":"2024-01-01T00:00:00Z","Architecture":"arm64","Variant":"v8","Os":"linux","Size":4261550,"Config":{"Env":["PATH=/bin"],"Cmd":["sh"],"Labels":{"a":"b"},"ExposedPorts":{"8080/tcp":{},"53/udp":{}}},"RootFS":{"Type":"layers","Layers":["sha256:l1"]}}`)
+	got, err := parseDockerImageInspect(b)
+	if err != nil {
+		t.Fatalf("parseDockerImageInspect: %v", err)
+	}
+	want := &ImageInspect{
+		ID:          "abc",
+		RepoTags:    []string{"docker.io/library/busybox:latest"},
+		RepoDigests: []string{"busybox@sha256:def"},
+		Size:        "4261550",
+		Created:     "2024-01-01T00:00:00Z",
+		Platform:    "linux/arm64/v8",
+		Labels:      map[string]string{"a": "b"},
+		Layers:      []string{"sha256:l1"},
+		Config: ImageConfig{
+			Env:          []string{"PATH=/bin"},
+			Cmd:          []string{"sh"},
+			ExposedPorts: []string{"53/udp", "8080/tcp"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseDockerImageInspect() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCRIImageInspect(t *testing.T) {
+	var ii crictlInspectImage
+	b := []byte(`{"status":{"id":"sha256:abc","repoTags":["docker.io/library/busybox:latest"],"repoDigests":[],"size":"4261550"},"info":{"imageSpec":{"created":"2024-01-01T00:00:00Z","architecture":"amd64","os":"linux","config":{"Cmd":["sh"]},"rootfs":{"type":"layers","diff_ids":["sha256:l1"]},"history":[{"created_by":"ADD file"},{"created_by":"CMD [\"sh\"]","empty_layer":true}]}}}`)
+	if err := json.Unmarshal(b, &ii); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	got := criImageInspect(&ii)
+	if got.Platform != "linux/amd64" {
+		t.Errorf("Platform = %q, want linux/amd64", got.Platform)
+	}
+	if diff := cmp.Diff([]string{"sha256:l1"}, got.Layers); diff != "" {
+		t.Errorf("Layers mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"sh"}, got.Config.Cmd); diff != "" {
+		t.Errorf("Cmd mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseDockerPrune(t *testing.T) {
+	output := `Deleted Images:
+untagged: app:dev
+deleted: sha256:abc
+deleted: sha256:def
+
+Total reclaimed space: 12MB
+`
+	got := parseDockerPrune(output)
+	if diff := cmp.Diff([]string{"abc", "def"}, got); diff != "" {
+		t.Errorf("parseDockerPrune() mismatch (-want +got):\n%s", diff)
+	}
+}
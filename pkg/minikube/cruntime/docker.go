@@ -345,6 +345,126 @@ func (r *Docker) RemoveImage(name string) error {
 	return nil
 }
 
+// InspectImage returns detailed information about an image
+func (r *Docker) InspectImage(name string) (*ImageInspect, error) {
+	c := exec.Command("docker", "image", "inspect", "--format", "{{json .}}", name)
+	rr, err := r.Runner.RunCmd(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "docker image inspect")
+	}
+	return parseDockerImageInspect(rr.Stdout.Bytes())
+}
+
+// parseDockerImageInspect converts the output of 'docker image inspect' into an ImageInspect
+func parseDockerImageInspect(b []byte) (*ImageInspect, error) {
+	var di struct {
+		ID           string         `json:"Id"`
+		RepoTags     []string       `json:"RepoTags"`
+		RepoDigests  []string       `json:"RepoDigests"`
+		Created      string         `json:"Created"`
+		Architecture string         `json:"Architecture"`
+		Variant      string         `json:"Variant"`
+		OS           string         `json:"Os"`
+		Size         int64          `json:"Size"`
+		Config       ociImageConfig `json:"Config"`
+		RootFS       struct {
+			Layers []string `json:"Layers"`
+		} `json:"RootFS"`
+	}
+	if err := json.Unmarshal(b, &di); err != nil {
+		return nil, errors.Wrap(err, "unmarshal docker image inspect")
+	}
+	tags := []string{}
+	for _, t := range di.RepoTags {
+		tags = append(tags, AddDockerIO(t))
+	}
+	return &ImageInspect{
+		ID:          strings.TrimPrefix(di.ID, "sha256:"),
+		RepoTags:    tags,
+		RepoDigests: di.RepoDigests,
+		Size:        fmt.Sprintf("%d", di.Size),
+		Created:     di.Created,
+		Platform:    imagePlatform(di.OS, di.Architecture, di.Variant),
+		Labels:      di.Config.Labels,
+		Layers:      di.RootFS.Layers,
+		Config: ImageConfig{
+			User:         di.Config.User,
+			Env:          di.Config.Env,
+			Entrypoint:   di.Config.Entrypoint,
+			Cmd:          di.Config.Cmd,
+			WorkingDir:   di.Config.WorkingDir,
+			ExposedPorts: sortedKeys(di.Config.ExposedPorts),
+		},
+	}, nil
+}
+
+// ImageHistory returns the layer history of an image
+func (r *Docker) ImageHistory(name string) ([]ImageHistoryEntry, error) {
+	c := exec.Command("docker", "history", "--no-trunc", "--human=false", "--format", "{{json .}}", name)
+	rr, err := r.Runner.RunCmd(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "docker history")
+	}
+	type dockerHistory struct {
+		ID        string `json:"ID"`
+		CreatedAt string `json:"CreatedAt"`
+		CreatedBy string `json:"CreatedBy"`
+		Size      string `json:"Size"`
+		Comment   string `json:"Comment"`
+	}
+	history := []ImageHistoryEntry{}
+	for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+		if line == "" {
+			continue
+		}
+		var h dockerHistory
+		if err := json.Unmarshal([]byte(line), &h); err != nil {
+			return nil, errors.Wrap(err, "history convert problem")
+		}
+		id := strings.TrimPrefix(h.ID, "sha256:")
+		if id == "<missing>" {
+			id = ""
+		}
+		history = append(history, ImageHistoryEntry{
+			ID:         id,
+			Created:    h.CreatedAt,
+			CreatedBy:  h.CreatedBy,
+			Size:       h.Size,
+			Comment:    h.Comment,
+			EmptyLayer: h.Size == "0",
+		})
+	}
+	return history, nil
+}
+
+// PruneImages removes images not used by any container
+func (r *Docker) PruneImages(o PruneImagesOptions) ([]string, error) {
+	klog.Infof("Pruning images: %+v", o)
+	args := []string{"image", "prune", "--force"}
+	if o.All {
+		args = append(args, "--all")
+	}
+	if o.OlderThan > 0 {
+		args = append(args, "--filter", fmt.Sprintf("until=%s", o.OlderThan))
+	}
+	rr, err := r.Runner.RunCmd(exec.Command("docker", args...))
+	if err != nil {
+		return nil, errors.Wrap(err, "prune images docker")
+	}
+	return parseDockerPrune(rr.Stdout.String()), nil
+}
+
+// parseDockerPrune returns the IDs of the images deleted by 'docker image prune'
+func parseDockerPrune(output string) []string {
+	removed := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		if id, ok := strings.CutPrefix(strings.TrimSpace(line), "deleted: "); ok {
+			removed = append(removed, strings.TrimPrefix(id, "sha256:"))
+		}
+	}
+	return removed
+}
+
 // TagImage tags an image in this runtime
 func (r *Docker) TagImage(source string, target string) error {
 	klog.Infof("Tagging image %s: %s", source, target)
@@ -604,7 +724,7 @@ func (r *Docker) configureDocker(driver string) error {
 // 2. Extract the preloaded tarball to the correct directory
 // 3. Remove the tarball within the VM
 func (r *Docker) Preload(cc config.ClusterConfig) error {
-	if !download.PreloadExists(cc.KubernetesConfig.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime, cc.Driver) {
+	if !preloadExists(cc) {
 		return nil
 	}
 	k8sVersion := cc.KubernetesConfig.KubernetesVersion
@@ -30,16 +30,19 @@ import (
 
 // DefaultKubeBinariesURL returns a URL to kube binaries
 func DefaultKubeBinariesURL() string {
-	return fmt.Sprintf("https://%s%s/release", releaseHost, releasePath)
+	return fmt.Sprintf("https://%s/release", releaseHost)
 }
 
 // binaryWithChecksumURL gets the location of a Kubernetes binary
 func binaryWithChecksumURL(binaryName, version, osName, archName, binaryURL string) (string, error) {
+	base := fmt.Sprintf("%s/%s/bin/%s/%s/%s", binaryURL, version, osName, archName, binaryName)
 	if binaryURL == "" {
-		binaryURL = DefaultKubeBinariesURL()
+		base = fmt.Sprintf("%s/%s/bin/%s/%s/%s", DefaultKubeBinariesURL(), version, osName, archName, binaryName)
+		params := artifactParams{Name: binaryName, Version: version, KubernetesVersion: version, OS: osName, Arch: archName}
+		if u, ok := sourceURL(ArtifactBinary, params); ok {
+			base = u
+		}
 	}
-
-	base := fmt.Sprintf("%s/%s/bin/%s/%s/%s", binaryURL, version, osName, archName, binaryName)
 	v, err := semver.Make(version[1:])
 	if err != nil {
 		return "", err
@@ -51,10 +54,14 @@ func binaryWithChecksumURL(binaryName, version, osName, archName, binaryURL stri
 	return fmt.Sprintf("%s?checksum=file:%s.sha1", base, base), nil
 }
 
+// BinaryPath returns the path a binary is cached at on the host
+func BinaryPath(binary, version, osName, archName string) string {
+	return path.Join(localpath.MakeMiniPath("cache", osName, archName, version), binary)
+}
+
 // Binary will download a binary onto the host
 func Binary(binary, version, osName, archName, binaryURL string) (string, error) {
-	targetDir := localpath.MakeMiniPath("cache", osName, archName, version)
-	targetFilepath := path.Join(targetDir, binary)
+	targetFilepath := BinaryPath(binary, version, osName, archName)
 	targetLock := targetFilepath + ".lock"
 
 	url, err := binaryWithChecksumURL(binary, version, osName, archName, binaryURL)
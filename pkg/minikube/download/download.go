@@ -43,15 +43,17 @@ var (
 	downloadHost = "storage.googleapis.com"
 
 	releaseHost = "dl.k8s.io"
-	releasePath = ""
+
+	// offline disables all network access, artifacts must already be cached
+	offline = false
 )
 
-// SetAliyunMirror set the download host for Aliyun mirror
-func SetAliyunMirror() {
-	downloadHost = aliyunMirror
+// ErrOffline is returned when an artifact is not cached and network access is disabled
+var ErrOffline = errors.New("artifact is not cached and network access is disabled in offline mode")
 
-	releaseHost = downloadHost
-	releasePath = "/kubernetes-release"
+// SetOffline disables all downloads, so that only cached artifacts are used
+func SetOffline(o bool) {
+	offline = o
 }
 
 // CreateDstDownloadMock is the default mock implementation of download.
@@ -89,6 +91,16 @@ func download(src, dst string) error {
 			"https": &getter.HttpGetter{Netrc: false},
 		},
 	}
+	// the artifact source may need credentials or a custom CA, which also apply to the checksum files
+	if sourceTransport != nil {
+		httpGetter := &getter.HttpGetter{Netrc: false, Client: httpClient()}
+		client.Getters["http"] = httpGetter
+		client.Getters["https"] = httpGetter
+	}
+
+	if offline {
+		return errors.Wrapf(ErrOffline, "downloading %s", src)
+	}
 
 	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
 		return errors.Wrap(err, "mkdir")
@@ -17,13 +17,21 @@ limitations under the License.
 package download
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/blang/semver/v4"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
@@ -36,6 +44,8 @@ func TestDownload(t *testing.T) {
 	t.Run("PreloadChecksumMismatch", testPreloadChecksumMismatch)
 	t.Run("PreloadExistsCaching", testPreloadExistsCaching)
 	t.Run("PreloadWithCachedSizeZero", testPreloadWithCachedSizeZero)
+	t.Run("ImportPreload", testImportPreload)
+	t.Run("Offline", testOffline)
 }
 
 // Returns a mock function that sleeps before incrementing `downloadsCounter` and creates the requested file.
@@ -242,3 +252,289 @@ func testPreloadWithCachedSizeZero(t *testing.T) {
 		t.Errorf("Expected only 1 download attempt but got %v!", downloadNum)
 	}
 }
+
+func testImportPreload(t *testing.T) {
+	t.Setenv("MINIKUBE_HOME", t.TempDir())
+	ensureChecksumValid = func(_, _, targetPath string, checksum []byte) error {
+		sum, err := md5File(targetPath)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(sum, checksum) {
+			return fmt.Errorf("checksum mismatch")
+		}
+		return nil
+	}
+
+	k8sVersion := constants.DefaultKubernetesVersion
+	src := filepath.Join(t.TempDir(), TarballName(k8sVersion, constants.Docker))
+	if err := os.WriteFile(src, []byte("preload"), 0o644); err != nil {
+		t.Fatalf("failed to write tarball: %v", err)
+	}
+	if err := WriteChecksumFile(src); err != nil {
+		t.Fatalf("failed to write checksum file: %v", err)
+	}
+
+	if err := ImportPreload(src, k8sVersion, constants.Containerd); err == nil {
+		t.Errorf("Expected error when importing a preload for another container runtime")
+	}
+
+	if err := ImportPreload(src, k8sVersion, constants.Docker); err != nil {
+		t.Fatalf("Failed to import preload: %v", err)
+	}
+	got, err := os.ReadFile(TarballPath(k8sVersion, constants.Docker))
+	if err != nil {
+		t.Fatalf("Expected preload to be imported into the cache: %v", err)
+	}
+	if string(got) != "preload" {
+		t.Errorf("Expected imported preload to contain %q, got %q", "preload", got)
+	}
+
+	if err := os.WriteFile(src, []byte("corrupted"), 0o644); err != nil {
+		t.Fatalf("failed to write tarball: %v", err)
+	}
+	if err := ImportPreload(src, k8sVersion, constants.Docker); err == nil {
+		t.Errorf("Expected error when importing a preload not matching its checksum")
+	}
+	if _, err := os.Stat(TarballPath(k8sVersion, constants.Docker)); !os.IsNotExist(err) {
+		t.Errorf("Expected preload failing verification to be removed from the cache, got: %v", err)
+	}
+}
+
+func testOffline(t *testing.T) {
+	SetOffline(true)
+	defer SetOffline(false)
+	downloadNum := 0
+	DownloadMock = mockSleepDownload(&downloadNum)
+	checkCache = func(_ string) (fs.FileInfo, error) { return nil, fmt.Errorf("cache not found") }
+	checkRemotePreloadExists = func(_, _ string) bool {
+		t.Errorf("Expected no remote preload check in offline mode")
+		return true
+	}
+
+	if _, err := Binary("kubectl", "v1.30.0", "linux", "amd64", ""); !errors.Is(err, ErrOffline) {
+		t.Errorf("Expected offline error downloading binary, got: %v", err)
+	}
+	if PreloadExists("v1.30.0", "offline", "docker", true) {
+		t.Errorf("Expected preload not to exist in offline mode")
+	}
+	if downloadNum != 0 {
+		t.Errorf("Expected no download attempt but got %v!", downloadNum)
+	}
+	pullDigest = func(img string) ([]byte, error) {
+		t.Errorf("Expected no pull of %s in offline mode", img)
+		return nil, nil
+	}
+	img := "gcr.io/k8s-minikube/kicbase:v0.0.1@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	if got, want := daemonImage(img), "gcr.io/k8s-minikube/kicbase:v0.0.1"; got != want {
+		t.Errorf("daemonImage(%q) = %q, want %q", img, got, want)
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	sum, _ := hex.DecodeString("d41d8cd98f00b204e9800998ecf8427e")
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{name: "raw", data: sum},
+		{name: "hex", data: []byte("d41d8cd98f00b204e9800998ecf8427e\n")},
+		{name: "md5sum output", data: []byte("d41d8cd98f00b204e9800998ecf8427e  preloaded-images.tar.lz4\n")},
+		{name: "empty", data: []byte{}, wantErr: true},
+		{name: "sha256", data: []byte("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"), wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseChecksum(tc.data)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("parseChecksum(%q) expected error, got %x", tc.data, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseChecksum(%q) unexpected error: %v", tc.data, err)
+			}
+			if !bytes.Equal(got, sum) {
+				t.Errorf("parseChecksum(%q) = %x, want %x", tc.data, got, sum)
+			}
+		})
+	}
+}
+
+func TestRemoteTarballURL(t *testing.T) {
+	defer SetPreloadBaseURL("")
+
+	name := TarballName("v1.30.0", constants.Docker)
+	if got := remoteTarballURL("v1.30.0", constants.Docker); !strings.HasPrefix(got, "https://"+downloadHost+"/"+PreloadBucket+"/") {
+		t.Errorf("remoteTarballURL() = %s, expected the default bucket", got)
+	}
+
+	SetPreloadBaseURL("https://mirror.example.com/preloads/")
+	want := fmt.Sprintf("https://mirror.example.com/preloads/%s/v1.30.0/%s", PreloadVersion, name)
+	if got := remoteTarballURL("v1.30.0", constants.Docker); got != want {
+		t.Errorf("remoteTarballURL() = %s, want %s", got, want)
+	}
+}
+
+func TestK3sWithChecksumURL(t *testing.T) {
+	want := "https://github.com/k3s-io/k3s/releases/download/v1.30.0+k3s1/k3s-arm64?checksum=file:https://github.com/k3s-io/k3s/releases/download/v1.30.0%2Bk3s1/sha256sum-arm64.txt"
+	got, err := k3sWithChecksumURL("k3s", "v1.30.0", "arm64")
+	if err != nil {
+		t.Fatalf("k3sWithChecksumURL() unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("k3sWithChecksumURL() = %s, want %s", got, want)
+	}
+
+	got, err = k3sWithChecksumURL(K3sImagesName("amd64"), "v1.30.0", "amd64")
+	if err != nil {
+		t.Fatalf("k3sWithChecksumURL() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(got, "https://github.com/k3s-io/k3s/releases/download/v1.30.0+k3s1/k3s-airgap-images-amd64.tar.gz?") {
+		t.Errorf("k3sWithChecksumURL() = %s, expected the images tarball", got)
+	}
+
+	if _, err := k3sWithChecksumURL("k3s", "v1.30.0", "s390x"); err == nil {
+		t.Errorf("k3sWithChecksumURL() expected an error for an architecture k3s is not released for")
+	}
+}
+
+func TestArtifactSource(t *testing.T) {
+	defer func() {
+		if err := SetArtifactSource(nil); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	s := &ArtifactSource{
+		Base: "https://artifactory.example.com/minikube/",
+		URLs: map[string]string{ArtifactBinary: "https://k8s.example.com/{{.Version}}/{{.OS}}-{{.Arch}}/{{.Name}}"},
+	}
+	if err := SetArtifactSource(s); err != nil {
+		t.Fatalf("SetArtifactSource() unexpected error: %v", err)
+	}
+
+	name := TarballName("v1.30.0", constants.Docker)
+	want := fmt.Sprintf("https://artifactory.example.com/minikube/preload/%s/v1.30.0/%s", PreloadVersion, name)
+	if got := remoteTarballURL("v1.30.0", constants.Docker); got != want {
+		t.Errorf("remoteTarballURL() = %s, want %s", got, want)
+	}
+
+	want = "https://k8s.example.com/v1.30.0/linux-amd64/kubelet"
+	got, err := binaryWithChecksumURL("kubelet", "v1.30.0", "linux", "amd64", "")
+	if err != nil {
+		t.Fatalf("binaryWithChecksumURL() unexpected error: %v", err)
+	}
+	if got != want+"?checksum=file:"+want+".sha256" {
+		t.Errorf("binaryWithChecksumURL() = %s, want %s with its checksum", got, want)
+	}
+
+	want = "https://artifactory.example.com/minikube/releases/download/v1.34.0/docker-machine-driver-kvm2"
+	if got := driverURL("docker-machine-driver-kvm2", semver.MustParse("1.34.0")); got != want {
+		t.Errorf("driverURL() = %s, want %s", got, want)
+	}
+
+	urls := withSourceISO([]string{"https://storage.googleapis.com/minikube/iso/minikube-v1.34.0-amd64.iso"})
+	if len(urls) != 2 || urls[0] != "https://artifactory.example.com/minikube/iso/minikube-v1.34.0-amd64.iso" {
+		t.Errorf("withSourceISO() = %v, expected the ISO in the source first", urls)
+	}
+
+	img, ok := SourceKicBaseImage("gcr.io/k8s-minikube/kicbase:v0.0.45")
+	if !ok || img != "artifactory.example.com/k8s-minikube/kicbase:v0.0.45" {
+		t.Errorf("SourceKicBaseImage() = %s, %v", img, ok)
+	}
+
+	if err := SetArtifactSource(&ArtifactSource{URLs: map[string]string{"helm": "{{.Name}}"}}); err == nil {
+		t.Errorf("SetArtifactSource() expected an error for an unknown artifact kind")
+	}
+}
+
+func TestAliyunMirror(t *testing.T) {
+	defer func() {
+		if err := SetArtifactSource(nil); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	SetAliyunMirror()
+
+	name := TarballName("v1.30.0", constants.Docker)
+	want := fmt.Sprintf("https://%s/%s/%s/v1.30.0/%s", aliyunMirror, PreloadBucket, PreloadVersion, name)
+	if got := remoteTarballURL("v1.30.0", constants.Docker); got != want {
+		t.Errorf("remoteTarballURL() = %s, want %s", got, want)
+	}
+	want = fmt.Sprintf("https://%s/kubernetes-release/release/v1.30.0/bin/linux/amd64/kubectl", aliyunMirror)
+	got, err := binaryWithChecksumURL("kubectl", "v1.30.0", "linux", "amd64", "")
+	if err != nil {
+		t.Fatalf("binaryWithChecksumURL() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(got, want+"?") {
+		t.Errorf("binaryWithChecksumURL() = %s, want %s", got, want)
+	}
+	// the ISOs and drivers are not mirrored
+	if _, ok := sourceURL(ArtifactDriver, artifactParams{}); ok {
+		t.Errorf("expected the Aliyun mirror not to serve drivers")
+	}
+}
+
+func TestArtifactSourceCredentials(t *testing.T) {
+	defer func() {
+		if err := SetArtifactSource(nil); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	creds := filepath.Join(t.TempDir(), "credentials")
+	content := "# artifactory token\nAuthorization: Bearer s3cr3t\n\nX-JFrog-Art-Api: key\n"
+	if err := os.WriteFile(creds, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	header, err := readCredentials(creds)
+	if err != nil {
+		t.Fatalf("readCredentials() unexpected error: %v", err)
+	}
+	if header.Get("Authorization") != "Bearer s3cr3t" || header.Get("X-JFrog-Art-Api") != "key" {
+		t.Errorf("readCredentials() = %v", header)
+	}
+
+	if err := SetArtifactSource(&ArtifactSource{Base: srv.URL, Credentials: creds}); err != nil {
+		t.Fatalf("SetArtifactSource() unexpected error: %v", err)
+	}
+	resp, err := httpClient().Get(srv.URL + "/preload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if gotAuth != "Bearer s3cr3t" {
+		t.Errorf("expected the credentials to be sent to the source, got Authorization %q", gotAuth)
+	}
+
+	// credentials are only sent to the hosts of the source
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer other.Close()
+	resp, err = httpClient().Get(other.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if gotAuth != "" {
+		t.Errorf("expected no credentials to be sent to %s, got Authorization %q", other.URL, gotAuth)
+	}
+
+	if err := os.WriteFile(creds, []byte("not a header\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readCredentials(creds); err == nil {
+		t.Errorf("readCredentials() expected an error for a malformed line")
+	}
+}
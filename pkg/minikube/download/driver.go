@@ -29,14 +29,23 @@ import (
 )
 
 func driverWithChecksumURL(name string, v semver.Version) string {
-	base := fmt.Sprintf("https://github.com/kubernetes/minikube/releases/download/v%s/%s", v, name)
+	base := driverURL(name, v)
 	return fmt.Sprintf("%s?checksum=file:%s.sha256", base, base)
 }
 func driverWithArchAndChecksumURL(name string, v semver.Version) string {
-	base := fmt.Sprintf("https://github.com/kubernetes/minikube/releases/download/v%s/%s-%s", v, name, runtime.GOARCH)
+	base := driverURL(fmt.Sprintf("%s-%s", name, runtime.GOARCH), v)
 	return fmt.Sprintf("%s?checksum=file:%s.sha256", base, base)
 }
 
+// driverURL returns the location of a file of a minikube release, in the artifact source if it serves drivers
+func driverURL(name string, v semver.Version) string {
+	params := artifactParams{Name: name, Version: "v" + v.String(), OS: runtime.GOOS, Arch: runtime.GOARCH}
+	if u, ok := sourceURL(ArtifactDriver, params); ok {
+		return u
+	}
+	return fmt.Sprintf("https://github.com/kubernetes/minikube/releases/download/v%s/%s", v, name)
+}
+
 // Driver downloads an arbitrary driver
 func Driver(name string, destination string, v semver.Version) error {
 	out.Step(style.FileDownload, "Downloading driver {{.driver}}:", out.V{"driver": name})
@@ -55,6 +55,11 @@ func imagePathInCache(img string) string {
 	return f
 }
 
+// ImageCachePath returns the path img is cached at by ImageToCache
+func ImageCachePath(img string) string {
+	return imagePathInCache(img)
+}
+
 // ImageExistsInCache if img exist in local cache directory
 func ImageExistsInCache(img string) bool {
 	f := imagePathInCache(img)
@@ -135,6 +140,9 @@ func ImageToCache(img string) error {
 		klog.Infof("%s exists in cache, skipping pull", img)
 		return nil
 	}
+	if offline {
+		return errors.Wrapf(ErrOffline, "pulling %s", img)
+	}
 
 	if err := os.MkdirAll(filepath.Dir(f), 0777); err != nil {
 		return errors.Wrapf(err, "making cache image directory: %s", f)
@@ -155,7 +163,11 @@ func ImageToCache(img string) error {
 		return errors.Wrap(err, "parsing tag")
 	}
 	klog.V(3).Infof("Getting image %v", ref)
-	i, err := remote.Image(ref, remote.WithPlatform(defaultPlatform))
+	opts := []remote.Option{remote.WithPlatform(defaultPlatform)}
+	if sourceTransport != nil {
+		opts = append(opts, remote.WithTransport(sourceTransport))
+	}
+	i, err := remote.Image(ref, opts...)
 	if err != nil {
 		if strings.Contains(err.Error(), "GitHub Docker Registry needs login") {
 			ErrGithubNeedsLogin := errors.New(err.Error())
@@ -275,12 +287,23 @@ func CacheToDaemon(img string) (string, error) {
 		return "", err
 	}
 
+	return daemonImage(img), nil
+}
+
+// pullDigest pulls an image into the local docker daemon, which records its digest
+var pullDigest = func(img string) ([]byte, error) {
 	platform := fmt.Sprintf("linux/%s", runtime.GOARCH)
-	cmd := exec.Command("docker", "pull", "--platform", platform, "--quiet", img)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	return exec.Command("docker", "pull", "--platform", platform, "--quiet", img).CombinedOutput()
+}
+
+// daemonImage returns the name of an image loaded into the local docker daemon, which has its digest only if it could be pulled
+func daemonImage(img string) string {
+	if offline {
+		return image.Tag(img)
+	}
+	if output, err := pullDigest(img); err != nil {
 		klog.Warningf("failed to pull image digest (expected if offline): %s: %v", output, err)
-		img = image.Tag(img)
+		return image.Tag(img)
 	}
-
-	return img, nil
+	return img
 }
@@ -40,13 +40,18 @@ const fileScheme = "file"
 
 // DefaultISOURLs returns a list of ISO URL's to consult by default, in priority order
 func DefaultISOURLs() []string {
+	return DefaultISOURLsForArch(runtime.GOARCH)
+}
+
+// DefaultISOURLsForArch returns the list of ISO URL's to consult by default for guests of an architecture, in priority order
+func DefaultISOURLsForArch(arch string) []string {
 	v := version.GetISOVersion()
 	isoBucket := "minikube-builds/iso/19917"
 
 	return []string{
-		fmt.Sprintf("https://storage.googleapis.com/%s/minikube-%s-%s.iso", isoBucket, v, runtime.GOARCH),
-		fmt.Sprintf("https://github.com/kubernetes/minikube/releases/download/%s/minikube-%s-%s.iso", v, v, runtime.GOARCH),
-		fmt.Sprintf("https://kubernetes.oss-cn-hangzhou.aliyuncs.com/minikube/iso/minikube-%s-%s.iso", v, runtime.GOARCH),
+		fmt.Sprintf("https://storage.googleapis.com/%s/minikube-%s-%s.iso", isoBucket, v, arch),
+		fmt.Sprintf("https://github.com/kubernetes/minikube/releases/download/%s/minikube-%s-%s.iso", v, v, arch),
+		fmt.Sprintf("https://kubernetes.oss-cn-hangzhou.aliyuncs.com/minikube/iso/minikube-%s-%s.iso", v, arch),
 	}
 }
 
@@ -66,6 +71,18 @@ func LocalISOResource(isoURL string) string {
 	return fileURI(localISOPath(u))
 }
 
+// ISOPath returns the local path of an ISO URL, which is in the cache unless the URL is a local file
+func ISOPath(isoURL string) string {
+	u, err := url.Parse(isoURL)
+	if err != nil {
+		return isoURL
+	}
+	if u.Scheme == fileScheme {
+		return filepath.FromSlash(u.Path)
+	}
+	return localISOPath(u)
+}
+
 // fileURI returns a file:// URI for a path
 func fileURI(path string) string {
 	return "file://" + filepath.ToSlash(path)
@@ -84,6 +101,11 @@ func localISOPath(u *url.URL) string {
 func ISO(urls []string, skipChecksum bool) (string, error) {
 	errs := map[string]string{}
 
+	// the default ISOs are looked for in the artifact source first
+	if !skipChecksum {
+		urls = withSourceISO(urls)
+	}
+
 	for _, url := range urls {
 		err := downloadISO(url, skipChecksum)
 		if err != nil {
@@ -103,6 +125,22 @@ func ISO(urls []string, skipChecksum bool) (string, error) {
 	return "", errors.New(msg.String())
 }
 
+// withSourceISO prepends the location of the first ISO in the artifact source to urls, if it serves ISOs
+func withSourceISO(urls []string) []string {
+	if len(urls) == 0 {
+		return urls
+	}
+	u, err := url.Parse(urls[0])
+	if err != nil || u.Scheme == fileScheme {
+		return urls
+	}
+	params := artifactParams{Name: path.Base(u.Path), Version: version.GetISOVersion(), OS: "linux", Arch: runtime.GOARCH}
+	if su, ok := sourceURL(ArtifactISO, params); ok {
+		return append([]string{su}, urls...)
+	}
+	return urls
+}
+
 // downloadISO downloads an ISO URL
 func downloadISO(isoURL string, skipChecksum bool) error {
 	u, err := url.Parse(isoURL)
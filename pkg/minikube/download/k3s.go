@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// k3sArchs maps the architectures k3s is released for to the suffix of their release assets
+var k3sArchs = map[string]string{
+	"amd64": "",
+	"arm64": "-arm64",
+	"arm":   "-armhf",
+}
+
+// K3sVersion returns the k3s release which ships a Kubernetes version
+func K3sVersion(k8sVersion string) string {
+	return k8sVersion + "+k3s1"
+}
+
+// K3sBinaryPath returns the path the k3s binary of a Kubernetes version is cached at on the host
+func K3sBinaryPath(k8sVersion, archName string) string {
+	return filepath.Join(localpath.MakeMiniPath("cache", "k3s", K3sVersion(k8sVersion), archName), "k3s")
+}
+
+// K3sImagesName returns the name of the tarball of the images k3s runs for an architecture
+func K3sImagesName(archName string) string {
+	return fmt.Sprintf("k3s-airgap-images-%s.tar.gz", archName)
+}
+
+// K3sImagesPath returns the path the k3s images tarball of a Kubernetes version is cached at on the host
+func K3sImagesPath(k8sVersion, archName string) string {
+	return filepath.Join(localpath.MakeMiniPath("cache", "k3s", K3sVersion(k8sVersion), archName), K3sImagesName(archName))
+}
+
+// k3sWithChecksumURL returns the location of a k3s release asset, verified against the checksums of the release
+func k3sWithChecksumURL(name, k8sVersion, archName string) (string, error) {
+	suffix, ok := k3sArchs[archName]
+	if !ok {
+		return "", fmt.Errorf("k3s is not released for %s", archName)
+	}
+	sums := fmt.Sprintf("sha256sum-%s.txt", archName)
+	if name == "k3s" {
+		name += suffix
+	}
+	// the checksum URL is a query parameter, in which the + of the k3s versions would read as a space
+	sumsURL := strings.ReplaceAll(k3sURL(sums, k8sVersion, archName), "+", "%2B")
+	return fmt.Sprintf("%s?checksum=file:%s", k3sURL(name, k8sVersion, archName), sumsURL), nil
+}
+
+// k3sURL returns the location of a file of a k3s release, in the artifact source if it serves k3s
+func k3sURL(name, k8sVersion, archName string) string {
+	v := K3sVersion(k8sVersion)
+	params := artifactParams{Name: name, Version: v, KubernetesVersion: k8sVersion, OS: "linux", Arch: archName}
+	if u, ok := sourceURL(ArtifactK3s, params); ok {
+		return u
+	}
+	return fmt.Sprintf("https://github.com/k3s-io/k3s/releases/download/%s/%s", v, name)
+}
+
+// K3sBinary downloads the k3s binary of a Kubernetes version onto the host
+func K3sBinary(k8sVersion, archName string) (string, error) {
+	return k3sAsset("k3s", K3sBinaryPath(k8sVersion, archName), k8sVersion, archName)
+}
+
+// K3sImages downloads the tarball of the images k3s runs for a Kubernetes version onto the host
+func K3sImages(k8sVersion, archName string) (string, error) {
+	return k3sAsset(K3sImagesName(archName), K3sImagesPath(k8sVersion, archName), k8sVersion, archName)
+}
+
+// k3sAsset downloads a k3s release asset to dst, unless it is already cached
+func k3sAsset(name, dst, k8sVersion, archName string) (string, error) {
+	u, err := k3sWithChecksumURL(name, k8sVersion, archName)
+	if err != nil {
+		return "", err
+	}
+
+	releaser, err := lockDownload(dst + ".lock")
+	if releaser != nil {
+		defer releaser.Release()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := checkCache(dst); err == nil {
+		klog.Infof("Not caching %s, using %s", name, dst)
+		return dst, nil
+	}
+
+	if err := download(u, dst); err != nil {
+		return "", errors.Wrapf(err, "download failed: %s", u)
+	}
+	return dst, nil
+}
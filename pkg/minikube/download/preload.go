@@ -21,6 +21,7 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path"
@@ -51,8 +52,17 @@ const (
 
 var (
 	preloadStates = make(map[string]map[string]bool)
+
+	// preloadBaseURL replaces the GCS bucket as the location of preload tarballs if set
+	preloadBaseURL = ""
 )
 
+// SetPreloadBaseURL sets the base URL preload tarballs and their checksum files are downloaded from.
+// Tarballs are expected at <base>/<preload version>/<k8s version>/<tarball name>, next to a <tarball name>.checksum file.
+func SetPreloadBaseURL(base string) {
+	preloadBaseURL = strings.TrimSuffix(base, "/")
+}
+
 // TarballName returns name of the tarball
 func TarballName(k8sVersion, containerRuntime string) string {
 	if containerRuntime == "crio" {
@@ -87,8 +97,15 @@ func TarballPath(k8sVersion, containerRuntime string) string {
 	return filepath.Join(targetDir(), TarballName(k8sVersion, containerRuntime))
 }
 
-// remoteTarballURL returns the URL for the remote tarball in GCS
+// remoteTarballURL returns the URL for the remote tarball in GCS, or under the preload base URL or in the artifact source if set
 func remoteTarballURL(k8sVersion, containerRuntime string) string {
+	if preloadBaseURL != "" {
+		return fmt.Sprintf("%s/%s/%s/%s", preloadBaseURL, PreloadVersion, k8sVersion, TarballName(k8sVersion, containerRuntime))
+	}
+	params := artifactParams{Name: TarballName(k8sVersion, containerRuntime), Version: PreloadVersion, KubernetesVersion: k8sVersion, OS: "linux", Arch: runtime.GOARCH}
+	if u, ok := sourceURL(ArtifactPreload, params); ok {
+		return u
+	}
 	return fmt.Sprintf("https://%s/%s/%s/%s/%s", downloadHost, PreloadBucket, PreloadVersion, k8sVersion, TarballName(k8sVersion, containerRuntime))
 }
 
@@ -103,7 +120,7 @@ func setPreloadState(k8sVersion, containerRuntime string, value bool) {
 
 var checkRemotePreloadExists = func(k8sVersion, containerRuntime string) bool {
 	url := remoteTarballURL(k8sVersion, containerRuntime)
-	resp, err := http.Head(url)
+	resp, err := httpClient().Head(url)
 	if err != nil {
 		klog.Warningf("%s fetch error: %v", url, err)
 		return false
@@ -148,6 +165,11 @@ func PreloadExists(k8sVersion, containerRuntime, driverName string, forcePreload
 		return true
 	}
 
+	if offline {
+		klog.Infof("Skipping remote preload check in offline mode")
+		return false
+	}
+
 	existence := checkRemotePreloadExists(k8sVersion, containerRuntime)
 	setPreloadState(k8sVersion, containerRuntime, existence)
 	return existence
@@ -234,6 +256,17 @@ func getStorageAttrs(name string) (*storage.ObjectAttrs, error) {
 // getChecksum returns the MD5 checksum of the preload tarball
 var getChecksum = func(k8sVersion, containerRuntime string) ([]byte, error) {
 	klog.Infof("getting checksum for %s ...", TarballName(k8sVersion, containerRuntime))
+	if preloadBaseURL != "" {
+		return getRemoteChecksumFile(remoteTarballURL(k8sVersion, containerRuntime) + ".checksum")
+	}
+	// mirrors may publish the checksum next to the tarball, otherwise it is the one of the tarball in GCS
+	if _, ok := sourceURL(ArtifactPreload, artifactParams{}); ok {
+		checksum, err := getRemoteChecksumFile(remoteTarballURL(k8sVersion, containerRuntime) + ".checksum")
+		if err == nil {
+			return checksum, nil
+		}
+		klog.Infof("no checksum file in the artifact source, using the one in GCS: %v", err)
+	}
 	filename := fmt.Sprintf("%s/%s/%s", PreloadVersion, k8sVersion, TarballName(k8sVersion, containerRuntime))
 	attrs, err := getStorageAttrs(filename)
 	if err != nil {
@@ -242,6 +275,124 @@ var getChecksum = func(k8sVersion, containerRuntime string) ([]byte, error) {
 	return attrs.MD5, nil
 }
 
+// getRemoteChecksumFile fetches a checksum file published next to a preload tarball
+func getRemoteChecksumFile(url string) ([]byte, error) {
+	resp, err := httpClient().Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting %s", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("getting %s: status code %d", url, resp.StatusCode)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", url)
+	}
+	return parseChecksum(b)
+}
+
+// parseChecksum accepts the contents of a checksum file either as the raw MD5 sum,
+// which is how minikube stores it, or hex encoded
+func parseChecksum(b []byte) ([]byte, error) {
+	if len(b) == md5.Size {
+		return b, nil
+	}
+	s := strings.Fields(string(b))
+	if len(s) > 0 {
+		if sum, err := hex.DecodeString(s[0]); err == nil && len(sum) == md5.Size {
+			return sum, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid md5 checksum of %d bytes", len(b))
+}
+
+// md5File returns the MD5 sum of a file
+func md5File(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// WriteChecksumFile writes the checksum of a preload tarball built locally to <path>.checksum,
+// in the format expected by ImportPreload and the preload base URL
+func WriteChecksumFile(path string) error {
+	checksum, err := md5File(path)
+	if err != nil {
+		return errors.Wrap(err, "md5")
+	}
+	return os.WriteFile(path+".checksum", checksum, 0o644)
+}
+
+// ImportPreload copies a locally built preload tarball into the cache, verifying it against
+// the checksum file next to it. The tarball must have been built for k8sVersion and containerRuntime.
+func ImportPreload(src, k8sVersion, containerRuntime string) error {
+	if filepath.Base(src) != TarballName(k8sVersion, containerRuntime) {
+		return fmt.Errorf("%s is not a preload for Kubernetes %s and %s, expected %s", src, k8sVersion, containerRuntime, TarballName(k8sVersion, containerRuntime))
+	}
+	b, err := os.ReadFile(src + ".checksum")
+	if err != nil {
+		return errors.Wrap(err, "reading checksum file")
+	}
+	checksum, err := parseChecksum(b)
+	if err != nil {
+		return errors.Wrapf(err, "parsing %s.checksum", src)
+	}
+
+	targetPath := TarballPath(k8sVersion, containerRuntime)
+	releaser, err := lockDownload(targetPath + ".lock")
+	if releaser != nil {
+		defer releaser.Release()
+	}
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("importing preload %s -> %s", src, targetPath)
+	if err := copyFile(src, targetPath); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+	if err := ensureChecksumValid(k8sVersion, containerRuntime, targetPath, checksum); err != nil {
+		os.Remove(targetPath)
+		return err
+	}
+	setPreloadState(k8sVersion, containerRuntime, true)
+	return nil
+}
+
+// copyFile copies src to dst through a temporary file, so that dst is never partially written
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
 // saveChecksumFile saves the checksum to a local file for later verification
 func saveChecksumFile(k8sVersion, containerRuntime string, checksum []byte) error {
 	klog.Infof("saving checksum for %s ...", TarballName(k8sVersion, containerRuntime))
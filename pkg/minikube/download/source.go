@@ -0,0 +1,303 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/klog/v2"
+)
+
+// Kinds of artifacts an artifact source serves
+const (
+	ArtifactISO     = "iso"
+	ArtifactKicBase = "kicbase"
+	ArtifactPreload = "preload"
+	ArtifactBinary  = "binary"
+	ArtifactDriver  = "driver"
+	ArtifactK3s     = "k3s"
+)
+
+// defaultArtifactTemplates lay out the artifacts under the base URL of a source,
+// for the kinds the source has no template for
+var defaultArtifactTemplates = map[string]string{
+	ArtifactISO:     "{{.Base}}/iso/{{.Name}}",
+	ArtifactKicBase: "{{.Host}}/{{.Name}}",
+	ArtifactPreload: "{{.Base}}/preload/{{.Version}}/{{.KubernetesVersion}}/{{.Name}}",
+	ArtifactBinary:  "{{.Base}}/release/{{.Version}}/bin/{{.OS}}/{{.Arch}}/{{.Name}}",
+	ArtifactDriver:  "{{.Base}}/releases/download/{{.Version}}/{{.Name}}",
+	ArtifactK3s:     "{{.Base}}/k3s/{{.Version}}/{{.Name}}",
+}
+
+// aliyunSource mirrors the preloads and Kubernetes binaries for users in China
+var aliyunSource = ArtifactSource{
+	URLs: map[string]string{
+		ArtifactPreload: "https://" + aliyunMirror + "/" + PreloadBucket + "/{{.Version}}/{{.KubernetesVersion}}/{{.Name}}",
+		ArtifactBinary:  "https://" + aliyunMirror + "/kubernetes-release/release/{{.Version}}/bin/{{.OS}}/{{.Arch}}/{{.Name}}",
+	},
+}
+
+// ArtifactSource is an alternative location to download the minikube artifacts from, such as an internal mirror
+type ArtifactSource struct {
+	// Base is the base URL of the source. If set, artifacts without a template in URLs are laid out under it.
+	Base string `yaml:"base"`
+	// URLs maps artifact kinds to the template of their URL, or of the image reference for the kicbase image.
+	// Templates can use {{.Base}}, {{.Host}}, {{.Name}}, {{.Version}}, {{.KubernetesVersion}}, {{.OS}} and {{.Arch}}.
+	URLs map[string]string `yaml:"urls"`
+	// Credentials is the path of a file with the HTTP headers to send to the source, one "Name: value" per line
+	Credentials string `yaml:"credentials"`
+	// CACert is the path of a PEM bundle of certificate authorities to trust in addition to the system ones
+	CACert string `yaml:"caCert"`
+}
+
+// artifactParams are the values available to the artifact templates
+type artifactParams struct {
+	Base              string
+	Host              string
+	Name              string
+	Version           string
+	KubernetesVersion string
+	OS                string
+	Arch              string
+}
+
+var (
+	// source is the artifact source in use, nil for the upstream locations
+	source *ArtifactSource
+	// sourceTransport sends the credentials of the source and trusts its CA
+	sourceTransport http.RoundTripper
+)
+
+// LoadArtifactSource parses an artifact mirror setting, which is either the base URL of the mirror
+// or the path of a YAML file describing an ArtifactSource
+func LoadArtifactSource(setting string) (*ArtifactSource, error) {
+	if u, err := url.Parse(setting); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		return &ArtifactSource{Base: setting}, nil
+	}
+	b, err := os.ReadFile(setting)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading artifact mirror config")
+	}
+	s := &ArtifactSource{}
+	if err := yaml.UnmarshalStrict(b, s); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", setting)
+	}
+	if s.Base == "" && len(s.URLs) == 0 {
+		return nil, fmt.Errorf("%s sets neither a base URL nor URL templates", setting)
+	}
+	return s, nil
+}
+
+// Validate checks that the source only has templates for known artifact kinds, which parse
+func (s *ArtifactSource) Validate() error {
+	if s.Base != "" {
+		if u, err := url.Parse(s.Base); err != nil || u.Host == "" {
+			return fmt.Errorf("%s is not a valid base URL", s.Base)
+		}
+	}
+	for kind, tmpl := range s.URLs {
+		if _, ok := defaultArtifactTemplates[kind]; !ok {
+			return fmt.Errorf("unknown artifact kind %q", kind)
+		}
+		if _, err := template.New(kind).Parse(tmpl); err != nil {
+			return errors.Wrapf(err, "%s template", kind)
+		}
+	}
+	return nil
+}
+
+// SetArtifactSource makes the ISO, kicbase, preload, binary, driver and k3s downloads use s, or the upstream locations if s is nil
+func SetArtifactSource(s *ArtifactSource) error {
+	if s == nil {
+		source = nil
+		sourceTransport = nil
+		return nil
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	s.Base = strings.TrimSuffix(s.Base, "/")
+
+	t, err := s.transport()
+	if err != nil {
+		return err
+	}
+	source = s
+	sourceTransport = t
+	klog.Infof("using artifact source %+v", *s)
+	return nil
+}
+
+// SetAliyunMirror set the download host for Aliyun mirror, unless an artifact source is configured
+func SetAliyunMirror() {
+	if source != nil {
+		klog.Infof("using artifact source %s instead of the Aliyun mirror", source.Base)
+		return
+	}
+	s := aliyunSource
+	if err := SetArtifactSource(&s); err != nil {
+		klog.Errorf("failed to set Aliyun mirror: %v", err)
+	}
+}
+
+// sourceURL returns the location of an artifact in the artifact source, if it serves artifacts of that kind
+func sourceURL(kind string, p artifactParams) (string, bool) {
+	if source == nil {
+		return "", false
+	}
+	tmpl, ok := source.URLs[kind]
+	if !ok {
+		if source.Base == "" {
+			return "", false
+		}
+		tmpl = defaultArtifactTemplates[kind]
+	}
+
+	p.Base = source.Base
+	if u, err := url.Parse(source.Base); err == nil {
+		p.Host = u.Host
+	}
+	t, err := template.New(kind).Parse(tmpl)
+	if err != nil {
+		klog.Errorf("invalid %s template %q: %v", kind, tmpl, err)
+		return "", false
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, p); err != nil {
+		klog.Errorf("executing %s template %q: %v", kind, tmpl, err)
+		return "", false
+	}
+	return b.String(), true
+}
+
+// SourceKicBaseImage returns the reference of the kicbase image img in the artifact source, if it serves it
+func SourceKicBaseImage(img string) (string, bool) {
+	repo := img
+	// strip the registry, the source serves the same repository
+	if i := strings.Index(img, "/"); i > 0 && strings.ContainsAny(img[:i], ".:") {
+		repo = img[i+1:]
+	}
+	params := artifactParams{Name: repo, OS: "linux", Arch: runtime.GOARCH}
+	return sourceURL(ArtifactKicBase, params)
+}
+
+// transport returns an HTTP transport trusting the CA of the source and sending its credentials
+func (s *ArtifactSource) transport() (http.RoundTripper, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if s.CACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			klog.Warningf("failed to load system cert pool: %v", err)
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(s.CACert)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading CA bundle")
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", s.CACert)
+		}
+		t.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	header := http.Header{}
+	if s.Credentials != "" {
+		var err error
+		if header, err = readCredentials(s.Credentials); err != nil {
+			return nil, err
+		}
+	}
+	return &headerTransport{base: t, header: header, hosts: s.hosts()}, nil
+}
+
+// hosts returns the hosts the source serves artifacts from, which are sent its credentials
+func (s *ArtifactSource) hosts() map[string]bool {
+	hosts := map[string]bool{}
+	if u, err := url.Parse(s.Base); err == nil && u.Host != "" {
+		hosts[u.Host] = true
+	}
+	for _, tmpl := range s.URLs {
+		// the host is the part of the URL before any template action
+		prefix := strings.SplitN(tmpl, "{{", 2)[0]
+		if u, err := url.Parse(prefix); err == nil && u.Host != "" {
+			hosts[u.Host] = true
+		}
+	}
+	return hosts
+}
+
+// readCredentials reads HTTP headers from a file with one "Name: value" per line
+func readCredentials(path string) (http.Header, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading credentials")
+	}
+	defer f.Close()
+
+	header := http.Header{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%s:%d: expected \"Name: value\"", path, n)
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return header, scanner.Err()
+}
+
+// headerTransport adds headers to the requests sent to some hosts
+type headerTransport struct {
+	base   http.RoundTripper
+	header http.Header
+	hosts  map[string]bool
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.header) > 0 && t.hosts[req.URL.Host] {
+		req = req.Clone(req.Context())
+		for k, v := range t.header {
+			req.Header[k] = v
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// httpClient returns the client for requests which may go to the artifact source
+func httpClient() *http.Client {
+	if sourceTransport == nil {
+		return http.DefaultClient
+	}
+	return &http.Client{Transport: sourceTransport}
+}
@@ -41,8 +41,12 @@ const (
 	Docker = "docker"
 	// Mock driver
 	Mock = "mock"
+	// Fake driver
+	Fake = "fake"
 	// None driver
 	None = "none"
+	// Nspawn driver
+	Nspawn = "nspawn"
 	// SSH driver
 	SSH = "ssh"
 	// KVM2 driver
@@ -81,21 +85,39 @@ var (
 
 // SupportedDrivers returns a list of supported drivers
 func SupportedDrivers() []string {
+	return append(builtinDrivers(), pluginDrivers()...)
+}
+
+// builtinDrivers returns the drivers built into minikube which are supported on this host
+func builtinDrivers() []string {
 	arch := detect.RuntimeArch()
 	for _, a := range constants.SupportedArchitectures {
 		if arch == a {
-			return supportedDrivers
+			return append([]string{}, supportedDrivers...)
 		}
 	}
 	// remote cluster only
 	return []string{SSH}
 }
 
-// DisplaySupportedDrivers returns a string with a list of supported drivers
+// pluginDrivers returns the out-of-tree drivers found on the host
+func pluginDrivers() []string {
+	names := []string{}
+	for _, d := range registry.List() {
+		if d.Plugin != "" {
+			names = append(names, d.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DisplaySupportedDrivers returns a string with a list of the supported built-in drivers. It is used at init,
+// so it does not look for driver plugins
 func DisplaySupportedDrivers() string {
 	var sd []string
-	for _, d := range SupportedDrivers() {
-		if registry.Driver(d).Priority == registry.Experimental {
+	for _, d := range builtinDrivers() {
+		if registry.Registered(d).Priority == registry.Experimental {
 			sd = append(sd, d+" (experimental)")
 			continue
 		}
@@ -104,8 +126,12 @@ func DisplaySupportedDrivers() string {
 	return strings.Join(sd, ", ")
 }
 
-// Supported returns if the driver is supported on this host.
+// Supported returns if the driver is supported on this host. The fake driver, which is meant for tests
+// of minikube itself, is supported everywhere without being listed
 func Supported(name string) bool {
+	if IsFake(name) {
+		return true
+	}
 	for _, d := range SupportedDrivers() {
 		if name == d {
 			return true
@@ -116,7 +142,7 @@ func Supported(name string) bool {
 
 // MachineType returns appropriate machine name for the driver
 func MachineType(name string) string {
-	if IsKIC(name) {
+	if IsKIC(name) || IsNspawn(name) {
 		return "container"
 	}
 
@@ -124,6 +150,10 @@ func MachineType(name string) string {
 		return "bare metal machine"
 	}
 
+	if IsFake(name) {
+		return "sandbox"
+	}
+
 	if IsVM(name) {
 		return "VM"
 	}
@@ -158,11 +188,21 @@ func IsMock(name string) bool {
 	return name == Mock
 }
 
+// IsFake checks if the driver is the sandboxed fake driver
+func IsFake(name string) bool {
+	return name == Fake
+}
+
 // IsNone checks if the driver is a none
 func IsNone(name string) bool {
 	return name == None
 }
 
+// IsNspawn checks if the driver is nspawn
+func IsNspawn(name string) bool {
+	return name == Nspawn
+}
+
 // IsKVM checks if the driver is a KVM[2]
 func IsKVM(name string) bool {
 	return name == KVM2 || name == AliasKVM
@@ -175,7 +215,7 @@ func IsQEMU(name string) bool {
 
 // IsVM checks if the driver is a VM
 func IsVM(name string) bool {
-	if IsKIC(name) || BareMetal(name) {
+	if IsKIC(name) || BareMetal(name) || IsFake(name) || IsNspawn(name) {
 		return false
 	}
 	return true
@@ -208,7 +248,7 @@ func IsHyperV(name string) bool {
 
 // AllowsPreload returns if preload is allowed for the driver
 func AllowsPreload(driverName string) bool {
-	return !BareMetal(driverName) && !IsSSH(driverName)
+	return !BareMetal(driverName) && !IsSSH(driverName) && !IsFake(driverName)
 }
 
 // NeedsPortForward returns true if driver is unable provide direct IP connectivity
@@ -273,6 +313,10 @@ type FlagHints struct {
 // FlagDefaults returns suggested defaults based on a driver
 func FlagDefaults(name string) FlagHints {
 	fh := FlagHints{}
+	if IsFake(name) {
+		// nothing is ever pulled into a sandbox, so there is nothing to cache either
+		return fh
+	}
 	if name != None {
 		fh.CacheImages = true
 		return fh
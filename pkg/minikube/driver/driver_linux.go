@@ -28,6 +28,7 @@ var supportedDrivers = []string{
 	QEMU,
 	VMware,
 	None,
+	Nspawn,
 	Docker,
 	Podman,
 	SSH,
@@ -63,7 +63,9 @@ func TestMachineType(t *testing.T) {
 		Podman:     "container",
 		Docker:     "container",
 		Mock:       "bare metal machine",
+		Fake:       "sandbox",
 		None:       "bare metal machine",
+		Nspawn:     "container",
 		SSH:        "bare metal machine",
 		KVM2:       "VM",
 		QEMU2:      "VM",
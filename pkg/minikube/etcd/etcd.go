@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package etcd manages the etcd members kubeadm runs as static pods on the control-plane nodes, or on dedicated etcd nodes
+package etcd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+var (
+	// certsDir is where the etcd certificates are, both on the node and in the etcd container
+	certsDir = path.Join(vmpath.GuestKubernetesCertsDir, "etcd")
+	// BackupDir is where snapshots are kept on the control-plane nodes, outside of the etcd data directory
+	BackupDir = path.Join(vmpath.GuestPersistentDir, "backup")
+)
+
+// Member is an etcd member of the cluster, running on a control-plane node or on a dedicated etcd node
+type Member struct {
+	// Name is the machine name of the node, which kubeadm names the member after
+	Name string
+	// IP is the address the member listens on for peers
+	IP string
+}
+
+// PeerURL returns the URL other members reach the member at
+func (m Member) PeerURL() string {
+	return fmt.Sprintf("https://%s:2380", m.IP)
+}
+
+// Members returns the etcd members of a cluster, one per control-plane node, or per dedicated etcd node with external etcd
+func Members(cc config.ClusterConfig) []Member {
+	members := []Member{}
+	for _, n := range config.EtcdNodes(cc) {
+		members = append(members, Member{Name: config.MachineName(cc, n), IP: n.IP})
+	}
+	return members
+}
+
+// containerID returns the ID of the running etcd container on a node
+func containerID(r command.Runner) (string, error) {
+	rr, err := r.RunCmd(exec.Command("sudo", "crictl", "ps", "--quiet", "--state", "running", "--name", "^etcd$"))
+	if err != nil {
+		return "", errors.Wrap(err, "crictl ps")
+	}
+	ids := strings.Fields(rr.Stdout.String())
+	if len(ids) == 0 {
+		return "", fmt.Errorf("etcd is not running")
+	}
+	return ids[0], nil
+}
+
+// waitForContainer waits until etcd runs on a node, as after kubelet restarted its static pod
+func waitForContainer(r command.Runner, timeout time.Duration) (string, error) {
+	var id string
+	get := func() (err error) {
+		id, err = containerID(r)
+		return err
+	}
+	if err := retry.Local(get, timeout); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// execInContainer runs an etcd tool in the etcd container of a node, which has the data directory and certificates mounted
+func execInContainer(r command.Runner, id string, args ...string) (*command.RunResult, error) {
+	cmd := append([]string{"sudo", "crictl", "exec", id}, args...)
+	return r.RunCmd(exec.Command(cmd[0], cmd[1:]...))
+}
+
+// etcdctl runs etcdctl against the local member of a node
+func etcdctl(r command.Runner, args ...string) (*command.RunResult, error) {
+	id, err := containerID(r)
+	if err != nil {
+		return nil, err
+	}
+	base := []string{
+		"etcdctl",
+		"--endpoints=https://127.0.0.1:2379",
+		"--cacert=" + path.Join(certsDir, "ca.crt"),
+		"--cert=" + path.Join(certsDir, "server.crt"),
+		"--key=" + path.Join(certsDir, "server.key"),
+	}
+	return execInContainer(r, id, append(base, args...)...)
+}
+
+// Snapshot saves a snapshot of the etcd keyspace to dst on the node, which should be in BackupDir
+func Snapshot(r command.Runner, dst string) error {
+	// etcd can only write to its data directory, so the snapshot is moved out of it afterwards
+	tmp := path.Join(bsutil.EtcdDataDir(), path.Base(dst))
+	if _, err := etcdctl(r, "snapshot", "save", tmp); err != nil {
+		return errors.Wrap(err, "etcdctl snapshot save")
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "mkdir", "-p", path.Dir(dst))); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "mv", tmp, dst)); err != nil {
+		return errors.Wrap(err, "mv")
+	}
+	klog.Infof("saved etcd snapshot to %s", dst)
+	return nil
+}
+
+// Restore replaces the data of the etcd member on a node by the snapshot at src, for a cluster of the given members.
+// The snapshot must be restored on every member, and kubelet is left running.
+func Restore(r command.Runner, src string, m Member, members []Member) error {
+	id, err := waitForContainer(r, 2*time.Minute)
+	if err != nil {
+		return errors.Wrap(err, "waiting for etcd")
+	}
+
+	// restore next to the live data, as the etcd container only sees its data directory
+	dataDir := bsutil.EtcdDataDir()
+	snapshot := path.Join(dataDir, "restore.db")
+	restored := path.Join(dataDir, "restore")
+	if _, err := r.RunCmd(exec.Command("sudo", "rm", "-rf", restored)); err != nil {
+		return errors.Wrap(err, "rm")
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "cp", src, snapshot)); err != nil {
+		return errors.Wrap(err, "cp")
+	}
+
+	cluster := []string{}
+	for _, member := range members {
+		cluster = append(cluster, fmt.Sprintf("%s=%s", member.Name, member.PeerURL()))
+	}
+	args := []string{
+		"etcdutl", "snapshot", "restore", snapshot,
+		"--name", m.Name,
+		"--initial-cluster", strings.Join(cluster, ","),
+		"--initial-advertise-peer-urls", m.PeerURL(),
+		"--data-dir", restored,
+	}
+	if _, err := execInContainer(r, id, args...); err != nil {
+		return errors.Wrap(err, "etcdutl snapshot restore")
+	}
+
+	// swap the data while etcd is down, kubelet restarts it with the restored data
+	sm := sysinit.New(r)
+	if err := sm.Stop("kubelet"); err != nil {
+		return errors.Wrap(err, "stop kubelet")
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "crictl", "stop", id)); err != nil {
+		klog.Warningf("failed to stop etcd container %s: %v", id, err)
+	}
+	swap := fmt.Sprintf("rm -rf %[1]s/member.old && mv %[1]s/member %[1]s/member.old && mv %[2]s/member %[1]s/member && rm -rf %[2]s %[3]s",
+		dataDir, restored, snapshot)
+	if _, err := r.RunCmd(exec.Command("sudo", "/bin/bash", "-c", swap)); err != nil {
+		return errors.Wrap(err, "replace etcd data")
+	}
+	if err := sm.Start("kubelet"); err != nil {
+		return errors.Wrap(err, "start kubelet")
+	}
+	klog.Infof("restored etcd member %s from %s", m.Name, src)
+	return nil
+}
+
+// Defrag releases the disk space the etcd member on a node holds for deleted keys
+func Defrag(r command.Runner) error {
+	if _, err := etcdctl(r, "defrag", "--command-timeout=60s"); err != nil {
+		return errors.Wrap(err, "etcdctl defrag")
+	}
+	return nil
+}
+
+// WaitForHealthy waits until the etcd member on a node is running and serves requests
+func WaitForHealthy(r command.Runner, timeout time.Duration) error {
+	health := func() error {
+		_, err := etcdctl(r, "endpoint", "health")
+		return err
+	}
+	if err := retry.Local(health, timeout); err != nil {
+		return errors.Wrap(err, "etcdctl endpoint health")
+	}
+	return nil
+}
+
+// AddMember announces a new member to the etcd cluster, through the etcd member on a node, before the new member is started.
+// A member already announced, by an earlier attempt to start it, is left as is.
+func AddMember(r command.Runner, m Member) error {
+	rr, err := etcdctl(r, "member", "list", "-w", "json")
+	if err != nil {
+		return errors.Wrap(err, "etcdctl member list")
+	}
+	var ml memberList
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &ml); err != nil {
+		return errors.Wrap(err, "parsing etcdctl member list")
+	}
+	for _, member := range ml.Members {
+		for _, u := range member.PeerURLs {
+			if u == m.PeerURL() {
+				klog.Infof("etcd member %s is already announced", m.Name)
+				return nil
+			}
+		}
+	}
+	if _, err := etcdctl(r, "member", "add", m.Name, "--peer-urls="+m.PeerURL()); err != nil {
+		return errors.Wrap(err, "etcdctl member add")
+	}
+	klog.Infof("announced etcd member %s at %s", m.Name, m.PeerURL())
+	return nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// alarmTypes are the names of the alarms etcd raises, by their number in the etcd API
+var alarmTypes = map[int]string{
+	0: "NONE",
+	1: "NOSPACE",
+	2: "CORRUPT",
+}
+
+// Status is the status of the etcd member of a node
+type Status struct {
+	// Node is the machine name of the node the member runs on
+	Node        string   `json:"node"`
+	MemberID    string   `json:"memberID"`
+	Version     string   `json:"version"`
+	DBSize      int64    `json:"dbSize"`
+	DBSizeInUse int64    `json:"dbSizeInUse"`
+	Leader      bool     `json:"leader"`
+	RaftTerm    uint64   `json:"raftTerm"`
+	RaftIndex   uint64   `json:"raftIndex"`
+	Members     []string `json:"members"`
+	Alarms      []string `json:"alarms"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// endpointStatus is the output of etcdctl endpoint status -w json
+type endpointStatus []struct {
+	Status struct {
+		Header struct {
+			MemberID uint64 `json:"member_id"`
+		} `json:"header"`
+		Version     string   `json:"version"`
+		DBSize      int64    `json:"dbSize"`
+		DBSizeInUse int64    `json:"dbSizeInUse"`
+		Leader      uint64   `json:"leader"`
+		RaftIndex   uint64   `json:"raftIndex"`
+		RaftTerm    uint64   `json:"raftTerm"`
+		Errors      []string `json:"errors"`
+	} `json:"Status"`
+}
+
+// memberList is the output of etcdctl member list -w json
+type memberList struct {
+	Members []struct {
+		ID        uint64   `json:"ID"`
+		Name      string   `json:"name"`
+		PeerURLs  []string `json:"peerURLs"`
+		IsLearner bool     `json:"isLearner"`
+	} `json:"members"`
+}
+
+// alarmList is the output of etcdctl alarm list -w json
+type alarmList struct {
+	Alarms []struct {
+		MemberID uint64 `json:"memberID"`
+		Alarm    int    `json:"alarm"`
+	} `json:"alarms"`
+}
+
+// MemberStatus returns the status of the etcd member on a node, along with the members and alarms of the cluster as the member sees them
+func MemberStatus(r command.Runner, node string) (Status, error) {
+	rr, err := etcdctl(r, "endpoint", "status", "-w", "json")
+	if err != nil {
+		return Status{}, errors.Wrap(err, "etcdctl endpoint status")
+	}
+	st, err := parseEndpointStatus(rr.Stdout.Bytes())
+	if err != nil {
+		return Status{}, err
+	}
+	st.Node = node
+
+	rr, err = etcdctl(r, "member", "list", "-w", "json")
+	if err != nil {
+		return Status{}, errors.Wrap(err, "etcdctl member list")
+	}
+	if st.Members, err = parseMembers(rr.Stdout.Bytes()); err != nil {
+		return Status{}, err
+	}
+
+	rr, err = etcdctl(r, "alarm", "list", "-w", "json")
+	if err != nil {
+		return Status{}, errors.Wrap(err, "etcdctl alarm list")
+	}
+	if st.Alarms, err = parseAlarms(rr.Stdout.Bytes(), st.MemberID); err != nil {
+		return Status{}, err
+	}
+	return st, nil
+}
+
+// parseEndpointStatus parses the status of the local member from etcdctl endpoint status
+func parseEndpointStatus(data []byte) (Status, error) {
+	var es endpointStatus
+	if err := json.Unmarshal(data, &es); err != nil {
+		return Status{}, errors.Wrap(err, "parsing etcdctl endpoint status")
+	}
+	if len(es) == 0 {
+		return Status{}, fmt.Errorf("etcdctl endpoint status returned no endpoint")
+	}
+	s := es[0].Status
+	return Status{
+		MemberID:    memberID(s.Header.MemberID),
+		Version:     s.Version,
+		DBSize:      s.DBSize,
+		DBSizeInUse: s.DBSizeInUse,
+		Leader:      s.Leader == s.Header.MemberID,
+		RaftTerm:    s.RaftTerm,
+		RaftIndex:   s.RaftIndex,
+		Errors:      s.Errors,
+	}, nil
+}
+
+// parseMembers parses the members of the cluster from etcdctl member list, as <name>(<id>)
+func parseMembers(data []byte) ([]string, error) {
+	var ml memberList
+	if err := json.Unmarshal(data, &ml); err != nil {
+		return nil, errors.Wrap(err, "parsing etcdctl member list")
+	}
+	members := []string{}
+	for _, m := range ml.Members {
+		s := fmt.Sprintf("%s(%s)", m.Name, memberID(m.ID))
+		if m.IsLearner {
+			s += " learner"
+		}
+		members = append(members, s)
+	}
+	return members, nil
+}
+
+// parseAlarms parses the alarms a member raised from etcdctl alarm list
+func parseAlarms(data []byte, member string) ([]string, error) {
+	var al alarmList
+	if err := json.Unmarshal(data, &al); err != nil {
+		return nil, errors.Wrap(err, "parsing etcdctl alarm list")
+	}
+	alarms := []string{}
+	for _, a := range al.Alarms {
+		if memberID(a.MemberID) != member {
+			continue
+		}
+		name, ok := alarmTypes[a.Alarm]
+		if !ok {
+			name = fmt.Sprintf("UNKNOWN(%d)", a.Alarm)
+		}
+		alarms = append(alarms, name)
+	}
+	return alarms, nil
+}
+
+// memberID formats the ID of a member in hex, as etcdctl does in its tables
+func memberID(id uint64) string {
+	return fmt.Sprintf("%x", id)
+}
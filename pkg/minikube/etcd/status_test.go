@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseStatus(t *testing.T) {
+	endpoint := `[{"Endpoint":"https://127.0.0.1:2379","Status":{"header":{"cluster_id":9920836294282154082,"member_id":12593026477526642892,"revision":1287,"raft_term":3},"version":"3.5.15","dbSize":2641920,"leader":12593026477526642892,"raftIndex":1432,"raftTerm":3,"raftAppliedIndex":1432,"dbSizeInUse":1265664}}]`
+	st, err := parseEndpointStatus([]byte(endpoint))
+	if err != nil {
+		t.Fatalf("parseEndpointStatus: %v", err)
+	}
+	want := Status{MemberID: "aec36adc501070cc", Version: "3.5.15", DBSize: 2641920, DBSizeInUse: 1265664, Leader: true, RaftTerm: 3, RaftIndex: 1432}
+	if diff := cmp.Diff(want, st); diff != "" {
+		t.Errorf("status mismatch (-want +got):\n%s", diff)
+	}
+
+	members := `{"header":{"cluster_id":9920836294282154082,"member_id":12593026477526642892,"raft_term":3},"members":[{"ID":12593026477526642892,"name":"minikube","peerURLs":["https://192.168.49.2:2380"],"clientURLs":["https://192.168.49.2:2379"]},{"ID":1,"name":"minikube-m02","peerURLs":["https://192.168.49.3:2380"],"isLearner":true}]}`
+	ms, err := parseMembers([]byte(members))
+	if err != nil {
+		t.Fatalf("parseMembers: %v", err)
+	}
+	if diff := cmp.Diff([]string{"minikube(aec36adc501070cc)", "minikube-m02(1) learner"}, ms); diff != "" {
+		t.Errorf("members mismatch (-want +got):\n%s", diff)
+	}
+
+	alarms := `{"header":{"cluster_id":9920836294282154082},"alarms":[{"memberID":12593026477526642892,"alarm":1},{"memberID":1,"alarm":2}]}`
+	as, err := parseAlarms([]byte(alarms), st.MemberID)
+	if err != nil {
+		t.Fatalf("parseAlarms: %v", err)
+	}
+	if diff := cmp.Diff([]string{"NOSPACE"}, as); diff != "" {
+		t.Errorf("alarms mismatch (-want +got):\n%s", diff)
+	}
+	// etcdctl omits the alarms when there are none
+	if as, err := parseAlarms([]byte(`{"header":{}}`), st.MemberID); err != nil || len(as) != 0 {
+		t.Errorf("expected no alarms, got %v, %v", as, err)
+	}
+}
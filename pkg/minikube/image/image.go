@@ -201,6 +201,11 @@ func imagePathInCache(img string) string {
 	return f
 }
 
+// CachePath returns the path img is cached at by SaveToDir in the image cache directory
+func CachePath(img string) string {
+	return imagePathInCache(img)
+}
+
 // UploadCachedImage uploads cached image
 func UploadCachedImage(imgName string) error {
 	tag, err := name.NewTag(imgName, name.WeakValidation)
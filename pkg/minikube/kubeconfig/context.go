@@ -92,8 +92,10 @@ func DeleteContext(machineName string, configPath ...string) error {
 	delete(kcfg.Clusters, machineName)
 	delete(kcfg.AuthInfos, machineName)
 	delete(kcfg.Contexts, machineName)
+	delete(kcfg.AuthInfos, OIDCContext(machineName))
+	delete(kcfg.Contexts, OIDCContext(machineName))
 
-	if kcfg.CurrentContext == machineName {
+	if kcfg.CurrentContext == machineName || kcfg.CurrentContext == OIDCContext(machineName) {
 		kcfg.CurrentContext = ""
 	}
 
@@ -324,6 +324,56 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdateExecUser(t *testing.T) {
+	cfg := &Settings{
+		ClusterName:          "test",
+		Namespace:            "dev",
+		ClusterServerAddress: "192.168.1.1:8443",
+		ClientCertificate:    "/home/apiserver.crt",
+		ClientKey:            "/home/apiserver.key",
+		CertificateAuthority: "/home/ca.crt",
+		ExecUser: &api.ExecConfig{
+			Command:    "kubectl",
+			Args:       []string{"oidc-login", "get-token", "--oidc-issuer-url=https://dex.example.com", "--oidc-client-id=minikube"},
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+		},
+	}
+	cfg.SetPath(filepath.Join(t.TempDir(), "kubeconfig"))
+	if err := Update(cfg); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	config, err := readOrNew(cfg.filePath())
+	if err != nil {
+		t.Fatalf("Error reading kubeconfig file: %v", err)
+	}
+	if config.CurrentContext != "test" {
+		t.Errorf("CurrentContext = %q, want the context of the client cert user", config.CurrentContext)
+	}
+	user, ok := config.AuthInfos["test-oidc"]
+	if !ok || user.Exec == nil || user.Exec.Command != "kubectl" || len(user.Exec.Args) != 4 {
+		t.Fatalf("AuthInfos[test-oidc] = %+v, want the exec user", user)
+	}
+	context, ok := config.Contexts["test-oidc"]
+	if !ok || context.AuthInfo != "test-oidc" || context.Cluster != "test" || context.Namespace != "dev" {
+		t.Fatalf("Contexts[test-oidc] = %+v, want the exec user on the cluster", context)
+	}
+
+	if err := DeleteContext("test", cfg.filePath()); err != nil {
+		t.Fatalf("DeleteContext() error: %v", err)
+	}
+	config, err = readOrNew(cfg.filePath())
+	if err != nil {
+		t.Fatalf("Error reading kubeconfig file: %v", err)
+	}
+	if _, ok := config.AuthInfos["test-oidc"]; ok {
+		t.Errorf("DeleteContext() kept the exec user")
+	}
+	if _, ok := config.Contexts["test-oidc"]; ok {
+		t.Errorf("DeleteContext() kept the exec user context")
+	}
+}
+
 func TestVerifyEndpoint(t *testing.T) {
 
 	var tests = []struct {
@@ -52,6 +52,9 @@ type Settings struct {
 	// Should the current context be kept when setting up this one
 	KeepContext bool
 
+	// ExecUser, if set, adds a user getting its credentials from this command, with a context named by OIDCContext
+	ExecUser *api.ExecConfig
+
 	// Should the certificate files be embedded instead of referenced by path
 	EmbedCerts bool
 
@@ -126,6 +129,16 @@ func PopulateFromSettings(cfg *Settings, apiCfg *api.Config) error {
 
 	apiCfg.Contexts[contextName] = context
 
+	if cfg.ExecUser != nil {
+		execUser := api.NewAuthInfo()
+		execUser.Exec = cfg.ExecUser.DeepCopy()
+		apiCfg.AuthInfos[OIDCContext(cfg.ClusterName)] = execUser
+
+		execContext := context.DeepCopy()
+		execContext.AuthInfo = OIDCContext(cfg.ClusterName)
+		apiCfg.Contexts[OIDCContext(cfg.ClusterName)] = execContext
+	}
+
 	// Only set current context to minikube if the user has not used the keepContext flag
 	if !cfg.KeepContext {
 		apiCfg.CurrentContext = cfg.ClusterName
@@ -134,6 +147,11 @@ func PopulateFromSettings(cfg *Settings, apiCfg *api.Config) error {
 	return nil
 }
 
+// OIDCContext returns the name of the user and context of a cluster which authenticate with its OpenID Connect provider
+func OIDCContext(clusterName string) string {
+	return clusterName + "-oidc"
+}
+
 // Update reads config from disk, adds the minikube settings, and writes it back.
 // activeContext is true when minikube is the CurrentContext
 // If no CurrentContext is set, the given name will be used.
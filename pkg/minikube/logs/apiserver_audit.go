@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// APIServerAuditSource is a control-plane node to read the apiserver audit log from
+type APIServerAuditSource struct {
+	Node   string
+	Runner command.Runner
+}
+
+// APIServerAuditFilter selects the events of the apiserver audit log, its empty fields match any event
+type APIServerAuditFilter struct {
+	User      string
+	Verb      string
+	Resource  string
+	Namespace string
+}
+
+// apiServerAuditEvent is the part of an audit.k8s.io/v1 Event which is printed
+type apiServerAuditEvent struct {
+	StageTimestamp time.Time `json:"stageTimestamp"`
+	Verb           string    `json:"verb"`
+	RequestURI     string    `json:"requestURI"`
+	User           struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	ObjectRef *struct {
+		Resource  string `json:"resource"`
+		Namespace string `json:"namespace"`
+	} `json:"objectRef"`
+	ResponseStatus *struct {
+		Code int `json:"code"`
+	} `json:"responseStatus"`
+
+	node string
+}
+
+// matches returns whether the event is selected by the filter
+func (f APIServerAuditFilter) matches(e apiServerAuditEvent) bool {
+	if f.User != "" && f.User != e.User.Username {
+		return false
+	}
+	if f.Verb != "" && f.Verb != e.Verb {
+		return false
+	}
+	if f.Resource != "" && (e.ObjectRef == nil || f.Resource != e.ObjectRef.Resource) {
+		return false
+	}
+	if f.Namespace != "" && (e.ObjectRef == nil || f.Namespace != e.ObjectRef.Namespace) {
+		return false
+	}
+	return true
+}
+
+// String formats the event as a line of the output
+func (e apiServerAuditEvent) String() string {
+	code := "-"
+	if e.ResponseStatus != nil {
+		code = strconv.Itoa(e.ResponseStatus.Code)
+	}
+	return fmt.Sprintf("%s %s %s %s %s %s", e.StageTimestamp.UTC().Format(time.RFC3339), e.node, e.User.Username, e.Verb, e.RequestURI, code)
+}
+
+// parseAPIServerAuditEvent parses a line of the audit log, written by the json backend of the apiserver
+func parseAPIServerAuditEvent(node string, line []byte) (apiServerAuditEvent, error) {
+	e := apiServerAuditEvent{node: node}
+	if err := json.Unmarshal(line, &e); err != nil {
+		return e, errors.Wrap(err, "unmarshal audit event")
+	}
+	return e, nil
+}
+
+// OutputAPIServerAudit outputs the events within the last lines of the apiserver audit logs of the control-plane nodes, oldest first
+func OutputAPIServerAudit(sources []APIServerAuditSource, lines int, filter APIServerAuditFilter, w io.Writer) error {
+	events := []apiServerAuditEvent{}
+	for _, s := range sources {
+		rr, err := s.Runner.RunCmd(exec.Command("sudo", "tail", "-n", strconv.Itoa(lines), bsutil.AuditLogPath))
+		if err != nil {
+			return errors.Wrapf(err, "read the apiserver audit log of %s, was the cluster started with --audit-policy?", s.Node)
+		}
+		scanner := bufio.NewScanner(&rr.Stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			e, err := parseAPIServerAuditEvent(s.Node, scanner.Bytes())
+			if err != nil {
+				klog.Warningf("skipping audit event of %s: %v", s.Node, err)
+				continue
+			}
+			if filter.matches(e) {
+				events = append(events, e)
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StageTimestamp.Before(events[j].StageTimestamp)
+	})
+	for _, e := range events {
+		if _, err := fmt.Fprintln(w, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FollowAPIServerAudit outputs the new events of the apiserver audit logs of the control-plane nodes as they are written
+func FollowAPIServerAudit(sources []APIServerAuditSource, filter APIServerAuditFilter, w io.Writer) error {
+	var mu sync.Mutex
+	g := errgroup.Group{}
+	for _, s := range sources {
+		g.Go(func() error {
+			cmd := exec.Command("sudo", "tail", "-n", "0", "-F", bsutil.AuditLogPath)
+			cmd.Stdout = &auditEventWriter{node: s.Node, filter: filter, w: w, mu: &mu}
+			if _, err := s.Runner.RunCmd(cmd); err != nil {
+				return errors.Wrapf(err, "follow the apiserver audit log of %s", s.Node)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// auditEventWriter writes the events of the audit log of a node selected by a filter, as the lines of the log are written to it
+type auditEventWriter struct {
+	node   string
+	filter APIServerAuditFilter
+	w      io.Writer
+	// mu serializes the writes of the nodes
+	mu  *sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write implements io.Writer
+func (a *auditEventWriter) Write(p []byte) (int, error) {
+	a.buf.Write(p)
+	for {
+		i := bytes.IndexByte(a.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := a.buf.Next(i + 1)
+		e, err := parseAPIServerAuditEvent(a.node, bytes.TrimSpace(line))
+		if err != nil {
+			klog.Warningf("skipping audit event of %s: %v", a.node, err)
+			continue
+		}
+		if !a.filter.matches(e) {
+			continue
+		}
+		a.mu.Lock()
+		_, err = fmt.Fprintln(a.w, e)
+		a.mu.Unlock()
+		if err != nil {
+			return 0, err
+		}
+	}
+}
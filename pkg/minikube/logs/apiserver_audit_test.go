@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+const (
+	getPods    = `{"kind":"Event","stageTimestamp":"2024-05-01T10:00:02Z","verb":"list","requestURI":"/api/v1/namespaces/default/pods","user":{"username":"minikube-user"},"objectRef":{"resource":"pods","namespace":"default"},"responseStatus":{"code":200}}`
+	createPod  = `{"kind":"Event","stageTimestamp":"2024-05-01T10:00:01Z","verb":"create","requestURI":"/api/v1/namespaces/default/pods","user":{"username":"minikube-user"},"objectRef":{"resource":"pods","namespace":"default"},"responseStatus":{"code":201}}`
+	getNodes   = `{"kind":"Event","stageTimestamp":"2024-05-01T10:00:03Z","verb":"get","requestURI":"/api/v1/nodes/m02","user":{"username":"system:node:m02"},"objectRef":{"resource":"nodes","name":"m02"},"responseStatus":{"code":200}}`
+	getVersion = `{"kind":"Event","stageTimestamp":"2024-05-01T10:00:04Z","verb":"get","requestURI":"/version","user":{"username":"minikube-user"}}`
+)
+
+func TestOutputAPIServerAudit(t *testing.T) {
+	tail := "sudo tail -n 50 /var/log/kubernetes/audit/audit.log"
+	primary := command.NewFakeCommandRunner()
+	primary.SetCommandToOutput(map[string]string{tail: getPods + "\n" + getNodes + "\nnot json\n"})
+	secondary := command.NewFakeCommandRunner()
+	secondary.SetCommandToOutput(map[string]string{tail: createPod + "\n" + getVersion + "\n"})
+	sources := []APIServerAuditSource{{Node: "minikube", Runner: primary}, {Node: "minikube-m02", Runner: secondary}}
+
+	tests := []struct {
+		name   string
+		filter APIServerAuditFilter
+		want   string
+	}{
+		{
+			name:   "all events oldest first",
+			filter: APIServerAuditFilter{},
+			want: `2024-05-01T10:00:01Z minikube-m02 minikube-user create /api/v1/namespaces/default/pods 201
+2024-05-01T10:00:02Z minikube minikube-user list /api/v1/namespaces/default/pods 200
+2024-05-01T10:00:03Z minikube system:node:m02 get /api/v1/nodes/m02 200
+2024-05-01T10:00:04Z minikube-m02 minikube-user get /version -
+`,
+		},
+		{
+			name:   "user and verb",
+			filter: APIServerAuditFilter{User: "minikube-user", Verb: "get"},
+			want: `2024-05-01T10:00:04Z minikube-m02 minikube-user get /version -
+`,
+		},
+		{
+			name:   "resource and namespace",
+			filter: APIServerAuditFilter{Resource: "pods", Namespace: "default"},
+			want: `2024-05-01T10:00:01Z minikube-m02 minikube-user create /api/v1/namespaces/default/pods 201
+2024-05-01T10:00:02Z minikube minikube-user list /api/v1/namespaces/default/pods 200
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := OutputAPIServerAudit(sources, 50, tc.filter, &b); err != nil {
+				t.Fatalf("OutputAPIServerAudit() error: %v", err)
+			}
+			if b.String() != tc.want {
+				t.Errorf("OutputAPIServerAudit() = %q, want %q", b.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestAuditEventWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := &auditEventWriter{node: "minikube", filter: APIServerAuditFilter{Verb: "create"}, w: &b, mu: &sync.Mutex{}}
+
+	// events are split across writes as the log is tailed
+	for _, p := range []string{createPod[:20], createPod[20:] + "\n" + getPods, "\n"} {
+		if _, err := w.Write([]byte(p)); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+	}
+	want := "2024-05-01T10:00:01Z minikube minikube-user create /api/v1/namespaces/default/pods 201\n"
+	if b.String() != want {
+		t.Errorf("auditEventWriter wrote %q, want %q", b.String(), want)
+	}
+}
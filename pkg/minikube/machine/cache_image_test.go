@@ -257,3 +257,69 @@ func TestMergeImageLists(t *testing.T) {
 		}
 	}
 }
+
+func TestMergeImageInspects(t *testing.T) {
+	order := []string{"minikube", "minikube-m02", "minikube-m03"}
+	results := map[string]*cruntime.ImageInspect{
+		"minikube": {
+			ID:          "image_id_1",
+			RepoTags:    []string{"docker.io/library/busybox:latest"},
+			RepoDigests: []string{"busybox@sha256:1"},
+		},
+		"minikube-m02": {
+			ID:          "image_id_1",
+			RepoTags:    []string{"docker.io/library/busybox:latest", "docker.io/library/busybox:stable"},
+			RepoDigests: []string{"busybox@sha256:1"},
+		},
+		"minikube-m03": {
+			ID:       "image_id_2",
+			RepoTags: []string{"docker.io/library/busybox:latest"},
+		},
+	}
+	expected := []NodeImageInspect{
+		{
+			Nodes: []string{"minikube", "minikube-m02"},
+			ImageInspect: cruntime.ImageInspect{
+				ID:          "image_id_1",
+				RepoTags:    []string{"docker.io/library/busybox:latest", "docker.io/library/busybox:stable"},
+				RepoDigests: []string{"busybox@sha256:1"},
+			},
+		},
+		{
+			Nodes: []string{"minikube-m03"},
+			ImageInspect: cruntime.ImageInspect{
+				ID:       "image_id_2",
+				RepoTags: []string{"docker.io/library/busybox:latest"},
+			},
+		},
+	}
+
+	got := mergeImageInspects(order, results)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("mergeImageInspects() = %+v;\nwant %+v", got, expected)
+	}
+}
+
+func TestDiffImageLists(t *testing.T) {
+	order := []string{"minikube", "minikube-m02"}
+	lists := map[string][]cruntime.ListImage{
+		"minikube": {
+			{ID: "image_id_1", RepoTags: []string{"registry.k8s.io/pause:3.9"}},
+			{ID: "image_id_2", RepoTags: []string{"docker.io/library/app:dev"}},
+			{ID: "image_id_3", RepoTags: []string{"docker.io/library/only-here:v1"}},
+		},
+		"minikube-m02": {
+			{ID: "image_id_1", RepoTags: []string{"registry.k8s.io/pause:3.9"}},
+			{ID: "image_id_4", RepoTags: []string{"docker.io/library/app:dev"}},
+		},
+	}
+	expected := []ImageDiff{
+		{Image: "docker.io/library/app:dev", IDs: map[string]string{"minikube": "image_id_2", "minikube-m02": "image_id_4"}},
+		{Image: "docker.io/library/only-here:v1", IDs: map[string]string{"minikube": "image_id_3", "minikube-m02": ""}},
+	}
+
+	got := diffImageLists(order, lists)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("diffImageLists() = %+v;\nwant %+v", got, expected)
+	}
+}
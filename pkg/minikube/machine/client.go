@@ -151,11 +151,25 @@ func (api *LocalClient) Close() error {
 	return nil
 }
 
+// SelfRunner is implemented by the drivers which run the commands of their machines themselves, without ssh
+type SelfRunner interface {
+	Runner() (command.Runner, error)
+}
+
+// runsItself returns whether the driver of the host runs its commands, so it cannot be provisioned over ssh
+func runsItself(h *host.Host) bool {
+	_, ok := h.Driver.(SelfRunner)
+	return ok
+}
+
 // CommandRunner returns best available command runner for this host
 func CommandRunner(h *host.Host) (command.Runner, error) {
 	if h.DriverName == driver.Mock {
 		return &command.FakeCommandRunner{}, nil
 	}
+	if d, ok := h.Driver.(SelfRunner); ok {
+		return d.Runner()
+	}
 	if driver.BareMetal(h.Driver.DriverName()) {
 		return command.NewExecRunner(true), nil
 	}
@@ -230,7 +244,7 @@ func (api *LocalClient) Create(h *host.Host) error {
 			"provisioning",
 			func() error {
 				// Skippable because we don't reconfigure Docker?
-				if driver.BareMetal(h.Driver.DriverName()) {
+				if driver.BareMetal(h.Driver.DriverName()) || runsItself(h) {
 					return nil
 				}
 				return provisionDockerMachine(h)
@@ -19,6 +19,7 @@ package machine
 import (
 	"flag"
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -409,6 +410,53 @@ func TestDeleteHostErrMachineNotExist(t *testing.T) {
 	}
 }
 
+// goneDiskDriver is a machine with extra disks whose container or VM is already gone
+type goneDiskDriver struct {
+	tests.MockDriver
+	disks []string
+}
+
+func (d *goneDiskDriver) GetState() (state.State, error) {
+	return state.Error, fmt.Errorf("no such container")
+}
+
+func (d *goneDiskDriver) AddDisk(_ int) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (d *goneDiskDriver) RemoveDisk(device string) error {
+	d.disks = slices.DeleteFunc(d.disks, func(dev string) bool { return dev == device })
+	return nil
+}
+
+func (d *goneDiskDriver) Disks() []string {
+	return slices.Clone(d.disks)
+}
+
+func TestDeleteHostRemovesDisksOfGoneMachine(t *testing.T) {
+	tests.MakeTempDir(t)
+
+	RegisterMockDriver(t)
+	api := tests.NewMockAPI(t)
+	h, err := createHost(api, &defaultClusterConfig, &config.Node{Name: "minikube"})
+	if err != nil {
+		t.Errorf("createHost failed: %v", err)
+	}
+
+	d := &goneDiskDriver{MockDriver: tests.MockDriver{T: t}, disks: []string{"/dev/loop5", "/dev/loop6"}}
+	h.Driver = d
+
+	cc := defaultClusterConfig
+	cc.Name = viper.GetString("profile")
+
+	if err := DeleteHost(api, config.MachineName(cc, config.Node{Name: "minikube"}), false); err != nil {
+		t.Fatalf("Unexpected error deleting host: %v", err)
+	}
+	if len(d.disks) != 0 {
+		t.Errorf("extra disks left after deleting the machine: %v", d.disks)
+	}
+}
+
 func TestStatus(t *testing.T) {
 	tests.MakeTempDir(t)
 
@@ -80,6 +80,9 @@ func DeleteHost(api libmachine.API, machineName string, deleteAbandoned ...bool)
 	if err != nil {
 		// Assume that the host has already been deleted, log and return
 		klog.Infof("Unable to get host status for %s, assuming it has already been deleted: %v", machineName, err)
+		if host != nil {
+			removeDisks(api, host)
+		}
 		return nil
 	}
 
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// DiskAttacher is implemented by the drivers which can add extra disks to a machine, and remove them, after its creation
+type DiskAttacher interface {
+	// AddDisk creates an extra disk of sizeMB and attaches it to the machine, returning its device
+	AddDisk(sizeMB int) (string, error)
+	// RemoveDisk detaches the extra disk of a device from the machine and deletes it
+	RemoveDisk(device string) error
+	// Disks returns the devices of the extra disks of the machine, in the order they were added
+	Disks() []string
+}
+
+// AddDisk adds an extra disk of sizeMB to a machine, returning its device
+func AddDisk(api libmachine.API, machineName string, sizeMB int) (string, error) {
+	h, err := LoadHost(api, machineName)
+	if err != nil {
+		return "", err
+	}
+	a, ok := h.Driver.(DiskAttacher)
+	if !ok {
+		return "", fmt.Errorf("the %s driver can not add disks to machines", h.DriverName)
+	}
+	dev, err := a.AddDisk(sizeMB)
+	// the disk may have been created even if attaching it to the running machine failed
+	if serr := api.Save(h); serr != nil {
+		return dev, errors.Wrap(serr, "save")
+	}
+	return dev, errors.Wrap(err, "add disk")
+}
+
+// RemoveDisk removes the extra disk of a device from a machine
+func RemoveDisk(api libmachine.API, machineName string, device string) error {
+	h, err := LoadHost(api, machineName)
+	if err != nil {
+		return err
+	}
+	a, ok := h.Driver.(DiskAttacher)
+	if !ok {
+		return fmt.Errorf("the %s driver can not remove disks from machines", h.DriverName)
+	}
+	if err := a.RemoveDisk(device); err != nil {
+		return errors.Wrap(err, "remove disk")
+	}
+	return api.Save(h)
+}
+
+// Disks returns the devices of the extra disks of a machine which can add and remove them
+func Disks(api libmachine.API, machineName string) ([]string, error) {
+	h, err := LoadHost(api, machineName)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := h.Driver.(DiskAttacher)
+	if !ok {
+		return nil, fmt.Errorf("the %s driver can not list the disks of machines", h.DriverName)
+	}
+	return a.Disks(), nil
+}
+
+// removeDisks removes the extra disks of a machine which is already gone, as they would outlive it otherwise
+func removeDisks(api libmachine.API, h *host.Host) {
+	a, ok := h.Driver.(DiskAttacher)
+	if !ok {
+		return
+	}
+	for _, dev := range a.Disks() {
+		if err := a.RemoveDisk(dev); err != nil {
+			klog.Warningf("failed to remove the extra disk %s of %s: %v", dev, h.Name, err)
+		}
+	}
+	if err := api.Save(h); err != nil {
+		klog.Warningf("save %s: %v", h.Name, err)
+	}
+}
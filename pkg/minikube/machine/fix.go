@@ -60,7 +60,7 @@ func fixHost(api libmachine.API, cc *config.ClusterConfig, n *config.Node) (*hos
 	if err != nil {
 		return h, errors.Wrap(err, "error loading existing host. Please try running [minikube delete], then run [minikube start] again")
 	}
-	defer postStartValidations(h, cc.Driver)
+	defer postStartValidations(h, config.NodeDriver(*cc, *n))
 
 	driverName := h.Driver.DriverName()
 
@@ -73,7 +73,7 @@ func fixHost(api libmachine.API, cc *config.ClusterConfig, n *config.Node) (*hos
 	}
 
 	// Avoid reprovisioning "none" driver because provision.Detect requires SSH
-	if !driver.BareMetal(driverName) {
+	if !driver.BareMetal(driverName) && !runsItself(h) {
 		e := engineOptions(*cc)
 		h.HostOptions.EngineOptions.Env = e.Env
 		err = provisionDockerMachine(h)
@@ -86,10 +86,17 @@ func fixHost(api libmachine.API, cc *config.ClusterConfig, n *config.Node) (*hos
 		return h, nil
 	}
 
-	if err := postStartSetup(h, *cc); err != nil {
+	if err := postStartSetup(h, config.NodeResources(*cc, *n)); err != nil {
 		return h, errors.Wrap(err, "post-start")
 	}
 
+	// the disk may have grown while the machine was stopped, see ResizeDisk
+	if r, ok := h.Driver.(Resizer); ok {
+		if err := growPendingFilesystem(api, h, r); err != nil {
+			klog.Warningf("unable to grow the filesystem: %v", err)
+		}
+	}
+
 	// on vm node restart and for ha (multi-control plane) topology only (for now),
 	// we deliberately aim to restore backed up machine config early,
 	// so that remaining code logic can amend files as needed,
@@ -105,7 +112,8 @@ func fixHost(api libmachine.API, cc *config.ClusterConfig, n *config.Node) (*hos
 
 func recreateIfNeeded(api libmachine.API, cc *config.ClusterConfig, n *config.Node, h *host.Host) (*host.Host, error) {
 	machineName := config.MachineName(*cc, *n)
-	machineType := driver.MachineType(cc.Driver)
+	drv := config.NodeDriver(*cc, *n)
+	machineType := driver.MachineType(drv)
 	recreated := false
 	s, serr := h.Driver.GetState()
 
@@ -118,7 +126,7 @@ func recreateIfNeeded(api libmachine.API, cc *config.ClusterConfig, n *config.No
 		}
 
 		if !me || err == constants.ErrMachineMissing {
-			out.Step(style.Shrug, `{{.driver_name}} "{{.cluster}}" {{.machine_type}} is missing, will recreate.`, out.V{"driver_name": cc.Driver, "cluster": machineName, "machine_type": machineType})
+			out.Step(style.Shrug, `{{.driver_name}} "{{.cluster}}" {{.machine_type}} is missing, will recreate.`, out.V{"driver_name": drv, "cluster": machineName, "machine_type": machineType})
 			demolish(api, *cc, *n, h)
 
 			klog.Infof("Sleeping 1 second for extra luck!")
@@ -141,13 +149,13 @@ func recreateIfNeeded(api libmachine.API, cc *config.ClusterConfig, n *config.No
 	if s == state.Running {
 		if !recreated {
 			register.Reg.SetStep(register.UpdatingDriver)
-			out.Step(style.Running, `Updating the running {{.driver_name}} "{{.cluster}}" {{.machine_type}} ...`, out.V{"driver_name": cc.Driver, "cluster": machineName, "machine_type": machineType})
+			out.Step(style.Running, `Updating the running {{.driver_name}} "{{.cluster}}" {{.machine_type}} ...`, out.V{"driver_name": drv, "cluster": machineName, "machine_type": machineType})
 		}
 		return h, nil
 	}
 
 	if !recreated {
-		out.Step(style.Restarting, `Restarting existing {{.driver_name}} {{.machine_type}} for "{{.cluster}}" ...`, out.V{"driver_name": cc.Driver, "cluster": machineName, "machine_type": machineType})
+		out.Step(style.Restarting, `Restarting existing {{.driver_name}} {{.machine_type}} for "{{.cluster}}" ...`, out.V{"driver_name": drv, "cluster": machineName, "machine_type": machineType})
 	}
 	if err := h.Driver.Start(); err != nil {
 		MaybeDisplayAdvice(err, h.DriverName)
@@ -269,6 +277,9 @@ func machineExists(d string, s state.State, err error) (bool, error) {
 	if s == state.Running || s == state.Stopped {
 		return true, nil
 	}
+	if errors.Is(err, constants.ErrMachineMissing) {
+		return false, constants.ErrMachineMissing
+	}
 	switch d {
 	case driver.HyperKit:
 		return machineExistsMessage(s, err, "connection is shut down")
@@ -0,0 +1,353 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// NodeImageInspect is the inspection result of an image shared by one or more nodes
+type NodeImageInspect struct {
+	Nodes                 []string `json:"nodes" yaml:"nodes"`
+	cruntime.ImageInspect `yaml:",inline"`
+}
+
+// ImageDiff is an image whose ID differs between the nodes of a cluster
+type ImageDiff struct {
+	Image string `json:"image" yaml:"image"`
+	// IDs maps a node name to the ID of the image on that node, empty if it is missing
+	IDs map[string]string `json:"ids" yaml:"ids"`
+}
+
+// nodeRuntimes returns the container runtimes of the running nodes of a cluster, keyed by machine name.
+// If names is not empty, only nodes matching one of the given node or machine names are returned.
+func nodeRuntimes(api libmachine.API, cc *config.ClusterConfig, names []string) ([]string, map[string]cruntime.Manager, error) {
+	order := []string{}
+	runtimes := map[string]cruntime.Manager{}
+	for _, n := range cc.Nodes {
+		m := config.MachineName(*cc, n)
+		if len(names) > 0 && !slices.Contains(names, n.Name) && !slices.Contains(names, m) {
+			continue
+		}
+
+		status, err := Status(api, m)
+		if err != nil {
+			klog.Warningf("error getting status for %s: %v", m, err)
+			continue
+		}
+		if status != state.Running.String() {
+			klog.Infof("skipping %s: not running", m)
+			continue
+		}
+
+		h, err := api.Load(m)
+		if err != nil {
+			klog.Warningf("Failed to load machine %q: %v", m, err)
+			continue
+		}
+		runner, err := CommandRunner(h)
+		if err != nil {
+			return nil, nil, err
+		}
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "error creating container runtime")
+		}
+		order = append(order, m)
+		runtimes[m] = cr
+	}
+	if len(order) == 0 {
+		return nil, nil, fmt.Errorf("no running nodes found in profile %q", cc.Name)
+	}
+	return order, runtimes, nil
+}
+
+// InspectImage prints detailed information about an image on every selected node in profile
+func InspectImage(profile *config.Profile, img string, nodes []string, format string) error {
+	api, err := NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "error creating api client")
+	}
+	defer api.Close()
+
+	c, err := config.Load(profile.Name)
+	if err != nil {
+		klog.Errorf("Failed to load profile %q: %v", profile.Name, err)
+		return errors.Wrapf(err, "error loading config for profile :%v", profile.Name)
+	}
+
+	order, runtimes, err := nodeRuntimes(api, c, nodes)
+	if err != nil {
+		return err
+	}
+
+	results := map[string]*cruntime.ImageInspect{}
+	for _, m := range order {
+		ii, err := runtimes[m].InspectImage(img)
+		if err != nil {
+			klog.Warningf("Failed to inspect image %s on %s: %v", img, m, err)
+			continue
+		}
+		results[m] = ii
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("image %q not found on any node", img)
+	}
+
+	merged := mergeImageInspects(order, results)
+	switch format {
+	case "yaml":
+		b, err := yaml.Marshal(merged)
+		if err != nil {
+			return errors.Wrap(err, "marshal yaml")
+		}
+		fmt.Printf("%s", b)
+	default:
+		b, err := json.MarshalIndent(merged, "", "    ")
+		if err != nil {
+			return errors.Wrap(err, "marshal json")
+		}
+		fmt.Printf("%s\n", b)
+	}
+	return nil
+}
+
+// mergeImageInspects groups the per-node results of an image inspection by image ID,
+// so that nodes holding the same image share one entry listing all of their tags and digests
+func mergeImageInspects(order []string, results map[string]*cruntime.ImageInspect) []NodeImageInspect {
+	merged := []NodeImageInspect{}
+	byID := map[string]int{}
+	for _, m := range order {
+		ii, ok := results[m]
+		if !ok {
+			continue
+		}
+		idx, seen := byID[ii.ID]
+		if !seen {
+			byID[ii.ID] = len(merged)
+			merged = append(merged, NodeImageInspect{Nodes: []string{m}, ImageInspect: *ii})
+			continue
+		}
+		merged[idx].Nodes = append(merged[idx].Nodes, m)
+		merged[idx].RepoTags = appendMissing(merged[idx].RepoTags, ii.RepoTags...)
+		merged[idx].RepoDigests = appendMissing(merged[idx].RepoDigests, ii.RepoDigests...)
+	}
+	return merged
+}
+
+// appendMissing appends the items that are not already in list
+func appendMissing(list []string, items ...string) []string {
+	for _, i := range items {
+		if !slices.Contains(list, i) {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
+// ImageHistory prints the layer history of an image on a single node of profile
+func ImageHistory(profile *config.Profile, img string, nodeName string, format string) error {
+	api, err := NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "error creating api client")
+	}
+	defer api.Close()
+
+	c, err := config.Load(profile.Name)
+	if err != nil {
+		klog.Errorf("Failed to load profile %q: %v", profile.Name, err)
+		return errors.Wrapf(err, "error loading config for profile :%v", profile.Name)
+	}
+
+	// show the history from the primary control-plane node by default
+	names := []string{nodeName}
+	if nodeName == "" {
+		cp, err := config.ControlPlane(*c)
+		if err != nil {
+			return err
+		}
+		names = []string{config.MachineName(*c, cp)}
+	}
+	order, runtimes, err := nodeRuntimes(api, c, names)
+	if err != nil {
+		return err
+	}
+
+	history, err := runtimes[order[0]].ImageHistory(img)
+	if err != nil {
+		return errors.Wrapf(err, "history of %s on %s", img, order[0])
+	}
+
+	switch format {
+	case "json":
+		b, err := json.Marshal(history)
+		if err != nil {
+			return errors.Wrap(err, "marshal json")
+		}
+		fmt.Printf("%s\n", b)
+	case "yaml":
+		b, err := yaml.Marshal(history)
+		if err != nil {
+			return errors.Wrap(err, "marshal yaml")
+		}
+		fmt.Printf("%s", b)
+	default:
+		var data [][]string
+		for _, h := range history {
+			id := parseImageID(h.ID)
+			if id == "" {
+				id = "<missing>"
+			}
+			data = append(data, []string{id, h.Created, h.CreatedBy, humanImageSize(h.Size), h.Comment})
+		}
+		renderTable([]string{"Image ID", "Created", "Created By", "Size", "Comment"}, data)
+	}
+	return nil
+}
+
+// DiffImages prints the images whose IDs differ between the selected nodes of profile
+func DiffImages(profile *config.Profile, nodes []string, format string) error {
+	api, err := NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "error creating api client")
+	}
+	defer api.Close()
+
+	c, err := config.Load(profile.Name)
+	if err != nil {
+		klog.Errorf("Failed to load profile %q: %v", profile.Name, err)
+		return errors.Wrapf(err, "error loading config for profile :%v", profile.Name)
+	}
+
+	order, runtimes, err := nodeRuntimes(api, c, nodes)
+	if err != nil {
+		return err
+	}
+	if len(order) < 2 {
+		return fmt.Errorf("at least two running nodes are required to compare images, found %d", len(order))
+	}
+
+	lists := map[string][]cruntime.ListImage{}
+	for _, m := range order {
+		list, err := runtimes[m].ListImages(cruntime.ListImagesOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "list images on %s", m)
+		}
+		lists[m] = list
+	}
+
+	diffs := diffImageLists(order, lists)
+	switch format {
+	case "json":
+		b, err := json.Marshal(diffs)
+		if err != nil {
+			return errors.Wrap(err, "marshal json")
+		}
+		fmt.Printf("%s\n", b)
+	case "yaml":
+		b, err := yaml.Marshal(diffs)
+		if err != nil {
+			return errors.Wrap(err, "marshal yaml")
+		}
+		fmt.Printf("%s", b)
+	default:
+		if len(diffs) == 0 {
+			out.Styled(style.Check, "All {{.count}} nodes have the same images", out.V{"count": len(order)})
+			return nil
+		}
+		var data [][]string
+		for _, d := range diffs {
+			row := []string{d.Image}
+			for _, m := range order {
+				id := parseImageID(d.IDs[m])
+				if id == "" {
+					id = "<missing>"
+				}
+				row = append(row, id)
+			}
+			data = append(data, row)
+		}
+		renderTable(append([]string{"Image"}, order...), data)
+	}
+	return nil
+}
+
+// diffImageLists compares image lists from different nodes by tag,
+// returning the tags which are missing on some nodes or point at different image IDs
+func diffImageLists(order []string, lists map[string][]cruntime.ListImage) []ImageDiff {
+	ids := map[string]map[string]string{}
+	for _, m := range order {
+		for _, img := range lists[m] {
+			for _, tag := range img.RepoTags {
+				if _, ok := ids[tag]; !ok {
+					ids[tag] = map[string]string{}
+				}
+				ids[tag][m] = img.ID
+			}
+		}
+	}
+
+	diffs := []ImageDiff{}
+	for tag, perNode := range ids {
+		same := len(perNode) == len(order)
+		for _, m := range order {
+			if perNode[m] != perNode[order[0]] {
+				same = false
+			}
+		}
+		if same {
+			continue
+		}
+		d := ImageDiff{Image: tag, IDs: map[string]string{}}
+		for _, m := range order {
+			d.IDs[m] = perNode[m]
+		}
+		diffs = append(diffs, d)
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Image < diffs[j].Image
+	})
+	return diffs
+}
+
+// renderTable renders a pretty table with the given header
+func renderTable(header []string, data [][]string) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+}
@@ -27,6 +27,7 @@ import (
 	libprovision "github.com/docker/machine/libmachine/provision"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/qemu"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/vmpath"
@@ -34,6 +35,11 @@ import (
 	"k8s.io/minikube/pkg/util/retry"
 )
 
+// PortForwarder is implemented by drivers which forward ports of the host to their running machines
+type PortForwarder interface {
+	ForwardPort(hostPort, guestPort int) error
+}
+
 // Machine contains information about a machine
 type Machine struct {
 	*host.Host
@@ -122,7 +128,7 @@ func provisionDockerMachine(h *host.Host) error {
 func fastDetectProvisioner(h *host.Host) (libprovision.Provisioner, error) {
 	d := h.Driver.DriverName()
 	switch {
-	case driver.IsKIC(d):
+	case driver.IsKIC(d), driver.IsNspawn(d):
 		return provision.NewUbuntuProvisioner(h.Driver), nil
 	case driver.BareMetal(d), driver.IsSSH(d):
 		return libprovision.DetectProvisioner(h.Driver)
@@ -144,6 +150,10 @@ func saveHost(api libmachine.API, h *host.Host, cfg *config.ClusterConfig, n *co
 	}
 	if ip == "127.0.0.1" && driver.IsQEMU(h.Driver.DriverName()) {
 		ip = "10.0.2.15"
+		// the other nodes reach the node on the network they share
+		if d, ok := h.Driver.(*qemu.Driver); ok && d.SharedNetworkIP != "" {
+			ip = d.SharedNetworkIP
+		}
 	}
 	n.IP = ip
 	return config.SaveNode(cfg, n)
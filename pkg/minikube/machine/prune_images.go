@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+)
+
+// PruneImages removes unused images from the primary control-plane node, the given node, or all nodes in profile
+func PruneImages(profile *config.Profile, allNodes bool, nodeName string, o cruntime.PruneImagesOptions) error {
+	api, err := NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "error creating api client")
+	}
+	defer api.Close()
+
+	c, err := config.Load(profile.Name)
+	if err != nil {
+		klog.Errorf("Failed to load profile %q: %v", profile.Name, err)
+		return errors.Wrapf(err, "error loading config for profile :%v", profile.Name)
+	}
+
+	var names []string
+	if !allNodes {
+		// prune images on the control-plane node by default
+		names = []string{nodeName}
+		if nodeName == "" {
+			cp, err := config.ControlPlane(*c)
+			if err != nil {
+				return err
+			}
+			names = []string{config.MachineName(*c, cp)}
+		}
+	}
+	order, runtimes, err := nodeRuntimes(api, c, names)
+	if err != nil {
+		return err
+	}
+
+	o.Keep = append(o.Keep, keepImages(c)...)
+	failed := []string{}
+	for _, m := range order {
+		removed, err := runtimes[m].PruneImages(o)
+		if err != nil {
+			failed = append(failed, m)
+			klog.Warningf("Failed to prune images on %s: %v", m, err)
+			out.WarningT("Failed to prune images on {{.node}}: {{.error}}", out.V{"node": m, "error": err})
+			continue
+		}
+		out.Styled(style.Deleted, "Removed {{.count}} unused images from {{.node}}", out.V{"count": len(removed), "node": m})
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("failed pruning images on: %s", strings.Join(failed, " "))
+	}
+	return nil
+}
+
+// keepImages returns the images a prune leaves on the nodes of a cluster even when no container uses them: the pause
+// image and the rest of the images kubeadm needs, which the preload put there and would otherwise be pulled again
+func keepImages(cc *config.ClusterConfig) []string {
+	k8s := cc.KubernetesConfig
+	keep, err := images.Kubeadm(k8s.ImageRepository, k8s.KubernetesVersion)
+	if err != nil {
+		klog.Warningf("unable to list the kubeadm images of %s: %v", k8s.KubernetesVersion, err)
+	}
+	if v, err := util.ParseKubernetesVersion(k8s.KubernetesVersion); err == nil {
+		keep = append(keep, images.Pause(v, k8s.ImageRepository))
+	}
+	return keep
+}
+
+// imageGCInterval is the least time between two image garbage collections of a node, which status checks may trigger on every poll
+const imageGCInterval = 10 * time.Minute
+
+// ImageGCNeeded returns whether the /var usage of a node is above the image garbage collection threshold of the cluster
+func ImageGCNeeded(cc config.ClusterConfig, used int) bool {
+	return cc.ImageGCThreshold > 0 && used >= cc.ImageGCThreshold
+}
+
+// ImageGCDue returns whether the image garbage collection of a node is due, recording it if so. The node stays above the
+// threshold on every status poll until a prune freed the space, which must not start another prune each time.
+func ImageGCDue(cc config.ClusterConfig, machineName string) bool {
+	stamp := filepath.Join(config.ProfileFolderPath(cc.Name), "image-gc-"+machineName)
+	if fi, err := os.Stat(stamp); err == nil && time.Since(fi.ModTime()) < imageGCInterval {
+		return false
+	}
+	if err := os.WriteFile(stamp, nil, 0600); err != nil {
+		klog.Warningf("unable to record the image garbage collection of %s: %v", machineName, err)
+		return false
+	}
+	return true
+}
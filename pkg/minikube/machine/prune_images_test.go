@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"os"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestImageGCNeeded(t *testing.T) {
+	cases := []struct {
+		threshold int
+		used      int
+		want      bool
+	}{
+		{threshold: 0, used: 99, want: false},
+		{threshold: 80, used: 79, want: false},
+		{threshold: 80, used: 80, want: true},
+		{threshold: 80, used: 95, want: true},
+	}
+	for _, tc := range cases {
+		cc := config.ClusterConfig{ImageGCThreshold: tc.threshold}
+		if got := ImageGCNeeded(cc, tc.used); got != tc.want {
+			t.Errorf("ImageGCNeeded(threshold=%d, used=%d) = %v, want %v", tc.threshold, tc.used, got, tc.want)
+		}
+	}
+}
+
+func TestImageGCDue(t *testing.T) {
+	tests.MakeTempDir(t)
+
+	cc := config.ClusterConfig{Name: "gc"}
+	if err := os.MkdirAll(config.ProfileFolderPath(cc.Name), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if !ImageGCDue(cc, "gc") {
+		t.Errorf("expected the first image garbage collection of gc to be due")
+	}
+	if ImageGCDue(cc, "gc") {
+		t.Errorf("expected no image garbage collection of gc to be due right after the last one")
+	}
+	if !ImageGCDue(cc, "gc-m02") {
+		t.Errorf("expected the image garbage collection of gc-m02 to be due regardless of gc")
+	}
+}
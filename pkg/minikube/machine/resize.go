@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// Resizer is implemented by the drivers which can change the resources of a machine after its creation
+type Resizer interface {
+	// ResizeDisk grows the disk of the machine to sizeMB
+	ResizeDisk(sizeMB int) error
+	// SetMemory sets the memory of the machine to sizeMB, at most the memory it was created with
+	SetMemory(sizeMB int) error
+	// FilesystemGrowPending returns whether the disk grew since its filesystem last did
+	FilesystemGrowPending() bool
+	// FilesystemGrown records that the filesystem fills the disk again
+	FilesystemGrown()
+}
+
+// Snapshotter is implemented by the drivers which can save the state of a machine, to bring it back later
+type Snapshotter interface {
+	// Snapshot saves the state of the machine under a name
+	Snapshot(name string) error
+	// RestoreSnapshot brings the machine back to a saved state
+	RestoreSnapshot(name string) error
+	// DeleteSnapshot deletes a saved state
+	DeleteSnapshot(name string) error
+	// Snapshots returns the names of the saved states, oldest first
+	Snapshots() ([]string, error)
+}
+
+// dataPartition matches the device of the data partition of the minikube ISO, which is the only one of its disk
+var dataPartition = regexp.MustCompile(`^(/dev/[a-z]+)([0-9]+)$`)
+
+// ResizeDisk grows the disk of a machine to sizeMB, and the filesystem on it if the machine runs.
+// The filesystem of a machine which is not running grows at its next start.
+func ResizeDisk(api libmachine.API, machineName string, sizeMB int) error {
+	h, err := LoadHost(api, machineName)
+	if err != nil {
+		return err
+	}
+	r, ok := h.Driver.(Resizer)
+	if !ok {
+		return fmt.Errorf("the %s driver can not resize machines", h.DriverName)
+	}
+	if err := r.ResizeDisk(sizeMB); err != nil {
+		return errors.Wrap(err, "resize disk")
+	}
+	if err := api.Save(h); err != nil {
+		return errors.Wrap(err, "save")
+	}
+
+	s, err := h.Driver.GetState()
+	if err != nil || s != state.Running {
+		return err
+	}
+	return growPendingFilesystem(api, h, r)
+}
+
+// growPendingFilesystem grows the filesystem of a running machine if its disk grew since it last did
+func growPendingFilesystem(api libmachine.API, h *host.Host, r Resizer) error {
+	if !r.FilesystemGrowPending() {
+		return nil
+	}
+	cr, err := CommandRunner(h)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+	if err := growFilesystem(cr); err != nil {
+		return err
+	}
+	r.FilesystemGrown()
+	return api.Save(h)
+}
+
+// SetMemory sets the memory of a machine to sizeMB
+func SetMemory(api libmachine.API, machineName string, sizeMB int) error {
+	h, err := LoadHost(api, machineName)
+	if err != nil {
+		return err
+	}
+	r, ok := h.Driver.(Resizer)
+	if !ok {
+		return fmt.Errorf("the %s driver can not resize machines", h.DriverName)
+	}
+	if err := r.SetMemory(sizeMB); err != nil {
+		return errors.Wrap(err, "set memory")
+	}
+	return api.Save(h)
+}
+
+// LoadSnapshotter returns the driver of a machine, if it can snapshot it
+func LoadSnapshotter(api libmachine.API, machineName string) (Snapshotter, error) {
+	h, err := LoadHost(api, machineName)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := h.Driver.(Snapshotter)
+	if !ok {
+		return nil, fmt.Errorf("the %s driver can not snapshot machines", h.DriverName)
+	}
+	return s, nil
+}
+
+// growFilesystem grows the data partition of a machine and its filesystem to the end of the disk,
+// which they already span unless the disk was resized
+func growFilesystem(cr command.Runner) error {
+	rr, err := cr.RunCmd(exec.Command("sudo", "blkid", "-o", "device", "-l", "-t", "LABEL=boot2docker-data"))
+	if err != nil {
+		return errors.Wrap(err, "data partition")
+	}
+	part := strings.TrimSpace(rr.Stdout.String())
+	m := dataPartition.FindStringSubmatch(part)
+	if m == nil {
+		return fmt.Errorf("unexpected data partition %q", part)
+	}
+	klog.Infof("growing %s to the end of %s", part, m[1])
+
+	// sfdisk also moves the backup GPT header to the new end of the disk
+	c := exec.Command("sudo", "sfdisk", "--no-reread", "--no-tell-kernel", "-N", m[2], m[1])
+	c.Stdin = strings.NewReader(", +\n")
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "grow partition")
+	}
+	if _, err := cr.RunCmd(exec.Command("sudo", "partprobe", m[1])); err != nil {
+		return errors.Wrap(err, "partprobe")
+	}
+	if _, err := cr.RunCmd(exec.Command("sudo", "resize2fs", part)); err != nil {
+		return errors.Wrap(err, "grow filesystem")
+	}
+	return nil
+}
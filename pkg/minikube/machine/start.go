@@ -72,9 +72,10 @@ var requiredDirectories = []string{
 // StartHost starts a host VM.
 func StartHost(api libmachine.API, cfg *config.ClusterConfig, n *config.Node) (*host.Host, bool, error) {
 	machineName := config.MachineName(*cfg, *n)
+	drv := config.NodeDriver(*cfg, *n)
 
 	// Prevent machine-driver boot races, as well as our own certificate race
-	releaser, err := acquireMachinesLock(machineName, cfg.Driver)
+	releaser, err := acquireMachinesLock(machineName, drv)
 	if err != nil {
 		return nil, false, errors.Wrap(err, "boot lock")
 	}
@@ -99,7 +100,7 @@ func StartHost(api libmachine.API, cfg *config.ClusterConfig, n *config.Node) (*
 	if err != nil {
 		return h, exists, err
 	}
-	return h, exists, ensureSyncedGuestClock(h, cfg.Driver)
+	return h, exists, ensureSyncedGuestClock(h, drv)
 }
 
 // engineOptions returns docker engine options for the dockerd running inside minikube
@@ -122,21 +123,23 @@ func engineOptions(cfg config.ClusterConfig) *engine.Options {
 }
 
 func createHost(api libmachine.API, cfg *config.ClusterConfig, n *config.Node) (*host.Host, error) {
-	klog.Infof("createHost starting for %q (driver=%q)", n.Name, cfg.Driver)
+	klog.Infof("createHost starting for %q (driver=%q)", n.Name, config.NodeDriver(*cfg, *n))
 	start := time.Now()
 	defer func() {
 		klog.Infof("duration metric: took %s to createHost", time.Since(start))
 	}()
 
-	if cfg.Driver != driver.SSH {
-		showHostInfo(nil, *cfg)
+	// nodes of a node pool, or added with another driver, may have other resources and driver than the cluster-wide ones
+	nodeCfg := config.NodeResources(*cfg, *n)
+	if nodeCfg.Driver != driver.SSH {
+		showHostInfo(nil, nodeCfg)
 	}
 
-	def := registry.Driver(cfg.Driver)
+	def := registry.Driver(nodeCfg.Driver)
 	if def.Empty() {
-		return nil, fmt.Errorf("unsupported/missing driver: %s", cfg.Driver)
+		return nil, fmt.Errorf("unsupported/missing driver: %s", nodeCfg.Driver)
 	}
-	dd, err := def.Config(*cfg, *n)
+	dd, err := def.Config(nodeCfg, *n)
 	if err != nil {
 		return nil, errors.Wrap(err, "config")
 	}
@@ -145,18 +148,18 @@ func createHost(api libmachine.API, cfg *config.ClusterConfig, n *config.Node) (
 		return nil, errors.Wrap(err, "marshal")
 	}
 
-	h, err := api.NewHost(cfg.Driver, data)
+	h, err := api.NewHost(nodeCfg.Driver, data)
 	if err != nil {
 		return nil, errors.Wrap(err, "new host")
 	}
-	defer postStartValidations(h, cfg.Driver)
+	defer postStartValidations(h, nodeCfg.Driver)
 
 	h.HostOptions.AuthOptions.CertDir = localpath.MiniPath()
 	h.HostOptions.AuthOptions.StorePath = localpath.MiniPath()
 	h.HostOptions.EngineOptions = engineOptions(*cfg)
 
 	cstart := time.Now()
-	klog.Infof("libmachine.API.Create for %q (driver=%q)", cfg.Name, cfg.Driver)
+	klog.Infof("libmachine.API.Create for %q (driver=%q)", cfg.Name, nodeCfg.Driver)
 
 	if cfg.StartHostTimeout == 0 {
 		cfg.StartHostTimeout = 6 * time.Minute
@@ -165,11 +168,11 @@ func createHost(api libmachine.API, cfg *config.ClusterConfig, n *config.Node) (
 		return nil, errors.Wrap(err, "creating host")
 	}
 	klog.Infof("duration metric: took %s to libmachine.API.Create %q", time.Since(cstart), cfg.Name)
-	if cfg.Driver == driver.SSH {
-		showHostInfo(h, *cfg)
+	if nodeCfg.Driver == driver.SSH {
+		showHostInfo(h, nodeCfg)
 	}
 
-	if err := postStartSetup(h, *cfg); err != nil {
+	if err := postStartSetup(h, nodeCfg); err != nil {
 		return h, errors.Wrap(err, "post-start")
 	}
 
@@ -335,7 +338,7 @@ func postStartSetup(h *host.Host, mc config.ClusterConfig) error {
 		showLocalOsRelease()
 	}
 
-	if driver.IsVM(mc.Driver) || driver.IsKIC(mc.Driver) || driver.IsSSH(mc.Driver) {
+	if driver.IsVM(mc.Driver) || driver.IsKIC(mc.Driver) || driver.IsSSH(mc.Driver) || driver.IsNspawn(mc.Driver) {
 		logRemoteOsRelease(r)
 	}
 
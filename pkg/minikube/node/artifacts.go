@@ -0,0 +1,314 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"os"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/cni"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/detect"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/image"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/preload"
+)
+
+// Kinds of artifacts needed to start a cluster
+const (
+	ArtifactISO     = "iso"
+	ArtifactKicBase = "kicbase"
+	ArtifactPreload = "preload"
+	ArtifactBinary  = "binary"
+	ArtifactImage   = "image"
+)
+
+// Artifact is a file which has to be on the host to start a cluster without network access
+type Artifact struct {
+	Kind string
+	// Name identifies the artifact, such as an image reference or binary name
+	Name string
+	// Path is where minikube looks for the artifact
+	Path string
+	// fetch downloads the artifact to Path
+	fetch func() error
+}
+
+// Cached returns whether the artifact exists on the host
+func (a Artifact) Cached() bool {
+	st, err := os.Stat(a.Path)
+	return err == nil && st.Size() > 0
+}
+
+// ArtifactOptions are the settings besides the cluster config deciding which artifacts are needed
+type ArtifactOptions struct {
+	// ISOURLs are the ISOs to choose from, in order of preference
+	ISOURLs []string
+	// Preload is whether the preloaded images tarball is used where available
+	Preload bool
+	// Addons are the names of the addons to enable
+	Addons []string
+}
+
+// RequiredArtifacts returns every artifact needed to start a cluster for cc, CNI manifests aside,
+// which are built into minikube
+func RequiredArtifacts(cc *config.ClusterConfig, opts ArtifactOptions) ([]Artifact, error) {
+	arts := []Artifact{}
+
+	if driver.IsVM(cc.Driver) && !driver.IsSSH(cc.Driver) && len(opts.ISOURLs) > 0 {
+		iso := Artifact{Kind: ArtifactISO, Name: opts.ISOURLs[0], Path: download.ISOPath(opts.ISOURLs[0])}
+		// any of the ISOs will do, prefer one which is already cached
+		for _, u := range opts.ISOURLs {
+			if a := (Artifact{Path: download.ISOPath(u)}); a.Cached() {
+				iso = Artifact{Kind: ArtifactISO, Name: u, Path: a.Path}
+				break
+			}
+		}
+		urls := opts.ISOURLs
+		iso.fetch = func() error {
+			_, err := download.ISO(urls, false)
+			return err
+		}
+		arts = append(arts, iso)
+	}
+
+	if driver.IsKIC(cc.Driver) {
+		baseImg := cc.KicBaseImage
+		if baseImg == "" {
+			baseImg = kic.BaseImage
+		}
+		arts = append(arts, Artifact{
+			Kind:  ArtifactKicBase,
+			Name:  baseImg,
+			Path:  download.ImageCachePath(baseImg),
+			fetch: func() error { return download.ImageToCache(baseImg) },
+		})
+	}
+
+	k8s := cc.KubernetesConfig
+	if k8s.KubernetesVersion == constants.NoKubernetesVersion {
+		return arts, nil
+	}
+
+	// images which do not need to be cached individually, because they are part of the preload
+	preloaded := []string{}
+	arch := config.GuestArch(*cc)
+	if cc.Bootstrapper == bootstrapper.K3s {
+		// k3s is a single binary, and the tarball of its images is its preload
+		arts = append(arts, Artifact{
+			Kind:  ArtifactBinary,
+			Name:  "k3s",
+			Path:  download.K3sBinaryPath(k8s.KubernetesVersion, arch),
+			fetch: func() error { _, err := download.K3sBinary(k8s.KubernetesVersion, arch); return err },
+		}, Artifact{
+			Kind:  ArtifactPreload,
+			Name:  download.K3sImagesName(arch),
+			Path:  download.K3sImagesPath(k8s.KubernetesVersion, arch),
+			fetch: func() error { _, err := download.K3sImages(k8s.KubernetesVersion, arch); return err },
+		})
+	} else if opts.Preload && driver.AllowsPreload(cc.Driver) && arch == runtime.GOARCH && k8s.ImageRepository == "" { // TODO: remove imageRepository check once #7695 is fixed
+		arts = append(arts, Artifact{
+			Kind:  ArtifactPreload,
+			Name:  download.TarballName(k8s.KubernetesVersion, k8s.ContainerRuntime),
+			Path:  download.TarballPath(k8s.KubernetesVersion, k8s.ContainerRuntime),
+			fetch: func() error { return download.Preload(k8s.KubernetesVersion, k8s.ContainerRuntime, cc.Driver) },
+		})
+		imgs, err := preload.Images(preload.BuildConfig{KubernetesVersion: k8s.KubernetesVersion, ContainerRuntime: k8s.ContainerRuntime})
+		if err != nil {
+			return nil, err
+		}
+		preloaded = imgs
+	} else {
+		for _, b := range constants.KubernetesReleaseBinaries {
+			b := b
+			arts = append(arts, Artifact{
+				Kind: ArtifactBinary,
+				Name: b,
+				Path: download.BinaryPath(b, k8s.KubernetesVersion, "linux", arch),
+				fetch: func() error {
+					_, err := download.Binary(b, k8s.KubernetesVersion, "linux", arch, cc.BinaryMirror)
+					return err
+				},
+			})
+		}
+		imgs, err := images.Kubeadm(k8s.ImageRepository, k8s.KubernetesVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, "kubeadm images")
+		}
+		arts = appendImageArtifacts(arts, imgs, preloaded)
+	}
+
+	imgs, err := cni.Images(*cc)
+	if err != nil {
+		return nil, errors.Wrap(err, "cni images")
+	}
+	arts = appendImageArtifacts(arts, imgs, preloaded)
+
+	imgs, err = addonImages(cc, opts.Addons)
+	if err != nil {
+		return nil, err
+	}
+	return appendImageArtifacts(arts, imgs, preloaded), nil
+}
+
+// OfflineArtifacts returns the artifacts needed by 'minikube start' for cc, according to its flags.
+// existingAddons is nil if addons are not installed.
+func OfflineArtifacts(cc *config.ClusterConfig, existingAddons map[string]bool) ([]Artifact, error) {
+	opts := ArtifactOptions{
+		ISOURLs: viper.GetStringSlice("iso-url"),
+		Preload: viper.GetBool("preload"),
+	}
+	if existingAddons != nil {
+		opts.Addons = EnabledAddons(addons.ToEnable(cc, existingAddons, viper.GetStringSlice(config.AddonListFlag)))
+	}
+	return RequiredArtifacts(cc, opts)
+}
+
+// EnabledAddons returns the names of the enabled addons in toEnable
+func EnabledAddons(toEnable map[string]bool) []string {
+	names := []string{}
+	for name, enabled := range toEnable {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadOfflineImages loads the cached images of the CNI and addons into the node, as registries cannot be reached offline
+func loadOfflineImages(starter Starter) error {
+	arts, err := OfflineArtifacts(starter.Cfg, starter.ExistingAddons)
+	if err != nil {
+		return err
+	}
+	imgs := ArtifactImages(arts)
+	if len(imgs) == 0 {
+		return nil
+	}
+	return machine.LoadCachedImages(starter.Cfg, starter.Runner, imgs, detect.ImageCacheDir(), false)
+}
+
+// appendImageArtifacts appends an artifact for each image which is not in the skip list or already added
+func appendImageArtifacts(arts []Artifact, imgs []string, skip []string) []Artifact {
+	for _, img := range imgs {
+		if slices.Contains(skip, img) || slices.ContainsFunc(arts, func(a Artifact) bool { return a.Kind == ArtifactImage && a.Name == img }) {
+			continue
+		}
+		img := img
+		arts = append(arts, Artifact{
+			Kind:  ArtifactImage,
+			Name:  img,
+			Path:  image.CachePath(img),
+			fetch: func() error { return image.SaveToDir([]string{img}, detect.ImageCacheDir(), false) },
+		})
+	}
+	return arts
+}
+
+// addonImages returns the images deployed by the named addons, using the registries they are pulled from
+func addonImages(cc *config.ClusterConfig, addonNames []string) ([]string, error) {
+	imgs := []string{}
+	for _, name := range addonNames {
+		addon, ok := assets.Addons[name]
+		if !ok {
+			klog.Warningf("unknown addon %q", name)
+			continue
+		}
+		addonImgs, customRegistries, err := assets.SelectAndPersistImages(addon, cc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s addon images", name)
+		}
+		keys := make([]string, 0, len(addonImgs))
+		for k := range addonImgs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			imgs = append(imgs, addonImageRef(k, addonImgs[k], addon.Registries, customRegistries, cc))
+		}
+	}
+	return imgs, nil
+}
+
+// addonImageRef returns the full reference of an addon image, resolving its registry the same way the addon templates do
+func addonImageRef(name, img string, registries, customRegistries map[string]string, cc *config.ClusterConfig) string {
+	registry := registries[name]
+	if _, ok := cc.CustomAddonImages[name]; ok {
+		registry = ""
+	}
+	if cc.KubernetesConfig.ImageRepository != "" {
+		registry = cc.KubernetesConfig.ImageRepository
+	}
+	if r, ok := customRegistries[name]; ok {
+		registry = r
+	}
+	if registry == "" {
+		return img
+	}
+	return strings.TrimSuffix(registry, "/") + "/" + img
+}
+
+// MissingArtifacts returns the artifacts which are not on the host
+func MissingArtifacts(arts []Artifact) []Artifact {
+	missing := []Artifact{}
+	for _, a := range arts {
+		if !a.Cached() {
+			missing = append(missing, a)
+		}
+	}
+	return missing
+}
+
+// CacheArtifacts downloads the artifacts which are not on the host yet
+func CacheArtifacts(arts []Artifact) error {
+	for _, a := range MissingArtifacts(arts) {
+		if a.fetch == nil {
+			continue
+		}
+		klog.Infof("caching %s %s", a.Kind, a.Name)
+		if err := a.fetch(); err != nil {
+			return errors.Wrapf(err, "caching %s %s", a.Kind, a.Name)
+		}
+	}
+	return nil
+}
+
+// ArtifactImages returns the images among arts, which have to be loaded into the nodes when offline
+func ArtifactImages(arts []Artifact) []string {
+	imgs := []string{}
+	for _, a := range arts {
+		if a.Kind == ArtifactImage {
+			imgs = append(imgs, a.Name)
+		}
+	}
+	return imgs
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestAddonImageRef(t *testing.T) {
+	registries := map[string]string{"Dashboard": "docker.io"}
+	tests := []struct {
+		name             string
+		cc               config.ClusterConfig
+		customRegistries map[string]string
+		want             string
+	}{
+		{
+			name: "default registry",
+			want: "docker.io/kubernetesui/dashboard:v2",
+		},
+		{
+			name: "global image repository",
+			cc:   config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{ImageRepository: "mirror.example.com/"}},
+			want: "mirror.example.com/kubernetesui/dashboard:v2",
+		},
+		{
+			name:             "custom registry",
+			cc:               config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{ImageRepository: "mirror.example.com"}},
+			customRegistries: map[string]string{"Dashboard": "registry.example.com"},
+			want:             "registry.example.com/kubernetesui/dashboard:v2",
+		},
+		{
+			name: "custom image",
+			cc:   config.ClusterConfig{CustomAddonImages: map[string]string{"Dashboard": "kubernetesui/dashboard:v2"}},
+			want: "kubernetesui/dashboard:v2",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := addonImageRef("Dashboard", "kubernetesui/dashboard:v2", registries, tc.customRegistries, &tc.cc)
+			if got != tc.want {
+				t.Errorf("addonImageRef() = %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRequiredArtifacts(t *testing.T) {
+	t.Setenv(localpath.MinikubeHome, t.TempDir())
+
+	cc := &config.ClusterConfig{
+		Driver: "docker",
+		KubernetesConfig: config.KubernetesConfig{
+			KubernetesVersion: "v1.30.0",
+			ContainerRuntime:  "containerd",
+		},
+	}
+
+	arts, err := RequiredArtifacts(cc, ArtifactOptions{Preload: true})
+	if err != nil {
+		t.Fatalf("RequiredArtifacts() unexpected error: %v", err)
+	}
+	kinds := map[string]int{}
+	for _, a := range arts {
+		kinds[a.Kind]++
+	}
+	// the kindnet image deployed for containerd is part of the preload
+	want := map[string]int{ArtifactKicBase: 1, ArtifactPreload: 1}
+	for k, n := range want {
+		if kinds[k] != n {
+			t.Errorf("expected %d %s artifacts, got %d: %+v", n, k, kinds[k], arts)
+		}
+	}
+	if kinds[ArtifactImage] != 0 || kinds[ArtifactBinary] != 0 {
+		t.Errorf("expected no image or binary artifacts with a preload, got %+v", arts)
+	}
+
+	arts, err = RequiredArtifacts(cc, ArtifactOptions{Preload: false})
+	if err != nil {
+		t.Fatalf("RequiredArtifacts() unexpected error: %v", err)
+	}
+	kinds = map[string]int{}
+	for _, a := range arts {
+		kinds[a.Kind]++
+	}
+	if kinds[ArtifactPreload] != 0 || kinds[ArtifactBinary] != 3 || kinds[ArtifactImage] == 0 {
+		t.Errorf("expected binaries and images instead of a preload, got %+v", arts)
+	}
+
+	missing := MissingArtifacts(arts)
+	if len(missing) != len(arts) {
+		t.Errorf("expected all %d artifacts to be missing, got %d", len(arts), len(missing))
+	}
+	if err := os.MkdirAll(filepath.Dir(arts[0].Path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(arts[0].Path, []byte("cached"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := MissingArtifacts(arts); len(got) != len(arts)-1 {
+		t.Errorf("expected %d missing artifacts after caching %s, got %d", len(arts)-1, arts[0].Name, len(got))
+	}
+}
+
+func TestRequiredArtifactsK3s(t *testing.T) {
+	t.Setenv(localpath.MinikubeHome, t.TempDir())
+
+	cc := &config.ClusterConfig{
+		Driver:       "docker",
+		Bootstrapper: bootstrapper.K3s,
+		KubernetesConfig: config.KubernetesConfig{
+			KubernetesVersion: "v1.30.0",
+			ContainerRuntime:  "containerd",
+		},
+	}
+
+	arts, err := RequiredArtifacts(cc, ArtifactOptions{Preload: true})
+	if err != nil {
+		t.Fatalf("RequiredArtifacts() unexpected error: %v", err)
+	}
+	names := map[string]string{}
+	for _, a := range arts {
+		if a.Kind == ArtifactBinary || a.Kind == ArtifactPreload {
+			names[a.Kind] = a.Name
+		}
+	}
+	// the k3s binary and images take the place of the kubeadm binaries and preload
+	if names[ArtifactBinary] != "k3s" || !strings.HasPrefix(names[ArtifactPreload], "k3s-airgap-images-") {
+		t.Errorf("expected the k3s binary and images, got %+v", arts)
+	}
+}
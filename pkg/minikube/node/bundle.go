@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// WriteBundle writes the cached artifacts to a gzipped tarball at dst, with paths relative to the minikube home
+// directory, so that extracting it into the minikube home directory of another host fills its cache
+func WriteBundle(arts []Artifact, dst string) ([]string, error) {
+	home := localpath.MiniPath()
+	files := []string{}
+	for _, a := range arts {
+		rel, err := filepath.Rel(home, a.Path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			klog.Warningf("skipping %s %s: %s is not in %s", a.Kind, a.Name, a.Path, home)
+			continue
+		}
+		files = append(files, rel)
+		// the checksum saved next to the preload is used to verify it
+		if a.Kind == ArtifactPreload {
+			if _, err := os.Stat(a.Path + ".checksum"); err == nil {
+				files = append(files, rel+".checksum")
+			}
+		}
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return nil, errors.Wrap(err, "create")
+	}
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for _, rel := range files {
+		if err := addToTar(tw, filepath.Join(home, rel), filepath.ToSlash(rel)); err != nil {
+			f.Close()
+			os.Remove(dst)
+			return nil, errors.Wrapf(err, "adding %s", rel)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "tar")
+	}
+	if err := gw.Close(); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "gzip")
+	}
+	return files, f.Close()
+}
+
+// addToTar adds the file at path to tw under name
+func addToTar(tw *tar.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(st, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
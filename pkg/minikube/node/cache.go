@@ -30,6 +30,7 @@ import (
 	"golang.org/x/sync/errgroup"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/download"
@@ -71,15 +72,37 @@ func beginCacheKubernetesImages(g *errgroup.Group, imageRepository string, k8sVe
 	})
 }
 
+// beginCacheK3s downloads the k3s binary and images in the background, which take the place of the kubeadm binaries and preload
+func beginCacheK3s(g *errgroup.Group, k8sVersion, arch string) {
+	g.Go(func() error {
+		return cacheK3s(k8sVersion, arch)
+	})
+}
+
+// cacheK3s downloads the k3s binary and images for a Kubernetes version and architecture
+func cacheK3s(k8sVersion, arch string) error {
+	if _, err := download.K3sBinary(k8sVersion, arch); err != nil {
+		return errors.Wrap(err, "k3s binary")
+	}
+	if _, err := download.K3sImages(k8sVersion, arch); err != nil {
+		return errors.Wrap(err, "k3s images")
+	}
+	return nil
+}
+
 // handleDownloadOnly caches appropariate binaries and images
-func handleDownloadOnly(cacheGroup, kicGroup *errgroup.Group, k8sVersion, containerRuntime, driverName string) {
+func handleDownloadOnly(cacheGroup, kicGroup *errgroup.Group, k8sVersion, containerRuntime, driverName, bsName string) {
 	// If --download-only, complete the remaining downloads and exit.
 	if !viper.GetBool("download-only") {
 		return
 	}
 
 	binariesURL := viper.GetString("binary-mirror")
-	if err := doCacheBinaries(k8sVersion, containerRuntime, driverName, binariesURL); err != nil {
+	if bsName == bootstrapper.K3s {
+		if err := cacheK3s(k8sVersion, runtime.GOARCH); err != nil {
+			exit.Error(reason.InetCacheBinaries, "Failed to cache binaries", err)
+		}
+	} else if err := doCacheBinaries(k8sVersion, containerRuntime, driverName, binariesURL); err != nil {
 		exit.Error(reason.InetCacheBinaries, "Failed to cache binaries", err)
 	}
 	if _, err := CacheKubectlBinary(k8sVersion, binariesURL); err != nil {
@@ -126,6 +149,9 @@ func beginDownloadKicBaseImage(g *errgroup.Group, cc *config.ClusterConfig, down
 		if baseImg == kic.BaseImage && len(cc.KubernetesConfig.ImageRepository) != 0 {
 			baseImg = updateKicImageRepo(baseImg, cc.KubernetesConfig.ImageRepository)
 			cc.KicBaseImage = baseImg
+		} else if img, ok := download.SourceKicBaseImage(baseImg); ok && baseImg == kic.BaseImage {
+			baseImg = img
+			cc.KicBaseImage = baseImg
 		}
 		var finalImg string
 		// If we end up using a fallback image, notify the user
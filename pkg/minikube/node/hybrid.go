@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/network"
+)
+
+// CheckDriver returns an error if nodes of the driver drv, on the network nw, cannot join the cluster:
+// nodes of different drivers have to reach each other directly, which the networks of some drivers never allow
+func CheckDriver(cc config.ClusterConfig, drv, nw string) error {
+	if drv == cc.Driver {
+		return nil
+	}
+	for _, d := range []string{cc.Driver, drv} {
+		switch {
+		case driver.BareMetal(d), driver.IsFake(d):
+			return fmt.Errorf("nodes of the %s driver cannot be mixed with nodes of other drivers", d)
+		case driver.NeedsPortForward(d):
+			return fmt.Errorf("nodes of the %s driver are only reachable through ports forwarded to the host, and cannot be mixed with nodes of other drivers", d)
+		}
+	}
+	if (driver.IsQEMU(cc.Driver) && network.IsBuiltinQEMU(cc.Network)) || (driver.IsQEMU(drv) && network.IsBuiltinQEMU(nw)) {
+		return fmt.Errorf("nodes of the %s driver on the builtin network are only reachable from the host, use another network to mix them with nodes of other drivers", driver.QEMU2)
+	}
+	return nil
+}
+
+// nodeCgroupDriver returns the cgroup driver for the container runtime of a node. kubeadm join configures the kubelet
+// of a node like the one of the control plane, so nodes of another driver use the cgroup driver of the cluster, not their own
+func nodeCgroupDriver(cc config.ClusterConfig, n config.Node) string {
+	cd := cgroupDriver(cc)
+	if drv := config.NodeDriver(cc, n); drv != cc.Driver {
+		klog.Infof("using the %q cgroup driver of the %s control plane for %s node %q", cd, cc.Driver, drv, n.Name)
+	}
+	return cd
+}
+
+// checkReachable checks that a node of another driver than the one of the cluster and the control plane reach each other
+func checkReachable(cc config.ClusterConfig, n config.Node, r command.Runner) error {
+	host, port := cc.KubernetesConfig.APIServerHAVIP, cc.APIServerPort
+	if !config.IsHA(cc) {
+		cp, err := config.ControlPlane(cc)
+		if err != nil {
+			return err
+		}
+		host, port = cp.IP, cp.Port
+	}
+	if err := dial(r, host, port); err != nil {
+		return errors.Wrapf(err, "node %s cannot reach the control plane at %s", n.Name, net.JoinHostPort(host, strconv.Itoa(port)))
+	}
+
+	cp, err := config.ControlPlane(cc)
+	if err != nil {
+		return err
+	}
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "get api client")
+	}
+	defer api.Close()
+	h, err := machine.LoadHost(api, config.MachineName(cc, cp))
+	if err != nil {
+		return errors.Wrap(err, "load control-plane host")
+	}
+	cpr, err := machine.CommandRunner(h)
+	if err != nil {
+		return errors.Wrap(err, "get control-plane command runner")
+	}
+	sshPort := 22
+	if driver.IsSSH(config.NodeDriver(cc, n)) {
+		sshPort = cc.SSHPort
+	}
+	if err := dial(cpr, n.IP, sshPort); err != nil {
+		return errors.Wrapf(err, "the control plane cannot reach node %s at %s", n.Name, net.JoinHostPort(n.IP, strconv.Itoa(sshPort)))
+	}
+	return nil
+}
+
+// dial checks that a TCP connection to host:port can be opened from the machine of the runner
+func dial(r command.Runner, host string, port int) error {
+	addr := fmt.Sprintf("/dev/tcp/%s/%d", host, port)
+	klog.Infof("checking %s is reachable", addr)
+	if _, err := r.RunCmd(exec.Command("timeout", "5", "bash", "-c", "exec 3<>"+addr)); err != nil {
+		return err
+	}
+	return nil
+}
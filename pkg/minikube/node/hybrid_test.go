@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestCheckDriver(t *testing.T) {
+	tests := []struct {
+		name    string
+		cc      config.ClusterConfig
+		drv     string
+		network string
+		wantErr bool
+	}{
+		{
+			name: "driver of the cluster",
+			cc:   config.ClusterConfig{Driver: "none"},
+			drv:  "none",
+		},
+		{
+			name: "ssh node in a cluster of VMs",
+			cc:   config.ClusterConfig{Driver: "kvm2"},
+			drv:  "ssh",
+		},
+		{
+			name:    "qemu node on a dedicated network",
+			cc:      config.ClusterConfig{Driver: "kvm2"},
+			drv:     "qemu2",
+			network: "socket_vmnet",
+		},
+		{
+			name:    "qemu node on the builtin network",
+			cc:      config.ClusterConfig{Driver: "kvm2"},
+			drv:     "qemu2",
+			network: "builtin",
+			wantErr: true,
+		},
+		{
+			name:    "cluster on the builtin network",
+			cc:      config.ClusterConfig{Driver: "qemu2", Network: "user"},
+			drv:     "ssh",
+			wantErr: true,
+		},
+		{
+			name:    "bare metal cluster",
+			cc:      config.ClusterConfig{Driver: "none"},
+			drv:     "kvm2",
+			wantErr: true,
+		},
+		{
+			name:    "bare metal node",
+			cc:      config.ClusterConfig{Driver: "kvm2"},
+			drv:     "none",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckDriver(tc.cc, tc.drv, tc.network)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("CheckDriver(%s, %s, %q) = %v, wantErr %v", tc.cc.Driver, tc.drv, tc.network, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNodeCgroupDriver(t *testing.T) {
+	tests := []struct {
+		name string
+		cc   config.ClusterConfig
+		n    config.Node
+		want string
+	}{
+		{
+			name: "kic node in a cluster of VMs",
+			cc:   config.ClusterConfig{Driver: "kvm2"},
+			n:    config.Node{Name: "m02", Driver: "docker"},
+			want: constants.CgroupfsCgroupDriver,
+		},
+		{
+			name: "VM node in a kic cluster",
+			cc:   config.ClusterConfig{Driver: "docker"},
+			n:    config.Node{Name: "m02", Driver: "kvm2"},
+			want: cgroupDriver(config.ClusterConfig{Driver: "docker"}),
+		},
+		{
+			name: "ssh node in a kic cluster",
+			cc:   config.ClusterConfig{Driver: "podman"},
+			n:    config.Node{Name: "m02", Driver: "ssh"},
+			want: cgroupDriver(config.ClusterConfig{Driver: "podman"}),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := nodeCgroupDriver(tc.cc, tc.n); got != tc.want {
+				t.Errorf("nodeCgroupDriver(%s, %s) = %q, want the %q of the control plane", tc.cc.Driver, tc.n.Driver, got, tc.want)
+			}
+		})
+	}
+}
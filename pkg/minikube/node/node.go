@@ -73,6 +73,12 @@ func Add(cc *config.ClusterConfig, n config.Node, delOnFail bool) error {
 	if err != nil {
 		return err
 	}
+	// a node of another driver than the one of the cluster is on a network of its own
+	if drv := config.NodeDriver(*cc, n); drv != cc.Driver {
+		if err := checkReachable(*cc, n, r); err != nil {
+			return errors.Wrapf(err, "the networks of the %s and %s drivers do not reach each other, remove the node with %q", drv, cc.Driver, mustload.ExampleCmd(cc.Name, "node delete "+n.Name))
+		}
+	}
 	s := Starter{
 		Runner:         r,
 		PreExists:      p,
@@ -206,7 +212,61 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 	}
 
 	cc.Nodes = append(cc.Nodes[:index], cc.Nodes[index+1:]...)
-	return n, config.SaveProfile(viper.GetString(config.ProfileName), &cc)
+	if err := config.SaveProfile(viper.GetString(config.ProfileName), &cc); err != nil {
+		return n, err
+	}
+	// stop balancing the apiserver traffic to a deleted control-plane node
+	if n.ControlPlane {
+		return n, ConfigureLoadBalancer(api, cc)
+	}
+	return n, nil
+}
+
+// SetControlPlane promotes a worker node to a control-plane node, or demotes a control-plane node to a worker node,
+// by removing it from the cluster and joining it again in its new role, keeping its host.
+func SetControlPlane(cc *config.ClusterConfig, name string, controlPlane bool) error {
+	n, err := teardown(*cc, name)
+	if err != nil {
+		return errors.Wrap(err, "teardown")
+	}
+
+	n.ControlPlane = controlPlane
+	n.Worker = true
+	if controlPlane && n.Port == 0 {
+		n.Port = cc.APIServerPort
+	}
+	if err := config.SaveNode(cc, n); err != nil {
+		return errors.Wrap(err, "save node")
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "get api client")
+	}
+	h, err := machine.LoadHost(api, config.MachineName(*cc, *n))
+	if err != nil {
+		return errors.Wrap(err, "load host")
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		return errors.Wrap(err, "get command runner")
+	}
+	s := Starter{
+		Runner:     r,
+		PreExists:  false, // join the cluster again, as it was reset
+		MachineAPI: api,
+		Host:       h,
+		Cfg:        cc,
+		Node:       n,
+	}
+	if _, err := Start(s); err != nil {
+		return err
+	}
+	// a promoted node is added to the load balancer when started, a demoted one has to be removed from it
+	if !controlPlane {
+		return ConfigureLoadBalancer(api, *cc)
+	}
+	return nil
 }
 
 // Retrieve finds the node by name in the given cluster
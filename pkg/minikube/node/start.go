@@ -36,6 +36,7 @@ import (
 	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/klog/v2"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/addons"
@@ -100,7 +101,7 @@ func Start(starter Starter) (*kubeconfig.Settings, error) { // nolint:gocyclo
 	}
 	if stopk8s {
 		nv := semver.Version{Major: 0, Minor: 0, Patch: 0}
-		cr := configureRuntimes(starter.Runner, *starter.Cfg, nv)
+		cr := configureRuntimes(starter.Runner, *starter.Cfg, *starter.Node, nv)
 
 		showNoK8sVersionInfo(cr)
 
@@ -117,7 +118,7 @@ func Start(starter Starter) (*kubeconfig.Settings, error) { // nolint:gocyclo
 	}
 
 	// configure the runtime (docker, containerd, crio)
-	cr := configureRuntimes(starter.Runner, *starter.Cfg, sv)
+	cr := configureRuntimes(starter.Runner, *starter.Cfg, *starter.Node, sv)
 
 	// check if installed runtime is compatible with current minikube code
 	if err = cruntime.CheckCompatibility(cr); err != nil {
@@ -126,6 +127,11 @@ func Start(starter Starter) (*kubeconfig.Settings, error) { // nolint:gocyclo
 
 	showVersionInfo(starter.Node.KubernetesVersion, cr)
 
+	// dedicated etcd and load balancer nodes do not join the cluster
+	if starter.Node.Etcd || starter.Node.LoadBalancer {
+		return nil, startTopologyNode(starter, cr)
+	}
+
 	// add "host.minikube.internal" dns alias (intentionally non-fatal)
 	hostIP, err := cluster.HostIP(starter.Host, starter.Cfg.Name)
 	if err != nil {
@@ -180,7 +186,7 @@ func Start(starter Starter) (*kubeconfig.Settings, error) { // nolint:gocyclo
 
 		// join cluster only on first node start
 		// except for vm driver in non-ha (non-multi-control plane) cluster - fallback to old behaviour
-		if !starter.PreExists || (driver.IsVM(starter.Cfg.Driver) && !config.IsHA(*starter.Cfg)) {
+		if !starter.PreExists || (driver.IsVM(config.NodeDriver(*starter.Cfg, *starter.Node)) && !config.IsHA(*starter.Cfg)) {
 			// make sure to use the command runner for the primary control plane to generate the join token
 			pcpBs, err := cluster.ControlPlaneBootstrapper(starter.MachineAPI, starter.Cfg, viper.GetString(cmdcfg.Bootstrapper))
 			if err != nil {
@@ -190,6 +196,18 @@ func Start(starter Starter) (*kubeconfig.Settings, error) { // nolint:gocyclo
 				return nil, errors.Wrap(err, "join node to cluster")
 			}
 		}
+
+		if starter.Node.ControlPlane {
+			if err := ConfigureLoadBalancer(starter.MachineAPI, *starter.Cfg); err != nil {
+				return nil, errors.Wrap(err, "configure load balancer")
+			}
+		}
+	}
+
+	if viper.GetBool("offline") {
+		if err := loadOfflineImages(starter); err != nil {
+			out.FailureT("Unable to load cached images: {{.error}}", out.V{"error": err})
+		}
 	}
 
 	go configureMounts(&wg, *starter.Cfg)
@@ -219,12 +237,12 @@ func Start(starter Starter) (*kubeconfig.Settings, error) { // nolint:gocyclo
 	}
 
 	// discourage use of the virtualbox driver
-	if starter.Cfg.Driver == driver.VirtualBox && viper.GetBool(config.WantVirtualBoxDriverWarning) {
+	if config.NodeDriver(*starter.Cfg, *starter.Node) == driver.VirtualBox && viper.GetBool(config.WantVirtualBoxDriverWarning) {
 		warnVirtualBox()
 	}
 
 	// special ops for "none" driver on control-plane node, like change minikube directory
-	if starter.Node.ControlPlane && driver.IsNone(starter.Cfg.Driver) {
+	if starter.Node.ControlPlane && driver.IsNone(config.NodeDriver(*starter.Cfg, *starter.Node)) {
 		prepareNone()
 	}
 
@@ -279,7 +297,8 @@ func startPrimaryControlPlane(starter Starter, cr cruntime.Manager) (*kubeconfig
 		return nil, nil, fmt.Errorf("node not marked as primary control-plane")
 	}
 
-	if config.IsHA(*starter.Cfg) {
+	// with haproxy, the load balancer node already set its IP as the control-plane endpoint
+	if config.IsHA(*starter.Cfg) && !config.UsesHAProxy(*starter.Cfg) {
 		n, err := network.Inspect(starter.Node.IP)
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "inspect network")
@@ -297,6 +316,12 @@ func startPrimaryControlPlane(starter Starter, cr cruntime.Manager) (*kubeconfig
 		return nil, nil, errors.Wrap(err, "Failed to setup kubeadm")
 	}
 
+	if config.IsExternalEtcd(*starter.Cfg) {
+		if err := CopyEtcdClientCerts(starter.MachineAPI, *starter.Cfg, starter.Runner); err != nil {
+			return nil, nil, errors.Wrap(err, "copying etcd client certs")
+		}
+	}
+
 	if err := bs.StartCluster(*starter.Cfg); err != nil {
 		ExitIfFatal(err, false)
 		out.LogEntries("Error starting cluster", err, logs.FindProblems(cr, bs, *starter.Cfg, starter.Runner))
@@ -382,15 +407,30 @@ func Provision(cc *config.ClusterConfig, n *config.Node, delOnFail bool) (comman
 		if config.IsPrimaryControlPlane(*cc, *n) {
 			role = "primary control-plane"
 		}
+		if n.Etcd {
+			role = "etcd"
+		}
+		if n.LoadBalancer {
+			role = "load balancer"
+		}
 		out.Step(style.ThumbsUp, "Starting \"{{.node}}\" {{.role}} node in \"{{.cluster}}\" cluster", out.V{"node": name, "role": role, "cluster": cc.Name})
 	}
 
-	if driver.IsKIC(cc.Driver) {
-		beginDownloadKicBaseImage(&kicGroup, cc, viper.GetBool("download-only"))
+	// in a hybrid cluster the node may not be of the cluster driver
+	drv := config.NodeDriver(*cc, *n)
+	nodeCfg := config.NodeResources(*cc, *n)
+	if driver.IsKIC(drv) {
+		beginDownloadKicBaseImage(&kicGroup, &nodeCfg, viper.GetBool("download-only"))
 	}
 
-	if !driver.BareMetal(cc.Driver) {
-		beginCacheKubernetesImages(&cacheGroup, cc.KubernetesConfig.ImageRepository, n.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime, cc.Driver)
+	// nothing is ever pulled into a sandbox, so there is nothing to cache for it either
+	if !driver.BareMetal(drv) && !driver.IsFake(drv) {
+		if cc.Bootstrapper == bootstrapper.K3s {
+			beginCacheK3s(&cacheGroup, n.KubernetesVersion, config.GuestArch(*cc))
+		} else if config.GuestArch(*cc) == runtime.GOARCH {
+			// the preload and the cached images are of the architecture of the host, emulated guests pull their own
+			beginCacheKubernetesImages(&cacheGroup, cc.KubernetesConfig.ImageRepository, n.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime, drv)
+		}
 	}
 
 	// Abstraction leakage alert: startHost requires the config to be saved, to satistfy pkg/provision/buildroot.
@@ -399,16 +439,20 @@ func Provision(cc *config.ClusterConfig, n *config.Node, delOnFail bool) (comman
 		return nil, false, nil, nil, errors.Wrap(err, "Failed to save config")
 	}
 
-	handleDownloadOnly(&cacheGroup, &kicGroup, n.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime, cc.Driver)
-	if driver.IsKIC(cc.Driver) {
+	handleDownloadOnly(&cacheGroup, &kicGroup, n.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime, drv, cc.Bootstrapper)
+	if driver.IsKIC(drv) {
 		waitDownloadKicBaseImage(&kicGroup)
+		// keep the fallback image, if one had to be used
+		cc.KicBaseImage = nodeCfg.KicBaseImage
 	}
 
 	return startMachine(cc, n, delOnFail)
 }
 
 // ConfigureRuntimes does what needs to happen to get a runtime going.
-func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, kv semver.Version) cruntime.Manager {
+func configureRuntimes(runner cruntime.CommandRunner, cfg config.ClusterConfig, n config.Node, kv semver.Version) cruntime.Manager {
+	cc := config.NodeResources(cfg, n)
+	cgroup := nodeCgroupDriver(cfg, n)
 	co := cruntime.Config{
 		Type:              cc.KubernetesConfig.ContainerRuntime,
 		Socket:            cc.KubernetesConfig.CRISocket,
@@ -456,7 +500,7 @@ func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, k
 			KubernetesVersion: co.KubernetesVersion,
 			InsecureRegistry:  co.InsecureRegistry})
 		if err == nil {
-			err = containerd.Enable(false, cgroupDriver(cc), inUserNamespace) // do not disableOthers, as it's not primary cr
+			err = containerd.Enable(false, cgroup, inUserNamespace) // do not disableOthers, as it's not primary cr
 		}
 		if err != nil {
 			klog.Warningf("cannot ensure containerd is configured properly and reloaded for docker - cluster might be unstable: %v", err)
@@ -464,7 +508,7 @@ func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, k
 	}
 
 	disableOthers := !driver.BareMetal(cc.Driver)
-	if err = cr.Enable(disableOthers, cgroupDriver(cc), inUserNamespace); err != nil {
+	if err = cr.Enable(disableOthers, cgroup, inUserNamespace); err != nil {
 		exit.Error(reason.RuntimeEnable, "Failed to enable container runtime", err)
 	}
 
@@ -644,15 +688,37 @@ func setupKubeconfig(h host.Host, cc config.ClusterConfig, n config.Node, cluste
 		ClusterServerAddress: addr,
 		ClientCertificate:    localpath.ClientCert(cc.Name),
 		ClientKey:            localpath.ClientKey(cc.Name),
-		CertificateAuthority: localpath.CACert(),
+		CertificateAuthority: bootstrapper.CACert(cc),
 		KeepContext:          cc.KeepContext,
 		EmbedCerts:           cc.EmbedCerts,
+		ExecUser:             oidcExecUser(cc.KubernetesConfig.OIDC),
 	}
 
 	kcs.SetPath(kubeconfig.PathFromEnv())
 	return kcs
 }
 
+// oidcExecUser returns the kubectl oidc-login (kubelogin) command which gets the tokens of the OpenID Connect provider of a cluster
+func oidcExecUser(oidc config.OIDCConfig) *api.ExecConfig {
+	if !oidc.Enabled() {
+		return nil
+	}
+	args := []string{"oidc-login", "get-token", "--oidc-issuer-url=" + oidc.IssuerURL, "--oidc-client-id=" + oidc.ClientID}
+	if oidc.ClientSecret != "" {
+		args = append(args, "--oidc-client-secret="+oidc.ClientSecret)
+	}
+	if oidc.CAFile != "" {
+		args = append(args, "--certificate-authority="+oidc.CAFile)
+	}
+	return &api.ExecConfig{
+		APIVersion:      "client.authentication.k8s.io/v1beta1",
+		Command:         "kubectl",
+		Args:            args,
+		InteractiveMode: api.IfAvailableExecInteractiveMode,
+		InstallHint:     "The kubelogin plugin is required to log in with the OpenID Connect provider, see https://github.com/int128/kubelogin",
+	}
+}
+
 // StartMachine starts a VM
 func startMachine(cfg *config.ClusterConfig, node *config.Node, delOnFail bool) (runner command.Runner, preExists bool, machineAPI libmachine.API, host *host.Host, err error) {
 	m, err := machine.NewAPIClient()
@@ -673,7 +739,8 @@ func startMachine(cfg *config.ClusterConfig, node *config.Node, delOnFail bool)
 		return runner, preExists, m, host, errors.Wrap(err, "Failed to validate network")
 	}
 
-	if driver.IsQEMU(host.Driver.DriverName()) && network.IsBuiltinQEMU(cfg.Network) {
+	// the apiserver port of the host is forwarded to the primary control-plane node alone
+	if driver.IsQEMU(host.Driver.DriverName()) && network.IsBuiltinQEMU(cfg.Network) && config.IsPrimaryControlPlane(*cfg, *node) {
 		apiServerPort, err := getPort()
 		if err != nil {
 			return runner, preExists, m, host, errors.Wrap(err, "Failed to find apiserver port")
@@ -745,7 +812,7 @@ func startHostInternal(api libmachine.API, cc *config.ClusterConfig, n *config.N
 	}
 
 	// Don't use host.Driver to avoid nil pointer deref
-	drv := cc.Driver
+	drv := config.NodeDriver(*cc, *n)
 	out.ErrT(style.Sad, `Failed to start {{.driver}} {{.driver_type}}. Running "{{.cmd}}" may fix it: {{.error}}`, out.V{"driver": drv, "driver_type": driver.MachineType(drv), "cmd": mustload.ExampleCmd(cc.Name, "delete"), "error": err})
 	return host, exists, err
 }
@@ -787,12 +854,14 @@ func validateNetwork(h *host.Host, r command.Runner, imageRepository string) (st
 	}
 
 	// Non-blocking
-	go tryRegistry(r, h.Driver.DriverName(), imageRepository, ip)
+	if !viper.GetBool("offline") {
+		go tryRegistry(r, h.Driver.DriverName(), imageRepository, ip)
+	}
 	return ip, nil
 }
 
 func shouldTrySSH(driverName, ip string) bool {
-	if driver.BareMetal(driverName) || driver.IsKIC(driverName) {
+	if driver.BareMetal(driverName) || driver.IsKIC(driverName) || driver.IsFake(driverName) {
 		return false
 	}
 	// QEMU with user network
@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/cluster/ha/haproxy"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/etcd"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	kconst "k8s.io/minikube/third_party/kubeadm/app/constants"
+)
+
+// startTopologyNode starts a dedicated etcd or load balancer node, which runs its static pod with a standalone kubelet instead of joining the cluster
+func startTopologyNode(starter Starter, cr cruntime.Manager) error {
+	cc, n, r := starter.Cfg, starter.Node, starter.Runner
+
+	if err := cr.Preload(*cc); err != nil {
+		klog.Infof("preload failed, images will be pulled: %v", err)
+	}
+
+	sm := sysinit.New(r)
+	if err := bsutil.TransferBinaries(cc.KubernetesConfig, config.GuestArch(*cc), r, sm, cc.BinaryMirror); err != nil {
+		return errors.Wrap(err, "downloading binaries")
+	}
+
+	unit, kubeletCfg, err := bsutil.NewStandaloneKubeletConfig(*cc, *n, cr)
+	if err != nil {
+		return errors.Wrap(err, "generating kubelet config")
+	}
+	kubeletService, err := bsutil.NewKubeletService(cc.KubernetesConfig)
+	if err != nil {
+		return errors.Wrap(err, "generating kubelet service")
+	}
+	files := []assets.CopyableFile{
+		assets.NewMemoryAssetTarget(unit, bsutil.KubeletSystemdConfFile, "0644"),
+		assets.NewMemoryAssetTarget(kubeletService, bsutil.KubeletServiceFile, "0644"),
+		assets.NewMemoryAssetTarget(kubeletCfg, bsutil.StandaloneKubeletConfigFile, "0644"),
+	}
+	kubeletPath := path.Join(vmpath.GuestPersistentDir, "binaries", cc.KubernetesConfig.KubernetesVersion, "kubelet")
+	shims, err := sm.GenerateInitShim("kubelet", kubeletPath, bsutil.KubeletSystemdConfFile)
+	if err != nil {
+		return errors.Wrap(err, "shim")
+	}
+	files = append(files, shims...)
+
+	if n.LoadBalancer {
+		// the load balancer is the control-plane endpoint of the cluster, as the virtual IP is with kube-vip
+		cc.KubernetesConfig.APIServerHAVIP = n.IP
+		lbFiles, err := loadBalancerFiles(*cc)
+		if err != nil {
+			return err
+		}
+		files = append(files, lbFiles...)
+	}
+
+	if err := bsutil.CopyFiles(r, files); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+	if err := sm.Restart("kubelet"); err != nil {
+		return errors.Wrap(err, "restarting kubelet")
+	}
+
+	if n.Etcd {
+		if err := startEtcdMember(starter); err != nil {
+			return errors.Wrap(err, "starting etcd member")
+		}
+	}
+	return config.Write(viper.GetString(config.ProfileName), cc)
+}
+
+// startEtcdMember generates the certificates and the static pod of the external etcd member of a dedicated etcd node,
+// which joins the members of the etcd nodes before it. Kubelet restarts the members which already have a static pod.
+func startEtcdMember(starter Starter) error {
+	cc, n, r := *starter.Cfg, *starter.Node, starter.Runner
+	name := config.MachineName(cc, n)
+
+	if starter.PreExists {
+		if _, err := r.RunCmd(exec.Command("sudo", "test", "-f", path.Join(vmpath.GuestManifestsDir, "etcd.yaml"))); err == nil {
+			klog.Infof("etcd member %s already exists", name)
+			return nil
+		}
+	}
+
+	joined := []config.Node{}
+	for _, en := range config.EtcdNodes(cc) {
+		if en.Name == n.Name {
+			break
+		}
+		joined = append(joined, en)
+	}
+	member := etcd.Member{Name: name, IP: n.IP}
+	initialCluster := []string{}
+	for _, en := range joined {
+		m := etcd.Member{Name: config.MachineName(cc, en), IP: en.IP}
+		initialCluster = append(initialCluster, fmt.Sprintf("%s=%s", m.Name, m.PeerURL()))
+	}
+	initialCluster = append(initialCluster, fmt.Sprintf("%s=%s", member.Name, member.PeerURL()))
+
+	state := "new"
+	// certs are generated in order, as the ones of the members are signed by the CA of etcd
+	certs := []string{"etcd-server", "etcd-peer", "etcd-healthcheck-client"}
+	if len(joined) == 0 {
+		// the first member generates the CA of etcd, and the cert the apiservers authenticate to etcd with
+		certs = append([]string{"etcd-ca"}, append(certs, "apiserver-etcd-client")...)
+	} else {
+		state = "existing"
+		fr, err := nodeRunner(starter.MachineAPI, cc, joined[0])
+		if err != nil {
+			return err
+		}
+		for _, f := range []string{bsutil.EtcdCACert, bsutil.EtcdCAKey} {
+			if err := copyFromNode(fr, r, f); err != nil {
+				return err
+			}
+		}
+		if err := etcd.AddMember(fr, member); err != nil {
+			return err
+		}
+	}
+
+	kubeadmCfg, err := bsutil.GenerateEtcdKubeadmYAML(cc, n, initialCluster, state)
+	if err != nil {
+		return errors.Wrap(err, "generating kubeadm config")
+	}
+	if err := bsutil.CopyFiles(r, []assets.CopyableFile{assets.NewMemoryAssetTarget(kubeadmCfg, constants.KubeadmYamlPath, "0640")}); err != nil {
+		return errors.Wrap(err, "copy kubeadm config")
+	}
+
+	kubeadm := bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion)
+	cmds := []string{}
+	for _, c := range certs {
+		cmds = append(cmds, fmt.Sprintf("%s init phase certs %s --config %s", kubeadm, c, constants.KubeadmYamlPath))
+	}
+	cmds = append(cmds, fmt.Sprintf("%s init phase etcd local --config %s", kubeadm, constants.KubeadmYamlPath))
+	for _, c := range cmds {
+		if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", c)); err != nil {
+			return errors.Wrap(err, "kubeadm")
+		}
+	}
+
+	// the next member can only be announced once this one joined, for the cluster to keep its quorum
+	if err := etcd.WaitForHealthy(r, kconst.DefaultControlPlaneTimeout); err != nil {
+		return errors.Wrapf(err, "waiting for etcd member %s", name)
+	}
+	klog.Infof("etcd member %s joined %v", name, initialCluster)
+	return nil
+}
+
+// CopyEtcdClientCerts copies the cert the apiserver of a control-plane node authenticates to the external etcd with, from the first etcd node which generated it
+func CopyEtcdClientCerts(api libmachine.API, cc config.ClusterConfig, r command.Runner) error {
+	ens := config.EtcdNodes(cc)
+	if len(ens) == 0 {
+		return fmt.Errorf("no etcd nodes found")
+	}
+	fr, err := nodeRunner(api, cc, ens[0])
+	if err != nil {
+		return err
+	}
+	for _, f := range []string{bsutil.EtcdCACert, bsutil.EtcdClientCert, bsutil.EtcdClientKey} {
+		if err := copyFromNode(fr, r, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConfigureLoadBalancer updates the haproxy config of the load balancer node with the current control-plane nodes, for clusters served by haproxy
+func ConfigureLoadBalancer(api libmachine.API, cc config.ClusterConfig) error {
+	if !config.UsesHAProxy(cc) {
+		return nil
+	}
+	lb, err := config.LoadBalancerNode(cc)
+	if err != nil {
+		return err
+	}
+	r, err := nodeRunner(api, cc, lb)
+	if err != nil {
+		return err
+	}
+	files, err := loadBalancerFiles(cc)
+	if err != nil {
+		return err
+	}
+	// kubelet recreates haproxy as the config hash of its manifest changed
+	if err := bsutil.CopyFiles(r, files); err != nil {
+		return errors.Wrap(err, "copy haproxy config")
+	}
+	return nil
+}
+
+// loadBalancerFiles returns the haproxy config and static pod of the load balancer node
+func loadBalancerFiles(cc config.ClusterConfig) ([]assets.CopyableFile, error) {
+	cfg, manifest, err := haproxy.Configure(cc)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating haproxy config")
+	}
+	return []assets.CopyableFile{
+		assets.NewMemoryAssetTarget(cfg, haproxy.ConfigPath, "0644"),
+		assets.NewMemoryAssetTarget(manifest, path.Join(vmpath.GuestManifestsDir, haproxy.Manifest), "0600"),
+	}, nil
+}
+
+// nodeRunner returns the command runner of another node of the cluster
+func nodeRunner(api libmachine.API, cc config.ClusterConfig, n config.Node) (command.Runner, error) {
+	h, err := machine.LoadHost(api, config.MachineName(cc, n))
+	if err != nil {
+		return nil, errors.Wrapf(err, "load host %s", config.MachineName(cc, n))
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		return nil, errors.Wrapf(err, "command runner of %s", config.MachineName(cc, n))
+	}
+	return r, nil
+}
+
+// copyFromNode copies a file to the same path on another node
+func copyFromNode(from command.Runner, to command.Runner, file string) error {
+	f := assets.NewMemoryAsset(nil, path.Dir(file), path.Base(file), "0600")
+	if err := from.CopyFrom(f); err != nil {
+		return errors.Wrapf(err, "copy %s from node", file)
+	}
+	if _, err := to.RunCmd(exec.Command("sudo", "mkdir", "-p", path.Dir(file))); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if err := to.Copy(f); err != nil {
+		return errors.Wrapf(err, "copy %s to node", file)
+	}
+	return nil
+}
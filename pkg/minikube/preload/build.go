@@ -0,0 +1,271 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preload builds preloaded images tarballs
+package preload
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/detect"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+const (
+	dockerStorageDriver   = "overlay2"
+	containerdSnapshotter = "overlayfs"
+	podmanStorageDriver   = "overlay"
+)
+
+// BuildConfig is the configuration used to build a preload tarball
+type BuildConfig struct {
+	KubernetesVersion string
+	ContainerRuntime  string
+	// ExtraImages are pulled into the tarball in addition to the Kubernetes images
+	ExtraImages []string
+	// ImageRepository is an alternative repository to pull the Kubernetes images from
+	ImageRepository string
+	// ContainerName is the name of the temporary container the tarball is generated in
+	ContainerName string
+	// OutputDir is where the tarball and its checksum file are written to
+	OutputDir string
+}
+
+// Build generates a preloaded images tarball in a temporary kic container and copies it,
+// along with its checksum file, to the output directory using the name download.TarballPath expects.
+// It returns the path of the tarball.
+func Build(cfg BuildConfig) (string, error) {
+	if cfg.ContainerRuntime == "crio" {
+		cfg.ContainerRuntime = "cri-o"
+	}
+	name := cfg.ContainerName
+	d := kic.NewDriver(kic.Config{
+		ClusterName:       name,
+		KubernetesVersion: cfg.KubernetesVersion,
+		ContainerRuntime:  cfg.ContainerRuntime,
+		OCIBinary:         oci.Docker,
+		MachineName:       name,
+		ImageDigest:       kic.BaseImage,
+		StorePath:         localpath.MiniPath(),
+		CPU:               2,
+		Memory:            4000,
+		APIServerPort:     8080,
+	})
+
+	baseDir := filepath.Dir(d.GetSSHKeyPath())
+	defer os.RemoveAll(baseDir)
+
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return "", errors.Wrap(err, "mkdir")
+	}
+	if err := d.Create(); err != nil {
+		return "", errors.Wrap(err, "creating kic driver")
+	}
+	defer func() {
+		if err := d.Remove(); err != nil {
+			klog.Warningf("failed to remove %s: %v", name, err)
+		}
+	}()
+
+	runner := command.NewKICRunner(name, d.OCIBinary)
+	if err := verifyStorage(runner, cfg.ContainerRuntime); err != nil {
+		return "", errors.Wrap(err, "verifying storage")
+	}
+
+	imgs, err := Images(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	sv, err := util.ParseKubernetesVersion(cfg.KubernetesVersion)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to parse Kubernetes version")
+	}
+	cr, err := cruntime.New(cruntime.Config{
+		Type:              cfg.ContainerRuntime,
+		Runner:            runner,
+		ImageRepository:   cfg.ImageRepository,
+		KubernetesVersion: sv,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed create new runtime")
+	}
+	// will need to do this to enable the container run-time service
+	if err := cr.Enable(true, detect.CgroupDriver(), false); err != nil {
+		return "", errors.Wrap(err, "enable container runtime")
+	}
+
+	for _, img := range imgs {
+		pull := func() error {
+			return cr.PullImage(img)
+		}
+		// retry up to 5 times if network is bad
+		if err := retry.Expo(pull, time.Microsecond, time.Minute, 5); err != nil {
+			return "", errors.Wrapf(err, "pull image %s", img)
+		}
+	}
+
+	// Transfer in k8s binaries
+	kcfg := config.KubernetesConfig{
+		KubernetesVersion: cfg.KubernetesVersion,
+	}
+	if err := bsutil.TransferBinaries(kcfg, runtime.GOARCH, runner, sysinit.New(runner), ""); err != nil {
+		return "", errors.Wrap(err, "transferring k8s binaries")
+	}
+
+	tf := download.TarballName(cfg.KubernetesVersion, cfg.ContainerRuntime)
+	if err := createImageTarball(runner, tf, cfg.ContainerRuntime); err != nil {
+		return "", errors.Wrap(err, "create tarball")
+	}
+
+	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+		return "", errors.Wrap(err, "mkdir")
+	}
+	dest := filepath.Join(cfg.OutputDir, tf)
+	cmd := exec.Command(d.OCIBinary, "cp", fmt.Sprintf("%s:/%s", name, tf), dest)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", errors.Wrapf(err, "cp cmd: %s: %s", cmd.Args, output)
+	}
+
+	if err := download.WriteChecksumFile(dest); err != nil {
+		return "", errors.Wrap(err, "checksum")
+	}
+	return dest, nil
+}
+
+// Images returns the images a preload tarball is built with
+func Images(cfg BuildConfig) ([]string, error) {
+	imgs, err := images.Kubeadm(cfg.ImageRepository, cfg.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "kubeadm images")
+	}
+	// kic overlay image is only needed by containerd and cri-o https://github.com/kubernetes/minikube/issues/7428
+	if cfg.ContainerRuntime != "docker" {
+		imgs = append(imgs, images.KindNet(cfg.ImageRepository))
+	}
+	return append(imgs, cfg.ExtraImages...), nil
+}
+
+func verifyStorage(runner command.Runner, containerRuntime string) error {
+	var verify func() error
+	switch containerRuntime {
+	case "docker":
+		verify = func() error { return verifyDockerStorage(runner) }
+	case "containerd":
+		verify = func() error { return verifyContainerdStorage(runner) }
+	case "cri-o":
+		verify = func() error { return verifyPodmanStorage(runner) }
+	default:
+		return nil
+	}
+	if err := retry.Expo(verify, 100*time.Microsecond, time.Minute*2); err != nil {
+		return errors.Wrapf(err, "%s storage type is incompatible", containerRuntime)
+	}
+	return nil
+}
+
+func verifyDockerStorage(runner command.Runner) error {
+	rr, err := runner.RunCmd(exec.Command("docker", "info", "-f", "{{.Info.Driver}}"))
+	if err != nil {
+		return err
+	}
+	driver := strings.TrimSpace(rr.Stdout.String())
+	if driver != dockerStorageDriver {
+		return fmt.Errorf("docker storage driver %s does not match requested %s", driver, dockerStorageDriver)
+	}
+	return nil
+}
+
+func verifyContainerdStorage(runner command.Runner) error {
+	rr, err := runner.RunCmd(exec.Command("sudo", "containerd", "config", "dump"))
+	if err != nil {
+		return err
+	}
+	var driver string
+	for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+		if strings.Contains(line, "snapshotter = ") {
+			driver = strings.Split(line, " = ")[1]
+			driver = strings.Trim(driver, "\"")
+		}
+	}
+	if driver != containerdSnapshotter {
+		return fmt.Errorf("containerd snapshotter %s does not match requested %s", driver, containerdSnapshotter)
+	}
+	return nil
+}
+
+func verifyPodmanStorage(runner command.Runner) error {
+	rr, err := runner.RunCmd(exec.Command("sudo", "podman", "info", "-f", "json"))
+	if err != nil {
+		return err
+	}
+	var info map[string]map[string]interface{}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &info); err != nil {
+		return err
+	}
+	driver := info["store"]["graphDriverName"]
+	if driver != podmanStorageDriver {
+		return fmt.Errorf("podman storage driver %s does not match requested %s", driver, podmanStorageDriver)
+	}
+	return nil
+}
+
+// tarballDirs returns the directories under /var which are saved into the tarball
+func tarballDirs(containerRuntime string) []string {
+	// directories to save into tarball
+	dirs := []string{
+		"./lib/minikube/binaries",
+	}
+	switch containerRuntime {
+	case "docker":
+		dirs = append(dirs, path.Join("./lib/docker", dockerStorageDriver), "./lib/docker/image")
+	case "containerd":
+		dirs = append(dirs, "./lib/containerd")
+	case "cri-o":
+		dirs = append(dirs, "./lib/containers")
+	}
+	return dirs
+}
+
+func createImageTarball(runner command.Runner, tarballFilename, containerRuntime string) error {
+	args := []string{"tar", "--xattrs", "--xattrs-include", "security.capability", "-I", "lz4", "-C", "/var", "-cf", path.Join("/", tarballFilename)}
+	args = append(args, tarballDirs(containerRuntime)...)
+	if _, err := runner.RunCmd(exec.Command("sudo", args...)); err != nil {
+		return errors.Wrapf(err, "tarball cmd: %s", args)
+	}
+	return nil
+}
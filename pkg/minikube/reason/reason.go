@@ -100,6 +100,8 @@ var (
 	InternalCacheLoad = Kind{ID: "MK_CACHE_LOAD", ExitCode: ExProgramError}
 	// minikube failed to load a Docker Machine CommandRunner
 	InternalCommandRunner = Kind{ID: "MK_COMMAND_RUNNER", ExitCode: ExProgramError}
+	// minikube failed to build a preloaded images tarball
+	InternalPreloadBuild = Kind{ID: "MK_PRELOAD_BUILD", ExitCode: ExProgramError}
 	// minikube failed to start nerdctld
 	StartNerdctld = Kind{ID: "MK_START_NERDCTLD", ExitCode: ExProgramError}
 	// minikube failed to generate shell command completion for a supported shell
@@ -268,10 +270,18 @@ var (
 
 	// minikube failed to determine current user
 	HostCurrentUser = Kind{ID: "HOST_CURRENT_USER", ExitCode: ExHostConfig}
+	// minikube failed to load the artifact mirror configured with 'minikube config set artifact-mirror'
+	HostArtifactMirror = Kind{ID: "HOST_ARTIFACT_MIRROR", ExitCode: ExHostConfig}
+	// minikube failed to write a bundle of cached artifacts
+	HostCacheBundle = Kind{ID: "HOST_CACHE_BUNDLE", ExitCode: ExHostError}
 	// minikube failed to delete cached images from host
 	HostDelCache = Kind{ID: "HOST_DEL_CACHE", ExitCode: ExHostError}
 	// minikube failed to kill a mount process
 	HostKillMountProc = Kind{ID: "HOST_KILL_MOUNT_PROC", ExitCode: ExHostError}
+	// minikube is missing artifacts required to start without network access
+	HostOfflineArtifacts = Kind{ID: "HOST_OFFLINE_ARTIFACTS", ExitCode: ExHostConfig}
+	// minikube failed to import a preloaded images tarball into the cache
+	HostPreloadImport = Kind{ID: "HOST_PRELOAD_IMPORT", ExitCode: ExHostError}
 	// minikube failed to update host Kubernetes resources config
 	HostKubeconfigUpdate = Kind{ID: "HOST_KUBECONFIG_UPDATE", ExitCode: ExHostConfig}
 	// minikube failed to delete Kubernetes config from context for a given profile
@@ -310,6 +320,10 @@ var (
 	DrvUnsupported = Kind{ID: "DRV_UNSUPPORTED", ExitCode: ExDriverUnsupported}
 	// the driver in use does not support multi-node clusters
 	DrvUnsupportedMulti = Kind{ID: "DRV_UNSUPPORTED_MULTINODE", ExitCode: ExDriverConflict}
+	// the nodes of the drivers of a cluster cannot reach each other
+	DrvUnreachableNetworks = Kind{ID: "DRV_UNREACHABLE_NETWORKS", ExitCode: ExDriverConflict, Advice: translate.T("Add the node with a driver whose network reaches the one of the cluster, or with the driver of the cluster")}
+	// the driver in use does not support a feature which was asked for
+	DrvUnsupportedFeature = Kind{ID: "DRV_UNSUPPORTED_FEATURE", ExitCode: ExDriverUnsupported, Advice: translate.T("Run 'minikube drivers list' to find a driver which supports it")}
 	// the specified driver is not supported on the host OS
 	DrvUnsupportedOS = Kind{ID: "DRV_UNSUPPORTED_OS", ExitCode: ExDriverUnsupported}
 	// the driver in use does not support the selected profile or multiple profiles
@@ -327,14 +341,28 @@ var (
 	// the specified driver needs to be run as root
 	DrvNeedsRoot = Kind{ID: "DRV_NEEDS_ROOT", ExitCode: ExDriverPermission}
 
+	// minikube failed to read the apiserver audit log of a control-plane node
+	GuestAPIServerAudit = Kind{ID: "GUEST_APISERVER_AUDIT", ExitCode: ExGuestError}
 	// minikube failed to load cached images
 	GuestCacheLoad = Kind{ID: "GUEST_CACHE_LOAD", ExitCode: ExGuestError}
 	// minikube failed to setup certificates
 	GuestCert = Kind{ID: "GUEST_CERT", ExitCode: ExGuestError}
+	// minikube failed to list the certificates of the cluster
+	GuestCertList = Kind{ID: "GUEST_CERT_LIST", ExitCode: ExGuestError}
+	// minikube failed to issue new certificates to a running cluster
+	GuestCertRotate = Kind{ID: "GUEST_CERT_ROTATE", ExitCode: ExGuestError}
 	// minikube failed to access the control plane
 	GuestCpConfig = Kind{ID: "GUEST_CP_CONFIG", ExitCode: ExGuestConfig}
 	// minikube failed to properly delete a resource, such as a profile
 	GuestDeletion = Kind{ID: "GUEST_DELETION", ExitCode: ExGuestError}
+	// minikube failed to save a snapshot of etcd
+	GuestEtcdBackup = Kind{ID: "GUEST_ETCD_BACKUP", ExitCode: ExGuestError}
+	// minikube failed to restore etcd from a snapshot
+	GuestEtcdRestore = Kind{ID: "GUEST_ETCD_RESTORE", ExitCode: ExGuestError}
+	// minikube failed to defragment etcd
+	GuestEtcdDefrag = Kind{ID: "GUEST_ETCD_DEFRAG", ExitCode: ExGuestError}
+	// minikube failed to get the status of etcd
+	GuestEtcdStatus = Kind{ID: "GUEST_ETCD_STATUS", ExitCode: ExGuestError}
 	// minikube failed to list images on the machine
 	GuestImageList = Kind{ID: "GUEST_IMAGE_LIST", ExitCode: ExGuestError}
 	// minikube failed to pull or load an image
@@ -351,6 +379,10 @@ var (
 	GuestImagePush = Kind{ID: "GUEST_IMAGE_PUSH", ExitCode: ExGuestError}
 	// minikube failed to tag an image
 	GuestImageTag = Kind{ID: "GUEST_IMAGE_TAG", ExitCode: ExGuestError}
+	// minikube failed to inspect or compare an image
+	GuestImageInspect = Kind{ID: "GUEST_IMAGE_INSPECT", ExitCode: ExGuestError}
+	// minikube failed to prune images
+	GuestImagePrune = Kind{ID: "GUEST_IMAGE_PRUNE", ExitCode: ExGuestError}
 	// minikube failed to load host
 	GuestLoadHost = Kind{ID: "GUEST_LOAD_HOST", ExitCode: ExGuestError}
 	// minkube failed to create a mount
@@ -370,10 +402,20 @@ var (
 	GuestNodeAdd = Kind{ID: "GUEST_NODE_ADD", ExitCode: ExGuestError}
 	// minikube failed to remove a node from the cluster
 	GuestNodeDelete = Kind{ID: "GUEST_NODE_DELETE", ExitCode: ExGuestError}
+	// minikube failed to add, remove or list the extra disks of a node
+	GuestNodeDisk = Kind{ID: "GUEST_NODE_DISK", ExitCode: ExGuestError}
+	// the apiserver of an ha (multi-control plane) cluster did not recover from the loss of its leader control-plane node
+	GuestNodeFailover = Kind{ID: "GUEST_NODE_FAILOVER", ExitCode: ExGuestTimeout}
 	// minikube failed to provision a node
 	GuestNodeProvision = Kind{ID: "GUEST_NODE_PROVISION", ExitCode: ExGuestError}
+	// minikube failed to resize the disk or the memory of a node
+	GuestNodeResize = Kind{ID: "GUEST_NODE_RESIZE", ExitCode: ExGuestError}
 	// minikube failed to retrieve information for a cluster node
 	GuestNodeRetrieve = Kind{ID: "GUEST_NODE_RETRIEVE", ExitCode: ExGuestNotFound}
+	// minikube failed to promote or demote a cluster node
+	GuestNodeRole = Kind{ID: "GUEST_NODE_ROLE", ExitCode: ExGuestError}
+	// minikube failed to save, restore, delete or list the snapshots of a node
+	GuestNodeSnapshot = Kind{ID: "GUEST_NODE_SNAPSHOT", ExitCode: ExGuestError}
 	// minikube failed to startup a cluster node
 	GuestNodeStart = Kind{ID: "GUEST_NODE_START", ExitCode: ExGuestError}
 	// minikube failed to pause the cluster process
@@ -424,6 +466,8 @@ var (
 	InetCacheBinaries = Kind{ID: "INET_CACHE_BINARIES", ExitCode: ExInternetError}
 	// minikube failed to cache the kubectl binary
 	InetCacheKubectl = Kind{ID: "INET_CACHE_KUBECTL", ExitCode: ExInternetError}
+	// minikube failed to download the artifacts to bundle for an offline start
+	InetCacheBundle = Kind{ID: "INET_CACHE_BUNDLE", ExitCode: ExInternetError}
 	// minikube failed to cache required images to tar files
 	InetCacheTar = Kind{ID: "INET_CACHE_TAR", ExitCode: ExInternetError}
 	// minikube failed to download licenses
@@ -479,6 +523,14 @@ var (
 	KubernetesInstallFailed = Kind{ID: "K8S_INSTALL_FAILED", ExitCode: ExControlPlaneError}
 	// minikube failed to update the Kubernetes cluster because the container runtime was unavailable
 	KubernetesInstallFailedRuntimeNotRunning = Kind{ID: "K8S_INSTALL_FAILED_CONTAINER_RUNTIME_NOT_RUNNING", ExitCode: ExRuntimeNotRunning}
+	// minikube failed to change the Kubernetes settings of the cluster in place
+	KubernetesConfigureFailed = Kind{ID: "K8S_CONFIGURE_FAILED", ExitCode: ExControlPlaneError}
+	// the pre-flight checks found upgrading the Kubernetes version of the cluster to be unsafe
+	KubernetesUpgradePreflight = Kind{ID: "K8S_UPGRADE_PREFLIGHT", ExitCode: ExControlPlaneConflict}
+	// minikube failed to upgrade the Kubernetes version of the cluster
+	KubernetesUpgradeFailed = Kind{ID: "K8S_UPGRADE_FAILED", ExitCode: ExControlPlaneError}
+	// the resource conditions given with --wait-for were not met in time
+	KubernetesWaitTimeout = Kind{ID: "K8S_WAIT_TIMEOUT", ExitCode: ExControlPlaneTimeout}
 	// an outdated Kubernetes version was specified for minikube to use
 	KubernetesTooOld = Kind{ID: "K8S_OLD_UNSUPPORTED", ExitCode: ExControlPlaneUnsupported}
 	// a too new Kubernetes version was specified for minikube to use
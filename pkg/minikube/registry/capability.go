@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Capability is a feature of minikube which only some drivers support
+type Capability string
+
+const (
+	// Mount is mounting host directories into the nodes, with 'minikube mount' or --mount
+	Mount Capability = "mount"
+	// MultiNode is running clusters of more than one node
+	MultiNode Capability = "multi-node"
+	// StaticIP is choosing the IP of the node with --static-ip
+	StaticIP Capability = "static-ip"
+	// ExtraDisks is attaching disks besides the one of the node with --extra-disks
+	ExtraDisks Capability = "extra-disks"
+	// GPUs is giving pods access to the GPUs of the host with --gpus
+	GPUs Capability = "gpus"
+	// ScheduledStop is stopping clusters later on, with 'minikube stop --schedule'
+	ScheduledStop Capability = "scheduled-stop"
+	// Tunnel is routing to LoadBalancer services with 'minikube tunnel'
+	Tunnel Capability = "tunnel"
+	// ExposedPorts is publishing ports of the node on the host with --ports
+	ExposedPorts Capability = "exposed-ports"
+)
+
+// AllCapabilities are all the capabilities, in the order they are listed in
+var AllCapabilities = []Capability{Mount, MultiNode, StaticIP, ExtraDisks, GPUs, ScheduledStop, Tunnel, ExposedPorts}
+
+// Capabilities is the set of capabilities of a driver
+type Capabilities []Capability
+
+// Has returns whether c is one of the capabilities
+func (cs Capabilities) Has(c Capability) bool {
+	for _, have := range cs {
+		if have == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Missing returns the capabilities of requested which are not in cs
+func (cs Capabilities) Missing(requested ...Capability) Capabilities {
+	var missing Capabilities
+	for _, c := range requested {
+		if !cs.Has(c) && !missing.Has(c) {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+func (cs Capabilities) String() string {
+	names := make([]string, len(cs))
+	for i, c := range cs {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Unsupported returns the capabilities of requested which the named driver lacks.
+// Drivers which are not registered, such as the mock driver of tests, are assumed to support everything.
+func Unsupported(name string, requested ...Capability) Capabilities {
+	d := Driver(name)
+	if d.Empty() {
+		return nil
+	}
+	return d.Capabilities.Missing(requested...)
+}
+
+// UnsupportedError is the error of a driver lacking capabilities which were asked for
+type UnsupportedError struct {
+	Driver  string
+	Missing Capabilities
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("the %s driver does not support %s", e.Driver, e.Missing)
+}
+
+// CheckCapabilities returns an *UnsupportedError if the named driver lacks any of the requested capabilities
+func CheckCapabilities(name string, requested ...Capability) error {
+	if missing := Unsupported(name, requested...); len(missing) > 0 {
+		return &UnsupportedError{Driver: name, Missing: missing}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCapabilitiesMissing(t *testing.T) {
+	cs := Capabilities{Mount, MultiNode}
+	tests := []struct {
+		requested []Capability
+		want      Capabilities
+	}{
+		{nil, nil},
+		{[]Capability{Mount}, nil},
+		{[]Capability{Mount, GPUs}, Capabilities{GPUs}},
+		{[]Capability{GPUs, StaticIP, GPUs}, Capabilities{GPUs, StaticIP}},
+	}
+	for _, tc := range tests {
+		if diff := cmp.Diff(tc.want, cs.Missing(tc.requested...)); diff != "" {
+			t.Errorf("Missing(%v) mismatch (-want +got):\n%s", tc.requested, diff)
+		}
+	}
+}
+
+func TestCheckCapabilities(t *testing.T) {
+	globalRegistry = newRegistry()
+	if err := Register(DriverDef{Name: "foo", Capabilities: Capabilities{Mount}}); err != nil {
+		t.Fatalf("register returned error: %v", err)
+	}
+
+	if err := CheckCapabilities("foo", Mount); err != nil {
+		t.Errorf("CheckCapabilities(foo, mount) = %v, expected nil", err)
+	}
+	err := CheckCapabilities("foo", Mount, Tunnel, ExposedPorts)
+	if err == nil || err.Error() != "the foo driver does not support tunnel, exposed-ports" {
+		t.Errorf("CheckCapabilities(foo, mount, tunnel, exposed-ports) = %v", err)
+	}
+	if err := CheckCapabilities("bar", GPUs); err != nil {
+		t.Errorf("CheckCapabilities of an unregistered driver = %v, expected nil", err)
+	}
+}
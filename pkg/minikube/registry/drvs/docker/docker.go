@@ -46,13 +46,20 @@ const (
 )
 
 func init() {
+	capabilities := registry.Capabilities{registry.Mount, registry.MultiNode, registry.StaticIP, registry.GPUs, registry.ScheduledStop, registry.Tunnel, registry.ExposedPorts}
+	// extra disks are loop devices of the host
+	if runtime.GOOS == "linux" {
+		capabilities = append(capabilities, registry.ExtraDisks)
+	}
+
 	if err := registry.Register(registry.DriverDef{
-		Name:     driver.Docker,
-		Config:   configure,
-		Init:     func() drivers.Driver { return kic.NewDriver(kic.Config{OCIBinary: oci.Docker}) },
-		Status:   status,
-		Default:  true,
-		Priority: registry.HighlyPreferred,
+		Name:         driver.Docker,
+		Config:       configure,
+		Init:         func() drivers.Driver { return kic.NewDriver(kic.Config{OCIBinary: oci.Docker}) },
+		Status:       status,
+		Default:      true,
+		Priority:     registry.HighlyPreferred,
+		Capabilities: capabilities,
 	}); err != nil {
 		panic(fmt.Sprintf("register failed: %v", err))
 	}
@@ -92,6 +99,8 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		StaticIP:          cc.StaticIP,
 		ListenAddress:     cc.ListenAddress,
 		GPUs:              cc.GPUs,
+		ExtraDisks:        cc.ExtraDisks,
+		DiskSize:          cc.DiskSize,
 	}), nil
 }
 
@@ -121,6 +130,10 @@ func status() (retState registry.State) {
 	dockerEngineVersion := versions[0]
 	dockerPlatformVersion := versions[1]
 	klog.Infof("docker version: %s", version)
+	defer func() {
+		// the engine version is prefixed with the OS, as in linux-24.0.7
+		retState.Version = dockerEngineVersion[strings.Index(dockerEngineVersion, "-")+1:]
+	}()
 	if !viper.GetBool("force") {
 		if s := checkDockerDesktopVersion(dockerPlatformVersion); s.Error != nil {
 			return s
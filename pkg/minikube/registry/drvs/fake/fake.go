@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"k8s.io/minikube/pkg/drivers/fake"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+func init() {
+	if err := registry.Register(registry.DriverDef{
+		Name:         driver.Fake,
+		Config:       configure,
+		Init:         func() drivers.Driver { return fake.NewDriver(fake.Config{}) },
+		Status:       func() registry.State { return registry.State{Installed: true, Healthy: true} },
+		Default:      false, // runs nothing, only good for testing
+		Priority:     registry.Experimental,
+		Capabilities: registry.Capabilities{registry.MultiNode, registry.ScheduledStop},
+	}); err != nil {
+		panic(fmt.Sprintf("register failed: %v", err))
+	}
+}
+
+func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	name := config.MachineName(cc, n)
+	port := 0
+	if n.ControlPlane {
+		port = n.Port
+	}
+	return fake.NewDriver(fake.Config{
+		MachineName: name,
+		StorePath:   localpath.MiniPath(),
+		// loopback addresses other than 127.0.0.1 refuse connections at once, rather than reaching a local service
+		IPAddress:         fmt.Sprintf("127.0.0.%d", driver.IndexFromMachineName(name)+1),
+		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		APIServerPort:     port,
+	}), nil
+}
@@ -49,11 +49,12 @@ var (
 
 func init() {
 	if err := registry.Register(registry.DriverDef{
-		Name:     driver.HyperKit,
-		Config:   configure,
-		Status:   status,
-		Default:  true,
-		Priority: registry.Preferred,
+		Name:         driver.HyperKit,
+		Config:       configure,
+		Status:       status,
+		Default:      true,
+		Priority:     registry.Preferred,
+		Capabilities: registry.Capabilities{registry.Mount, registry.MultiNode, registry.ExtraDisks, registry.ScheduledStop, registry.Tunnel},
 	}); err != nil {
 		panic(fmt.Sprintf("register: %v", err))
 	}
@@ -43,12 +43,13 @@ const (
 
 func init() {
 	if err := registry.Register(registry.DriverDef{
-		Name:     driver.HyperV,
-		Init:     func() drivers.Driver { return hyperv.NewDriver("", "") },
-		Config:   configure,
-		Status:   status,
-		Default:  true,
-		Priority: registry.Preferred,
+		Name:         driver.HyperV,
+		Init:         func() drivers.Driver { return hyperv.NewDriver("", "") },
+		Config:       configure,
+		Status:       status,
+		Default:      true,
+		Priority:     registry.Preferred,
+		Capabilities: registry.Capabilities{registry.Mount, registry.MultiNode, registry.ScheduledStop, registry.Tunnel},
 	}); err != nil {
 		panic(fmt.Sprintf("register: %v", err))
 	}
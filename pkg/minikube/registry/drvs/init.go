@@ -19,10 +19,12 @@ package drvs
 import (
 	// Register all of the drvs we know of
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/docker"
+	_ "k8s.io/minikube/pkg/minikube/registry/drvs/fake"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/hyperkit"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/hyperv"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/kvm2"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/none"
+	_ "k8s.io/minikube/pkg/minikube/registry/drvs/nspawn"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/parallels"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/podman"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/qemu2"
@@ -30,4 +32,12 @@ import (
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/vfkit"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/virtualbox"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/vmware"
+
+	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/minikube/registry/drvs/plugins"
 )
+
+func init() {
+	// out-of-tree drivers are only looked for when a driver is first looked up, as it runs each of them
+	registry.AddDiscoverer(plugins.Register)
+}
@@ -43,12 +43,13 @@ const (
 
 func init() {
 	if err := registry.Register(registry.DriverDef{
-		Name:     driver.KVM2,
-		Alias:    []string{driver.AliasKVM},
-		Config:   configure,
-		Status:   status,
-		Default:  true,
-		Priority: registry.Preferred,
+		Name:         driver.KVM2,
+		Alias:        []string{driver.AliasKVM},
+		Config:       configure,
+		Status:       status,
+		Default:      true,
+		Priority:     registry.Preferred,
+		Capabilities: registry.Capabilities{registry.Mount, registry.MultiNode, registry.ExtraDisks, registry.ScheduledStop, registry.Tunnel},
 	}); err != nil {
 		panic(fmt.Sprintf("register failed: %v", err))
 	}
@@ -33,13 +33,14 @@ import (
 
 func init() {
 	if err := registry.Register(registry.DriverDef{
-		Name:     driver.None,
-		Alias:    []string{driver.AliasNative},
-		Config:   configure,
-		Init:     func() drivers.Driver { return none.NewDriver(none.Config{}) },
-		Status:   status,
-		Default:  false, // no isolation
-		Priority: registry.Discouraged,
+		Name:         driver.None,
+		Alias:        []string{driver.AliasNative},
+		Config:       configure,
+		Init:         func() drivers.Driver { return none.NewDriver(none.Config{}) },
+		Status:       status,
+		Default:      false, // no isolation
+		Priority:     registry.Discouraged,
+		Capabilities: registry.Capabilities{registry.Tunnel},
 	}); err != nil {
 		panic(fmt.Sprintf("register failed: %v", err))
 	}
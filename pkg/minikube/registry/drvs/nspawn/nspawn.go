@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nspawn
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/nspawn"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+const docURL = "https://minikube.sigs.k8s.io/docs/reference/drivers/nspawn/"
+
+func init() {
+	if err := registry.Register(registry.DriverDef{
+		Name:         driver.Nspawn,
+		Config:       configure,
+		Init:         func() drivers.Driver { return nspawn.NewDriver(nspawn.Config{}) },
+		Status:       status,
+		Default:      false, // needs passwordless sudo
+		Priority:     registry.Experimental,
+		Capabilities: registry.Capabilities{registry.Mount, registry.MultiNode, registry.ScheduledStop, registry.Tunnel},
+	}); err != nil {
+		panic(fmt.Sprintf("register failed: %v", err))
+	}
+}
+
+func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	id, err := node.ID(n.Name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node name %q: %v", n.Name, err)
+	}
+	image := cc.KicBaseImage
+	if image == "" {
+		image = kic.BaseImage
+	}
+	return nspawn.NewDriver(nspawn.Config{
+		ClusterName: cc.Name,
+		MachineName: config.MachineName(cc, n),
+		StorePath:   localpath.MiniPath(),
+		Image:       image,
+		CPU:         cc.CPUs,
+		Memory:      cc.Memory,
+		NodeID:      id,
+	}), nil
+}
+
+func status() registry.State {
+	if runtime.GOOS != "linux" {
+		return registry.State{Error: fmt.Errorf("the nspawn driver is only supported on Linux"), Doc: docURL}
+	}
+	for _, program := range []string{"systemd-nspawn", "machinectl"} {
+		if _, err := exec.LookPath(program); err != nil {
+			return registry.State{Error: err, Fix: "Install systemd-container", Doc: docURL}
+		}
+	}
+	if err := exec.Command("sudo", "-n", "true").Run(); err != nil {
+		return registry.State{Installed: true, Running: true, Error: fmt.Errorf("the nspawn driver requires passwordless sudo permissions: %v", err), Doc: docURL}
+	}
+	return registry.State{Installed: true, Healthy: true, Running: true}
+}
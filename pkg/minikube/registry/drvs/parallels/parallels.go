@@ -33,12 +33,13 @@ import (
 
 func init() {
 	err := registry.Register(registry.DriverDef{
-		Name:     driver.Parallels,
-		Config:   configure,
-		Status:   status,
-		Default:  true,
-		Priority: registry.Default,
-		Init:     func() drivers.Driver { return parallels.NewDriver("", "") },
+		Name:         driver.Parallels,
+		Config:       configure,
+		Status:       status,
+		Default:      true,
+		Priority:     registry.Default,
+		Capabilities: registry.Capabilities{registry.Mount, registry.MultiNode, registry.ScheduledStop, registry.Tunnel},
+		Init:         func() drivers.Driver { return parallels.NewDriver("", "") },
 	})
 	if err != nil {
 		panic(fmt.Sprintf("unable to register: %v", err))
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugins registers the out-of-tree driver plugins found on the host
+package plugins
+
+import (
+	"github.com/docker/machine/libmachine/drivers"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/drivers/plugin"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+// Register registers the driver of every plugin found in ~/.minikube/drivers or on the PATH, unless lookup finds a driver of the same name
+func Register(lookup func(name string) registry.DriverDef) {
+	for _, b := range plugin.Discover(plugin.SearchPath()) {
+		if !lookup(b.Name).Empty() {
+			klog.Warningf("ignoring driver plugin %s: driver %q already exists", b.Path, b.Name)
+			continue
+		}
+		info, err := plugin.QueryInfo(b)
+		if err != nil {
+			klog.Warningf("ignoring driver plugin %s: %v", b.Path, err)
+			continue
+		}
+		if taken := takenAlias(lookup, info.Alias); taken != "" {
+			klog.Warningf("ignoring driver plugin %s: alias %q already exists", b.Path, taken)
+			continue
+		}
+		b := b
+		if err := registry.Register(registry.DriverDef{
+			Name:         info.Name,
+			Alias:        info.Alias,
+			Config:       configure,
+			Init:         func() drivers.Driver { return plugin.NewDriver(b.Path) },
+			Status:       func() registry.State { return plugin.QueryStatus(b) },
+			Default:      info.Default,
+			Priority:     info.RegistryPriority(),
+			Capabilities: info.Capabilities,
+			Plugin:       b.Path,
+		}); err != nil {
+			klog.Warningf("ignoring driver plugin %s: %v", b.Path, err)
+		}
+	}
+}
+
+// takenAlias returns the first of the aliases which names a registered driver, if any
+func takenAlias(lookup func(name string) registry.DriverDef, aliases []string) string {
+	for _, a := range aliases {
+		if !lookup(a).Empty() {
+			return a
+		}
+	}
+	return ""
+}
+
+func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	return plugin.MachineConfig{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: config.MachineName(cc, n),
+			StorePath:   localpath.MiniPath(),
+			SSHUser:     "docker",
+		},
+		Boot2DockerURL:    download.LocalISOResource(cc.MinikubeISO),
+		CPU:               cc.CPUs,
+		Memory:            cc.Memory,
+		DiskSize:          cc.DiskSize,
+		ExtraDisks:        cc.ExtraDisks,
+		Network:           cc.Network,
+		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
+	}, nil
+}
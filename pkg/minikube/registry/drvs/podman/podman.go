@@ -45,9 +45,13 @@ var minReqPodmanVer = semver.Version{Major: 4, Minor: 9, Patch: 0}
 
 func init() {
 	priority := registry.Default
+	capabilities := registry.Capabilities{registry.Mount, registry.MultiNode, registry.StaticIP, registry.ScheduledStop, registry.Tunnel, registry.ExposedPorts}
 	if runtime.GOOS != "linux" {
 		// requires external VM set up
 		priority = registry.Experimental
+	} else {
+		// extra disks are loop devices of the host
+		capabilities = append(capabilities, registry.ExtraDisks)
 	}
 	// Staged rollout for default:
 	// - Linux (sudo podman)
@@ -55,12 +59,13 @@ func init() {
 	// - Windows (podman-remote)
 
 	if err := registry.Register(registry.DriverDef{
-		Name:     driver.Podman,
-		Config:   configure,
-		Init:     func() drivers.Driver { return kic.NewDriver(kic.Config{OCIBinary: oci.Podman}) },
-		Status:   status,
-		Default:  true,
-		Priority: priority,
+		Name:         driver.Podman,
+		Config:       configure,
+		Init:         func() drivers.Driver { return kic.NewDriver(kic.Config{OCIBinary: oci.Podman}) },
+		Status:       status,
+		Default:      true,
+		Priority:     priority,
+		Capabilities: capabilities,
 	}); err != nil {
 		panic(fmt.Sprintf("register failed: %v", err))
 	}
@@ -97,6 +102,8 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		ExtraArgs:         extraArgs,
 		ListenAddress:     cc.ListenAddress,
 		Subnet:            cc.Subnet,
+		ExtraDisks:        cc.ExtraDisks,
+		DiskSize:          cc.DiskSize,
 	}), nil
 }
 
@@ -132,7 +139,7 @@ func status() registry.State {
 				out.V{"minVersion": minReqPodmanVer.String(), "currentVersion": v.String()})
 		}
 
-		return registry.State{Installed: true, Healthy: true}
+		return registry.State{Installed: true, Healthy: true, Version: v.String()}
 	}
 
 	klog.Warningf("podman returned error: %v", err)
@@ -19,6 +19,7 @@ package qemu2
 import (
 	"crypto/rand"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -35,7 +36,9 @@ import (
 	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/network"
 )
 
 const docURL = "https://minikube.sigs.k8s.io/docs/reference/drivers/qemu/"
@@ -46,20 +49,20 @@ func init() {
 		priority = registry.Experimental
 	}
 	if err := registry.Register(registry.DriverDef{
-		Name:     driver.QEMU2,
-		Alias:    []string{driver.AliasQEMU},
-		Init:     func() drivers.Driver { return qemu.NewDriver("", "") },
-		Config:   configure,
-		Status:   status,
-		Default:  true,
-		Priority: priority,
+		Name:         driver.QEMU2,
+		Alias:        []string{driver.AliasQEMU},
+		Init:         func() drivers.Driver { return qemu.NewDriver("", "") },
+		Config:       configure,
+		Status:       status,
+		Default:      true,
+		Priority:     priority,
+		Capabilities: registry.Capabilities{registry.Mount, registry.MultiNode, registry.ExtraDisks, registry.ScheduledStop, registry.Tunnel, registry.ExposedPorts},
 	}); err != nil {
 		panic(fmt.Sprintf("register failed: %v", err))
 	}
 }
 
-func qemuSystemProgram() (string, error) {
-	arch := runtime.GOARCH
+func qemuSystemProgram(arch string) (string, error) {
 	switch arch {
 	case "amd64":
 		return "qemu-system-x86_64", nil
@@ -70,27 +73,35 @@ func qemuSystemProgram() (string, error) {
 	}
 }
 
-func qemuFirmwarePath(customPath string) (string, error) {
+// qemuFirmwareName returns the name of the edk2 firmware for guests of an architecture, as shipped with qemu
+func qemuFirmwareName(arch string) (string, error) {
+	switch arch {
+	case "amd64":
+		return "edk2-x86_64-code.fd", nil
+	case "arm64":
+		return "edk2-aarch64-code.fd", nil
+	default:
+		return "", fmt.Errorf("unknown arch: %s", arch)
+	}
+}
+
+func qemuFirmwarePath(customPath, arch string) (string, error) {
 	if customPath != "" {
 		return customPath, nil
 	}
-	if runtime.GOOS == "windows" {
-		return "C:\\Program Files\\qemu\\share\\edk2-x86_64-code.fd", nil
-	}
-	if detect.IsAmd64M1Emulation() {
-		return "/opt/homebrew/opt/qemu/share/qemu/edk2-x86_64-code.fd", nil
-	}
-	arch := runtime.GOARCH
-	// For macOS, find the correct brew installation path for qemu firmware
-	if runtime.GOOS == "darwin" {
-		switch arch {
-		case "amd64":
-			return "/usr/local/opt/qemu/share/qemu/edk2-x86_64-code.fd", nil
-		case "arm64":
-			return "/opt/homebrew/opt/qemu/share/qemu/edk2-aarch64-code.fd", nil
-		default:
-			return "", fmt.Errorf("unknown arch: %s", arch)
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		name, err := qemuFirmwareName(arch)
+		if err != nil {
+			return "", err
 		}
+		if runtime.GOOS == "windows" {
+			return "C:\\Program Files\\qemu\\share\\" + name, nil
+		}
+		// For macOS, find the correct brew installation path for qemu firmware
+		if runtime.GOARCH == "arm64" || detect.IsAmd64M1Emulation() {
+			return "/opt/homebrew/opt/qemu/share/qemu/" + name, nil
+		}
+		return "/usr/local/opt/qemu/share/qemu/" + name, nil
 	}
 
 	switch arch {
@@ -103,12 +114,7 @@ func qemuFirmwarePath(customPath string) (string, error) {
 	}
 }
 
-func qemuVersion() (semver.Version, error) {
-	qemuSystem, err := qemuSystemProgram()
-	if err != nil {
-		return semver.Version{}, err
-	}
-
+func qemuVersion(qemuSystem string) (semver.Version, error) {
 	cmd := exec.Command(qemuSystem, "-version")
 	rr, err := cmd.Output()
 	if err != nil {
@@ -120,13 +126,15 @@ func qemuVersion() (semver.Version, error) {
 
 func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 	name := config.MachineName(cc, n)
-	qemuSystem, err := qemuSystemProgram()
+	arch := config.GuestArch(cc)
+	qemuSystem, err := qemuSystemProgram(arch)
 	if err != nil {
 		return nil, err
 	}
+	accel := qemu.Accel(cc.QemuAccel, arch)
 	var qemuMachine string
 	var qemuCPU string
-	switch runtime.GOARCH {
+	switch arch {
 	case "amd64":
 		qemuMachine = "" // default
 		// set cpu type to max to enable higher microarchitecture levels
@@ -135,10 +143,14 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 	case "arm64":
 		qemuMachine = "virt"
 		qemuCPU = "cortex-a72"
+		// an emulated guest has no host CPU to pass through, and needs none of the workarounds of the accelerators
+		if qemu.IsEmulated(accel) {
+			break
+		}
 		// highmem=off needed for qemu 6.2.0 and lower, see https://patchwork.kernel.org/project/qemu-devel/patch/20201126215017.41156-9-agraf@csgraf.de/#23800615 for details
 		if runtime.GOOS == "darwin" {
 			qemu7 := semver.MustParse("7.0.0")
-			v, err := qemuVersion()
+			v, err := qemuVersion(qemuSystem)
 			if err != nil {
 				return nil, err
 			}
@@ -147,14 +159,14 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 				qemuMachine += ",highmem=off"
 			}
 			qemuCPU = "host"
-		} else if _, err := os.Stat("/dev/kvm"); err == nil {
+		} else if accel == "kvm" {
 			qemuMachine += ",gic-version=3"
 			qemuCPU = "host"
 		}
 	default:
-		return nil, fmt.Errorf("unknown arch: %s", runtime.GOARCH)
+		return nil, fmt.Errorf("unknown arch: %s", arch)
 	}
-	qemuFirmware, err := qemuFirmwarePath(cc.CustomQemuFirmwarePath)
+	qemuFirmware, err := qemuFirmwarePath(cc.CustomQemuFirmwarePath, arch)
 	if err != nil {
 		return nil, err
 	}
@@ -163,6 +175,20 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		return nil, fmt.Errorf("generating MAC address: %v", err)
 	}
 
+	var forwardedPorts []string
+	var sharedNetwork, sharedNetworkIP string
+	if network.IsBuiltinQEMU(cc.Network) {
+		// a port of the host can only be forwarded to one node
+		if config.IsPrimaryControlPlane(cc, n) {
+			forwardedPorts = cc.ExposedPorts
+		}
+		sharedNetwork = sharedNetworkAddress(cc.Name)
+		sharedNetworkIP, err = sharedNetworkNodeIP(n.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return qemu.Driver{
 		BaseDriver: &drivers.BaseDriver{
 			MachineName: name,
@@ -177,9 +203,10 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		FirstQuery:            true,
 		DiskPath:              filepath.Join(localpath.MiniPath(), "machines", name, fmt.Sprintf("%s.img", name)),
 		Program:               qemuSystem,
-		BIOS:                  runtime.GOARCH != "arm64",
+		BIOS:                  arch != "arm64",
 		MachineType:           qemuMachine,
 		CPUType:               qemuCPU,
+		Accel:                 accel,
 		Firmware:              qemuFirmware,
 		VirtioDrives:          false,
 		Network:               cc.Network,
@@ -189,11 +216,40 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		SocketVMNetPath:       cc.SocketVMnetPath,
 		SocketVMNetClientPath: cc.SocketVMnetClientPath,
 		ExtraDisks:            cc.ExtraDisks,
+		ForwardedPorts:        forwardedPorts,
+		SharedNetwork:         sharedNetwork,
+		SharedNetworkIP:       sharedNetworkIP,
 	}, nil
 }
 
+// sharedNetworkAddress returns the multicast group:port of the network between the nodes of a cluster with the builtin network,
+// which differs from one cluster, and one minikube home, to the other
+func sharedNetworkAddress(clusterName string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(filepath.Join(localpath.MiniPath(), clusterName)))
+	sum := h.Sum32()
+	// 239.0.0.0/8 is for multicast within an organization, and ports above 49152 are dynamic
+	return fmt.Sprintf("239.%d.%d.%d:%d", byte(sum>>24), byte(sum>>16), byte(sum>>8), 49152+sum%16384)
+}
+
+// sharedNetworkNodeIP returns the IP of a node on the shared network of its cluster, which follows from its ID
+func sharedNetworkNodeIP(nodeName string) (string, error) {
+	id, err := node.ID(nodeName)
+	if err != nil {
+		return "", fmt.Errorf("invalid node name %q: %v", nodeName, err)
+	}
+	if id > 244 {
+		return "", fmt.Errorf("the shared network has no room for node %q", nodeName)
+	}
+	return fmt.Sprintf("10.0.3.%d", 10+id), nil
+}
+
 func status() registry.State {
-	qemuSystem, err := qemuSystemProgram()
+	arch := viper.GetString("qemu-arch")
+	if arch == "" {
+		arch = runtime.GOARCH
+	}
+	qemuSystem, err := qemuSystemProgram(arch)
 	if err != nil {
 		return registry.State{Error: err, Doc: docURL}
 	}
@@ -202,7 +258,7 @@ func status() registry.State {
 		return registry.State{Error: err, Fix: "Install qemu-system", Doc: docURL}
 	}
 
-	qemuFirmware, err := qemuFirmwarePath(viper.GetString("qemu-firmware-path"))
+	qemuFirmware, err := qemuFirmwarePath(viper.GetString("qemu-firmware-path"), arch)
 	if err != nil {
 		return registry.State{Error: err, Doc: docURL}
 	}
@@ -211,7 +267,24 @@ func status() registry.State {
 		return registry.State{Error: err, Fix: "Install uefi firmware", Doc: docURL}
 	}
 
-	return registry.State{Installed: true, Healthy: true, Running: true}
+	var version string
+	if v, err := qemuVersion(qemuSystem); err == nil {
+		version = v.String()
+	}
+
+	if qemu.IsEmulated(qemu.Accel(viper.GetString("qemu-accel"), arch)) {
+		return registry.State{
+			Installed:        true,
+			Healthy:          true,
+			Running:          true,
+			NeedsImprovement: true,
+			Fix:              "run a guest of the host architecture with hardware acceleration (KVM or HVF) rather than TCG software emulation",
+			Doc:              docURL + "#emulation",
+			Version:          version,
+		}
+	}
+
+	return registry.State{Installed: true, Healthy: true, Running: true, Version: version}
 }
 
 func generateMACAddress() (string, error) {
@@ -29,18 +29,20 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/registry"
 )
 
 func init() {
 	err := registry.Register(registry.DriverDef{
-		Name:     driver.SSH,
-		Alias:    []string{driver.AliasSSH},
-		Config:   configure,
-		Status:   status,
-		Default:  false, // requires external VM
-		Priority: registry.Discouraged,
-		Init:     func() drivers.Driver { return ssh.NewDriver(ssh.Config{}) },
+		Name:         driver.SSH,
+		Alias:        []string{driver.AliasSSH},
+		Config:       configure,
+		Status:       status,
+		Default:      false, // requires external VM
+		Priority:     registry.Discouraged,
+		Capabilities: registry.Capabilities{registry.Mount, registry.MultiNode, registry.ScheduledStop, registry.Tunnel},
+		Init:         func() drivers.Driver { return ssh.NewDriver(ssh.Config{}) },
 	})
 	if err != nil {
 		panic(fmt.Sprintf("unable to register: %v", err))
@@ -54,18 +56,24 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		ContainerRuntime: cc.KubernetesConfig.ContainerRuntime,
 	})
 
-	if cc.SSHIPAddress == "" {
+	id, err := node.ID(n.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "node %q", n.Name)
+	}
+	ip := config.SSHHost(cc, id)
+	if ip == "" {
 		return nil, errors.Errorf("please provide an IP address")
 	}
 
 	// We don't want the API server listening on loopback interface,
 	// even if we might use a tunneled VM port for the SSH service
-	if cc.SSHIPAddress == "127.0.0.1" || cc.SSHIPAddress == "localhost" {
+	if ip == "127.0.0.1" || ip == "localhost" {
 		return nil, errors.Errorf("please provide real IP address")
 	}
 
-	d.IPAddress = cc.SSHIPAddress
+	d.IPAddress = ip
 	d.SSHUser = cc.SSHUser
+	d.PrepareHost = cc.SSHPrepareHost
 
 	if strings.HasPrefix(cc.SSHKey, "~") {
 		dirname, err := os.UserHomeDir()
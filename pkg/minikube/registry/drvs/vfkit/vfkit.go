@@ -39,12 +39,13 @@ const (
 
 func init() {
 	if err := registry.Register(registry.DriverDef{
-		Name:     driver.VFKit,
-		Init:     func() drivers.Driver { return vfkit.NewDriver("", "") },
-		Config:   configure,
-		Status:   status,
-		Default:  true,
-		Priority: registry.Experimental,
+		Name:         driver.VFKit,
+		Init:         func() drivers.Driver { return vfkit.NewDriver("", "") },
+		Config:       configure,
+		Status:       status,
+		Default:      true,
+		Priority:     registry.Experimental,
+		Capabilities: registry.Capabilities{registry.Mount, registry.MultiNode, registry.ExtraDisks, registry.ScheduledStop, registry.Tunnel},
 	}); err != nil {
 		panic(fmt.Sprintf("register failed: %v", err))
 	}
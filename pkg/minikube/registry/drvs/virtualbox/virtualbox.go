@@ -43,12 +43,13 @@ const (
 
 func init() {
 	err := registry.Register(registry.DriverDef{
-		Name:     driver.VirtualBox,
-		Config:   configure,
-		Status:   status,
-		Default:  true,
-		Priority: registry.Fallback,
-		Init:     func() drivers.Driver { return virtualbox.NewDriver("", "") },
+		Name:         driver.VirtualBox,
+		Config:       configure,
+		Status:       status,
+		Default:      true,
+		Priority:     registry.Fallback,
+		Capabilities: registry.Capabilities{registry.Mount, registry.MultiNode, registry.ScheduledStop, registry.Tunnel},
+		Init:         func() drivers.Driver { return virtualbox.NewDriver("", "") },
 	})
 	if err != nil {
 		panic(fmt.Sprintf("unable to register: %v", err))
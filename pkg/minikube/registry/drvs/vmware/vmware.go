@@ -31,12 +31,13 @@ import (
 
 func init() {
 	err := registry.Register(registry.DriverDef{
-		Name:     driver.VMware,
-		Config:   configure,
-		Default:  false,
-		Priority: registry.Deprecated,
-		Init:     func() drivers.Driver { return vmware.NewDriver("", "") },
-		Status:   status,
+		Name:         driver.VMware,
+		Config:       configure,
+		Default:      false,
+		Priority:     registry.Deprecated,
+		Capabilities: registry.Capabilities{registry.Mount, registry.MultiNode, registry.ScheduledStop, registry.Tunnel},
+		Init:         func() drivers.Driver { return vmware.NewDriver("", "") },
+		Status:       status,
 	})
 	if err != nil {
 		panic(fmt.Sprintf("unable to register: %v", err))
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"sync"
 	"time"
 
 	"k8s.io/klog/v2"
@@ -33,8 +34,12 @@ const (
 	Docker = "docker"
 	// Mock driver
 	Mock = "mock"
+	// Fake driver
+	Fake = "fake"
 	// None driver
 	None = "none"
+	// Nspawn is Kubernetes in a systemd-nspawn container on the host
+	Nspawn = "nspawn"
 )
 
 // IsKIC checks if the driver is a Kubernetes in container
@@ -47,9 +52,19 @@ func IsMock(name string) bool {
 	return name == Mock
 }
 
+// IsFake checks if the driver is the sandboxed fake driver
+func IsFake(name string) bool {
+	return name == Fake
+}
+
+// IsNspawn checks if the driver runs the node in a systemd-nspawn container
+func IsNspawn(name string) bool {
+	return name == Nspawn
+}
+
 // IsVM checks if the driver is a VM
 func IsVM(name string) bool {
-	if IsKIC(name) || IsMock(name) || BareMetal(name) {
+	if IsKIC(name) || IsMock(name) || IsFake(name) || BareMetal(name) || IsNspawn(name) {
 		return false
 	}
 	return true
@@ -63,8 +78,34 @@ func BareMetal(name string) bool {
 var (
 	// globalRegistry is a globally accessible driver registry
 	globalRegistry = newRegistry()
+
+	// discoverers register the drivers found at runtime, such as out-of-tree plugins
+	discoverers []Discoverer
+	// discovered is how many of the discoverers ran
+	discovered int
+	discovery  sync.Mutex
 )
 
+// Discoverer registers drivers found at runtime, given a lookup of the drivers registered so far
+type Discoverer func(lookup func(name string) DriverDef)
+
+// AddDiscoverer adds a discoverer to the global registry. Discoverers run on its first lookup or listing
+// rather than at init, as finding drivers may mean running programs
+func AddDiscoverer(d Discoverer) {
+	discovery.Lock()
+	defer discovery.Unlock()
+	discoverers = append(discoverers, d)
+}
+
+// discover runs the discoverers which did not run yet. The registry may be looked up at init, before all of them are added
+func discover() {
+	discovery.Lock()
+	defer discovery.Unlock()
+	for ; discovered < len(discoverers); discovered++ {
+		discoverers[discovered](globalRegistry.Driver)
+	}
+}
+
 // DriverState is metadata relating to a driver and status
 type DriverState struct {
 	// Name is the name of the driver used internally
@@ -93,6 +134,7 @@ func (d DriverState) String() string {
 
 // List lists drivers in global registry
 func List() []DriverDef {
+	discover()
 	return globalRegistry.List()
 }
 
@@ -103,6 +145,13 @@ func Register(driver DriverDef) error {
 
 // Driver gets a named driver from the global registry
 func Driver(name string) DriverDef {
+	discover()
+	return globalRegistry.Driver(name)
+}
+
+// Registered gets a named driver from the global registry without discovering drivers first, for lookups at init
+// which only care about the built-in drivers
+func Registered(name string) DriverDef {
 	return globalRegistry.Driver(name)
 }
 
@@ -110,6 +159,7 @@ func Driver(name string) DriverDef {
 func Available(vm bool) []DriverState {
 	sts := []DriverState{}
 	klog.Infof("Querying for installed drivers using PATH=%s", os.Getenv("PATH"))
+	discover()
 
 	for _, d := range globalRegistry.List() {
 		if vm && !IsVM(d.Name) {
@@ -149,6 +199,7 @@ func Available(vm bool) []DriverState {
 
 // Status returns the state of a driver within the global registry
 func Status(name string) State {
+	discover()
 	d := globalRegistry.Driver(name)
 	if d.Empty() {
 		return State{}
@@ -113,6 +113,14 @@ func TestGlobalAvailable(t *testing.T) {
 	}
 }
 
+func TestIsVM(t *testing.T) {
+	for name, want := range map[string]bool{"kvm2": true, "qemu2": true, "docker": false, "podman": false, "none": false, "fake": false, "nspawn": false} {
+		if got := IsVM(name); got != want {
+			t.Errorf("IsVM(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
 func TestGlobalStatus(t *testing.T) {
 	globalRegistry = newRegistry()
 
@@ -135,3 +143,40 @@ func TestGlobalStatus(t *testing.T) {
 		t.Errorf("status mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestGlobalDiscover(t *testing.T) {
+	globalRegistry = newRegistry()
+	discoverers, discovered = nil, 0
+	defer func() { discoverers, discovered = nil, 0 }()
+
+	// lookups before the discoverers are added, as at init, do not keep them from running later
+	if !Driver("plugin").Empty() {
+		t.Errorf("driver.Empty = false, expected true")
+	}
+	runs := 0
+	AddDiscoverer(func(lookup func(string) DriverDef) {
+		runs++
+		if lookup("foo").Empty() {
+			t.Errorf("lookup(foo) is empty, expected built-in drivers to be registered before discovery")
+		}
+		if err := Register(DriverDef{Name: "plugin"}); err != nil {
+			t.Errorf("register returned error: %v", err)
+		}
+	})
+	if err := Register(DriverDef{Name: "foo"}); err != nil {
+		t.Errorf("register returned error: %v", err)
+	}
+	if runs != 0 {
+		t.Errorf("discoverer ran %d times before any lookup, expected 0", runs)
+	}
+
+	if Driver("plugin").Empty() {
+		t.Errorf("driver.Empty = true, expected discovered driver")
+	}
+	if got := len(List()); got != 2 {
+		t.Errorf("len(List()) = %d, expected 2", got)
+	}
+	if runs != 1 {
+		t.Errorf("discoverer ran %d times, expected 1", runs)
+	}
+}
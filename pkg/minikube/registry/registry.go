@@ -51,6 +51,26 @@ const (
 	HighlyPreferred
 )
 
+var priorityNames = map[Priority]string{
+	Unknown:         "Unknown",
+	Obsolete:        "Obsolete",
+	Unhealthy:       "Unhealthy",
+	Experimental:    "Experimental",
+	Discouraged:     "Discouraged",
+	Deprecated:      "Deprecated",
+	Fallback:        "Fallback",
+	Default:         "Default",
+	Preferred:       "Preferred",
+	HighlyPreferred: "HighlyPreferred",
+}
+
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
 // Registry contains all the supported driver definitions on the host
 type Registry interface {
 	// Register a driver in registry
@@ -108,6 +128,12 @@ type DriverDef struct {
 
 	// Priority returns the prioritization for selecting a driver by default.
 	Priority Priority
+
+	// Capabilities are the features of minikube the driver supports
+	Capabilities Capabilities
+
+	// Plugin is the path of the executable of an out-of-tree driver, empty for the drivers built into minikube
+	Plugin string
 }
 
 // Empty returns true if the driver is nil
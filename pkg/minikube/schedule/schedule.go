@@ -21,9 +21,9 @@ import (
 
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/config"
-	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/registry"
 )
 
 // Daemonize daemonizes minikube so that scheduled stop happens as expected
@@ -36,8 +36,8 @@ func Daemonize(profiles []string, duration time.Duration) error {
 	var daemonizeProfiles []string
 	for _, p := range profiles {
 		_, cc := mustload.Partial(p)
-		if driver.BareMetal(cc.Driver) {
-			out.WarningT("scheduled stop is not supported on the none driver, skipping scheduling")
+		if err := registry.CheckCapabilities(cc.Driver, registry.ScheduledStop); err != nil {
+			out.WarningT("scheduled stop is not supported on the {{.driver}} driver, skipping scheduling of {{.profile}}", out.V{"driver": cc.Driver, "profile": p})
 			continue
 		}
 		daemonizeProfiles = append(daemonizeProfiles, p)
@@ -0,0 +1,323 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// Check is the result of a pre-flight check
+type Check struct {
+	Name string
+	// Problems are the reasons the upgrade is unsafe, none if the check passed
+	Problems []string
+}
+
+// Passed returns whether the check found no problems
+func (c Check) Passed() bool {
+	return len(c.Problems) == 0
+}
+
+// removedAPI is an API which is no longer served from a Kubernetes release on
+// ref: https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+type removedAPI struct {
+	APIVersion string
+	// Kind is empty if every kind of the API version was removed in the same release
+	Kind    string
+	Removed semver.Version
+}
+
+var removedAPIs = []removedAPI{
+	{APIVersion: "extensions/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "networking.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "apiextensions.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "apiregistration.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "authentication.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "authorization.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "certificates.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "coordination.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "scheduling.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSIStorageCapacity", Removed: semver.Version{Major: 1, Minor: 27}},
+	{APIVersion: "storage.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 22}},
+	{APIVersion: "batch/v1beta1", Removed: semver.Version{Major: 1, Minor: 25}},
+	{APIVersion: "discovery.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 25}},
+	{APIVersion: "events.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 25}},
+	{APIVersion: "autoscaling/v2beta1", Removed: semver.Version{Major: 1, Minor: 25}},
+	{APIVersion: "policy/v1beta1", Removed: semver.Version{Major: 1, Minor: 25}},
+	{APIVersion: "node.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 25}},
+	{APIVersion: "autoscaling/v2beta2", Removed: semver.Version{Major: 1, Minor: 26}},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Removed: semver.Version{Major: 1, Minor: 26}},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Removed: semver.Version{Major: 1, Minor: 29}},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Removed: semver.Version{Major: 1, Minor: 32}},
+}
+
+// removedIn returns the release an API was removed in, if it was
+func removedIn(apiVersion, kind string) (semver.Version, bool) {
+	for _, api := range removedAPIs {
+		if api.APIVersion == apiVersion && (api.Kind == "" || api.Kind == kind) {
+			return api.Removed, true
+		}
+	}
+	return semver.Version{}, false
+}
+
+// minor returns the major.minor release of a version, which APIs are removed in
+func minor(v semver.Version) semver.Version {
+	return semver.Version{Major: v.Major, Minor: v.Minor}
+}
+
+// ValidateVersion returns an error if target is not newer than current
+func ValidateVersion(current, target semver.Version) error {
+	if target.LT(current) {
+		return fmt.Errorf("downgrading from v%s to v%s is not supported", current, target)
+	}
+	if target.EQ(current) {
+		return fmt.Errorf("the cluster already runs Kubernetes v%s", current)
+	}
+	return nil
+}
+
+// checkVersion checks that kubeadm can upgrade from current to target, which skips no minor release
+func checkVersion(current, target semver.Version) Check {
+	c := Check{Name: "Kubernetes version"}
+	if target.Major != current.Major || target.Minor > current.Minor+1 {
+		c.Problems = append(c.Problems, fmt.Sprintf("upgrading from v%s to v%s skips a minor release, upgrade to v%d.%d first", current, target, current.Major, current.Minor+1))
+	}
+	return c
+}
+
+// deprecatedAPIMetric matches the samples of the metric the apiserver reports requests to deprecated APIs with
+var deprecatedAPIMetric = regexp.MustCompile(`^apiserver_requested_deprecated_apis\{(.*)\}\s+(\S+)`)
+
+// metricLabel matches a label of a metric sample
+var metricLabel = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// deprecatedAPIProblems returns the APIs requested since the apiserver started, according to its metrics,
+// which are no longer served by target
+func deprecatedAPIProblems(metrics io.Reader, target semver.Version) []string {
+	problems := []string{}
+	scanner := bufio.NewScanner(metrics)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		m := deprecatedAPIMetric.FindStringSubmatch(scanner.Text())
+		if m == nil || m[2] == "0" {
+			continue
+		}
+		labels := map[string]string{}
+		for _, l := range metricLabel.FindAllStringSubmatch(m[1], -1) {
+			labels[l[1]] = l[2]
+		}
+		removed, err := semver.ParseTolerant(labels["removed_release"])
+		if err != nil || minor(target).LT(removed) {
+			continue
+		}
+		gv := labels["version"]
+		if labels["group"] != "" {
+			gv = labels["group"] + "/" + gv
+		}
+		problems = append(problems, fmt.Sprintf("%s %s is still requested but was removed in Kubernetes %s", gv, labels["resource"], labels["removed_release"]))
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// checkDeprecatedAPIs checks that nothing uses APIs which target no longer serves
+func checkDeprecatedAPIs(client kubernetes.Interface, target semver.Version) (Check, error) {
+	c := Check{Name: "Deprecated APIs"}
+	b, err := client.CoreV1().RESTClient().Get().AbsPath("/metrics").DoRaw(context.Background())
+	if err != nil {
+		return c, errors.Wrap(err, "getting apiserver metrics")
+	}
+	c.Problems = deprecatedAPIProblems(bytes.NewReader(b), target)
+	return c, nil
+}
+
+// documentSeparator splits YAML documents
+var documentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// manifestRemovedAPIs returns the objects of a manifest using APIs which target no longer serves
+func manifestRemovedAPIs(b []byte, target semver.Version) []string {
+	apiVersionLine := regexp.MustCompile(`(?m)^apiVersion:\s*["']?([^"'\s]+)`)
+	kindLine := regexp.MustCompile(`(?m)^kind:\s*["']?([^"'\s]+)`)
+
+	objs := []string{}
+	for _, doc := range documentSeparator.Split(string(b), -1) {
+		av := apiVersionLine.FindStringSubmatch(doc)
+		k := kindLine.FindStringSubmatch(doc)
+		if av == nil || k == nil {
+			continue
+		}
+		if removed, ok := removedIn(av[1], k[1]); ok && !minor(target).LT(removed) {
+			objs = append(objs, fmt.Sprintf("%s %s", av[1], k[1]))
+		}
+	}
+	return objs
+}
+
+// checkAddons checks that the manifests of the enabled addons, rendered for target, only use APIs target serves
+func checkAddons(cc *config.ClusterConfig, target semver.Version) (Check, error) {
+	c := Check{Name: "Addons"}
+
+	tcc := *cc
+	tcc.KubernetesConfig.KubernetesVersion = "v" + target.String()
+	var netInfo assets.NetworkInfo
+	if cp, err := config.ControlPlane(tcc); err == nil {
+		netInfo.ControlPlaneNodeIP = cp.IP
+		netInfo.ControlPlaneNodePort = cp.Port
+	}
+
+	names := []string{}
+	for name, enabled := range cc.Addons {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addon, ok := assets.Addons[name]
+		if !ok {
+			continue
+		}
+		images, customRegistries, err := assets.SelectAndPersistImages(addon, &tcc)
+		if err != nil {
+			return c, errors.Wrapf(err, "%s images", name)
+		}
+		data := assets.GenerateTemplateData(addon, &tcc, netInfo, images, customRegistries, true)
+		if err, ok := data.(error); ok {
+			return c, errors.Wrapf(err, "%s template data", name)
+		}
+		for _, a := range addon.Assets {
+			var f assets.CopyableFile = a
+			if a.IsTemplate() {
+				if f, err = a.Evaluate(data); err != nil {
+					c.Problems = append(c.Problems, fmt.Sprintf("%s: %s does not render for Kubernetes v%s: %v", name, a.GetTargetName(), target, err))
+					continue
+				}
+			}
+			b, err := io.ReadAll(f)
+			if err != nil {
+				return c, errors.Wrapf(err, "reading %s", a.GetTargetName())
+			}
+			// the bundled assets are shared, rewind them for the next reader
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return c, errors.Wrapf(err, "rewinding %s", a.GetTargetName())
+			}
+			for _, obj := range manifestRemovedAPIs(b, target) {
+				c.Problems = append(c.Problems, fmt.Sprintf("%s: %s in %s is not served by Kubernetes v%s", name, obj, a.GetTargetName(), target))
+			}
+		}
+	}
+	return c, nil
+}
+
+// maxKubeletSkew returns how many minor releases kubelets may be older than the apiserver
+// ref: https://kubernetes.io/releases/version-skew-policy/#kubelet
+func maxKubeletSkew(apiserver semver.Version) uint64 {
+	if apiserver.GTE(semver.Version{Major: 1, Minor: 28}) {
+		return 3
+	}
+	return 2
+}
+
+// skewProblems checks that the control planes run the current version and that the
+// other kubelets stay within the supported skew while the control planes are upgraded first
+func skewProblems(nodes []core.Node, current, target semver.Version) []string {
+	problems := []string{}
+	for _, n := range nodes {
+		kv, err := semver.ParseTolerant(n.Status.NodeInfo.KubeletVersion)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("node %s: unable to parse kubelet version %q", n.Name, n.Status.NodeInfo.KubeletVersion))
+			continue
+		}
+		if _, ok := n.Labels["node-role.kubernetes.io/control-plane"]; ok && !minor(kv).EQ(minor(current)) {
+			problems = append(problems, fmt.Sprintf("control-plane node %s runs kubelet v%s, but the cluster runs Kubernetes v%s", n.Name, kv, current))
+		}
+		if kv.Major != target.Major || kv.Minor+maxKubeletSkew(target) < target.Minor {
+			problems = append(problems, fmt.Sprintf("node %s runs kubelet v%s, which is too old for Kubernetes v%s", n.Name, kv, target))
+		}
+		ready := false
+		for _, c := range n.Status.Conditions {
+			if c.Type == core.NodeReady && c.Status == core.ConditionTrue {
+				ready = true
+			}
+		}
+		if !ready {
+			problems = append(problems, fmt.Sprintf("node %s is not Ready", n.Name))
+		}
+	}
+	return problems
+}
+
+// checkNodes checks the versions and state of the nodes
+func checkNodes(cc *config.ClusterConfig, client kubernetes.Interface, current, target semver.Version) (Check, error) {
+	c := Check{Name: "Node versions"}
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), meta.ListOptions{})
+	if err != nil {
+		return c, errors.Wrap(err, "listing nodes")
+	}
+	c.Problems = skewProblems(nodes.Items, current, target)
+
+	registered := map[string]bool{}
+	for _, n := range nodes.Items {
+		registered[n.Name] = true
+	}
+	for _, n := range config.KubernetesNodes(*cc) {
+		if name := config.MachineName(*cc, n); !registered[name] {
+			c.Problems = append(c.Problems, fmt.Sprintf("node %s is not registered with the cluster", name))
+		}
+	}
+	return c, nil
+}
+
+// Preflight checks whether upgrading the cluster to target is safe
+func Preflight(cc *config.ClusterConfig, client kubernetes.Interface, target semver.Version) ([]Check, error) {
+	current, err := semver.ParseTolerant(cc.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing Kubernetes version")
+	}
+
+	checks := []Check{checkVersion(current, target)}
+	for _, check := range []func() (Check, error){
+		func() (Check, error) { return checkDeprecatedAPIs(client, target) },
+		func() (Check, error) { return checkAddons(cc, target) },
+		func() (Check, error) { return checkNodes(cc, client, current, target) },
+	} {
+		c, err := check()
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s check", strings.ToLower(c.Name))
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
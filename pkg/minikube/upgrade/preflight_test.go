@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateVersion(t *testing.T) {
+	current := semver.MustParse("1.30.2")
+	tests := []struct {
+		target  string
+		wantErr bool
+		passed  bool
+	}{
+		{target: "1.30.5", passed: true},
+		{target: "1.31.0", passed: true},
+		{target: "1.32.0", passed: false},
+		{target: "1.30.2", wantErr: true},
+		{target: "1.29.0", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.target, func(t *testing.T) {
+			target := semver.MustParse(tc.target)
+			err := ValidateVersion(current, target)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ValidateVersion(%s, %s) error = %v, wantErr %v", current, target, err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := checkVersion(current, target).Passed(); got != tc.passed {
+				t.Errorf("checkVersion(%s, %s).Passed() = %v, want %v", current, target, got, tc.passed)
+			}
+		})
+	}
+}
+
+func TestDeprecatedAPIProblems(t *testing.T) {
+	metrics := `# HELP apiserver_requested_deprecated_apis [STABLE] Gauge of deprecated APIs that have been requested, broken out by API group, version, resource, subresource, and removed_release.
+# TYPE apiserver_requested_deprecated_apis gauge
+apiserver_requested_deprecated_apis{group="flowcontrol.apiserver.k8s.io",removed_release="1.29",resource="flowschemas",subresource="",version="v1beta2"} 1
+apiserver_requested_deprecated_apis{group="flowcontrol.apiserver.k8s.io",removed_release="1.32",resource="prioritylevelconfigurations",subresource="",version="v1beta3"} 1
+apiserver_requested_deprecated_apis{group="",removed_release="",resource="componentstatuses",subresource="",version="v1"} 1
+apiserver_request_total{code="200",resource="pods",verb="LIST",version="v1"} 12
+`
+	got := deprecatedAPIProblems(strings.NewReader(metrics), semver.MustParse("1.29.1"))
+	if len(got) != 1 || !strings.Contains(got[0], "flowcontrol.apiserver.k8s.io/v1beta2 flowschemas") {
+		t.Errorf("deprecatedAPIProblems() = %v, expected only the flowschemas removed in 1.29", got)
+	}
+	if got := deprecatedAPIProblems(strings.NewReader(metrics), semver.MustParse("1.28.4")); len(got) != 0 {
+		t.Errorf("deprecatedAPIProblems() = %v, expected none before 1.29", got)
+	}
+}
+
+func TestManifestRemovedAPIs(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: Namespace
+metadata:
+  name: example
+---
+apiVersion: policy/v1beta1
+kind: PodSecurityPolicy
+metadata:
+  name: example
+---
+apiVersion: storage.k8s.io/v1beta1
+kind: CSIStorageCapacity
+metadata:
+  name: example
+`
+	got := manifestRemovedAPIs([]byte(manifest), semver.MustParse("1.26.0"))
+	if len(got) != 1 || got[0] != "policy/v1beta1 PodSecurityPolicy" {
+		t.Errorf("manifestRemovedAPIs() = %v, want [policy/v1beta1 PodSecurityPolicy]", got)
+	}
+	got = manifestRemovedAPIs([]byte(manifest), semver.MustParse("1.27.0"))
+	if len(got) != 2 {
+		t.Errorf("manifestRemovedAPIs() = %v, expected CSIStorageCapacity to be removed in 1.27 too", got)
+	}
+}
+
+func TestSkewProblems(t *testing.T) {
+	node := func(name, kubelet string, cp, ready bool) core.Node {
+		n := core.Node{ObjectMeta: meta.ObjectMeta{Name: name, Labels: map[string]string{}}}
+		if cp {
+			n.Labels["node-role.kubernetes.io/control-plane"] = ""
+		}
+		n.Status.NodeInfo.KubeletVersion = kubelet
+		status := core.ConditionFalse
+		if ready {
+			status = core.ConditionTrue
+		}
+		n.Status.Conditions = []core.NodeCondition{{Type: core.NodeReady, Status: status}}
+		return n
+	}
+	current := semver.MustParse("1.30.0")
+	target := semver.MustParse("1.31.0")
+
+	nodes := []core.Node{node("minikube", "v1.30.0", true, true), node("minikube-m02", "v1.28.3", false, true)}
+	if got := skewProblems(nodes, current, target); len(got) != 0 {
+		t.Errorf("skewProblems() = %v, expected none", got)
+	}
+
+	nodes = []core.Node{node("minikube", "v1.29.0", true, true), node("minikube-m02", "v1.27.0", false, false)}
+	if got := skewProblems(nodes, current, target); len(got) != 3 {
+		t.Errorf("skewProblems() = %v, expected the control plane skew, the old kubelet and the node which is not Ready", got)
+	}
+}
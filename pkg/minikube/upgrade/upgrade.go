@@ -0,0 +1,385 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgrade upgrades the Kubernetes version of running clusters in place
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/detect"
+	"k8s.io/minikube/pkg/minikube/etcd"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+const (
+	// manifestsDir is where kubeadm writes the static pod manifests of the control plane
+	manifestsDir = vmpath.GuestManifestsDir
+	// kubeletConfig is the kubelet configuration kubeadm rewrites during upgrades
+	kubeletConfig = "/var/lib/kubelet/config.yaml"
+	// nodeReadyTimeout is how long to wait for an upgraded node to be Ready
+	nodeReadyTimeout = 5 * time.Minute
+)
+
+// Options configures an upgrade
+type Options struct {
+	// KubernetesVersion is the version to upgrade to
+	KubernetesVersion string
+	// Bootstrapper is the name of the bootstrapper of the cluster
+	Bootstrapper string
+}
+
+// upgrader upgrades the nodes of a cluster one at a time
+type upgrader struct {
+	api    libmachine.API
+	client kubernetes.Interface
+	old    config.ClusterConfig
+	new    config.ClusterConfig
+	bsName string
+	// runners are the command runners of the nodes, by machine name
+	runners map[string]command.Runner
+	// snapshot is where the etcd snapshot is on the etcd nodes
+	snapshot string
+	// touched are the nodes the upgrade changed, which have to be rolled back on failure
+	touched []config.Node
+}
+
+// Upgrade upgrades a running cluster to a new Kubernetes version, the control-plane nodes first, then the workers.
+// etcd is snapshotted first, and the cluster is rolled back to the snapshot if the upgrade fails.
+func Upgrade(api libmachine.API, cc *config.ClusterConfig, opts Options) error {
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "kubernetes client")
+	}
+
+	u := &upgrader{
+		api:     api,
+		client:  client,
+		old:     *cc,
+		new:     withVersion(*cc, opts.KubernetesVersion),
+		bsName:  opts.Bootstrapper,
+		runners: map[string]command.Runner{},
+	}
+
+	for _, n := range cc.Nodes {
+		m := config.MachineName(*cc, n)
+		h, err := machine.LoadHost(api, m)
+		if err != nil {
+			return errors.Wrapf(err, "load host %s", m)
+		}
+		r, err := machine.CommandRunner(h)
+		if err != nil {
+			return errors.Wrapf(err, "command runner %s", m)
+		}
+		u.runners[m] = r
+	}
+
+	out.Step(style.Copying, "Saving a snapshot of etcd ...")
+	if err := u.saveSnapshot(); err != nil {
+		return errors.Wrap(err, "etcd snapshot")
+	}
+
+	if err := u.upgradeNodes(); err != nil {
+		out.ErrT(style.Failure, "Upgrade failed, rolling back to Kubernetes {{.version}} ...", out.V{"version": u.old.KubernetesConfig.KubernetesVersion})
+		if rerr := u.rollback(); rerr != nil {
+			return errors.Wrapf(err, "rollback failed (%v), etcd snapshot is at %s on the etcd nodes", rerr, u.snapshot)
+		}
+		return errors.Wrap(err, "upgrade rolled back")
+	}
+
+	*cc = withVersion(*cc, opts.KubernetesVersion)
+	return config.SaveProfile(cc.Name, cc)
+}
+
+// withVersion returns a copy of a cluster config at another Kubernetes version, which its nodes record too
+func withVersion(cc config.ClusterConfig, version string) config.ClusterConfig {
+	cc.KubernetesConfig.KubernetesVersion = version
+	cc.Nodes = append([]config.Node(nil), cc.Nodes...)
+	for i := range cc.Nodes {
+		cc.Nodes[i].KubernetesVersion = version
+	}
+	return cc
+}
+
+// machineName returns the machine name of a node
+func (u *upgrader) machineName(n config.Node) string {
+	return config.MachineName(u.old, n)
+}
+
+// saveSnapshot snapshots etcd on the first node running an etcd member, and copies the snapshot to the others
+func (u *upgrader) saveSnapshot() error {
+	members := config.EtcdNodes(u.old)
+	if len(members) == 0 {
+		return fmt.Errorf("no etcd nodes found")
+	}
+	u.snapshot = path.Join(etcd.BackupDir, fmt.Sprintf("upgrade-%s-%s.db", u.old.KubernetesConfig.KubernetesVersion, time.Now().Format("20060102150405")))
+	r := u.runners[u.machineName(members[0])]
+	if err := etcd.Snapshot(r, u.snapshot); err != nil {
+		return err
+	}
+
+	others := members[1:]
+	if len(others) == 0 {
+		return nil
+	}
+	rr, err := r.RunCmd(exec.Command("sudo", "cat", u.snapshot))
+	if err != nil {
+		return errors.Wrap(err, "read snapshot")
+	}
+	for _, n := range others {
+		f := assets.NewMemoryAssetTarget(rr.Stdout.Bytes(), u.snapshot, "0600")
+		if err := u.runners[u.machineName(n)].Copy(f); err != nil {
+			return errors.Wrapf(err, "copy snapshot to %s", u.machineName(n))
+		}
+	}
+	return nil
+}
+
+// upgradeOrder returns the nodes to upgrade: the control-plane nodes, then the workers. The dedicated etcd and
+// load balancer nodes are not Kubernetes nodes, kubeadm has nothing to upgrade on them
+func upgradeOrder(cc config.ClusterConfig) []config.Node {
+	nodes := config.ControlPlanes(cc)
+	for _, n := range config.KubernetesNodes(cc) {
+		if !n.ControlPlane {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// upgradeNodes upgrades the control-plane nodes one at a time, then the workers
+func (u *upgrader) upgradeNodes() error {
+	for _, n := range upgradeOrder(u.old) {
+		out.Step(style.Improvement, "Upgrading node {{.name}} to Kubernetes {{.version}} ...", out.V{"name": u.machineName(n), "version": u.new.KubernetesConfig.KubernetesVersion})
+		if err := u.upgradeNode(n); err != nil {
+			return errors.Wrapf(err, "upgrade node %s", u.machineName(n))
+		}
+	}
+	return nil
+}
+
+// upgradeNode upgrades a node with kubeadm: drains it, installs the new binaries, upgrades its components and restarts kubelet
+func (u *upgrader) upgradeNode(n config.Node) error {
+	m := u.machineName(n)
+	r := u.runners[m]
+
+	if err := u.backup(n); err != nil {
+		return errors.Wrap(err, "backup")
+	}
+	u.touched = append(u.touched, n)
+
+	// with no other node to move its pods to, draining would only cause downtime
+	if len(config.KubernetesNodes(u.old)) > 1 {
+		if err := u.kubectl("drain", m, "--ignore-daemonsets", "--delete-emptydir-data", "--timeout=5m"); err != nil {
+			return errors.Wrap(err, "drain")
+		}
+	}
+
+	cr, err := u.runtime(u.new, r)
+	if err != nil {
+		return err
+	}
+	if n.ControlPlane && u.new.KubernetesConfig.ShouldLoadCachedImages {
+		imgs, err := images.Kubeadm(u.new.KubernetesConfig.ImageRepository, u.new.KubernetesConfig.KubernetesVersion)
+		if err == nil {
+			err = machine.LoadCachedImages(&u.new, r, imgs, detect.ImageCacheDir(), false)
+		}
+		if err != nil {
+			klog.Warningf("unable to load cached images, kubeadm will pull them: %v", err)
+		}
+	}
+	// installs the new binaries and the kubelet service using them
+	bs, err := cluster.Bootstrapper(u.api, u.bsName, u.new, r)
+	if err != nil {
+		return errors.Wrap(err, "bootstrapper")
+	}
+	if err := bs.UpdateNode(u.new, n, cr); err != nil {
+		return errors.Wrap(err, "update node")
+	}
+
+	kubeadm := bsutil.InvokeKubeadm(u.new.KubernetesConfig.KubernetesVersion)
+	c := fmt.Sprintf("%s upgrade node", kubeadm)
+	if config.IsPrimaryControlPlane(u.new, n) {
+		c = fmt.Sprintf("%s upgrade apply %s --yes --certificate-renewal=false --ignore-preflight-errors=all", kubeadm, u.new.KubernetesConfig.KubernetesVersion)
+		if v, err := semver.ParseTolerant(u.new.KubernetesConfig.KubernetesVersion); err == nil && len(v.Pre) > 0 {
+			c += " --allow-experimental-upgrades --allow-release-candidate-upgrades"
+		}
+	}
+	if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", c)); err != nil {
+		return errors.Wrap(err, "kubeadm upgrade")
+	}
+
+	if config.IsPrimaryControlPlane(u.new, n) {
+		// the config kubeadm was upgraded with, so that the next start does not see drift
+		if _, err := r.RunCmd(exec.Command("sudo", "cp", constants.KubeadmYamlPath+".new", constants.KubeadmYamlPath)); err != nil {
+			return errors.Wrap(err, "cp")
+		}
+	}
+	if err := sysinit.New(r).Restart("kubelet"); err != nil {
+		return errors.Wrap(err, "restart kubelet")
+	}
+	if err := u.waitForNode(m, u.new.KubernetesConfig.KubernetesVersion); err != nil {
+		return err
+	}
+	return u.kubectl("uncordon", m)
+}
+
+// backup saves what kubeadm changes on a node outside of etcd, to roll it back
+func (u *upgrader) backup(n config.Node) error {
+	dir := path.Join(etcd.BackupDir, "upgrade")
+	c := fmt.Sprintf("rm -rf %[1]s && mkdir -p %[1]s && cp -a %[2]s %[1]s/kubelet-config.yaml", dir, kubeletConfig)
+	if n.ControlPlane {
+		c += fmt.Sprintf(" && cp -a %s %s/manifests", manifestsDir, dir)
+	}
+	_, err := u.runners[u.machineName(n)].RunCmd(exec.Command("sudo", "/bin/bash", "-c", c))
+	return err
+}
+
+// restore reverts a node to its backup and the kubelet of the old version
+func (u *upgrader) restore(n config.Node) error {
+	r := u.runners[u.machineName(n)]
+	cr, err := u.runtime(u.old, r)
+	if err != nil {
+		return err
+	}
+	bs, err := cluster.Bootstrapper(u.api, u.bsName, u.old, r)
+	if err != nil {
+		return errors.Wrap(err, "bootstrapper")
+	}
+	if err := bs.UpdateNode(u.old, n, cr); err != nil {
+		return errors.Wrap(err, "update node")
+	}
+
+	dir := path.Join(etcd.BackupDir, "upgrade")
+	c := fmt.Sprintf("cp -a %s/kubelet-config.yaml %s", dir, kubeletConfig)
+	if n.ControlPlane {
+		c += fmt.Sprintf(" && rm -rf %[2]s && cp -a %[1]s/manifests %[2]s", dir, manifestsDir)
+	}
+	if config.IsPrimaryControlPlane(u.old, n) {
+		c += fmt.Sprintf(" && cp %[1]s.new %[1]s", constants.KubeadmYamlPath)
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "/bin/bash", "-c", c)); err != nil {
+		return errors.Wrap(err, "restore backup")
+	}
+	return sysinit.New(r).Restart("kubelet")
+}
+
+// rollback reverts the nodes the upgrade touched and restores etcd from the snapshot
+func (u *upgrader) rollback() error {
+	for i := len(u.touched) - 1; i >= 0; i-- {
+		n := u.touched[i]
+		klog.Infof("rolling back node %s", u.machineName(n))
+		if err := u.restore(n); err != nil {
+			return errors.Wrapf(err, "restore node %s", u.machineName(n))
+		}
+	}
+
+	out.Step(style.Resetting, "Restoring etcd from the snapshot ...")
+	members := etcd.Members(u.old)
+	for i, n := range config.EtcdNodes(u.old) {
+		if err := etcd.Restore(u.runners[u.machineName(n)], u.snapshot, members[i], members); err != nil {
+			return errors.Wrapf(err, "restore etcd on %s", u.machineName(n))
+		}
+	}
+
+	for _, n := range u.touched {
+		m := u.machineName(n)
+		if err := u.waitForNode(m, u.old.KubernetesConfig.KubernetesVersion); err != nil {
+			return err
+		}
+		if err := u.kubectl("uncordon", m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runtime returns the container runtime of a node, for a cluster config
+func (u *upgrader) runtime(cc config.ClusterConfig, r command.Runner) (cruntime.Manager, error) {
+	v, err := semver.ParseTolerant(cc.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing Kubernetes version")
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: r, Socket: cc.KubernetesConfig.CRISocket, KubernetesVersion: v})
+	if err != nil {
+		return nil, errors.Wrap(err, "runtime")
+	}
+	return cr, nil
+}
+
+// kubectl runs kubectl on the primary control-plane node, which has the binary of the old version in any case
+func (u *upgrader) kubectl(args ...string) error {
+	pcp, err := config.ControlPlane(u.old)
+	if err != nil {
+		return errors.Wrap(err, "get primary control-plane node")
+	}
+	kubectl := kapi.KubectlBinaryPath(u.old.KubernetesConfig.KubernetesVersion)
+	c := append([]string{"sudo", "KUBECONFIG=/var/lib/minikube/kubeconfig", kubectl}, args...)
+	run := func() error {
+		_, err := u.runners[u.machineName(pcp)].RunCmd(exec.Command(c[0], c[1:]...))
+		return err
+	}
+	// the apiserver may be restarting after an upgrade
+	return retry.Expo(run, time.Second, 2*time.Minute)
+}
+
+// waitForNode waits for a node to be Ready with the kubelet of a Kubernetes version
+func (u *upgrader) waitForNode(name, version string) error {
+	ready := func() error {
+		n, err := u.client.CoreV1().Nodes().Get(context.Background(), name, meta.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if n.Status.NodeInfo.KubeletVersion != version {
+			return fmt.Errorf("node %s runs kubelet %s", name, n.Status.NodeInfo.KubeletVersion)
+		}
+		for _, c := range n.Status.Conditions {
+			if c.Type == core.NodeReady && c.Status == core.ConditionTrue {
+				return nil
+			}
+		}
+		return fmt.Errorf("node %s is not Ready", name)
+	}
+	if err := retry.Local(ready, nodeReadyTimeout); err != nil {
+		return errors.Wrapf(err, "waiting for node %s", name)
+	}
+	return nil
+}
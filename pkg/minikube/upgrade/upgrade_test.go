@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestWithVersion(t *testing.T) {
+	old := config.ClusterConfig{
+		KubernetesConfig: config.KubernetesConfig{KubernetesVersion: "v1.30.2"},
+		Nodes: []config.Node{
+			{Name: "", KubernetesVersion: "v1.30.2", ControlPlane: true},
+			{Name: "m02", KubernetesVersion: "v1.30.2"},
+		},
+	}
+	cc := withVersion(old, "v1.31.0")
+	if got := cc.KubernetesConfig.KubernetesVersion; got != "v1.31.0" {
+		t.Errorf("KubernetesVersion = %s, want v1.31.0", got)
+	}
+	for _, n := range cc.Nodes {
+		if n.KubernetesVersion != "v1.31.0" {
+			t.Errorf("node %q KubernetesVersion = %s, want v1.31.0", n.Name, n.KubernetesVersion)
+		}
+	}
+	for _, n := range old.Nodes {
+		if n.KubernetesVersion != "v1.30.2" {
+			t.Errorf("original node %q KubernetesVersion = %s, want it unchanged at v1.30.2", n.Name, n.KubernetesVersion)
+		}
+	}
+}
+
+func TestUpgradeOrder(t *testing.T) {
+	names := func(nodes []config.Node) []string {
+		names := []string{}
+		for _, n := range nodes {
+			names = append(names, n.Name)
+		}
+		return names
+	}
+	tests := []struct {
+		name     string
+		cc       config.ClusterConfig
+		upgrade  []string
+		snapshot string
+	}{
+		{
+			name:     "single node",
+			cc:       config.ClusterConfig{Nodes: []config.Node{{Name: "", ControlPlane: true, Worker: true}}},
+			upgrade:  []string{""},
+			snapshot: "",
+		},
+		{
+			name: "stacked etcd",
+			cc: config.ClusterConfig{Nodes: []config.Node{
+				{Name: "", ControlPlane: true, Worker: true},
+				{Name: "m02", Worker: true},
+				{Name: "m03", ControlPlane: true, Worker: true},
+			}},
+			upgrade:  []string{"", "m03", "m02"},
+			snapshot: "",
+		},
+		{
+			name: "external etcd",
+			cc: config.ClusterConfig{
+				KubernetesConfig: config.KubernetesConfig{EtcdTopology: config.EtcdExternal},
+				Nodes: []config.Node{
+					{Name: "", ControlPlane: true, Worker: true},
+					{Name: "m02", Etcd: true},
+					{Name: "m03", Etcd: true},
+					{Name: "m04", Worker: true},
+				},
+			},
+			upgrade:  []string{"", "m04"},
+			snapshot: "m02",
+		},
+		{
+			name: "haproxy",
+			cc: config.ClusterConfig{
+				KubernetesConfig: config.KubernetesConfig{HALoadBalancer: config.HAProxy},
+				Nodes: []config.Node{
+					{Name: "", ControlPlane: true, Worker: true},
+					{Name: "m02", ControlPlane: true, Worker: true},
+					{Name: "m03", LoadBalancer: true},
+				},
+			},
+			upgrade:  []string{"", "m02"},
+			snapshot: "",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := names(upgradeOrder(tc.cc)); !reflect.DeepEqual(got, tc.upgrade) {
+				t.Errorf("upgradeOrder() = %q, want %q", got, tc.upgrade)
+			}
+			if got := config.EtcdNodes(tc.cc)[0].Name; got != tc.snapshot {
+				t.Errorf("etcd snapshot taken on %q, want %q", got, tc.snapshot)
+			}
+		})
+	}
+}
@@ -29,8 +29,14 @@ const (
 	GuestBackupDir = GuestPersistentDir + "/backup"
 	// GuestKubernetesCertsDir are where Kubernetes certificates are stored
 	GuestKubernetesCertsDir = GuestPersistentDir + "/certs"
+	// GuestAPIServerConfigDir is where the audit policy and admission configuration of the apiserver are stored
+	GuestAPIServerConfigDir = GuestPersistentDir + "/apiserver"
+	// GuestAuditLogDir is where the apiserver writes its audit log
+	GuestAuditLogDir = "/var/log/kubernetes/audit"
 	// GuestCertAuthDir is where system CA certificates are installed to
 	GuestCertAuthDir = "/usr/share/ca-certificates"
+	// GuestOIDCCACert is where the CA cert of the OIDC provider of the cluster is installed to
+	GuestOIDCCACert = GuestCertAuthDir + "/oidc-ca.pem"
 	// GuestCertStoreDir is where system SSL certificates are installed
 	GuestCertStoreDir = "/etc/ssl/certs"
 	// GuestGvisorDir is where gvisor bootstraps from
@@ -18,8 +18,10 @@ package util
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
@@ -86,7 +88,7 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 	if decodedSignerKey == nil {
 		return errors.New("Unable to decode key")
 	}
-	signerKey, err := x509.ParsePKCS1PrivateKey(decodedSignerKey.Bytes)
+	signerKey, err := parseSignerKey(decodedSignerKey.Bytes)
 	if err != nil {
 		return errors.Wrap(err, "Error parsing private key: decodedSignerKey.Bytes")
 	}
@@ -116,6 +118,56 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 	return writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey)
 }
 
+// ValidateCACert checks that a cert is a CA which can sign certs, and that the key belongs to it
+func ValidateCACert(certPath, keyPath string) error {
+	certBytes, err := os.ReadFile(certPath)
+	if err != nil {
+		return errors.Wrap(err, "Error reading file: certPath")
+	}
+	keyBytes, err := os.ReadFile(keyPath)
+	if err != nil {
+		return errors.Wrap(err, "Error reading file: keyPath")
+	}
+	pair, err := tls.X509KeyPair(certBytes, keyBytes)
+	if err != nil {
+		return errors.Wrap(err, "Error loading key pair")
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return errors.Wrap(err, "Error parsing certificate")
+	}
+	if !cert.IsCA || (cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0) {
+		return errors.Errorf("%s is not a certificate authority", certPath)
+	}
+	if time.Now().After(cert.NotAfter) {
+		return errors.Errorf("%s expired on %s", certPath, cert.NotAfter)
+	}
+	decodedKey, _ := pem.Decode(keyBytes)
+	if _, err := parseSignerKey(decodedKey.Bytes); err != nil {
+		return errors.Wrap(err, "Error parsing private key")
+	}
+	return nil
+}
+
+// parseSignerKey parses a PKCS#1, PKCS#8 or EC private key
+func parseSignerKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
 func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
 	keyBytes, err := os.ReadFile(keyPath)
 	if err == nil {
@@ -134,7 +186,7 @@ func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
 	return priv, nil
 }
 
-func writeCertsAndKeys(template *x509.Certificate, certPath string, signeeKey *rsa.PrivateKey, keyPath string, parent *x509.Certificate, signingKey *rsa.PrivateKey) error {
+func writeCertsAndKeys(template *x509.Certificate, certPath string, signeeKey *rsa.PrivateKey, keyPath string, parent *x509.Certificate, signingKey crypto.Signer) error {
 	derBytes, err := x509.CreateCertificate(rand.Reader, template, parent, &signeeKey.PublicKey, signingKey)
 	if err != nil {
 		return errors.Wrap(err, "Error creating certificate")
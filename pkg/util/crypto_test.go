@@ -17,12 +17,18 @@ limitations under the License.
 package util
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
+	"math/big"
 	"net"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/constants"
 )
@@ -133,3 +139,60 @@ func TestGenerateSignedCert(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCACert(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// an ECDSA CA with a PKCS#8 key, as issued by many enterprise CAs
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Enterprise CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert := filepath.Join(tmpDir, "enterprise-ca.crt")
+	caKey := filepath.Join(tmpDir, "enterprise-ca.key")
+	if err := os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(caKey, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ValidateCACert(caCert, caKey); err != nil {
+		t.Fatalf("ValidateCACert() error = %v", err)
+	}
+	certPath := filepath.Join(tmpDir, "apiserver.crt")
+	if err := GenerateSignedCert(certPath, filepath.Join(tmpDir, "apiserver.key"), "minikube", nil, []string{"minikube"}, caCert, caKey, time.Hour); err != nil {
+		t.Fatalf("GenerateSignedCert() with an ECDSA CA error = %v", err)
+	}
+
+	// a leaf cert is not a CA
+	if err := ValidateCACert(certPath, filepath.Join(tmpDir, "apiserver.key")); err == nil {
+		t.Errorf("ValidateCACert() of a leaf cert should have returned an error")
+	}
+	// the key of another CA does not match
+	otherCert := filepath.Join(tmpDir, "other.crt")
+	otherKey := filepath.Join(tmpDir, "other.key")
+	if err := GenerateCACert(otherCert, otherKey, "other"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateCACert(caCert, otherKey); err == nil {
+		t.Errorf("ValidateCACert() with a mismatched key should have returned an error")
+	}
+}